@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
+	_ "time/tzdata" // TIMEZONE works on hosts without a zoneinfo database
 
 	"mcp-godo/pkg/handler"
 	"mcp-godo/pkg/todo"
@@ -14,47 +18,109 @@ import (
 var todoService todo.TodoService
 var projectService todo.ProjectService
 var categoryService todo.CategoryService
+var tagService todo.TagService
+var timeService todo.TimeService
+var reminderService todo.ReminderService
+var savedFilterService todo.SavedFilterService
 var config todo.Config
 
+const defaultToolTimeout = 30 * time.Second
+
+const defaultReminderInterval = time.Minute
+
+// dateFormats describes what every date argument accepts
+const dateFormats = "a date such as 2030-11-03, a time such as 2030-11-03T17:00:00Z, or an expression such as tomorrow, in 3 days, end of month or next friday 5pm. Times without an offset are in the server's timezone"
+
+// dueDateFormats is dateFormats for due dates, which may be a whole day
+const dueDateFormats = dateFormats + ". A day without a time of day, such as 2030-11-03 or friday, is an all-day due date"
+
 func loadConfig(){
 	config.StorageType = os.Getenv("STORAGE_TYPE")
 	config.SQLDBPath = os.Getenv("DB_PATH")
 	config.HTTPPort = os.Getenv("HTTP_PORT")
+	config.DisableAutoMigrate = os.Getenv("DISABLE_AUTO_MIGRATE") == "true"
+	config.MaxOpenConns, _ = strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
+	config.MaxIdleConns, _ = strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
+	config.ConnMaxLifetime, _ = time.ParseDuration(os.Getenv("DB_CONN_MAX_LIFETIME"))
+
+	config.ToolTimeout = defaultToolTimeout
+	if timeout := os.Getenv("TOOL_TIMEOUT"); timeout != "" {
+		if d, err := time.ParseDuration(timeout); err == nil {
+			config.ToolTimeout = d
+		} else {
+			fmt.Println("Ignoring invalid TOOL_TIMEOUT:", err)
+		}
+	}
+
+	if policy, err := todo.ParseCompletionPolicy(os.Getenv("SUBTASK_COMPLETION")); err == nil {
+		config.SubtaskCompletion = policy
+	} else {
+		fmt.Println("Ignoring invalid SUBTASK_COMPLETION:", err)
+	}
+
+	config.Location = time.Local
+	if name := os.Getenv("TIMEZONE"); name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			config.Location = loc
+		} else {
+			fmt.Println("Ignoring invalid TIMEZONE:", err)
+		}
+	}
+
+	config.ReminderInterval = defaultReminderInterval
+	if interval := os.Getenv("REMINDER_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil && d >= 0 {
+			config.ReminderInterval = d
+		} else {
+			fmt.Println("Ignoring invalid REMINDER_INTERVAL:", interval)
+		}
+	}
 }
 
 func main() {
-	var err error
 	loadConfig()
-	
-	todoService, err = todo.NewTodoServiceFromConfig(config)	
-	if err != nil {
-		fmt.Println("Error creating todo service:", err)
-		return
-	}
 
-	// Initialize project service using the same database connection
-	projectService, err = todo.NewProjectServiceFromConfig(config)
-	if err != nil {
-		fmt.Println("Error creating project service:", err)
-		return
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
 	}
-
-	// Initialize category service using the same database connection
-	categoryService, err = todo.NewCategoryServiceFromConfig(config)
+	
+	// All services share one connection pool
+	storage, err := todo.NewStorage(config)
 	if err != nil {
-		fmt.Println("Error creating category service:", err)
+		fmt.Println("Error creating storage:", err)
 		return
 	}
+	defer storage.Close()
+
+	todoService = storage.Todos
+	projectService = storage.Projects
+	categoryService = storage.Categories
+	tagService = storage.Tags
+	timeService = storage.TimeEntries
+	reminderService = storage.Reminders
+	savedFilterService = storage.Filters
 
-	// Create a new MCP server
+	// Create a new MCP server. Its session hooks track the connected
+	// clients that reminders are sent to.
+	hooks := &server.Hooks{}
+	sessions := handler.NewSessions(hooks)
 	s := server.NewMCPServer(
 		"Todo MCP",
 		"1.0.0",
 		server.WithToolCapabilities(true),
 		server.WithResourceCapabilities(true, true),
+		server.WithLogging(),
+		server.WithToolHandlerMiddleware(handler.TimeoutMiddleware(config.ToolTimeout)),
+		server.WithHooks(hooks),
 	)
 
-	addTools(s)
+	toolHandler := addTools(s)
+	addResources(s)
+
+	// Reminders are sent to every connected client as log messages
+	if config.ReminderInterval > 0 {
+		go toolHandler.RunReminders(context.Background(), sessions, config.ReminderInterval)
+	}
 
 	httpServer := server.NewStreamableHTTPServer(s)
 	if err := httpServer.Start(fmt.Sprintf(":%s", config.HTTPPort)); err != nil {
@@ -62,8 +128,18 @@ func main() {
 	}
 }
 
-func addTools(s *server.MCPServer) {
-	handler := handler.NewHandlerWithProjectAndCategory(todoService, projectService, categoryService)
+func addTools(s *server.MCPServer) *handler.Handler {
+	handler := handler.NewHandlerWithServices(todo.Services{
+		Todos:       todoService,
+		Projects:    projectService,
+		Categories:  categoryService,
+		Tags:        tagService,
+		TimeEntries: timeService,
+		Reminders:   reminderService,
+		Filters:     savedFilterService,
+	})
+	handler.SetCompletionPolicy(config.SubtaskCompletion)
+	handler.SetTimezone(config.Location)
 
 	// Add tool with project_id support
 	tool := mcp.NewTool("add_todo",
@@ -73,20 +149,30 @@ func addTools(s *server.MCPServer) {
 			mcp.Description("The title of the todo item"),
 		),
 		mcp.WithString("due_date",
-			mcp.Description("The due date of the todo item in ISO 8601 format it should match the template '2006-01-02T15:04:05Z' if the user has not specified a time, assume midnight of the due date"),
+			mcp.Description("The due date of the todo item: "+dueDateFormats),
 		),
 		mcp.WithNumber("project_id",
 			mcp.Description("The ID of the project to assign this todo to (optional)"),
 		),
+		mcp.WithString("priority",
+			mcp.Description("The priority of the todo item (optional): none, low, medium, high or urgent, or P1 (urgent) to P4 (low)"),
+		),
+		mcp.WithString("notes",
+			mcp.Description("Long-form notes for the todo item in Markdown, such as context, links or acceptance criteria (optional)"),
+		),
 	)
 	s.AddTool(tool, handler.AddTodoHandler)
 	
 	completeTodoTool := mcp.NewTool("complete_todo",
-		mcp.WithDescription("Complete a single todo item by ID - you may need to call get_active_todos or list_todos in order to get the correct ID - lookup by title or other attributes won't work with this call"),
+		mcp.WithDescription("Complete a single todo item by ID - you may need to call get_active_todos or list_todos in order to get the correct ID - lookup by title or other attributes won't work with this call. If the todo has a recurrence pattern, its next occurrence is created and reported, as are todos it was the last open blocker of"),
 		mcp.WithString("id",
 			mcp.Required(),
 			mcp.Description("The ID of the todo item"),
 		),
+		mcp.WithString("subtasks",
+			mcp.Description("What to do if the todo has open subtasks: 'block' refuses to complete it, 'complete' completes them too. Defaults to the server setting"),
+			mcp.Enum("block", "complete"),
+		),
 	)
 	s.AddTool(completeTodoTool, handler.CompleteTodoHandler)
 	
@@ -123,7 +209,13 @@ func addTools(s *server.MCPServer) {
 	s.AddTool(deleteTodoTool, handler.DeleteTodoHandler)
 	
 	getActiveTodosTool := mcp.NewTool("get_active_todos",
-		mcp.WithDescription("Retrieve all active (not completed) todos (generally prefer this over list_todos)"),
+		mcp.WithDescription("Retrieve all active (not completed) todos (generally prefer this over list_todos). Todos waiting on open blockers are marked 'Blocked by'"),
+		mcp.WithBoolean("hide_blocked",
+			mcp.Description("Leave out todos that are waiting on an open blocker (optional, defaults to false)"),
+		),
+		mcp.WithBoolean("include_deferred",
+			mcp.Description("Also list todos whose start date is still to come (optional, defaults to false)"),
+		),
 	)
 	s.AddTool(getActiveTodosTool, handler.GetActiveTodosHandler)
 	
@@ -140,11 +232,69 @@ func addTools(s *server.MCPServer) {
 		),
 		mcp.WithString("due_date",
 			mcp.Required(),
-			mcp.Description("The new due date for the todo item"),
+			mcp.Description("The new due date for the todo item: "+dueDateFormats),
 		),
 	)
 	s.AddTool(updateDueDateTool, handler.UpdateDueDateHandler)
 
+	setPriorityTool := mcp.NewTool("set_priority",
+		mcp.WithDescription("Set the priority of a single todo item by ID. get_active_todos, get_project_todos and get_category_todos list higher priorities first"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithString("priority",
+			mcp.Required(),
+			mcp.Description("The new priority: none, low, medium, high or urgent, or P1 (urgent) to P4 (low). Use none to clear it"),
+		),
+	)
+	s.AddTool(setPriorityTool, handler.SetPriorityHandler)
+
+	updateTodoTool := mcp.NewTool("update_todo",
+		mcp.WithDescription("Update a single todo item by ID. Only the fields given are changed, and the full updated todo is returned"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithString("title",
+			mcp.Description("The new title of the todo item"),
+		),
+		mcp.WithString("due_date",
+			mcp.Description("The new due date: "+dueDateFormats+". Use an empty string to clear it"),
+		),
+		mcp.WithNumber("project_id",
+			mcp.Description("The ID of the project to move the todo to. Use 0 to remove it from its project"),
+		),
+		mcp.WithNumber("category_id",
+			mcp.Description("The ID of the category to assign the todo to. Use 0 to remove it from its category"),
+		),
+		mcp.WithString("notes",
+			mcp.Description("The new notes of the todo item in Markdown. Use an empty string to clear them"),
+		),
+		mcp.WithString("start_date",
+			mcp.Description("The new start date; get_active_todos hides the todo until then: "+dateFormats+". Use an empty string to clear it"),
+		),
+		mcp.WithString("estimate",
+			mcp.Description("The expected effort, such as 45m, 2h or 1h30m. Use an empty string to clear it"),
+		),
+	)
+	s.AddTool(updateTodoTool, handler.UpdateTodoHandler)
+
+	snoozeTodoTool := mcp.NewTool("snooze_todo",
+		mcp.WithDescription("Defer a todo so get_active_todos and get_next_actions hide it until its new start date. Give either duration or until"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithString("duration",
+			mcp.Description("How long to snooze from now, such as 90m, 2h, 3d, 1w or 1d12h"),
+		),
+		mcp.WithString("until",
+			mcp.Description("When the todo comes back: "+dateFormats),
+		),
+	)
+	s.AddTool(snoozeTodoTool, handler.SnoozeTodoHandler)
+
 	titleSearchTool := mcp.NewTool("title_search",
 		mcp.WithDescription("Search todos by title, if this returns nothing or an error, call get_active_todos to find the todo "),
 		mcp.WithString("query",
@@ -158,23 +308,37 @@ func addTools(s *server.MCPServer) {
 	)
 	s.AddTool(titleSearchTool, handler.TitleSearchHandler)
 
+	searchTodosTool := mcp.NewTool("search_todos",
+		mcp.WithDescription("Search todos by title and notes. Use this when the words you are looking for may be in a todo's notes rather than its title"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The search query to use"),
+		),
+		mcp.WithBoolean("active_only",
+			mcp.DefaultBool(true),
+			mcp.Description("Whether to only search active todos or not"),
+		),
+	)
+	s.AddTool(searchTodosTool, handler.SearchTodosHandler)
+
 	// Add recurrence pattern tool
 	addRecurrencePatternTool := mcp.NewTool("add_recurrence_pattern",
-		mcp.WithDescription("Add a recurrence pattern to a todo item"),
+		mcp.WithDescription("Add a recurrence pattern to a todo item, either as a frequency and interval or as an iCalendar RRULE. Completing the todo then creates its next occurrence, due at the next date of the pattern after the previous due date, until the end date or count is reached. Use preview_recurrence to check the dates first"),
 		mcp.WithString("todo_id",
 			mcp.Required(),
 			mcp.Description("The ID of the todo item"),
 		),
 		mcp.WithString("frequency",
-			mcp.Required(),
-			mcp.Description("The frequency of the recurrence (e.g., 'daily', 'weekly', 'monthly', 'yearly')"),
+			mcp.Description("The frequency of the recurrence (e.g., 'daily', 'weekly', 'monthly', 'yearly'). Required unless rrule is given"),
 		),
 		mcp.WithNumber("interval",
-			mcp.Required(),
-			mcp.Description("The interval between recurrences (e.g., 1 for every day/week/month/year)"),
+			mcp.Description("The interval between recurrences (e.g., 1 for every day/week/month/year). Required unless rrule is given"),
+		),
+		mcp.WithString("rrule",
+			mcp.Description("An RFC 5545 RRULE such as 'FREQ=WEEKLY;BYDAY=MO,WE,FR' or 'FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1' (last weekday of the month). Supports INTERVAL, COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY, BYSETPOS and WKST (optional)"),
 		),
 		mcp.WithString("until",
-			mcp.Description("The end date for the recurrence pattern in ISO 8601 format (optional)"),
+			mcp.Description("The end date for the recurrence pattern (optional): "+dateFormats),
 		),
 		mcp.WithNumber("count",
 			mcp.Description("The number of times the recurrence should occur (optional)"),
@@ -182,6 +346,33 @@ func addTools(s *server.MCPServer) {
 	)
 	s.AddTool(addRecurrencePatternTool, handler.AddRecurrencePatternHandler)
 
+	// Preview recurrence tool
+	previewRecurrenceTool := mcp.NewTool("preview_recurrence",
+		mcp.WithDescription("List the upcoming dates of a recurrence pattern without saving it. Takes the same pattern arguments as add_recurrence_pattern"),
+		mcp.WithString("frequency",
+			mcp.Description("The frequency of the recurrence (e.g., 'daily', 'weekly', 'monthly', 'yearly'). Required unless rrule is given"),
+		),
+		mcp.WithNumber("interval",
+			mcp.Description("The interval between recurrences. Required unless rrule is given"),
+		),
+		mcp.WithString("rrule",
+			mcp.Description("An RFC 5545 RRULE such as 'FREQ=MONTHLY;BYDAY=2TU' (optional)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("The end date for the recurrence pattern (optional): "+dateFormats),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("The number of times the recurrence should occur (optional)"),
+		),
+		mcp.WithString("start",
+			mcp.Description("The first due date of the series; its time of day is kept by every occurrence (optional, defaults to now): "+dateFormats),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("How many dates to list, from 1 to 50 (optional, defaults to 5)"),
+		),
+	)
+	s.AddTool(previewRecurrenceTool, handler.PreviewRecurrenceHandler)
+
 	// Get recurrence pattern tool
 	getRecurrencePatternTool := mcp.NewTool("get_recurrence_pattern",
 		mcp.WithDescription("Retrieve a recurrence pattern by its ID"),
@@ -192,11 +383,119 @@ func addTools(s *server.MCPServer) {
 	)
 	s.AddTool(getRecurrencePatternTool, handler.GetRecurrencePatternHandler)
 
+	// Add recurrence pattern management tools
+	addRecurrenceTools(s, handler)
+
 	// Add project management tools
 	addProjectTools(s, handler)
 
 	// Add category management tools
 	addCategoryTools(s, handler)
+
+	// Add tag tools
+	addTagTools(s, handler)
+
+	// Add subtask tools
+	addSubtaskTools(s, handler)
+
+	// Add dependency tools
+	addDependencyTools(s, handler)
+
+	// Add time tracking tools
+	addTimeTools(s, handler)
+
+	// Add reminder tools
+	addReminderTools(s, handler)
+
+	// Add saved filter tools
+	addFilterTools(s, handler)
+	return handler
+}
+
+func addResources(s *server.MCPServer) {
+	handler := handler.NewHandler(todoService)
+	handler.SetTimezone(config.Location)
+
+	s.AddResource(mcp.NewResource("todos://all", "All todos",
+		mcp.WithResourceDescription("Every todo item as JSON"),
+		mcp.WithMIMEType("application/json"),
+	), handler.ListTodosResourceHandler)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate("todos://{id}", "Todo",
+		mcp.WithTemplateDescription("A single todo item as JSON, followed by its notes as Markdown when it has any"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), handler.GetSingleTodoResourceHandler)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate("todos://{id}/notes", "Todo notes",
+		mcp.WithTemplateDescription("The notes of a single todo item as Markdown"),
+		mcp.WithTemplateMIMEType("text/markdown"),
+	), handler.GetSingleTodoResourceHandler)
+}
+
+func addRecurrenceTools(s *server.MCPServer, handler *handler.Handler) {
+	// List recurrence patterns tool
+	listRecurrencePatternsTool := mcp.NewTool("list_recurrence_patterns",
+		mcp.WithDescription("List recurrence patterns with their IDs and a readable summary. Pass a todo_id to get the patterns of the series that todo belongs to"),
+		mcp.WithString("todo_id",
+			mcp.Description("The ID of any todo in a recurring series (optional, lists every pattern when omitted)"),
+		),
+	)
+	s.AddTool(listRecurrencePatternsTool, handler.ListRecurrencePatternsHandler)
+
+	// Update recurrence pattern tool
+	updateRecurrencePatternTool := mcp.NewTool("update_recurrence_pattern",
+		mcp.WithDescription("Change the schedule of a recurrence pattern. Only the arguments given are changed; the todos already created keep their due dates"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("The ID of the recurrence pattern"),
+		),
+		mcp.WithString("frequency",
+			mcp.Description("The new frequency ('daily', 'weekly', 'monthly' or 'yearly'); replaces any rrule (optional)"),
+		),
+		mcp.WithNumber("interval",
+			mcp.Description("The new interval; replaces any rrule (optional)"),
+		),
+		mcp.WithString("rrule",
+			mcp.Description("A new RFC 5545 RRULE, or an empty string to go back to frequency and interval (optional)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("The new end date, or an empty string for none (optional, not allowed for rrule patterns): "+dateFormats),
+		),
+		mcp.WithNumber("count",
+			mcp.Description("The new number of occurrences, or 0 for no limit (optional, not allowed for rrule patterns)"),
+		),
+	)
+	s.AddTool(updateRecurrencePatternTool, handler.UpdateRecurrencePatternHandler)
+
+	// Delete recurrence pattern tool
+	deleteRecurrencePatternTool := mcp.NewTool("delete_recurrence_pattern",
+		mcp.WithDescription("Delete a recurrence pattern so completing its todos no longer creates new occurrences. Existing todos are kept"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("The ID of the recurrence pattern"),
+		),
+	)
+	s.AddTool(deleteRecurrencePatternTool, handler.DeleteRecurrencePatternHandler)
+
+	// Pause recurrence pattern tool
+	pauseRecurrencePatternTool := mcp.NewTool("pause_recurrence_pattern",
+		mcp.WithDescription("Pause a recurrence pattern: completing its todos creates no new occurrences until it is resumed"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("The ID of the recurrence pattern"),
+		),
+	)
+	s.AddTool(pauseRecurrencePatternTool, handler.PauseRecurrencePatternHandler)
+
+	// Resume recurrence pattern tool
+	resumeRecurrencePatternTool := mcp.NewTool("resume_recurrence_pattern",
+		mcp.WithDescription("Resume a paused recurrence pattern. If the series' latest todo was completed while paused, its next occurrence from today is created and reported"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("The ID of the recurrence pattern"),
+		),
+	)
+	s.AddTool(resumeRecurrencePatternTool, handler.ResumeRecurrencePatternHandler)
 }
 
 func addProjectTools(s *server.MCPServer, handler *handler.Handler) {
@@ -262,6 +561,9 @@ func addProjectTools(s *server.MCPServer, handler *handler.Handler) {
 			mcp.Required(),
 			mcp.Description("The ID of the project"),
 		),
+		mcp.WithBoolean("include_deferred",
+			mcp.Description("Also list todos whose start date is still to come (optional, defaults to false)"),
+		),
 	)
 	s.AddTool(getProjectTodosTool, handler.GetProjectTodosHandler)
 
@@ -277,7 +579,7 @@ func addProjectTools(s *server.MCPServer, handler *handler.Handler) {
 			mcp.Description("The ID of the project to add the todo to"),
 		),
 		mcp.WithString("due_date",
-			mcp.Description("The due date of the todo item in ISO 8601 format"),
+			mcp.Description("The due date of the todo item: "+dueDateFormats),
 		),
 	)
 	s.AddTool(addTodoToProjectTool, handler.AddTodoToProjectHandler)
@@ -352,6 +654,9 @@ func addCategoryTools(s *server.MCPServer, handler *handler.Handler) {
 			mcp.Required(),
 			mcp.Description("The ID of the category"),
 		),
+		mcp.WithBoolean("include_deferred",
+			mcp.Description("Also list todos whose start date is still to come (optional, defaults to false)"),
+		),
 	)
 	s.AddTool(getCategoryTodosTool, handler.GetCategoryTodosHandler)
 
@@ -385,3 +690,336 @@ func addCategoryTools(s *server.MCPServer, handler *handler.Handler) {
 	)
 	s.AddTool(removeTodoFromCategoryTool, handler.RemoveTodoFromCategoryHandler)
 }
+
+func addTagTools(s *server.MCPServer, handler *handler.Handler) {
+	// Add tags tool
+	addTagsTool := mcp.NewTool("add_tags",
+		mcp.WithDescription("Add one or more tags to a todo item. Tags that do not exist yet are created. Tag names are case-insensitive and a leading '#' is ignored"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("The tag names to add, e.g. [\"work\", \"errands\"]"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	)
+	s.AddTool(addTagsTool, handler.AddTagsHandler)
+
+	// Remove tags tool
+	removeTagsTool := mcp.NewTool("remove_tags",
+		mcp.WithDescription("Remove one or more tags from a todo item"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("The tag names to remove"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	)
+	s.AddTool(removeTagsTool, handler.RemoveTagsHandler)
+
+	// List tags tool
+	listTagsTool := mcp.NewTool("list_tags",
+		mcp.WithDescription("List all tags with the number of todos that carry each one"),
+	)
+	s.AddTool(listTagsTool, handler.ListTagsHandler)
+
+	// Rename tag tool
+	renameTagTool := mcp.NewTool("rename_tag",
+		mcp.WithDescription("Rename a tag. Fails if the new name is already a tag; use merge_tags to combine two tags"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The current name of the tag"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("The new name of the tag"),
+		),
+	)
+	s.AddTool(renameTagTool, handler.RenameTagHandler)
+
+	// Merge tags tool
+	mergeTagsTool := mcp.NewTool("merge_tags",
+		mcp.WithDescription("Merge one tag into another: every todo tagged 'from' is tagged 'into' instead, and 'from' is deleted"),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("The tag to merge and delete"),
+		),
+		mcp.WithString("into",
+			mcp.Required(),
+			mcp.Description("The tag to keep"),
+		),
+	)
+	s.AddTool(mergeTagsTool, handler.MergeTagsHandler)
+
+	// Get todos by tags tool
+	getTodosByTagsTool := mcp.NewTool("get_todos_by_tags",
+		mcp.WithDescription("Retrieve the todos that have any, or all, of a set of tags, highest priority first"),
+		mcp.WithArray("tags",
+			mcp.Required(),
+			mcp.Description("The tag names to filter by"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("match",
+			mcp.Description("'any' (default) to match todos with at least one of the tags, or 'all' to match todos with every tag"),
+			mcp.Enum("any", "all"),
+		),
+	)
+	s.AddTool(getTodosByTagsTool, handler.GetTodosByTagsHandler)
+}
+
+func addSubtaskTools(s *server.MCPServer, handler *handler.Handler) {
+	// Add subtask tool
+	addSubtaskTool := mcp.NewTool("add_subtask",
+		mcp.WithDescription("Add a subtask under an existing todo. The subtask joins the parent's project and category, and get_todo shows it in the parent's subtask tree"),
+		mcp.WithString("parent_id",
+			mcp.Required(),
+			mcp.Description("The ID of the parent todo item"),
+		),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.Description("The title of the subtask"),
+		),
+		mcp.WithString("due_date",
+			mcp.Description("The due date of the subtask (optional): "+dueDateFormats),
+		),
+		mcp.WithString("priority",
+			mcp.Description("The priority of the subtask (optional): none, low, medium, high or urgent, or P1 (urgent) to P4 (low)"),
+		),
+	)
+	s.AddTool(addSubtaskTool, handler.AddSubtaskHandler)
+
+	// Move subtask tool
+	moveSubtaskTool := mcp.NewTool("move_subtask",
+		mcp.WithDescription("Move a todo, with its own subtasks, under another todo, or make it a top-level todo. A todo cannot be moved under itself or one of its subtasks"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item to move"),
+		),
+		mcp.WithString("parent_id",
+			mcp.Description("The ID of the new parent todo item; empty or omitted makes the todo top-level"),
+		),
+	)
+	s.AddTool(moveSubtaskTool, handler.MoveSubtaskHandler)
+}
+
+func addDependencyTools(s *server.MCPServer, handler *handler.Handler) {
+	// Add blocker tool
+	addBlockerTool := mcp.NewTool("add_blocker",
+		mcp.WithDescription("Record that a todo cannot start until another todo is completed. A todo cannot block itself, and blockers cannot form a cycle"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item that waits"),
+		),
+		mcp.WithString("blocker_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item that must be completed first"),
+		),
+	)
+	s.AddTool(addBlockerTool, handler.AddBlockerHandler)
+
+	// Remove blocker tool
+	removeBlockerTool := mcp.NewTool("remove_blocker",
+		mcp.WithDescription("Remove a blocker from a todo"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item that waits"),
+		),
+		mcp.WithString("blocker_id",
+			mcp.Required(),
+			mcp.Description("The ID of the blocker to remove"),
+		),
+	)
+	s.AddTool(removeBlockerTool, handler.RemoveBlockerHandler)
+
+	// Get next actions tool
+	getNextActionsTool := mcp.NewTool("get_next_actions",
+		mcp.WithDescription("Retrieve the active todos that are not waiting on any open blocker, highest priority first and then soonest due"),
+	)
+	s.AddTool(getNextActionsTool, handler.GetNextActionsHandler)
+}
+
+func addTimeTools(s *server.MCPServer, handler *handler.Handler) {
+	// Start timer tool
+	startTimerTool := mcp.NewTool("start_timer",
+		mcp.WithDescription("Start timing work on a todo. Only one timer runs at a time; call stop_timer before starting another"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithString("note",
+			mcp.Description("What the time is spent on (optional)"),
+		),
+	)
+	s.AddTool(startTimerTool, handler.StartTimerHandler)
+
+	// Stop timer tool
+	stopTimerTool := mcp.NewTool("stop_timer",
+		mcp.WithDescription("Stop the running timer and record the time spent"),
+	)
+	s.AddTool(stopTimerTool, handler.StopTimerHandler)
+
+	// Log time tool
+	logTimeTool := mcp.NewTool("log_time",
+		mcp.WithDescription("Record time already spent on a todo without a timer"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithString("duration",
+			mcp.Required(),
+			mcp.Description("How long was spent, such as 45m, 2h or 1h30m"),
+		),
+		mcp.WithString("started_at",
+			mcp.Description("When the work started (optional, defaults to duration before now): "+dateFormats),
+		),
+		mcp.WithString("note",
+			mcp.Description("What the time was spent on (optional)"),
+		),
+	)
+	s.AddTool(logTimeTool, handler.LogTimeHandler)
+
+	// Get time entries tool
+	getTimeEntriesTool := mcp.NewTool("get_time_entries",
+		mcp.WithDescription("List the time tracked on a todo with its total and estimate"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+	)
+	s.AddTool(getTimeEntriesTool, handler.GetTimeEntriesHandler)
+
+	// Delete time entry tool
+	deleteTimeEntryTool := mcp.NewTool("delete_time_entry",
+		mcp.WithDescription("Delete a time entry, such as one logged by mistake"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("The ID of the time entry"),
+		),
+	)
+	s.AddTool(deleteTimeEntryTool, handler.DeleteTimeEntryHandler)
+
+	// Time report tool
+	timeReportTool := mcp.NewTool("time_report",
+		mcp.WithDescription("Summarize the time tracked over a date range by todo, project and category"),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("The start of the range: "+dateFormats),
+		),
+		mcp.WithString("to",
+			mcp.Description("The end of the range (optional, defaults to now): "+dateFormats+". A day without a time of day includes that whole day"),
+		),
+	)
+	s.AddTool(timeReportTool, handler.TimeReportHandler)
+}
+
+func addReminderTools(s *server.MCPServer, handler *handler.Handler) {
+	// Set reminders tool
+	setRemindersTool := mcp.NewTool("set_reminders",
+		mcp.WithDescription("Set when the server reminds connected clients of a todo's due date, replacing its reminders. Reminders are sent as log message notifications and fire once per due date; moving the due date re-arms them"),
+		mcp.WithString("todo_id",
+			mcp.Required(),
+			mcp.Description("The ID of the todo item"),
+		),
+		mcp.WithArray("offsets",
+			mcp.Required(),
+			mcp.Description("How long before the due date each reminder fires, such as [\"1d\", \"15m\", \"0\"], where 0 is at the due date. An empty array removes the todo's reminders"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+	)
+	s.AddTool(setRemindersTool, handler.SetRemindersHandler)
+}
+
+func addFilterTools(s *server.MCPServer, handler *handler.Handler) {
+	// Arguments shared by create_filter and update_filter
+	criteria := []mcp.ToolOption{
+		mcp.WithNumber("project_id",
+			mcp.Description("Only todos in this project (optional)"),
+		),
+		mcp.WithNumber("category_id",
+			mcp.Description("Only todos in this category (optional)"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Only todos carrying every one of these tags (optional)"),
+			mcp.Items(map[string]any{"type": "string"}),
+		),
+		mcp.WithString("due_after",
+			mcp.Description("Only todos due from this date on (optional), resolved each time the filter runs: "+dateFormats),
+		),
+		mcp.WithString("due_before",
+			mcp.Description("Only todos due by this date (optional), resolved each time the filter runs. A day without a time of day, such as end of week, includes that whole day; use now for overdue todos"),
+		),
+		mcp.WithString("priority",
+			mcp.Description("Only todos of at least this priority (optional): low, medium, high or urgent, or P1 (urgent) to P4 (low)"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Which todos to include by completion (optional, defaults to open)"),
+			mcp.Enum("open", "completed", "all"),
+		),
+		mcp.WithString("query",
+			mcp.Description("Only todos with this text in the title or notes, ignoring case (optional)"),
+		),
+	}
+
+	// Create filter tool
+	createFilterTool := mcp.NewTool("create_filter", append([]mcp.ToolOption{
+		mcp.WithDescription("Save a named filter, or smart list, such as \"Work this week\" or \"Overdue personal\". A todo must meet every criterion given"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the filter"),
+		),
+	}, criteria...)...)
+	s.AddTool(createFilterTool, handler.CreateFilterHandler)
+
+	// List filters tool
+	listFiltersTool := mcp.NewTool("list_filters",
+		mcp.WithDescription("List the saved filters with their IDs and criteria"),
+	)
+	s.AddTool(listFiltersTool, handler.ListFiltersHandler)
+
+	// Run filter tool
+	runFilterTool := mcp.NewTool("run_filter",
+		mcp.WithDescription("List the todos matching a saved filter, highest priority first and then soonest due"),
+		mcp.WithNumber("id",
+			mcp.Description("The ID of the filter (id or name required)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("The name of the filter, ignoring case (id or name required)"),
+		),
+		mcp.WithBoolean("include_deferred",
+			mcp.Description("Also list todos whose start date is still to come (optional, defaults to false)"),
+		),
+	)
+	s.AddTool(runFilterTool, handler.RunFilterHandler)
+
+	// Update filter tool
+	updateFilterTool := mcp.NewTool("update_filter", append([]mcp.ToolOption{
+		mcp.WithDescription("Change a saved filter. Only the arguments given are changed; an empty string, empty array or 0 clears a criterion"),
+		mcp.WithNumber("id",
+			mcp.Description("The ID of the filter (id or name required)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("The name of the filter, ignoring case (id or name required)"),
+		),
+		mcp.WithString("new_name",
+			mcp.Description("A new name for the filter (optional)"),
+		),
+	}, criteria...)...)
+	s.AddTool(updateFilterTool, handler.UpdateFilterHandler)
+
+	// Delete filter tool
+	deleteFilterTool := mcp.NewTool("delete_filter",
+		mcp.WithDescription("Delete a saved filter. The todos it matched are not changed"),
+		mcp.WithNumber("id",
+			mcp.Description("The ID of the filter (id or name required)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("The name of the filter, ignoring case (id or name required)"),
+		),
+	)
+	s.AddTool(deleteFilterTool, handler.DeleteFilterHandler)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"mcp-godo/pkg/todo"
+)
+
+const migrateUsage = `Usage: mcp-godo migrate <command>
+
+Commands:
+  up          Apply all pending migrations
+  down [n]    Roll back the last n applied migrations (default 1)
+  status      List migrations and whether they have been applied
+
+The database is selected with the STORAGE_TYPE and DB_PATH environment variables.`
+
+// runMigrate implements the "migrate" subcommand and returns the process exit code
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Println(migrateUsage)
+		return 2
+	}
+
+	db, dialect, err := todo.OpenDatabase(config)
+	if err != nil {
+		fmt.Println("Error opening database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := todo.NewMigrator(db, dialect)
+	if err != nil {
+		fmt.Println("Error loading migrations:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Println("Error applying migrations:", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Println("Error: down expects a positive number of migrations")
+				return 2
+			}
+		}
+		rolledBack, err := migrator.Down(steps)
+		for _, m := range rolledBack {
+			fmt.Printf("Rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Println("Error rolling back migrations:", err)
+			return 1
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("No applied migrations to roll back")
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Println("Error reading migration status:", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Println(migrateUsage)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,10 @@
+-- migrations/mariadb/0001_create_todos_table.sql
+-- Creates the base todos table
+
+CREATE TABLE IF NOT EXISTS todos (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    completed_at DATETIME DEFAULT NULL,
+    due_date DATETIME DEFAULT NULL,
+    created_date DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP()
+) ENGINE=InnoDB;
@@ -1,4 +1,4 @@
--- migrations/0002_add_recurrence_support.down.sql
+-- migrations/mariadb/0002_add_recurrence_support.down.sql
 -- Rolls back the recurrence pattern support
 
 BEGIN;
@@ -1,13 +1,13 @@
--- migrations/0002_add_recurrence_support.sql
+-- migrations/mariadb/0002_add_recurrence_support.sql
 -- Adds support for recurring todos
 
 BEGIN;
 
 -- Add reference_id column to todos table
-ALTER TABLE todos ADD COLUMN reference_id INT;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS reference_id INT;
 
 -- Create recurrence_patterns table
-CREATE TABLE recurrence_patterns (
+CREATE TABLE IF NOT EXISTS recurrence_patterns (
     id INT AUTO_INCREMENT PRIMARY KEY,
     todo_id VARCHAR(255) NOT NULL,
     frequency VARCHAR(50) NOT NULL,
@@ -19,6 +19,6 @@ CREATE TABLE recurrence_patterns (
 ) ENGINE=InnoDB;
 
 -- Add index for better performance
-CREATE INDEX idx_recurrence_patterns_todo_id ON recurrence_patterns(todo_id);
+CREATE INDEX IF NOT EXISTS idx_recurrence_patterns_todo_id ON recurrence_patterns(todo_id);
 
-COMMIT;
\ No newline at end of file
+COMMIT;
@@ -1,4 +1,4 @@
--- migrations/0003_add_projects_table.down.sql
+-- migrations/mariadb/0003_add_projects_table.down.sql
 -- Rolls back the projects table support
 
 BEGIN;
@@ -1,10 +1,10 @@
--- migrations/0003_add_projects_table.sql
+-- migrations/mariadb/0003_add_projects_table.sql
 -- Adds support for project management
 
 BEGIN;
 
 -- Create projects table
-CREATE TABLE projects (
+CREATE TABLE IF NOT EXISTS projects (
     id INT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL UNIQUE,
     description TEXT,
@@ -13,6 +13,6 @@ CREATE TABLE projects (
 ) ENGINE=InnoDB;
 
 -- Add index for better performance on name lookups
-CREATE INDEX idx_projects_name ON projects(name);
+CREATE INDEX IF NOT EXISTS idx_projects_name ON projects(name);
 
-COMMIT;
\ No newline at end of file
+COMMIT;
@@ -1,4 +1,4 @@
--- migrations/0004_update_todos_add_project_id.down.sql
+-- migrations/mariadb/0004_update_todos_add_project_id.down.sql
 -- Rolls back the project_id addition to todos table
 
 BEGIN;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0005_add_categories_table.down.sql
+-- Rolls back the categories table
+
+-- Drop the index
+DROP INDEX IF EXISTS idx_categories_name ON categories;
+
+-- Drop the categories table
+DROP TABLE IF EXISTS categories;
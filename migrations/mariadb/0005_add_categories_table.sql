@@ -1,7 +1,7 @@
--- Migration: Add categories table
--- Created: 2025-10-25
+-- migrations/mariadb/0005_add_categories_table.sql
+-- Adds the categories table
 
-CREATE TABLE categories (
+CREATE TABLE IF NOT EXISTS categories (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(255) NOT NULL UNIQUE,
     description TEXT,
@@ -11,4 +11,4 @@ CREATE TABLE categories (
 ) ENGINE=InnoDB;
 
 -- Indexes for performance
-CREATE INDEX idx_categories_name ON categories(name);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_categories_name ON categories(name);
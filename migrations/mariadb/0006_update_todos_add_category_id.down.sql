@@ -0,0 +1,11 @@
+-- migrations/mariadb/0006_update_todos_add_category_id.down.sql
+-- Rolls back the category_id addition to todos table
+
+-- Drop the foreign key constraint first
+ALTER TABLE todos DROP FOREIGN KEY IF EXISTS fk_todos_category;
+
+-- Drop the index
+DROP INDEX IF EXISTS idx_todos_category_id ON todos;
+
+-- Drop the category_id column
+ALTER TABLE todos DROP COLUMN IF EXISTS category_id;
@@ -0,0 +1,14 @@
+-- migrations/mariadb/0006_update_todos_add_category_id.sql
+-- Adds category_id column to todos table for category relationship
+
+-- BIGINT to match categories.id, which the foreign key requires
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS category_id BIGINT DEFAULT NULL;
+
+-- Create index for performance on category_id field
+CREATE INDEX IF NOT EXISTS idx_todos_category_id ON todos(category_id);
+
+-- Add foreign key constraint with ON DELETE SET NULL
+ALTER TABLE todos
+ADD CONSTRAINT fk_todos_category
+FOREIGN KEY IF NOT EXISTS (category_id) REFERENCES categories(id)
+ON DELETE SET NULL;
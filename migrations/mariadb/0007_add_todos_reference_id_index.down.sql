@@ -0,0 +1,7 @@
+-- Rolls back the todos.reference_id index
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_todos_reference_id ON todos;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Indexes todos.reference_id, which links each occurrence of a recurring todo
+-- back to the first todo in its series
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_todos_reference_id ON todos(reference_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0008_add_recurrence_rrule.down.sql
+-- Rolls back the rrule column on recurrence_patterns
+
+BEGIN;
+
+ALTER TABLE recurrence_patterns DROP COLUMN IF EXISTS rrule;
+
+COMMIT;
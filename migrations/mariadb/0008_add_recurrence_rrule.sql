@@ -0,0 +1,9 @@
+-- migrations/mariadb/0008_add_recurrence_rrule.sql
+-- Stores an RFC 5545 RRULE on recurrence patterns. NULL keeps the simple
+-- frequency/interval behavior.
+
+BEGIN;
+
+ALTER TABLE recurrence_patterns ADD COLUMN IF NOT EXISTS rrule TEXT NULL;
+
+COMMIT;
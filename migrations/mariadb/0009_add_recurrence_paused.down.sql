@@ -0,0 +1,8 @@
+-- migrations/mariadb/0009_add_recurrence_paused.down.sql
+-- Rolls back the paused column on recurrence_patterns
+
+BEGIN;
+
+ALTER TABLE recurrence_patterns DROP COLUMN IF EXISTS paused;
+
+COMMIT;
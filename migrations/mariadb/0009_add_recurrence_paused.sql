@@ -0,0 +1,9 @@
+-- migrations/mariadb/0009_add_recurrence_paused.sql
+-- Lets a recurrence pattern be paused without deleting it. A paused pattern
+-- creates no further occurrences.
+
+BEGIN;
+
+ALTER TABLE recurrence_patterns ADD COLUMN IF NOT EXISTS paused BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
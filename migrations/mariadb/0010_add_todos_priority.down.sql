@@ -0,0 +1,8 @@
+-- migrations/mariadb/0010_add_todos_priority.down.sql
+-- Rolls back the priority column on todos
+
+BEGIN;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS priority;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0010_add_todos_priority.sql
+-- Adds a priority to todos: 0 none, 1 low, 2 medium, 3 high, 4 urgent.
+
+BEGIN;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS priority TINYINT NOT NULL DEFAULT 0;
+
+COMMIT;
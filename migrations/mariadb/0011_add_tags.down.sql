@@ -0,0 +1,10 @@
+-- migrations/mariadb/0011_add_tags.down.sql
+-- Rolls back the tags and todo_tags tables
+
+BEGIN;
+
+DROP TABLE IF EXISTS todo_tags;
+
+DROP TABLE IF EXISTS tags;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0012_add_todos_notes.down.sql
+-- Rolls back the notes column on todos
+
+BEGIN;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS notes;
+
+COMMIT;
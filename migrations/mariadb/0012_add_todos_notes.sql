@@ -0,0 +1,8 @@
+-- migrations/mariadb/0012_add_todos_notes.sql
+-- Adds a long-form Markdown notes body to todos. An empty string means no notes.
+
+BEGIN;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- migrations/mariadb/0013_add_todos_parent_id.down.sql
+-- Rolls back the parent_id column on todos
+
+BEGIN;
+
+ALTER TABLE todos DROP FOREIGN KEY IF EXISTS fk_todos_parent;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS parent_id;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- migrations/mariadb/0013_add_todos_parent_id.sql
+-- Adds parent_id so a todo can be a subtask of another. Deleting a todo deletes its subtasks.
+
+BEGIN;
+
+-- INT to match todos.id, which the foreign key requires
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS parent_id INT DEFAULT NULL;
+
+ALTER TABLE todos
+ADD CONSTRAINT fk_todos_parent
+FOREIGN KEY IF NOT EXISTS (parent_id) REFERENCES todos(id)
+ON DELETE CASCADE;
+
+COMMIT;
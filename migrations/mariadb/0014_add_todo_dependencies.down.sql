@@ -0,0 +1,7 @@
+-- Rolls back the todo_dependencies table
+
+BEGIN;
+
+DROP TABLE IF EXISTS todo_dependencies;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Adds todo_dependencies: a todo is blocked until every one of its blockers is completed.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS todo_dependencies (
+    todo_id INT NOT NULL,
+    blocker_id INT NOT NULL,
+    PRIMARY KEY (todo_id, blocker_id),
+    CONSTRAINT fk_todo_dependencies_todo FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+    CONSTRAINT fk_todo_dependencies_blocker FOREIGN KEY (blocker_id) REFERENCES todos(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+-- The primary key covers lookups by todo; this one covers lookups by blocker
+CREATE INDEX IF NOT EXISTS idx_todo_dependencies_blocker_id ON todo_dependencies(blocker_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0015_add_todos_start_date.down.sql
+-- Rolls back the start_date column on todos
+
+BEGIN;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS start_date;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0015_add_todos_start_date.sql
+-- Adds start_date so a todo can be deferred. Active listings hide it until then.
+
+BEGIN;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS start_date DATETIME DEFAULT NULL;
+
+COMMIT;
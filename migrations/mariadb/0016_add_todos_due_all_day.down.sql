@@ -0,0 +1,8 @@
+-- migrations/mariadb/0016_add_todos_due_all_day.down.sql
+-- Rolls back the due_all_day column on todos
+
+BEGIN;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS due_all_day;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- migrations/mariadb/0016_add_todos_due_all_day.sql
+-- Adds due_all_day to tell a due date that names a whole day, stored as midnight
+-- UTC, from one due at a moment. Existing due dates count as timed.
+
+BEGIN;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_all_day BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0017_add_todos_estimate.down.sql
+-- Rolls back the estimate_minutes column on todos
+
+BEGIN;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS estimate_minutes;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- migrations/mariadb/0017_add_todos_estimate.sql
+-- Adds estimate_minutes, the expected effort of a todo. NULL means not estimated.
+
+BEGIN;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS estimate_minutes INT DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Rolls back the time_entries table
+
+BEGIN;
+
+DROP TABLE IF EXISTS time_entries;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Adds time_entries: time spent on a todo, tracked with a timer or logged by hand.
+-- An entry without ended_at is the running timer; there is at most one.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS time_entries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    todo_id INT NOT NULL,
+    started_at DATETIME NOT NULL,
+    ended_at DATETIME DEFAULT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    -- 1 for the running timer and NULL otherwise, so the unique key allows a
+    -- single running timer
+    running TINYINT AS (IF(ended_at IS NULL, 1, NULL)) PERSISTENT,
+    UNIQUE KEY uq_time_entries_running (running),
+    CONSTRAINT fk_time_entries_todo FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_started_at ON time_entries(started_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Rolls back the reminders table
+
+BEGIN;
+
+DROP TABLE IF EXISTS reminders;
+
+COMMIT;
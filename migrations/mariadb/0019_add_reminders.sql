@@ -0,0 +1,15 @@
+-- Adds reminders: each fires offset_minutes before its todo's due date.
+-- notified_for is the due date it last fired for, so moving the due date re-arms it.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS reminders (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    todo_id INT NOT NULL,
+    offset_minutes INT NOT NULL DEFAULT 0,
+    notified_for DATETIME DEFAULT NULL,
+    UNIQUE KEY uq_reminders_todo_offset (todo_id, offset_minutes),
+    CONSTRAINT fk_reminders_todo FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE
+) ENGINE=InnoDB;
+
+COMMIT;
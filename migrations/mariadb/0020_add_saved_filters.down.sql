@@ -0,0 +1,7 @@
+-- Rolls back the saved_filters table
+
+BEGIN;
+
+DROP TABLE IF EXISTS saved_filters;
+
+COMMIT;
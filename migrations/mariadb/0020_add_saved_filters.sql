@@ -0,0 +1,14 @@
+-- Adds saved_filters: named todo queries. criteria holds the conditions as
+-- JSON so that new ones need no schema change.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS saved_filters (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    criteria TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+) ENGINE=InnoDB;
+
+COMMIT;
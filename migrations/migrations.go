@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema migrations for each
+// supported SQL dialect. Each dialect has its own directory of numbered
+// NNNN_name.sql files, with a matching NNNN_name.down.sql to roll it back.
+package migrations
+
+import "embed"
+
+//go:embed mariadb/*.sql postgres/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,4 @@
+-- migrations/postgres/0001_create_todos_table.down.sql
+-- Drops the base todos table
+
+DROP TABLE IF EXISTS todos;
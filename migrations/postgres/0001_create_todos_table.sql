@@ -0,0 +1,10 @@
+-- migrations/postgres/0001_create_todos_table.sql
+-- Creates the base todos table
+
+CREATE TABLE todos (
+    id BIGSERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    completed_at TIMESTAMPTZ DEFAULT NULL,
+    due_date TIMESTAMPTZ DEFAULT NULL,
+    created_date TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
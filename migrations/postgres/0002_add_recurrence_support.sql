@@ -0,0 +1,22 @@
+-- migrations/postgres/0002_add_recurrence_support.sql
+-- Adds support for recurring todos
+
+-- Add reference_id column to todos table
+ALTER TABLE todos ADD COLUMN reference_id BIGINT;
+
+-- Create recurrence_patterns table. interval is a keyword in Postgres, so the
+-- column is always double-quoted. There is no ON UPDATE CURRENT_TIMESTAMP;
+-- updated_at is set by whoever updates the row.
+CREATE TABLE recurrence_patterns (
+    id BIGSERIAL PRIMARY KEY,
+    todo_id VARCHAR(255) NOT NULL,
+    frequency VARCHAR(50) NOT NULL,
+    "interval" INTEGER NOT NULL,
+    until TIMESTAMPTZ NULL,
+    count INTEGER NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add index for better performance
+CREATE INDEX idx_recurrence_patterns_todo_id ON recurrence_patterns(todo_id);
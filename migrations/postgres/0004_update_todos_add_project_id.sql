@@ -0,0 +1,9 @@
+-- migrations/postgres/0004_update_todos_add_project_id.sql
+-- Adds project_id to todos table
+
+-- Add project_id column to todos table. There is deliberately no foreign key:
+-- completed todos keep the id of a deleted project as history.
+ALTER TABLE todos ADD COLUMN project_id BIGINT DEFAULT NULL;
+
+-- Add index for better performance on project_id lookups
+CREATE INDEX idx_todos_project_id ON todos(project_id);
@@ -1,3 +1,6 @@
+-- migrations/postgres/0006_update_todos_add_category_id.down.sql
+-- Rolls back the category_id addition to todos table
+
 -- Drop the foreign key constraint first
 ALTER TABLE todos DROP CONSTRAINT IF EXISTS fk_todos_category;
 
@@ -5,4 +8,4 @@ ALTER TABLE todos DROP CONSTRAINT IF EXISTS fk_todos_category;
 DROP INDEX IF EXISTS idx_todos_category_id;
 
 -- Drop the category_id column
-ALTER TABLE todos DROP COLUMN IF EXISTS category_id;
\ No newline at end of file
+ALTER TABLE todos DROP COLUMN IF EXISTS category_id;
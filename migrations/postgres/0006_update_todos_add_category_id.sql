@@ -0,0 +1,13 @@
+-- migrations/postgres/0006_update_todos_add_category_id.sql
+-- Adds category_id column to todos table for category relationship
+
+ALTER TABLE todos ADD COLUMN category_id BIGINT DEFAULT NULL;
+
+-- Create index for performance on category_id field
+CREATE INDEX idx_todos_category_id ON todos(category_id);
+
+-- Deleting a category leaves its todos uncategorized
+ALTER TABLE todos
+ADD CONSTRAINT fk_todos_category
+FOREIGN KEY (category_id) REFERENCES categories(id)
+ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+-- Rolls back the todos.reference_id index
+
+DROP INDEX IF EXISTS idx_todos_reference_id;
@@ -0,0 +1,4 @@
+-- migrations/postgres/0008_add_recurrence_rrule.down.sql
+-- Rolls back the rrule column on recurrence_patterns
+
+ALTER TABLE recurrence_patterns DROP COLUMN IF EXISTS rrule;
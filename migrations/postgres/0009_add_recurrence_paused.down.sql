@@ -0,0 +1,4 @@
+-- migrations/postgres/0009_add_recurrence_paused.down.sql
+-- Rolls back the paused column on recurrence_patterns
+
+ALTER TABLE recurrence_patterns DROP COLUMN IF EXISTS paused;
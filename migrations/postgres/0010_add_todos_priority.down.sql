@@ -0,0 +1,4 @@
+-- migrations/postgres/0010_add_todos_priority.down.sql
+-- Rolls back the priority column on todos
+
+ALTER TABLE todos DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,8 @@
+-- migrations/postgres/0011_add_tags.down.sql
+-- Rolls back the tags and todo_tags tables
+
+DROP INDEX IF EXISTS idx_todo_tags_tag_id;
+
+DROP TABLE IF EXISTS todo_tags;
+
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,17 @@
+-- migrations/postgres/0011_add_tags.sql
+-- Adds tags and the todo_tags join table. A todo can have any number of tags.
+
+CREATE TABLE tags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE todo_tags (
+    todo_id BIGINT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, tag_id)
+);
+
+-- The primary key covers lookups by todo; this one covers lookups by tag
+CREATE INDEX idx_todo_tags_tag_id ON todo_tags(tag_id);
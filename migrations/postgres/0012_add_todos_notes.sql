@@ -0,0 +1,4 @@
+-- migrations/postgres/0012_add_todos_notes.sql
+-- Adds a long-form Markdown notes body to todos. An empty string means no notes.
+
+ALTER TABLE todos ADD COLUMN notes TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- migrations/postgres/0013_add_todos_parent_id.down.sql
+-- Rolls back the parent_id column on todos
+
+DROP INDEX IF EXISTS idx_todos_parent_id;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,5 @@
+-- Rolls back the todo_dependencies table
+
+DROP INDEX IF EXISTS idx_todo_dependencies_blocker_id;
+
+DROP TABLE IF EXISTS todo_dependencies;
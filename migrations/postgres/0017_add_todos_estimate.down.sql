@@ -0,0 +1,4 @@
+-- migrations/postgres/0017_add_todos_estimate.down.sql
+-- Rolls back the estimate_minutes column on todos
+
+ALTER TABLE todos DROP COLUMN IF EXISTS estimate_minutes;
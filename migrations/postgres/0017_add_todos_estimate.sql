@@ -0,0 +1,4 @@
+-- migrations/postgres/0017_add_todos_estimate.sql
+-- Adds estimate_minutes, the expected effort of a todo. NULL means not estimated.
+
+ALTER TABLE todos ADD COLUMN estimate_minutes INTEGER DEFAULT NULL;
@@ -0,0 +1,16 @@
+-- Adds time_entries: time spent on a todo, tracked with a timer or logged by hand.
+-- An entry without ended_at is the running timer; there is at most one.
+
+CREATE TABLE time_entries (
+    id BIGSERIAL PRIMARY KEY,
+    todo_id BIGINT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ DEFAULT NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_time_entries_todo_id ON time_entries(todo_id);
+CREATE INDEX idx_time_entries_started_at ON time_entries(started_at);
+
+-- Allows a single running timer
+CREATE UNIQUE INDEX idx_time_entries_running ON time_entries((ended_at IS NULL)) WHERE ended_at IS NULL;
@@ -0,0 +1,10 @@
+-- Adds reminders: each fires offset_minutes before its todo's due date.
+-- notified_for is the due date it last fired for, so moving the due date re-arms it.
+
+CREATE TABLE reminders (
+    id BIGSERIAL PRIMARY KEY,
+    todo_id BIGINT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    offset_minutes INTEGER NOT NULL DEFAULT 0,
+    notified_for TIMESTAMPTZ DEFAULT NULL,
+    UNIQUE (todo_id, offset_minutes)
+);
@@ -0,0 +1,10 @@
+-- migrations/sqlite/0001_create_todos_table.sql
+-- Creates the base todos table
+
+CREATE TABLE todos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    completed_at DATETIME DEFAULT NULL,
+    due_date DATETIME DEFAULT NULL,
+    created_date DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+-- migrations/sqlite/0002_add_recurrence_support.down.sql
+-- Rolls back the recurrence pattern support
+
+-- Drop the index first
+DROP INDEX IF EXISTS idx_recurrence_patterns_todo_id;
+
+-- Drop the recurrence_patterns table
+DROP TABLE IF EXISTS recurrence_patterns;
+
+-- Remove the reference_id column
+ALTER TABLE todos DROP COLUMN reference_id;
@@ -0,0 +1,20 @@
+-- migrations/sqlite/0002_add_recurrence_support.sql
+-- Adds support for recurring todos
+
+-- Add reference_id column to todos table
+ALTER TABLE todos ADD COLUMN reference_id INTEGER;
+
+-- Create recurrence_patterns table
+CREATE TABLE recurrence_patterns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id VARCHAR(255) NOT NULL,
+    frequency VARCHAR(50) NOT NULL,
+    `interval` INTEGER NOT NULL,
+    until DATETIME NULL,
+    count INTEGER NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add index for better performance
+CREATE INDEX idx_recurrence_patterns_todo_id ON recurrence_patterns(todo_id);
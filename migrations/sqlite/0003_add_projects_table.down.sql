@@ -0,0 +1,8 @@
+-- migrations/sqlite/0003_add_projects_table.down.sql
+-- Rolls back the projects table support
+
+-- Drop the index first
+DROP INDEX IF EXISTS idx_projects_name;
+
+-- Drop the projects table
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,14 @@
+-- migrations/sqlite/0003_add_projects_table.sql
+-- Adds support for project management
+
+-- Create projects table
+CREATE TABLE projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Add index for better performance on name lookups
+CREATE INDEX idx_projects_name ON projects(name);
@@ -0,0 +1,8 @@
+-- migrations/sqlite/0004_update_todos_add_project_id.down.sql
+-- Rolls back the project_id addition to todos table
+
+-- Drop the index first
+DROP INDEX IF EXISTS idx_todos_project_id;
+
+-- Remove the project_id column
+ALTER TABLE todos DROP COLUMN project_id;
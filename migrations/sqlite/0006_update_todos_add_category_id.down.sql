@@ -0,0 +1,11 @@
+-- migrations/sqlite/0006_update_todos_add_category_id.down.sql
+-- Rolls back the category_id addition to todos table
+
+-- Drop the trigger first
+DROP TRIGGER IF EXISTS todos_category_delete;
+
+-- Drop the index
+DROP INDEX IF EXISTS idx_todos_category_id;
+
+-- Drop the category_id column
+ALTER TABLE todos DROP COLUMN category_id;
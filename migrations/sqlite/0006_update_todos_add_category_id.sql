@@ -0,0 +1,14 @@
+-- migrations/sqlite/0006_update_todos_add_category_id.sql
+-- Adds category_id column to todos table for category relationship
+
+ALTER TABLE todos ADD COLUMN category_id INTEGER DEFAULT NULL;
+
+-- Create index for performance on category_id field
+CREATE INDEX idx_todos_category_id ON todos(category_id);
+
+-- SQLite cannot add a foreign key to an existing table, so a trigger gives
+-- the same ON DELETE SET NULL behaviour as the MariaDB constraint
+CREATE TRIGGER todos_category_delete AFTER DELETE ON categories
+BEGIN
+    UPDATE todos SET category_id = NULL WHERE category_id = OLD.id;
+END;
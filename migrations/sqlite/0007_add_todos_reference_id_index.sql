@@ -0,0 +1,4 @@
+-- Indexes todos.reference_id, which links each occurrence of a recurring todo
+-- back to the first todo in its series
+
+CREATE INDEX idx_todos_reference_id ON todos(reference_id);
@@ -0,0 +1,4 @@
+-- migrations/sqlite/0008_add_recurrence_rrule.down.sql
+-- Rolls back the rrule column on recurrence_patterns
+
+ALTER TABLE recurrence_patterns DROP COLUMN rrule;
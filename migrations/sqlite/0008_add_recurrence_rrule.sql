@@ -0,0 +1,5 @@
+-- migrations/sqlite/0008_add_recurrence_rrule.sql
+-- Stores an RFC 5545 RRULE on recurrence patterns. NULL keeps the simple
+-- frequency/interval behavior.
+
+ALTER TABLE recurrence_patterns ADD COLUMN rrule TEXT NULL;
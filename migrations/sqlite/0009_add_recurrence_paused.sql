@@ -0,0 +1,5 @@
+-- migrations/sqlite/0009_add_recurrence_paused.sql
+-- Lets a recurrence pattern be paused without deleting it. A paused pattern
+-- creates no further occurrences.
+
+ALTER TABLE recurrence_patterns ADD COLUMN paused BOOLEAN NOT NULL DEFAULT 0;
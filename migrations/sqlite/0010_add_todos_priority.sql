@@ -0,0 +1,4 @@
+-- migrations/sqlite/0010_add_todos_priority.sql
+-- Adds a priority to todos: 0 none, 1 low, 2 medium, 3 high, 4 urgent.
+
+ALTER TABLE todos ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- migrations/sqlite/0012_add_todos_notes.down.sql
+-- Rolls back the notes column on todos
+
+ALTER TABLE todos DROP COLUMN notes;
@@ -0,0 +1,6 @@
+-- migrations/sqlite/0013_add_todos_parent_id.sql
+-- Adds parent_id so a todo can be a subtask of another. Deleting a todo deletes its subtasks.
+
+ALTER TABLE todos ADD COLUMN parent_id INTEGER REFERENCES todos(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_todos_parent_id ON todos(parent_id);
@@ -0,0 +1,10 @@
+-- Adds todo_dependencies: a todo is blocked until every one of its blockers is completed.
+
+CREATE TABLE todo_dependencies (
+    todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    blocker_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, blocker_id)
+);
+
+-- The primary key covers lookups by todo; this one covers lookups by blocker
+CREATE INDEX idx_todo_dependencies_blocker_id ON todo_dependencies(blocker_id);
@@ -0,0 +1,4 @@
+-- migrations/sqlite/0015_add_todos_start_date.down.sql
+-- Rolls back the start_date column on todos
+
+ALTER TABLE todos DROP COLUMN start_date;
@@ -0,0 +1,4 @@
+-- migrations/sqlite/0015_add_todos_start_date.sql
+-- Adds start_date so a todo can be deferred. Active listings hide it until then.
+
+ALTER TABLE todos ADD COLUMN start_date DATETIME DEFAULT NULL;
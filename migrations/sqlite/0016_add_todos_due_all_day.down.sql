@@ -0,0 +1,4 @@
+-- migrations/sqlite/0016_add_todos_due_all_day.down.sql
+-- Rolls back the due_all_day column on todos
+
+ALTER TABLE todos DROP COLUMN due_all_day;
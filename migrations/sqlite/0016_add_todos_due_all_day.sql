@@ -0,0 +1,5 @@
+-- migrations/sqlite/0016_add_todos_due_all_day.sql
+-- Adds due_all_day to tell a due date that names a whole day, stored as midnight
+-- UTC, from one due at a moment. Existing due dates count as timed.
+
+ALTER TABLE todos ADD COLUMN due_all_day BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- migrations/sqlite/0017_add_todos_estimate.down.sql
+-- Rolls back the estimate_minutes column on todos
+
+ALTER TABLE todos DROP COLUMN estimate_minutes;
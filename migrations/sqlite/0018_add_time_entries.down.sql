@@ -0,0 +1,7 @@
+-- Rolls back the time_entries table
+
+DROP INDEX IF EXISTS idx_time_entries_running;
+DROP INDEX IF EXISTS idx_time_entries_started_at;
+DROP INDEX IF EXISTS idx_time_entries_todo_id;
+
+DROP TABLE IF EXISTS time_entries;
@@ -0,0 +1,3 @@
+-- Rolls back the reminders table
+
+DROP TABLE IF EXISTS reminders;
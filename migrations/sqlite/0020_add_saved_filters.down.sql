@@ -0,0 +1,3 @@
+-- Rolls back the saved_filters table
+
+DROP TABLE IF EXISTS saved_filters;
@@ -0,0 +1,10 @@
+-- Adds saved_filters: named todo queries. criteria holds the conditions as
+-- JSON so that new ones need no schema change.
+
+CREATE TABLE saved_filters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    criteria TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
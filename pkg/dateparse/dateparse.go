@@ -0,0 +1,352 @@
+// Package dateparse reads the dates people and language models write: RFC
+// 3339 timestamps, ISO dates with or without a time of day, and relative
+// expressions such as "tomorrow", "in 3 days", "end of month" or
+// "next friday 5pm". Relative expressions are resolved against a reference
+// time in a configured timezone.
+//
+//	p := dateparse.Parser{Location: berlin}
+//	d, err := p.Parse("next monday at 9:30")
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is wrapped by every error Parse returns
+var ErrInvalidDate = errors.New("invalid date")
+
+// Date is a parsed date
+type Date struct {
+	Time time.Time
+	// AllDay reports that the input named a day but no time of day; Time is
+	// then midnight at the start of that day
+	AllDay bool
+}
+
+// Parser parses dates. The zero Parser resolves against time.Now in
+// time.Local.
+type Parser struct {
+	// Now returns the reference time of relative expressions; nil means
+	// time.Now
+	Now func() time.Time
+	// Location is the timezone of relative expressions and of dates given
+	// without an offset; nil means time.Local
+	Location *time.Location
+}
+
+// Parse resolves s against now, in now's location
+func Parse(s string, now time.Time) (Date, error) {
+	return Parser{Now: func() time.Time { return now }, Location: now.Location()}.Parse(s)
+}
+
+// layouts are the absolute formats Parse accepts besides RFC 3339, all read
+// in the parser's location
+var layouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// Parse parses s. Case and extra spaces in relative expressions are ignored.
+func (p Parser) Parse(s string) (Date, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return Date{}, fmt.Errorf("%w: date cannot be empty", ErrInvalidDate)
+	}
+	loc := p.location()
+
+	if t, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return Date{Time: t}, nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, trimmed, loc); err == nil {
+			return Date{Time: t}, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", trimmed, loc); err == nil {
+		return Date{Time: t, AllDay: true}, nil
+	}
+
+	d, ok := p.parseRelative(strings.Fields(strings.ToLower(trimmed)))
+	if !ok {
+		return Date{}, fmt.Errorf("%w: '%s' is not YYYY-MM-DD, RFC 3339 or an expression such as tomorrow, in 3 days or next friday 5pm", ErrInvalidDate, s)
+	}
+	return d, nil
+}
+
+func (p Parser) location() *time.Location {
+	if p.Location != nil {
+		return p.Location
+	}
+	return time.Local
+}
+
+func (p Parser) now() time.Time {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	return now().In(p.location())
+}
+
+// clock is a time of day
+type clock struct {
+	hour, minute int
+}
+
+// parseRelative parses a day expression optionally followed by a time of
+// day, such as "next friday at 5pm", or a time of day alone, which is today
+func (p Parser) parseRelative(words []string) (Date, bool) {
+	now := p.now()
+	words, at, hasClock := splitClock(words)
+	if len(words) > 0 && words[0] == "on" {
+		words = words[1:]
+	}
+
+	// Expressions that already carry a time of day
+	if !hasClock {
+		switch {
+		case len(words) == 1 && words[0] == "now":
+			return Date{Time: now}, true
+		case len(words) == 3 && words[0] == "in":
+			n, ok := count(words[1])
+			if !ok {
+				break
+			}
+			switch strings.TrimSuffix(words[2], "s") {
+			case "minute", "min":
+				return Date{Time: now.Add(time.Duration(n) * time.Minute)}, true
+			case "hour":
+				return Date{Time: now.Add(time.Duration(n) * time.Hour)}, true
+			}
+		}
+	}
+
+	day, ok := resolveDay(words, midnight(now))
+	if !ok {
+		return Date{}, false
+	}
+	if !hasClock {
+		return Date{Time: day, AllDay: true}, true
+	}
+	return Date{Time: time.Date(day.Year(), day.Month(), day.Day(), at.hour, at.minute, 0, 0, day.Location())}, true
+}
+
+// resolveDay returns the midnight that words names, counting from today
+func resolveDay(words []string, today time.Time) (time.Time, bool) {
+	switch len(words) {
+	case 0:
+		return today, true
+	case 1:
+		switch words[0] {
+		case "today", "tonight":
+			return today, true
+		case "tomorrow":
+			return today.AddDate(0, 0, 1), true
+		case "yesterday":
+			return today.AddDate(0, 0, -1), true
+		}
+		if wd, ok := weekday(words[0]); ok {
+			return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7), true
+		}
+	case 2:
+		switch words[0] + " " + words[1] {
+		case "next week":
+			return startOfWeek(today).AddDate(0, 0, 7), true
+		case "next month":
+			return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+		case "next year":
+			return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), true
+		}
+		wd, ok := weekday(words[1])
+		if !ok {
+			break
+		}
+		switch words[0] {
+		case "this":
+			// Today or later this week
+			return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7), true
+		case "next":
+			// Always after today
+			return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+6)%7+1), true
+		}
+	}
+
+	// in N days, in a week, in 2 months
+	if len(words) == 3 && words[0] == "in" {
+		n, ok := count(words[1])
+		if !ok {
+			return time.Time{}, false
+		}
+		switch strings.TrimSuffix(words[2], "s") {
+		case "day":
+			return today.AddDate(0, 0, n), true
+		case "week":
+			return today.AddDate(0, 0, 7*n), true
+		case "month":
+			return addMonths(today, n), true
+		case "year":
+			return addMonths(today, 12*n), true
+		}
+		return time.Time{}, false
+	}
+
+	// end of week, end of the month
+	if len(words) >= 3 && words[0] == "end" && words[1] == "of" {
+		rest := words[2:]
+		if rest[0] == "the" {
+			rest = rest[1:]
+		}
+		if len(rest) != 1 {
+			return time.Time{}, false
+		}
+		switch rest[0] {
+		case "week":
+			return startOfWeek(today).AddDate(0, 0, 6), true
+		case "month":
+			return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), true
+		case "year":
+			return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitClock removes a trailing time of day, such as "5pm", "5 pm",
+// "at 17:30", "noon" or "midnight", from words
+func splitClock(words []string) ([]string, clock, bool) {
+	n := len(words)
+	if n == 0 {
+		return words, clock{}, false
+	}
+	last := words[n-1]
+	used := 1
+	if (last == "am" || last == "pm") && n >= 2 {
+		last = words[n-2] + last
+		used = 2
+	}
+	at, ok := parseClock(last, n > used && words[n-used-1] == "at")
+	if !ok {
+		return words, clock{}, false
+	}
+	words = words[:n-used]
+	if len(words) > 0 && words[len(words)-1] == "at" {
+		words = words[:len(words)-1]
+	}
+	return words, at, true
+}
+
+// parseClock parses "noon", "midnight", "5pm", "5:30am" or "17:30". A bare
+// hour such as "5" is only a time after "at".
+func parseClock(s string, afterAt bool) (clock, bool) {
+	switch s {
+	case "noon":
+		return clock{hour: 12}, true
+	case "midnight":
+		return clock{}, true
+	}
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem = s[len(s)-2:]
+		s = s[:len(s)-2]
+	}
+	hourStr, minuteStr, hasMinute := strings.Cut(s, ":")
+	if meridiem == "" && !hasMinute && !afterAt {
+		return clock{}, false
+	}
+	hour, ok := digits(hourStr, 1, 2)
+	if !ok {
+		return clock{}, false
+	}
+	minute := 0
+	if hasMinute {
+		if minute, ok = digits(minuteStr, 2, 2); !ok || minute > 59 {
+			return clock{}, false
+		}
+	}
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return clock{}, false
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return clock{}, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return clock{hour: hour, minute: minute}, true
+}
+
+// digits parses s as a number of min to max ASCII digits
+func digits(s string, min, max int) (int, bool) {
+	if len(s) < min || len(s) > max {
+		return 0, false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// maxCount bounds "in N days" so the result stays a sensible date
+const maxCount = 10000
+
+// count parses the N of "in N days"; "a" and "an" are 1
+func count(s string) (int, bool) {
+	if s == "a" || s == "an" {
+		return 1, true
+	}
+	n, ok := digits(s, 1, 5)
+	if !ok || n > maxCount {
+		return 0, false
+	}
+	return n, true
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+func weekday(s string) (time.Weekday, bool) {
+	wd, ok := weekdays[s]
+	return wd, ok
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday on or before day
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// addMonths adds n months to day, moving to the end of the month rather than
+// overflowing into the next: January 31 plus one month is February 28 or 29
+func addMonths(day time.Time, n int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	d := day.Day()
+	if d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, day.Location())
+}
@@ -0,0 +1,176 @@
+package dateparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var zone = time.FixedZone("UTC+2", 2*60*60)
+
+// now is Wednesday 2030-03-06 14:15 in zone
+var now = time.Date(2030, time.March, 6, 14, 15, 0, 0, zone)
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, zone)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   time.Time
+		allDay bool
+	}{
+		// Absolute
+		{"2030-11-03", at(2030, time.November, 3, 0, 0), true},
+		{"2030-11-03T17:30:00Z", time.Date(2030, time.November, 3, 17, 30, 0, 0, time.UTC), false},
+		{"2030-11-03T17:30:00-05:00", time.Date(2030, time.November, 3, 22, 30, 0, 0, time.UTC), false},
+		{"2030-11-03T17:30:00", at(2030, time.November, 3, 17, 30), false},
+		{"2030-11-03 17:30", at(2030, time.November, 3, 17, 30), false},
+		{"  2030-11-03  ", at(2030, time.November, 3, 0, 0), true},
+
+		// Days
+		{"today", at(2030, time.March, 6, 0, 0), true},
+		{"Tomorrow", at(2030, time.March, 7, 0, 0), true},
+		{"yesterday", at(2030, time.March, 5, 0, 0), true},
+		{"friday", at(2030, time.March, 8, 0, 0), true},
+		{"wednesday", at(2030, time.March, 6, 0, 0), true},
+		{"this wed", at(2030, time.March, 6, 0, 0), true},
+		{"next wednesday", at(2030, time.March, 13, 0, 0), true},
+		{"next Monday", at(2030, time.March, 11, 0, 0), true},
+		{"on tuesday", at(2030, time.March, 12, 0, 0), true},
+		{"next week", at(2030, time.March, 11, 0, 0), true},
+		{"next month", at(2030, time.April, 1, 0, 0), true},
+		{"next year", at(2031, time.January, 1, 0, 0), true},
+		{"in 3 days", at(2030, time.March, 9, 0, 0), true},
+		{"in a week", at(2030, time.March, 13, 0, 0), true},
+		{"in 2 weeks", at(2030, time.March, 20, 0, 0), true},
+		{"in 1 month", at(2030, time.April, 6, 0, 0), true},
+		{"in 1 year", at(2031, time.March, 6, 0, 0), true},
+		{"end of week", at(2030, time.March, 10, 0, 0), true},
+		{"end of the month", at(2030, time.March, 31, 0, 0), true},
+		{"end of year", at(2030, time.December, 31, 0, 0), true},
+
+		// Times
+		{"now", now, false},
+		{"in 90 minutes", at(2030, time.March, 6, 15, 45), false},
+		{"in an hour", at(2030, time.March, 6, 15, 15), false},
+		{"5pm", at(2030, time.March, 6, 17, 0), false},
+		{"at 9", at(2030, time.March, 6, 9, 0), false},
+		{"tomorrow 9:30am", at(2030, time.March, 7, 9, 30), false},
+		{"next friday 5pm", at(2030, time.March, 8, 17, 0), false},
+		{"next friday at 5 PM", at(2030, time.March, 8, 17, 0), false},
+		{"monday at 17:45", at(2030, time.March, 11, 17, 45), false},
+		{"tomorrow noon", at(2030, time.March, 7, 12, 0), false},
+		{"friday midnight", at(2030, time.March, 8, 0, 0), false},
+		{"12am", at(2030, time.March, 6, 0, 0), false},
+		{"12pm", at(2030, time.March, 6, 12, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got.Time), "got %s, want %s", got.Time, tt.want)
+			assert.Equal(t, tt.allDay, got.AllDay)
+		})
+	}
+}
+
+func TestParseMonthEnds(t *testing.T) {
+	jan31 := time.Date(2030, time.January, 31, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"in 1 month", time.Date(2030, time.February, 28, 0, 0, 0, 0, time.UTC)},
+		{"in 2 months", time.Date(2030, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{"end of month", time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC)},
+		{"next month", time.Date(2030, time.February, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, jan31)
+		require.NoError(t, err, tt.in)
+		assert.True(t, tt.want.Equal(got.Time), "%s: got %s, want %s", tt.in, got.Time, tt.want)
+	}
+}
+
+func TestParserLocation(t *testing.T) {
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	// 2030-03-06 20:00 UTC is already March 7 in Tokyo
+	p := Parser{
+		Now:      func() time.Time { return time.Date(2030, time.March, 6, 20, 0, 0, 0, time.UTC) },
+		Location: tokyo,
+	}
+
+	got, err := p.Parse("tomorrow")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2030, time.March, 8, 0, 0, 0, 0, tokyo), got.Time)
+	got, err = p.Parse("2030-03-06 09:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2030, time.March, 6, 0, 0, 0, 0, time.UTC), got.Time.UTC())
+	got, err = p.Parse("2030-03-06T09:00:00Z")
+	require.NoError(t, err, "an explicit offset wins over the location")
+	assert.Equal(t, time.Date(2030, time.March, 6, 9, 0, 0, 0, time.UTC), got.Time.UTC())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"   ",
+		"soon",
+		"5",
+		"13pm",
+		"0am",
+		"24:00",
+		"17:60",
+		"in 3",
+		"in three days",
+		"in 2 hours 5pm",
+		"now 5pm",
+		"next",
+		"end of",
+		"end of the decade",
+		"2030-02-30",
+		"2030-13-01",
+		"in 99999 days",
+	} {
+		_, err := Parse(in, now)
+		assert.True(t, errors.Is(err, ErrInvalidDate), "%q: %v", in, err)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"2030-11-03", "2030-11-03T17:30:00+01:00", "2030-11-03 17:30",
+		"tomorrow", "in 3 days", "in 90 minutes", "end of the month",
+		"next friday at 5 pm", "monday 17:45", "at 9", "noon", "in a week",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		got, err := Parse(in, now)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidDate) {
+				t.Fatalf("Parse(%q) returned an unwrapped error: %v", in, err)
+			}
+			return
+		}
+		if got.AllDay {
+			local := got.Time.In(zone)
+			if local.Hour() != 0 || local.Minute() != 0 || local.Second() != 0 {
+				t.Fatalf("Parse(%q) is all-day but not midnight: %s", in, got.Time)
+			}
+		}
+		// Whatever Parse accepts must survive a round trip through RFC 3339
+		again, err := Parse(got.Time.Format(time.RFC3339Nano), now)
+		if err != nil {
+			t.Fatalf("Parse(%q) = %s, which does not parse back: %v", in, got.Time, err)
+		}
+		if !again.Time.Equal(got.Time) {
+			t.Fatalf("Parse(%q) = %s, which parses back as %s", in, got.Time, again.Time)
+		}
+	})
+}
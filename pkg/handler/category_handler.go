@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"mcp-godo/pkg/todo"
 
@@ -13,6 +14,10 @@ import (
 type CategoryHandler struct {
 	categoryService todo.CategoryService
 	todoService     todo.TodoService
+	// tagService is optional; when set, listings show each todo's tags
+	tagService todo.TagService
+	// location is the zone due dates are shown in; nil means UTC
+	location *time.Location
 }
 
 // NewCategoryHandler creates a new category handler
@@ -43,9 +48,9 @@ func (h *CategoryHandler) CreateCategoryHandler(ctx context.Context, request mcp
 	}
 	
 	// Create category
-	category, err := h.categoryService.CreateCategory(name, description, color)
+	category, err := h.categoryService.CreateCategory(ctx, name, description, color)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create category: %w", err)
+		return toolError("create category", err)
 	}
 	
 	// Format response
@@ -66,9 +71,9 @@ func (h *CategoryHandler) CreateCategoryHandler(ctx context.Context, request mcp
 
 // GetAllCategoriesHandler handles the get_all_categories MCP tool
 func (h *CategoryHandler) GetAllCategoriesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	categories, err := h.categoryService.GetAllCategories()
+	categories, err := h.categoryService.GetAllCategories(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve categories: %w", err)
+		return toolError("retrieve categories", err)
 	}
 	
 	if len(categories) == 0 {
@@ -105,9 +110,9 @@ func (h *CategoryHandler) GetCategoryHandler(ctx context.Context, request mcp.Ca
 	}
 	id := int64(idRaw)
 	
-	category, err := h.categoryService.GetCategoryByID(id)
+	category, err := h.categoryService.GetCategoryByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve category: %w", err)
+		return toolError("retrieve category", err)
 	}
 	
 	responseText := fmt.Sprintf("Category Details:\nID: %d\nName: %s", category.ID, category.Name)
@@ -153,9 +158,9 @@ func (h *CategoryHandler) UpdateCategoryHandler(ctx context.Context, request mcp
 	}
 	
 	// Update category
-	category, err := h.categoryService.UpdateCategory(id, name, description, color)
+	category, err := h.categoryService.UpdateCategory(ctx, id, name, description, color)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update category: %w", err)
+		return toolError("update category", err)
 	}
 	
 	responseText := fmt.Sprintf("Category updated successfully:\nID: %d\nName: %s", category.ID, category.Name)
@@ -182,9 +187,9 @@ func (h *CategoryHandler) DeleteCategoryHandler(ctx context.Context, request mcp
 	}
 	id := int64(idRaw)
 	
-	err := h.categoryService.DeleteCategory(id)
+	err := h.categoryService.DeleteCategory(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete category: %w", err)
+		return toolError("delete category", err)
 	}
 	
 	return mcp.NewToolResultText(fmt.Sprintf("Category with ID %d deleted successfully", id)), nil
@@ -199,15 +204,21 @@ func (h *CategoryHandler) GetCategoryTodosHandler(ctx context.Context, request m
 	}
 	id := int64(idRaw)
 	
-	todos, err := h.categoryService.GetTodosByCategory(id)
+	todos, err := h.categoryService.GetTodosByCategory(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve todos for category: %w", err)
+		return toolError("retrieve todos for category", err)
 	}
+	now := time.Now()
+	todos = hideDeferred(request, todos, now)
 	
 	if len(todos) == 0 {
 		return mcp.NewToolResultText("No todos found in this category"), nil
 	}
 	
+	var tags map[string][]todo.Tag
+	if h.tagService != nil {
+		tags, _ = h.tagService.GetAllTodoTags(ctx)
+	}
 	var resultText string
 	for i, todo := range todos {
 		if i > 0 {
@@ -222,7 +233,19 @@ func (h *CategoryHandler) GetCategoryTodosHandler(ctx context.Context, request m
 		}
 		
 		if todo.DueDate != nil {
-			resultText += fmt.Sprintf("\nDue Date: %s", todo.DueDate.Format("2006-01-02 15:04:05"))
+			resultText += fmt.Sprintf("\nDue Date: %s", h.formatDueDate(todo))
+		}
+		
+		if isDeferred(todo, now) {
+			resultText += fmt.Sprintf("\nDeferred Until: %s", h.formatTime(*todo.StartDate))
+		}
+		
+		if todo.Priority != 0 {
+			resultText += fmt.Sprintf("\nPriority: %s", todo.Priority)
+		}
+		
+		if len(tags[todo.ID]) > 0 {
+			resultText += fmt.Sprintf("\nTags: %s", joinTagNames(tags[todo.ID]))
 		}
 	}
 	
@@ -245,9 +268,9 @@ func (h *CategoryHandler) AssignTodoToCategoryHandler(ctx context.Context, reque
 	categoryID := int64(categoryIDRaw)
 	
 	// Call the todo service to assign the todo to the category
-	todo, err := h.todoService.AssignTodoToCategory(todoID, categoryID)
+	todo, err := h.todoService.AssignTodoToCategory(ctx, todoID, categoryID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to assign todo to category: %w", err)
+		return toolError("assign todo to category", err)
 	}
 	
 	return mcp.NewToolResultText(fmt.Sprintf("Todo '%s' (ID: %s) successfully assigned to category %d", todo.Title, todo.ID, categoryID)), nil
@@ -262,9 +285,9 @@ func (h *CategoryHandler) RemoveTodoFromCategoryHandler(ctx context.Context, req
 	}
 	
 	// Call the todo service to remove the todo from its category
-	todo, err := h.todoService.RemoveTodoFromCategory(todoID)
+	todo, err := h.todoService.RemoveTodoFromCategory(ctx, todoID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to remove todo from category: %w", err)
+		return toolError("remove todo from category", err)
 	}
 	
 	return mcp.NewToolResultText(fmt.Sprintf("Todo '%s' (ID: %s) successfully removed from its category", todo.Title, todo.ID)), nil
@@ -272,9 +295,9 @@ func (h *CategoryHandler) RemoveTodoFromCategoryHandler(ctx context.Context, req
 
 // GetUncategorizedTodosHandler handles the get_uncategorized_todos MCP tool
 func (h *CategoryHandler) GetUncategorizedTodosHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	todos, err := h.categoryService.GetUncategorizedTodos()
+	todos, err := h.categoryService.GetUncategorizedTodos(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve uncategorized todos: %w", err)
+		return toolError("retrieve uncategorized todos", err)
 	}
 	
 	if len(todos) == 0 {
@@ -295,9 +318,31 @@ func (h *CategoryHandler) GetUncategorizedTodosHandler(ctx context.Context, requ
 		}
 		
 		if todo.DueDate != nil {
-			resultText += fmt.Sprintf("\nDue Date: %s", todo.DueDate.Format("2006-01-02 15:04:05"))
+			resultText += fmt.Sprintf("\nDue Date: %s", h.formatDueDate(todo))
+		}
+		
+		if todo.Priority != 0 {
+			resultText += fmt.Sprintf("\nPriority: %s", todo.Priority)
 		}
 	}
 	
 	return mcp.NewToolResultText(resultText), nil
-}
\ No newline at end of file
+}
+
+// formatDueDate shows item's due date in the handler's location, or as a plain
+// date when it is all-day
+func (h *CategoryHandler) formatDueDate(item todo.TodoItem) string {
+	if item.DueAllDay {
+		return item.DueDate.UTC().Format("2006-01-02")
+	}
+	return h.formatTime(*item.DueDate)
+}
+
+// formatTime shows t in the handler's location
+func (h *CategoryHandler) formatTime(t time.Time) string {
+	loc := h.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02 15:04:05")
+}
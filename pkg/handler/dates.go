@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"time"
+
+	"mcp-godo/pkg/dateparse"
+	"mcp-godo/pkg/todo"
+)
+
+// SetDateParser sets how date arguments are read, such as the reference time
+// of "tomorrow". A parser without a Location uses the handler's timezone.
+func (h *Handler) SetDateParser(parser dateparse.Parser) {
+	h.dates = parser
+}
+
+// SetTimezone sets the zone that dates without an offset are read in and that
+// every date is shown in. The default is UTC.
+func (h *Handler) SetTimezone(loc *time.Location) {
+	h.dates.Location = loc
+}
+
+// timezone returns the zone dates are read and shown in
+func (h *Handler) timezone() *time.Location {
+	if h.dates.Location != nil {
+		return h.dates.Location
+	}
+	return time.UTC
+}
+
+// parseDate reads a date argument: RFC 3339, an ISO date with or without a
+// time of day, or a relative expression such as "next friday 5pm"
+func (h *Handler) parseDate(s string) (time.Time, error) {
+	d, err := h.parseDueDate(s)
+	return d.Time, err
+}
+
+// parseDueDate is parseDate that also reports whether s named a whole day
+func (h *Handler) parseDueDate(s string) (dateparse.Date, error) {
+	parser := h.dates
+	parser.Location = h.timezone()
+	return parser.Parse(s)
+}
+
+// formatTime shows t in the handler's timezone
+func (h *Handler) formatTime(t time.Time) string {
+	return t.In(h.timezone()).Format(time.RFC3339)
+}
+
+// formatDueDate shows item's due date in the handler's timezone, or as a
+// plain date when it is all-day, or "none"
+func (h *Handler) formatDueDate(item todo.TodoItem) string {
+	switch {
+	case item.DueDate == nil:
+		return "none"
+	case item.DueAllDay:
+		return item.DueDate.UTC().Format("2006-01-02")
+	}
+	return h.formatTime(*item.DueDate)
+}
+
+// dueInfo returns ", Overdue" or ", Due today" for an open todo, judged by
+// the calendar in the handler's timezone, or ""
+func (h *Handler) dueInfo(item todo.TodoItem, now time.Time) string {
+	if item.DueDate == nil || item.CompletedAt != nil {
+		return ""
+	}
+	now = now.In(h.timezone())
+	today := now.Format("2006-01-02")
+	dueDay := item.DueDate.In(h.timezone()).Format("2006-01-02")
+	if item.DueAllDay {
+		dueDay = item.DueDate.UTC().Format("2006-01-02")
+	}
+	switch {
+	case dueDay < today, !item.DueAllDay && item.DueDate.Before(now):
+		return ", Overdue"
+	case dueDay == today:
+		return ", Due today"
+	}
+	return ""
+}
+
+// inTimezone returns item with its times in the handler's timezone, for
+// output that shows them as they are, such as the JSON resources. An all-day
+// due date stays at midnight UTC so that it keeps its calendar date.
+func (h *Handler) inTimezone(item todo.TodoItem) todo.TodoItem {
+	in := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		local := t.In(h.timezone())
+		return &local
+	}
+	item.CompletedAt = in(item.CompletedAt)
+	if !item.DueAllDay {
+		item.DueDate = in(item.DueDate)
+	}
+	item.CreatedDate = item.CreatedDate.In(h.timezone())
+	item.StartDate = in(item.StartDate)
+	return item
+}
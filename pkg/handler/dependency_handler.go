@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AddBlockerHandler handles the add_blocker MCP tool
+func (h *Handler) AddBlockerHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	blockerID, ok := request.GetArguments()["blocker_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid blocker_id")
+	}
+	blockers, err := h.todoService.AddBlocker(ctx, todoID, blockerID)
+	if err != nil {
+		return toolError("add blocker", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo %s is blocked by: %s", todoID, joinTodoRefs(blockers))), nil
+}
+
+// RemoveBlockerHandler handles the remove_blocker MCP tool
+func (h *Handler) RemoveBlockerHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	blockerID, ok := request.GetArguments()["blocker_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid blocker_id")
+	}
+	blockers, err := h.todoService.RemoveBlocker(ctx, todoID, blockerID)
+	if err != nil {
+		return toolError("remove blocker", err)
+	}
+	if len(blockers) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Todo %s has no blockers left", todoID)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo %s is blocked by: %s", todoID, joinTodoRefs(blockers))), nil
+}
+
+// GetNextActionsHandler handles the get_next_actions MCP tool. Deferred todos
+// are left out too.
+func (h *Handler) GetNextActionsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	todos, err := h.todoService.GetNextActions(ctx)
+	if err != nil {
+		return toolError("get next actions", err)
+	}
+	if len(todos) == 0 {
+		return mcp.NewToolResultText("No unblocked active todos found"), nil
+	}
+
+	// Todos come highest priority first, then soonest due
+	tags := h.allTodoTags(ctx)
+	progress := h.subtaskProgress(ctx)
+	now := time.Now()
+	var resultText string
+	for _, todo := range todos {
+		if isDeferred(todo, now) {
+			continue
+		}
+		dueDate := "none"
+		if todo.DueDate != nil {
+			dueDate = h.formatDueDate(todo)
+		}
+		resultText += fmt.Sprintf("ID: %s, Title: %s, Due Date: %s%s%s%s\n",
+			todo.ID, todo.Title, dueDate, priorityInfo(todo), tagInfo(todo, tags), subtaskInfo(todo, progress))
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// openBlockers returns the open blockers of every blocked todo. Like the tag
+// lookup in listings, a failed lookup just leaves the annotation out.
+func (h *Handler) openBlockers(ctx context.Context) map[string][]string {
+	blockers, err := h.todoService.GetOpenBlockers(ctx)
+	if err != nil {
+		return nil
+	}
+	return blockers
+}
+
+// unblockedBy returns the todos that completing blockerID left with no open
+// blockers
+func (h *Handler) unblockedBy(ctx context.Context, blockerID string) []todo.TodoItem {
+	dependents, err := h.todoService.GetDependents(ctx, blockerID)
+	if err != nil || len(dependents) == 0 {
+		return nil
+	}
+	open, err := h.todoService.GetOpenBlockers(ctx)
+	if err != nil {
+		return nil
+	}
+	var unblocked []todo.TodoItem
+	for _, item := range dependents {
+		if item.CompletedAt == nil && len(open[item.ID]) == 0 {
+			unblocked = append(unblocked, item)
+		}
+	}
+	return unblocked
+}
+
+// blockedInfo returns the ", Blocked by: ..." part of a todo listing, or ""
+// when item has no open blockers
+func blockedInfo(item todo.TodoItem, blockers map[string][]string) string {
+	if len(blockers[item.ID]) == 0 {
+		return ""
+	}
+	return ", Blocked by: #" + strings.Join(blockers[item.ID], ", #")
+}
+
+// joinTodoRefs lists todos as "#ID Title" separated by commas, marking the
+// completed ones
+func joinTodoRefs(items []todo.TodoItem) string {
+	refs := make([]string, len(items))
+	for i, item := range items {
+		refs[i] = fmt.Sprintf("#%s %s", item.ID, item.Title)
+		if item.CompletedAt != nil {
+			refs[i] += " (done)"
+		}
+	}
+	return strings.Join(refs, ", ")
+}
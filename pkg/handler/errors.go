@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolError turns a service error into an MCP error result. Typed errors from
+// pkg/todo get a message that tells the caller what to fix or which tool to
+// use next; anything else is reported as "failed to <action>".
+func toolError(action string, err error) (*mcp.CallToolResult, error) {
+	var validationErr *todo.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid %s: %s", validationErr.Field, validationErr.Message)), nil
+	case errors.Is(err, todo.ErrValidation):
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid input: %v", err)), nil
+	case errors.Is(err, todo.ErrTodoNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use list_todos or title_search to find the todo ID.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrProjectNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use get_all_projects to find the project ID.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrCategoryNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use get_all_categories to find the category ID.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrTagNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use list_tags to find the tag name.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrRecurrencePatternNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use list_recurrence_patterns to find the pattern ID.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrOpenSubtasks):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Complete the subtasks first, or call complete_todo with subtasks set to complete.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrTimeEntryNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use get_time_entries to find the entry ID.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrTimerRunning):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Call stop_timer first.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrNoTimerRunning):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Call start_timer to start one.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrSavedFilterNotFound):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use list_filters to find the filter.", capitalize(err))), nil
+	case errors.Is(err, todo.ErrDuplicateName):
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Choose a different name or use the existing one.", capitalize(err))), nil
+	}
+	return mcp.NewToolResultErrorFromErr("failed to "+action, err), nil
+}
+
+// capitalize returns the error message with its first letter upper-cased
+func capitalize(err error) string {
+	msg := err.Error()
+	if msg == "" || msg[0] < 'a' || msg[0] > 'z' {
+		return msg
+	}
+	return string(msg[0]-'a'+'A') + msg[1:]
+}
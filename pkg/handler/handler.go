@@ -9,15 +9,26 @@ import (
 	"strings"
 	"time"
 
+	"mcp-godo/pkg/dateparse"
 	"mcp-godo/pkg/todo"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
 type Handler struct {
 	todoService    	todo.TodoService
 	projectService 	todo.ProjectService
 	categoryService	todo.CategoryService
+	tagService     	todo.TagService
+	timeService    	todo.TimeService
+	reminderService	todo.ReminderService
+	savedFilterService	todo.SavedFilterService
+
+	// completionPolicy is what complete_todo does with open subtasks by default
+	completionPolicy	todo.CompletionPolicy
+	// dates reads every date argument
+	dates	dateparse.Parser
 }
 
 func NewHandler(todoService todo.TodoService) *Handler {
@@ -39,56 +50,125 @@ func NewHandlerWithProjectAndCategory(todoService todo.TodoService, projectServi
 	}
 }
 
+// NewHandlerWithServices creates a handler using every service in services
+func NewHandlerWithServices(services todo.Services) *Handler {
+	return &Handler{
+		todoService:    	services.Todos,
+		projectService: 	services.Projects,
+		categoryService:	services.Categories,
+		tagService:     	services.Tags,
+		timeService:    	services.TimeEntries,
+		reminderService:	services.Reminders,
+		savedFilterService:	services.Filters,
+	}
+}
+
 func (h *Handler) AddRecurrencePatternHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	todoID, ok := request.GetArguments()["todo_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("invalid todo_id")
 	}
-	frequency, ok := request.GetArguments()["frequency"].(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid frequency")
+	pattern, err := h.recurrenceArguments(request)
+	if err != nil {
+		return nil, err
 	}
-	interval, ok := request.GetArguments()["interval"].(float64)
-	if !ok {
-		return nil, fmt.Errorf("invalid interval")
+	pattern.TodoID = todoID
+
+	patternID, err := h.todoService.AddRecurrencePattern(ctx, pattern)
+	if err != nil {
+		return toolError("add recurrence pattern", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Recurrence pattern added with ID: %d", patternID)), nil
+}
+
+// recurrenceArguments reads the pattern arguments shared by
+// add_recurrence_pattern and preview_recurrence. frequency and interval are
+// only required when no rrule is given.
+func (h *Handler) recurrenceArguments(request mcp.CallToolRequest) (todo.RecurrencePattern, error) {
+	var pattern todo.RecurrencePattern
+	if rruleRaw, ok := request.GetArguments()["rrule"]; ok {
+		rrule, ok := rruleRaw.(string)
+		if !ok {
+			return todo.RecurrencePattern{}, fmt.Errorf("invalid rrule")
+		}
+		pattern.RRule = rrule
+	}
+	if pattern.RRule == "" {
+		frequency, ok := request.GetArguments()["frequency"].(string)
+		if !ok {
+			return todo.RecurrencePattern{}, fmt.Errorf("invalid frequency")
+		}
+		interval, ok := request.GetArguments()["interval"].(float64)
+		if !ok {
+			return todo.RecurrencePattern{}, fmt.Errorf("invalid interval")
+		}
+		pattern.Frequency = frequency
+		pattern.Interval = int(interval)
 	}
 	untilRaw, ok := request.GetArguments()["until"]
-	var until *time.Time
 	if ok {
 		untilStr, ok := untilRaw.(string)
 		if !ok {
-			return nil, fmt.Errorf("invalid until")
+			return todo.RecurrencePattern{}, fmt.Errorf("invalid until")
 		}
-		untilTime, err := time.Parse(time.RFC3339, untilStr)
+		untilTime, err := h.parseDate(untilStr)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse until: %w", err)
+			return todo.RecurrencePattern{}, fmt.Errorf("failed to parse until: %w", err)
 		}
-		until = &untilTime
+		pattern.Until = &untilTime
 	}
 	countRaw, ok := request.GetArguments()["count"]
-	var count *int
 	if ok {
 		countVal, ok := countRaw.(float64)
 		if !ok {
-			return nil, fmt.Errorf("invalid count")
+			return todo.RecurrencePattern{}, fmt.Errorf("invalid count")
 		}
 		countInt := int(countVal)
-		count = &countInt
+		pattern.Count = &countInt
 	}
+	return pattern, nil
+}
+
+// maxPreviewOccurrences caps the limit argument of preview_recurrence
+const maxPreviewOccurrences = 50
 
-	pattern := todo.RecurrencePattern{
-		TodoID:    todoID,
-		Frequency: frequency,
-		Interval:  int(interval),
-		Until:     until,
-		Count:     count,
+func (h *Handler) PreviewRecurrenceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pattern, err := h.recurrenceArguments(request)
+	if err != nil {
+		return nil, err
+	}
+	// The series is expanded on the handler's calendar, as completing a
+	// recurring todo does
+	start := time.Now()
+	if startStr, ok := request.GetArguments()["start"].(string); ok && startStr != "" {
+		start, err = h.parseDate(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse start: %w", err)
+		}
+	}
+	start = start.In(h.timezone())
+	limit := 5
+	if limitRaw, ok := request.GetArguments()["limit"].(float64); ok {
+		limit = int(limitRaw)
+	}
+	if limit < 1 || limit > maxPreviewOccurrences {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid limit: limit must be between 1 and %d", maxPreviewOccurrences)), nil
 	}
 
-	patternID, err := h.todoService.AddRecurrencePattern(pattern)
+	dates, err := todo.PreviewRecurrence(pattern, start, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add recurrence pattern: %w", err)
+		return toolError("preview recurrence", err)
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Recurrence pattern added with ID: %d", patternID)), nil
+	if len(dates) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("The pattern has no occurrences from %s", h.formatTime(start))), nil
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Occurrences from %s:\n", h.formatTime(start))
+	for i, date := range dates {
+		fmt.Fprintf(&result, "%d. %s (%s)\n", i+1, h.formatTime(date), date.In(h.timezone()).Weekday())
+	}
+	return mcp.NewToolResultText(strings.TrimSuffix(result.String(), "\n")), nil
 }
 
 func (h *Handler) GetRecurrencePatternHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -97,18 +177,24 @@ func (h *Handler) GetRecurrencePatternHandler(ctx context.Context, request mcp.C
 		return nil, fmt.Errorf("invalid id")
 	}
 	id := int64(idRaw)
-	pattern, err := h.todoService.GetRecurrencePatternByID(id)
+	pattern, err := h.todoService.GetRecurrencePatternByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recurrence pattern: %w", err)
+		return toolError("get recurrence pattern", err)
 	}
 	resultText := fmt.Sprintf("ID: %d, TodoID: %s, Frequency: %s, Interval: %d", 
 		pattern.ID, pattern.TodoID, pattern.Frequency, pattern.Interval)
 	if pattern.Until != nil {
-		resultText += fmt.Sprintf(", Until: %s", pattern.Until.Format(time.RFC3339))
+		resultText += fmt.Sprintf(", Until: %s", h.formatTime(*pattern.Until))
 	}
 	if pattern.Count != nil {
 		resultText += fmt.Sprintf(", Count: %d", *pattern.Count)
 	}
+	if pattern.RRule != "" {
+		resultText += fmt.Sprintf(", RRULE: %s", pattern.RRule)
+	}
+	if pattern.Paused {
+		resultText += ", Paused: true"
+	}
 	return mcp.NewToolResultText(resultText), nil
 }
 
@@ -118,18 +204,21 @@ func (h *Handler) TitleSearchHandler(ctx context.Context, request mcp.CallToolRe
 		return nil, fmt.Errorf("invalid query")
 	}
 	activeOnly, _ := request.GetArguments()["active_only"].(bool)
-	todos := h.todoService.TitleSearchTodo(query, activeOnly)
+	todos, err := h.todoService.TitleSearchTodo(ctx, query, activeOnly)
+	if err != nil {
+		return toolError("search todos", err)
+	}
 	var results []string
 	for _, todo := range todos {
 		var dueDateStr string
 		if todo.DueDate != nil {
-			dueDateStr = todo.DueDate.Format(time.RFC3339)
+			dueDateStr = h.formatDueDate(todo)
 		}
 		referenceID := ""
 		if todo.ReferenceID != nil {
-			referenceID = fmt.Sprintf("ReferenceID: %d", *todo.ReferenceID)
+			referenceID = fmt.Sprintf(", ReferenceID: %d", *todo.ReferenceID)
 		}
-		results = append(results, fmt.Sprintf("ID: %s, Title: %s, CompletedAt: %s, Due Date: %s, %s", 
+		results = append(results, fmt.Sprintf("ID: %s, Title: %s, CompletedAt: %s, Due Date: %s%s", 
 			todo.ID, todo.Title, todo.CompletedAt, dueDateStr, referenceID))
 	}
 
@@ -149,15 +238,21 @@ func (h *Handler) UpdateDueDateHandler(ctx context.Context, request mcp.CallTool
 	if !ok{
 		return nil, fmt.Errorf("invalid due date")
 	}
-	dueDate, err := time.Parse(time.RFC3339, dueDateStr)
+	dueDate, err := h.parseDueDate(dueDateStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse due date: %w", err)
 	}
-	todo, err := h.todoService.SetDueDate(id, dueDate)
+	// A day without a time of day is kept as a calendar date
+	var item todo.TodoItem
+	if dueDate.AllDay {
+		item, err = h.todoService.UpdateTodo(ctx, id, todo.TodoPatch{DueDate: &dueDate.Time, DueAllDay: true})
+	} else {
+		item, err = h.todoService.SetDueDate(ctx, id, dueDate.Time)
+	}
 	if err != nil{
-		return nil, fmt.Errorf("failed to update due date: %w", err)
+		return toolError("update due date", err)
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Todo updated: ID=%s, Title=%s, Due Date=%s", todo.ID, todo.Title, todo.DueDate.Format(time.RFC3339))), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Todo updated: ID=%s, Title=%s, Due Date=%s", item.ID, item.Title, h.formatDueDate(item))), nil
 }
 
 func (h *Handler) UnCompleteTodoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -165,15 +260,18 @@ func (h *Handler) UnCompleteTodoHandler(ctx context.Context, request mcp.CallToo
 	if !ok{
 		return nil, fmt.Errorf("invalid id")
 	}
-	todo, err := h.todoService.UnCompleteTodo(id)
+	todo, err := h.todoService.UnCompleteTodo(ctx, id)
 	if err != nil{
-		return nil, fmt.Errorf("failed to uncomplete todo: %w", err)
+		return toolError("uncomplete todo", err)
 	}
 	return mcp.NewToolResultText(fmt.Sprintf("Todo uncompleted: ID=%s, Title=%s", todo.ID, todo.Title)), nil
 }
 
 func (h *Handler) GetCompletedTodosHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	todos := h.todoService.GetCompletedTodos()
+	todos, err := h.todoService.GetCompletedTodos(ctx)
+	if err != nil {
+		return toolError("get completed todos", err)
+	}
 	if len(todos) == 0 {
 		return mcp.NewToolResultText("No completed todos found"), nil
 	}
@@ -185,16 +283,39 @@ func (h *Handler) GetCompletedTodosHandler(ctx context.Context, request mcp.Call
 			referenceID = fmt.Sprintf(", ReferenceID: %d", *todo.ReferenceID)
 		}
 		resultText += fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s\n", 
-			todo.ID, todo.Title, status, todo.DueDate, todo.CreatedDate, referenceID)
+			todo.ID, todo.Title, status, h.formatDueDate(todo), h.formatTime(todo.CreatedDate), referenceID)
 	}
 	return mcp.NewToolResultText(resultText), nil
 }
 
 func (h *Handler) GetActiveTodosHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	todos := h.todoService.GetActiveTodos()
+	todos, err := h.todoService.GetActiveTodos(ctx)
+	if err != nil {
+		return toolError("get active todos", err)
+	}
+	now := time.Now()
+	todos = hideDeferred(request, todos, now)
+	var blockers map[string][]string
+	hideBlocked, _ := request.GetArguments()["hide_blocked"].(bool)
+	if hideBlocked {
+		blockers = h.openBlockers(ctx)
+		var unblocked []todo.TodoItem
+		for _, item := range todos {
+			if len(blockers[item.ID]) == 0 {
+				unblocked = append(unblocked, item)
+			}
+		}
+		todos = unblocked
+	}
 	if len(todos) == 0 {
 		return mcp.NewToolResultText("No active todos found"), nil
 	}
+	if !hideBlocked {
+		blockers = h.openBlockers(ctx)
+	}
+	patterns := seriesPatterns(h.todoService.ListRecurrencePatterns(ctx))
+	tags := h.allTodoTags(ctx)
+	progress := h.subtaskProgress(ctx)
 	var resultText string
 	for _, todo := range todos {
 		status := "Incomplete"
@@ -206,7 +327,7 @@ func (h *Handler) GetActiveTodosHandler(ctx context.Context, request mcp.CallToo
 		// Get project information if available
 		projectInfo := ""
 		if todo.ProjectID != nil && h.projectService != nil {
-			project, err := h.projectService.GetProject(*todo.ProjectID)
+			project, err := h.projectService.GetProject(ctx, *todo.ProjectID)
 			if err == nil {
 				projectInfo = fmt.Sprintf(", Project: %s", project.Name)
 			}
@@ -215,16 +336,16 @@ func (h *Handler) GetActiveTodosHandler(ctx context.Context, request mcp.CallToo
 		// Get category information if available
 		categoryInfo := ""
 		if todo.CategoryID != nil && h.categoryService != nil {
-			category, err := h.categoryService.GetCategoryByID(*todo.CategoryID)
+			category, err := h.categoryService.GetCategoryByID(ctx, *todo.CategoryID)
 			if err == nil {
 				categoryInfo = fmt.Sprintf(", Category: %s", category.Name)
 			}
 		}
 		
-		resultText += fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s%s%s\n",
-			todo.ID, todo.Title, status, todo.DueDate, todo.CreatedDate, referenceID, projectInfo, categoryInfo)
+		resultText += fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s%s%s%s%s%s%s%s%s%s\n",
+			todo.ID, todo.Title, status, h.formatDueDate(todo), h.formatTime(todo.CreatedDate), priorityInfo(todo), referenceID, projectInfo, categoryInfo, tagInfo(todo, tags), recurrenceInfo(todo, patterns), subtaskInfo(todo, progress), blockedInfo(todo, blockers), h.deferredInfo(todo, now), h.dueInfo(todo, now))
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Today's date is %s, and the list of todo items is: %s", time.Now().Format("2006-01-02"), resultText)), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Today's date is %s, and the list of todo items is: %s", now.In(h.timezone()).Format("2006-01-02"), resultText)), nil
 }
 
 func (h *Handler) DeleteTodoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -232,9 +353,9 @@ func (h *Handler) DeleteTodoHandler(ctx context.Context, request mcp.CallToolReq
 	if !ok {
 		return nil, errors.New("id must be a string")
 	}
-	todo, err := h.todoService.DeleteTodo(id)
+	todo, err := h.todoService.DeleteTodo(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete todo: %w", err)	
+		return toolError("delete todo", err)	
 	}
 
 	resultText := fmt.Sprintf("Deleted Todo: ID=%s, Title=%s", todo.ID, todo.Title)
@@ -246,9 +367,9 @@ func (h *Handler) GetTodoHandler(ctx context.Context, request mcp.CallToolReques
 	if !ok {
 		return nil, errors.New("id must be a string")
 	}
-	todo, err := h.todoService.GetTodo(id)
+	todo, err := h.todoService.GetTodo(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get todo: %w", err)
+		return toolError("get todo", err)
 	}
 	status := "Incomplete"
 	if todo.CompletedAt != nil {
@@ -259,14 +380,30 @@ func (h *Handler) GetTodoHandler(ctx context.Context, request mcp.CallToolReques
 		referenceID = fmt.Sprintf(", ReferenceID: %d", *todo.ReferenceID)
 	}
 
-	resultText := fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s\n", 
-		todo.ID, todo.Title, status, todo.DueDate, todo.CreatedDate, referenceID)
+	patterns := seriesPatterns(h.todoService.GetRecurrencePatternsForTodo(ctx, todo.ID))
+	tags := h.todoTags(ctx, todo.ID)
+	progress := h.subtaskProgress(ctx)
+
+	resultText := fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s%s%s%s%s%s%s\n", 
+		todo.ID, todo.Title, status, h.formatDueDate(todo), h.formatTime(todo.CreatedDate), priorityInfo(todo), referenceID, tagInfo(todo, tags), recurrenceInfo(todo, patterns), subtaskInfo(todo, progress), h.trackedInfo(ctx, todo), h.reminderInfo(ctx, todo))
+	if todo.Notes != "" {
+		resultText += "Notes:\n" + todo.Notes + "\n"
+	}
+	if tree := h.subtaskTree(ctx, todo.ID, 0); tree != "" {
+		resultText += "Subtasks:\n" + tree
+	}
 	
 	return mcp.NewToolResultText(resultText), nil
 }
 
 func (h *Handler) ListTodosHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	todos := h.todoService.GetAllTodos()
+	todos, err := h.todoService.GetAllTodos(ctx)
+	if err != nil {
+		return toolError("list todos", err)
+	}
+	patterns := seriesPatterns(h.todoService.ListRecurrencePatterns(ctx))
+	tags := h.allTodoTags(ctx)
+	progress := h.subtaskProgress(ctx)
 	var todosText []string
 	for _, todo := range todos {
 		status := "Incomplete"
@@ -277,8 +414,8 @@ func (h *Handler) ListTodosHandler(ctx context.Context, request mcp.CallToolRequ
 		if todo.ReferenceID != nil {
 			referenceID = fmt.Sprintf(", ReferenceID: %d", *todo.ReferenceID)
 		}
-		todosText = append(todosText, fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s\n", 
-			todo.ID, todo.Title, status, todo.DueDate, todo.CreatedDate, referenceID))
+		todosText = append(todosText, fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s, Created Date: %s%s%s%s%s%s\n", 
+			todo.ID, todo.Title, status, h.formatDueDate(todo), h.formatTime(todo.CreatedDate), priorityInfo(todo), referenceID, tagInfo(todo, tags), recurrenceInfo(todo, patterns), subtaskInfo(todo, progress)))
 	}
 	return mcp.NewToolResultText(strings.Join(todosText, "\n")), nil
 }
@@ -289,11 +426,25 @@ func (h *Handler) CompleteTodoHandler(ctx context.Context, request mcp.CallToolR
 		return nil, errors.New("id must be a string")
 	}
 
-	completedTodo, err := h.todoService.CompleteTodo(id)
+	policy, err := h.completionPolicyArgument(request)
+	if err != nil {
+		return toolError("complete todo", err)
+	}
+
+	completedTodo, next, err := h.todoService.CompleteTodoWithPolicy(ctx, id, policy)
 	if err != nil {
-		return nil, fmt.Errorf("failed to complete todo: %v", err)
+		return toolError("complete todo", err)
 	}
-	return mcp.NewToolResultText(fmt.Sprintf("Todo %s completed", completedTodo.Title)), nil
+	resultText := fmt.Sprintf("Todo %s completed", completedTodo.Title)
+	if next != nil {
+		// Recurring todos spawn their next occurrence on completion
+		resultText += fmt.Sprintf("\nNext occurrence created: ID=%s, Title=%s, Due Date=%s",
+			next.ID, next.Title, h.formatDueDate(*next))
+	}
+	if unblocked := h.unblockedBy(ctx, completedTodo.ID); len(unblocked) > 0 {
+		resultText += "\nUnblocked: " + joinTodoRefs(unblocked)
+	}
+	return mcp.NewToolResultText(resultText), nil
 }
 
 func (h *Handler) AddTodoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -305,19 +456,34 @@ func (h *Handler) AddTodoHandler(ctx context.Context, request mcp.CallToolReques
 	// Handle optional due_date
 	dueDateRaw, ok := request.GetArguments()["due_date"]
 	var dueDate *time.Time
+	var parsedDueDate dateparse.Date
 	if ok {
 		dueDateStr, ok := dueDateRaw.(string)
 		if !ok {
 			return nil, errors.New("due_date must be a string")
 		}
-		parsedDueDate, err := time.Parse(time.RFC3339, dueDateStr)
+		var err error
+		parsedDueDate, err = h.parseDueDate(dueDateStr)
 		if err != nil {
 			return nil, err
 		}
-		dueDate = &parsedDueDate
+		dueDate = &parsedDueDate.Time
+	}
+	
+	// Handle optional priority; parse it first so a bad one adds nothing
+	priority, err := priorityArgument(request)
+	if err != nil {
+		return toolError("add todo", err)
+	}
+	
+	// Handle optional notes
+	notes, _, err := notesArgument(request)
+	if err != nil {
+		return toolError("add todo", err)
 	}
 	
 	// Handle optional project_id
+	newTodo := todo.NewTodo{Title: title, DueDate: dueDate, DueAllDay: parsedDueDate.AllDay, Priority: priority, Notes: notes}
 	projectIDRaw, ok := request.GetArguments()["project_id"]
 	if ok {
 		projectIDFloat, ok := projectIDRaw.(float64)
@@ -325,26 +491,31 @@ func (h *Handler) AddTodoHandler(ctx context.Context, request mcp.CallToolReques
 			return nil, errors.New("project_id must be a number")
 		}
 		projectID := int64(projectIDFloat)
-		
-		// Add todo to project
-		_, err := h.todoService.AddTodoToProject(title, projectID, dueDate)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add todo to project: %w", err)
+		newTodo.ProjectID = &projectID
+	}
+	
+	// Every field goes in with the todo, so a failure adds nothing
+	if _, err := h.todoService.CreateTodo(ctx, newTodo); err != nil {
+		if newTodo.ProjectID != nil {
+			return toolError("add todo to project", err)
 		}
+		return toolError("add todo", err)
+	}
+	if newTodo.ProjectID != nil {
 		return mcp.NewToolResultText(fmt.Sprintf("%s added to project todo list", title)), nil
-	} else {
-		// Add regular todo
-		_, err := h.todoService.AddTodo(title, dueDate)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add todo: %w", err)
-		}
-		return mcp.NewToolResultText(fmt.Sprintf("%s added to todo list", title)), nil
 	}
+	return mcp.NewToolResultText(fmt.Sprintf("%s added to todo list", title)), nil
 }
 
 func (h *Handler) ListTodosResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	todos := h.todoService.GetAllTodos()
+	todos, err := h.todoService.GetAllTodos(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list todos: %w", err)
+	}
 	
+	for i := range todos {
+		todos[i] = h.inTimezone(todos[i])
+	}
 	jsonData, err := json.Marshal(todos)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal todos to JSON: %w", err)
@@ -359,34 +530,51 @@ func (h *Handler) ListTodosResourceHandler(ctx context.Context, request mcp.Read
 	}, nil
 }
 
+// GetSingleTodoResourceHandler serves todos://{id} as JSON followed by the
+// todo's notes as Markdown, and todos://{id}/notes as the notes alone
 func (h *Handler) GetSingleTodoResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	id := extractIDFromURI(request.Params.URI)
-	todo, err := h.todoService.GetTodo(id)
+	id, notesOnly := extractIDFromURI(request.Params.URI)
+	todo, err := h.todoService.GetTodo(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get todo item: %w", err)
 	}	
 	
-	jsonData, err := json.Marshal(todo)
+	notes := mcp.TextResourceContents{
+		URI:	fmt.Sprintf("todos://%s/notes", todo.ID),
+		MIMEType: "text/markdown",
+		Text: todo.Notes,
+	}
+	if notesOnly {
+		return []mcp.ResourceContents{notes}, nil
+	}
+
+	jsonData, err := json.Marshal(h.inTimezone(todo))
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal todo item: %w", err)
 	}
 
-	return []mcp.ResourceContents{
+	contents := []mcp.ResourceContents{
 		mcp.TextResourceContents{
 			URI:	request.Params.URI,
 			MIMEType: "application/json",
 			Text: string(jsonData),
 		},
-	}, nil
+	}
+	if todo.Notes != "" {
+		contents = append(contents, notes)
+	}
+	return contents, nil
 }
 
-func extractIDFromURI(uri string) string {
+// extractIDFromURI returns the todo ID in a todos://{id} or todos://{id}/notes
+// URI, and whether it is the notes URI
+func extractIDFromURI(uri string) (string, bool) {
     parsed, err := url.Parse(uri)
-    if err != nil || parsed.Scheme != "todos" || parsed.Opaque == "" {
-        return ""
+    if err != nil || parsed.Scheme != "todos" {
+        return "", false
     }
 
-    return strings.TrimPrefix(parsed.Path, "/")
+    return parsed.Host, parsed.Path == "/notes"
 }
 
 // Category handler methods
@@ -435,6 +623,8 @@ func (h *Handler) GetCategoryTodosHandler(ctx context.Context, request mcp.CallT
 		return nil, fmt.Errorf("category service not initialized")
 	}
 	categoryHandler := NewCategoryHandler(h.categoryService, h.todoService)
+	categoryHandler.tagService = h.tagService
+	categoryHandler.location = h.timezone()
 	return categoryHandler.GetCategoryTodosHandler(ctx, request)
 }
 
@@ -443,6 +633,7 @@ func (h *Handler) GetUncategorizedTodosHandler(ctx context.Context, request mcp.
 		return nil, fmt.Errorf("category service not initialized")
 	}
 	categoryHandler := NewCategoryHandler(h.categoryService, h.todoService)
+	categoryHandler.location = h.timezone()
 	return categoryHandler.GetUncategorizedTodosHandler(ctx, request)
 }
 
@@ -461,3 +652,19 @@ func (h *Handler) RemoveTodoFromCategoryHandler(ctx context.Context, request mcp
 	categoryHandler := NewCategoryHandler(h.categoryService, h.todoService)
 	return categoryHandler.RemoveTodoFromCategoryHandler(ctx, request)
 }
+
+// TimeoutMiddleware cancels the context passed to a tool handler after timeout,
+// so a slow query is abandoned instead of holding a connection indefinitely.
+// A zero or negative timeout leaves the context untouched.
+func TimeoutMiddleware(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
@@ -1,37 +1,64 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
+	_ "time/tzdata" // the timezone cases run on hosts without a zoneinfo database
 
+	"mcp-godo/pkg/dateparse"
 	"mcp-godo/pkg/todo"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockTodoService struct {
 	addTodoFunc            func(title string, dueDate *time.Time) (todo.TodoItem, error)
-	getAllTodosFunc        func() []todo.TodoItem
-	getActiveTodosFunc     func() []todo.TodoItem
-	getCompletedTodosFunc  func() []todo.TodoItem
+	getAllTodosFunc        func() ([]todo.TodoItem, error)
+	getActiveTodosFunc     func() ([]todo.TodoItem, error)
+	getCompletedTodosFunc  func() ([]todo.TodoItem, error)
 	getTodoFunc           func(id string) (todo.TodoItem, error)
 	completeTodoFunc      func(id string) (todo.TodoItem, error)
+	completeTodoWithNextFunc func(id string) (todo.TodoItem, *todo.TodoItem, error)
 	unCompleteTodoFunc    func(id string) (todo.TodoItem, error)
 	setDueDateFunc        func(id string, dueDate time.Time) (todo.TodoItem, error)
+	setPriorityFunc       func(id string, priority todo.Priority) (todo.TodoItem, error)
+	updateTodoFunc        func(id string, patch todo.TodoPatch) (todo.TodoItem, error)
+	setNotesFunc          func(id string, notes string) (todo.TodoItem, error)
 	deleteTodoFunc        func(id string) (todo.TodoItem, error)
-	titleSearchTodoFunc   func(query string, activeOnly bool) []todo.TodoItem
+	titleSearchTodoFunc   func(query string, activeOnly bool) ([]todo.TodoItem, error)
+	searchTodosFunc       func(query string, activeOnly bool) ([]todo.TodoItem, error)
 	addRecurrencePatternFunc    func(pattern todo.RecurrencePattern) (int64, error)
 	getRecurrencePatternByIDFunc func(id int64) (todo.RecurrencePattern, error)
+	listRecurrencePatternsFunc func() ([]todo.RecurrencePattern, error)
+	getRecurrencePatternsForTodoFunc func(todoID string) ([]todo.RecurrencePattern, error)
+	updateRecurrencePatternFunc func(pattern todo.RecurrencePattern) (todo.RecurrencePattern, error)
+	deleteRecurrencePatternFunc func(id int64) (todo.RecurrencePattern, error)
+	pauseRecurrencePatternFunc func(id int64) (todo.RecurrencePattern, error)
+	resumeRecurrencePatternFunc func(id int64) (todo.RecurrencePattern, *todo.TodoItem, error)
 	addTodoToProjectFunc  func(title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error)
 	addTodoToCategoryFunc func(title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error)
-	getTodosByProjectFunc func(projectID int64) []todo.TodoItem
-	getTodosByCategoryFunc func(categoryID int64) []todo.TodoItem
-	getUncategorizedTodosFunc func() []todo.TodoItem
+	getTodosByProjectFunc func(projectID int64) ([]todo.TodoItem, error)
+	getTodosByCategoryFunc func(categoryID int64) ([]todo.TodoItem, error)
+	getUncategorizedTodosFunc func() ([]todo.TodoItem, error)
 	assignTodoToCategoryFunc func(todoID string, categoryID int64) (todo.TodoItem, error)
 	removeTodoFromCategoryFunc func(todoID string) (todo.TodoItem, error)
+	completeTodoWithPolicyFunc func(id string, policy todo.CompletionPolicy) (todo.TodoItem, *todo.TodoItem, error)
+	addSubtaskFunc        func(parentID string, title string, dueDate *time.Time) (todo.TodoItem, error)
+	moveTodoFunc          func(id string, parentID *string) (todo.TodoItem, error)
+	getSubtasksFunc       func(parentID string) ([]todo.TodoItem, error)
+	getSubtaskProgressFunc func() (map[string]todo.SubtaskProgress, error)
+	addBlockerFunc         func(todoID string, blockerID string) ([]todo.TodoItem, error)
+	removeBlockerFunc      func(todoID string, blockerID string) ([]todo.TodoItem, error)
+	getBlockersFunc        func(todoID string) ([]todo.TodoItem, error)
+	getDependentsFunc      func(blockerID string) ([]todo.TodoItem, error)
+	getOpenBlockersFunc    func() (map[string][]string, error)
+	getNextActionsFunc     func() ([]todo.TodoItem, error)
 }
 
 func TestAddRecurrencePatternHandler(t *testing.T) {
@@ -42,6 +69,7 @@ func TestAddRecurrencePatternHandler(t *testing.T) {
 		mockFunc     func(pattern todo.RecurrencePattern) (int64, error)
 		expectedText string
 		expectError  bool
+		expectToolError bool
 	}{
 		{
 			name: "success with until and count",
@@ -77,6 +105,21 @@ func TestAddRecurrencePatternHandler(t *testing.T) {
 			expectedText: "Recurrence pattern added with ID: 2",
 			expectError: false,
 		},
+		{
+			name: "rrule without frequency and interval",
+			args: map[string]interface{}{
+				"todo_id": "123",
+				"rrule":   "FREQ=MONTHLY;BYDAY=2TU",
+			},
+			mockFunc: func(pattern todo.RecurrencePattern) (int64, error) {
+				if pattern.TodoID != "123" || pattern.RRule != "FREQ=MONTHLY;BYDAY=2TU" {
+					return 0, fmt.Errorf("unexpected pattern")
+				}
+				return 3, nil
+			},
+			expectedText: "Recurrence pattern added with ID: 3",
+			expectError: false,
+		},
 		{
 			name: "invalid todo_id",
 			args: map[string]interface{}{
@@ -120,7 +163,8 @@ func TestAddRecurrencePatternHandler(t *testing.T) {
 			mockFunc: func(pattern todo.RecurrencePattern) (int64, error) {
 				return 0, fmt.Errorf("service error")
 			},
-			expectError: true,
+			expectedText: "failed to add recurrence pattern: service error",
+			expectToolError: true,
 		},
 	}
 
@@ -143,6 +187,7 @@ func TestAddRecurrencePatternHandler(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectToolError, result.IsError)
 				assert.Equal(t, tt.expectedText, result.Content[0].(mcp.TextContent).Text)
 			}
 		})
@@ -157,6 +202,7 @@ func TestGetRecurrencePatternHandler(t *testing.T) {
 		mockFunc     func(id int64) (todo.RecurrencePattern, error)
 		expectedText string
 		expectError  bool
+		expectToolError bool
 	}{
 		{
 			name: "success with until and count",
@@ -203,7 +249,8 @@ func TestGetRecurrencePatternHandler(t *testing.T) {
 			mockFunc: func(id int64) (todo.RecurrencePattern, error) {
 				return todo.RecurrencePattern{}, fmt.Errorf("service error")
 			},
-			expectError: true,
+			expectedText: "failed to get recurrence pattern: service error",
+			expectToolError: true,
 		},
 	}
 
@@ -226,103 +273,227 @@ func TestGetRecurrencePatternHandler(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectToolError, result.IsError)
 				assert.Equal(t, tt.expectedText, result.Content[0].(mcp.TextContent).Text)
 			}
 		})
 	}
 }
 
-func (m *mockTodoService) AddTodo(title string, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *mockTodoService) AddTodo(ctx context.Context, title string, dueDate *time.Time) (todo.TodoItem, error) {
 	return m.addTodoFunc(title, dueDate)
 }
 
-func (m *mockTodoService) GetAllTodos() []todo.TodoItem {
+func (m *mockTodoService) GetAllTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	return m.getAllTodosFunc()
 }
 
-func (m *mockTodoService) GetActiveTodos() []todo.TodoItem {
+func (m *mockTodoService) GetActiveTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	return m.getActiveTodosFunc()
 }
 
-func (m *mockTodoService) GetCompletedTodos() []todo.TodoItem {
+func (m *mockTodoService) GetCompletedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	return m.getCompletedTodosFunc()
 }
 
-func (m *mockTodoService) GetTodo(id string) (todo.TodoItem, error) {
+func (m *mockTodoService) GetTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	return m.getTodoFunc(id)
 }
 
-func (m *mockTodoService) CompleteTodo(id string) (todo.TodoItem, error) {
+func (m *mockTodoService) CompleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	return m.completeTodoFunc(id)
 }
 
-func (m *mockTodoService) UnCompleteTodo(id string) (todo.TodoItem, error) {
+func (m *mockTodoService) CompleteTodoWithNext(ctx context.Context, id string) (todo.TodoItem, *todo.TodoItem, error) {
+	if m.completeTodoWithNextFunc == nil {
+		item, err := m.completeTodoFunc(id)
+		return item, nil, err
+	}
+	return m.completeTodoWithNextFunc(id)
+}
+
+func (m *mockTodoService) CompleteTodoWithPolicy(ctx context.Context, id string, policy todo.CompletionPolicy) (todo.TodoItem, *todo.TodoItem, error) {
+	if m.completeTodoWithPolicyFunc == nil {
+		return m.CompleteTodoWithNext(ctx, id)
+	}
+	return m.completeTodoWithPolicyFunc(id, policy)
+}
+
+func (m *mockTodoService) AddSubtask(ctx context.Context, parentID string, title string, dueDate *time.Time) (todo.TodoItem, error) {
+	return m.addSubtaskFunc(parentID, title, dueDate)
+}
+
+func (m *mockTodoService) CreateTodo(ctx context.Context, n todo.NewTodo) (todo.TodoItem, error) {
+	if n.ParentID != nil {
+		return m.AddSubtask(ctx, *n.ParentID, n.Title, n.DueDate)
+	}
+	if n.ProjectID != nil {
+		return m.AddTodoToProject(ctx, n.Title, *n.ProjectID, n.DueDate)
+	}
+	return m.AddTodo(ctx, n.Title, n.DueDate)
+}
+
+func (m *mockTodoService) MoveTodo(ctx context.Context, id string, parentID *string) (todo.TodoItem, error) {
+	return m.moveTodoFunc(id, parentID)
+}
+
+func (m *mockTodoService) GetSubtasks(ctx context.Context, parentID string) ([]todo.TodoItem, error) {
+	if m.getSubtasksFunc == nil {
+		return nil, nil
+	}
+	return m.getSubtasksFunc(parentID)
+}
+
+func (m *mockTodoService) GetSubtaskProgress(ctx context.Context) (map[string]todo.SubtaskProgress, error) {
+	if m.getSubtaskProgressFunc == nil {
+		return nil, nil
+	}
+	return m.getSubtaskProgressFunc()
+}
+
+func (m *mockTodoService) AddBlocker(ctx context.Context, todoID string, blockerID string) ([]todo.TodoItem, error) {
+	return m.addBlockerFunc(todoID, blockerID)
+}
+
+func (m *mockTodoService) RemoveBlocker(ctx context.Context, todoID string, blockerID string) ([]todo.TodoItem, error) {
+	return m.removeBlockerFunc(todoID, blockerID)
+}
+
+func (m *mockTodoService) GetBlockers(ctx context.Context, todoID string) ([]todo.TodoItem, error) {
+	return m.getBlockersFunc(todoID)
+}
+
+func (m *mockTodoService) GetDependents(ctx context.Context, blockerID string) ([]todo.TodoItem, error) {
+	if m.getDependentsFunc == nil {
+		return nil, nil
+	}
+	return m.getDependentsFunc(blockerID)
+}
+
+func (m *mockTodoService) GetOpenBlockers(ctx context.Context) (map[string][]string, error) {
+	if m.getOpenBlockersFunc == nil {
+		return nil, nil
+	}
+	return m.getOpenBlockersFunc()
+}
+
+func (m *mockTodoService) GetNextActions(ctx context.Context) ([]todo.TodoItem, error) {
+	return m.getNextActionsFunc()
+}
+
+func (m *mockTodoService) UnCompleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	return m.unCompleteTodoFunc(id)
 }
 
-func (m *mockTodoService) SetDueDate(id string, dueDate time.Time) (todo.TodoItem, error) {
+func (m *mockTodoService) SetDueDate(ctx context.Context, id string, dueDate time.Time) (todo.TodoItem, error) {
 	return m.setDueDateFunc(id, dueDate)
 }
 
-func (m *mockTodoService) DeleteTodo(id string) (todo.TodoItem, error) {
+func (m *mockTodoService) SetPriority(ctx context.Context, id string, priority todo.Priority) (todo.TodoItem, error) {
+	return m.setPriorityFunc(id, priority)
+}
+
+func (m *mockTodoService) UpdateTodo(ctx context.Context, id string, patch todo.TodoPatch) (todo.TodoItem, error) {
+	return m.updateTodoFunc(id, patch)
+}
+
+func (m *mockTodoService) SetNotes(ctx context.Context, id string, notes string) (todo.TodoItem, error) {
+	return m.setNotesFunc(id, notes)
+}
+
+func (m *mockTodoService) DeleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	return m.deleteTodoFunc(id)
 }
 
-func (m *mockTodoService) TitleSearchTodo(query string, activeOnly bool) []todo.TodoItem {
+func (m *mockTodoService) TitleSearchTodo(ctx context.Context, query string, activeOnly bool) ([]todo.TodoItem, error) {
 	return m.titleSearchTodoFunc(query, activeOnly)
 }
 
-func (m *mockTodoService) AddRecurrencePattern(pattern todo.RecurrencePattern) (int64, error) {
+func (m *mockTodoService) SearchTodos(ctx context.Context, query string, activeOnly bool) ([]todo.TodoItem, error) {
+	return m.searchTodosFunc(query, activeOnly)
+}
+
+func (m *mockTodoService) AddRecurrencePattern(ctx context.Context, pattern todo.RecurrencePattern) (int64, error) {
 	return m.addRecurrencePatternFunc(pattern)
 }
 
-func (m *mockTodoService) GetRecurrencePatternByID(id int64) (todo.RecurrencePattern, error) {
+func (m *mockTodoService) GetRecurrencePatternByID(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
 	return m.getRecurrencePatternByIDFunc(id)
 }
 
-func (m *mockTodoService) AddTodoToProject(title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *mockTodoService) ListRecurrencePatterns(ctx context.Context) ([]todo.RecurrencePattern, error) {
+	if m.listRecurrencePatternsFunc != nil {
+		return m.listRecurrencePatternsFunc()
+	}
+	return nil, nil
+}
+
+func (m *mockTodoService) GetRecurrencePatternsForTodo(ctx context.Context, todoID string) ([]todo.RecurrencePattern, error) {
+	if m.getRecurrencePatternsForTodoFunc != nil {
+		return m.getRecurrencePatternsForTodoFunc(todoID)
+	}
+	return nil, nil
+}
+
+func (m *mockTodoService) UpdateRecurrencePattern(ctx context.Context, pattern todo.RecurrencePattern) (todo.RecurrencePattern, error) {
+	return m.updateRecurrencePatternFunc(pattern)
+}
+
+func (m *mockTodoService) DeleteRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
+	return m.deleteRecurrencePatternFunc(id)
+}
+
+func (m *mockTodoService) PauseRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
+	return m.pauseRecurrencePatternFunc(id)
+}
+
+func (m *mockTodoService) ResumeRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, *todo.TodoItem, error) {
+	return m.resumeRecurrencePatternFunc(id)
+}
+
+func (m *mockTodoService) AddTodoToProject(ctx context.Context, title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error) {
 	if m.addTodoToProjectFunc != nil {
 		return m.addTodoToProjectFunc(title, projectID, dueDate)
 	}
 	return todo.TodoItem{}, nil
 }
 
-func (m *mockTodoService) AddTodoToCategory(title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *mockTodoService) AddTodoToCategory(ctx context.Context, title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error) {
 	if m.addTodoToCategoryFunc != nil {
 		return m.addTodoToCategoryFunc(title, categoryID, dueDate)
 	}
 	return todo.TodoItem{}, nil
 }
 
-func (m *mockTodoService) GetTodosByProject(projectID int64) []todo.TodoItem {
+func (m *mockTodoService) GetTodosByProject(ctx context.Context, projectID int64) ([]todo.TodoItem, error) {
 	if m.getTodosByProjectFunc != nil {
 		return m.getTodosByProjectFunc(projectID)
 	}
-	return []todo.TodoItem{}
+	return []todo.TodoItem{}, nil
 }
 
-func (m *mockTodoService) GetTodosByCategory(categoryID int64) []todo.TodoItem {
+func (m *mockTodoService) GetTodosByCategory(ctx context.Context, categoryID int64) ([]todo.TodoItem, error) {
 	if m.getTodosByCategoryFunc != nil {
 		return m.getTodosByCategoryFunc(categoryID)
 	}
-	return []todo.TodoItem{}
+	return []todo.TodoItem{}, nil
 }
 
-func (m *mockTodoService) GetUncategorizedTodos() []todo.TodoItem {
+func (m *mockTodoService) GetUncategorizedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	if m.getUncategorizedTodosFunc != nil {
 		return m.getUncategorizedTodosFunc()
 	}
-	return []todo.TodoItem{}
+	return []todo.TodoItem{}, nil
 }
 
-func (m *mockTodoService) AssignTodoToCategory(todoID string, categoryID int64) (todo.TodoItem, error) {
+func (m *mockTodoService) AssignTodoToCategory(ctx context.Context, todoID string, categoryID int64) (todo.TodoItem, error) {
 	if m.assignTodoToCategoryFunc != nil {
 		return m.assignTodoToCategoryFunc(todoID, categoryID)
 	}
 	return todo.TodoItem{}, nil
 }
 
-func (m *mockTodoService) RemoveTodoFromCategory(todoID string) (todo.TodoItem, error) {
+func (m *mockTodoService) RemoveTodoFromCategory(ctx context.Context, todoID string) (todo.TodoItem, error) {
 	if m.removeTodoFromCategoryFunc != nil {
 		return m.removeTodoFromCategoryFunc(todoID)
 	}
@@ -404,6 +575,7 @@ func TestCompleteTodoHandler(t *testing.T) {
 		mockFunc     func(id string) (todo.TodoItem, error)
 		expectedText string
 		expectError  bool
+		expectToolError bool
 	}{
 		{
 			name: "success",
@@ -428,7 +600,17 @@ func TestCompleteTodoHandler(t *testing.T) {
 			mockFunc: func(id string) (todo.TodoItem, error) {
 				return todo.TodoItem{}, errors.New("service error")
 			},
-			expectError: true,
+			expectedText: "failed to complete todo: service error",
+			expectToolError: true,
+		},
+		{
+			name: "todo not found",
+			args: map[string]interface{}{"id": "123"},
+			mockFunc: func(id string) (todo.TodoItem, error) {
+				return todo.TodoItem{}, fmt.Errorf("%w: id %s", todo.ErrTodoNotFound, id)
+			},
+			expectedText: "Todo not found: id 123. Use list_todos or title_search to find the todo ID.",
+			expectToolError: true,
 		},
 	}
 
@@ -451,36 +633,122 @@ func TestCompleteTodoHandler(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectToolError, result.IsError)
 				assert.Equal(t, tt.expectedText, result.Content[0].(mcp.TextContent).Text)
 			}
 		})
 	}
 }
 
+func TestCompleteTodoHandler_ReportsNextOccurrence(t *testing.T) {
+	due := time.Date(2030, time.March, 21, 9, 0, 0, 0, time.UTC)
+	mockSvc := &mockTodoService{
+		completeTodoWithNextFunc: func(id string) (todo.TodoItem, *todo.TodoItem, error) {
+			root := int64(123)
+			return todo.TodoItem{ID: id, Title: "Water plants"},
+				&todo.TodoItem{ID: "124", Title: "Water plants", DueDate: &due, ReferenceID: &root}, nil
+		},
+	}
+	h := NewHandler(mockSvc)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"id": "123"},
+		},
+	}
+	result, err := h.CompleteTodoHandler(context.Background(), req)
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Todo Water plants completed\nNext occurrence created: ID=124, Title=Water plants, Due Date=2030-03-21T09:00:00Z",
+		result.Content[0].(mcp.TextContent).Text)
+}
+
+func TestPreviewRecurrenceHandler(t *testing.T) {
+	h := NewHandler(&mockTodoService{})
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		result, err := h.PreviewRecurrenceHandler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Arguments: args},
+		})
+		assert.NoError(t, err)
+		return result
+	}
+
+	result := call(map[string]interface{}{
+		"rrule": "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"start": "2030-03-01T09:00:00Z",
+		"limit": 2.0,
+	})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Occurrences from 2030-03-01T09:00:00Z:\n1. 2030-03-29T09:00:00Z (Friday)\n2. 2030-04-30T09:00:00Z (Tuesday)",
+		result.Content[0].(mcp.TextContent).Text)
+
+	result = call(map[string]interface{}{
+		"frequency": "daily",
+		"interval":  1.0,
+		"count":     1.0,
+		"start":     "2030-03-01T09:00:00Z",
+	})
+	assert.Equal(t, "Occurrences from 2030-03-01T09:00:00Z:\n1. 2030-03-01T09:00:00Z (Friday)",
+		result.Content[0].(mcp.TextContent).Text)
+
+	// The series keeps its weekday and time of day in the handler's timezone
+	// across the DST change on 2030-03-10
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	h.SetTimezone(newYork)
+	result = call(map[string]interface{}{
+		"rrule": "FREQ=WEEKLY;BYDAY=MO",
+		"start": "2030-03-05T01:00:00Z",
+		"limit": 2.0,
+	})
+	assert.Equal(t, "Occurrences from 2030-03-04T20:00:00-05:00:\n1. 2030-03-04T20:00:00-05:00 (Monday)\n2. 2030-03-11T20:00:00-04:00 (Monday)",
+		result.Content[0].(mcp.TextContent).Text)
+	h.SetTimezone(time.UTC)
+
+	result = call(map[string]interface{}{"rrule": "FREQ=HOURLY"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Invalid rrule")
+
+	result = call(map[string]interface{}{"rrule": "FREQ=DAILY", "limit": 500.0})
+	assert.True(t, result.IsError)
+
+	_, err = h.PreviewRecurrenceHandler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Arguments: map[string]interface{}{"interval": 1.0}},
+	})
+	assert.EqualError(t, err, "invalid frequency")
+}
+
 func TestListTodosHandler(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
 		name          string
-		mockFunc     func() []todo.TodoItem
+		mockFunc     func() ([]todo.TodoItem, error)
 		expectError  bool
 	}{
 		{
 			name: "success with todos",
-			mockFunc: func() []todo.TodoItem {
+			mockFunc: func() ([]todo.TodoItem, error) {
 				return []todo.TodoItem{
 					{ID: "1", Title: "test1", CompletedAt: nil, CreatedDate: now},
 					{ID: "2", Title: "test2", CompletedAt: &now, CreatedDate: now},
-				}
+				}, nil
 			},
 			expectError: false,
 		},
 		{
 			name: "success empty",
-			mockFunc: func() []todo.TodoItem {
-				return []todo.TodoItem{}
+			mockFunc: func() ([]todo.TodoItem, error) {
+				return []todo.TodoItem{}, nil
 			},
 			expectError: false,
 		},
+		{
+			name: "service error",
+			mockFunc: func() ([]todo.TodoItem, error) {
+				return nil, fmt.Errorf("database unavailable")
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -498,6 +766,7 @@ func TestListTodosHandler(t *testing.T) {
 			
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
+			assert.Equal(t, tt.expectError, result.IsError)
 		})
 	}
 }
@@ -507,18 +776,18 @@ func TestTitleSearchHandler(t *testing.T) {
 	tests := []struct {
 		name          string
 		args         map[string]interface{}
-		mockFunc     func(query string, activeOnly bool) []todo.TodoItem
+		mockFunc     func(query string, activeOnly bool) ([]todo.TodoItem, error)
 		expectedText string
 		expectError  bool
 	}{
 		{
 			name: "success with results",
 			args: map[string]interface{}{"query": "test"},
-			mockFunc: func(query string, activeOnly bool) []todo.TodoItem {
+			mockFunc: func(query string, activeOnly bool) ([]todo.TodoItem, error) {
 				return []todo.TodoItem{
 					{ID: "1", Title: "test todo", CompletedAt: nil, CreatedDate: now},
 					{ID: "2", Title: "another test", CompletedAt: &now, CreatedDate: now},
-				}
+				}, nil
 			},
 			expectedText: fmt.Sprintf("ID: 1, Title: test todo, CompletedAt: <nil>, Due Date: \nID: 2, Title: another test, CompletedAt: %s, Due Date: ", now),
 			expectError: false,
@@ -526,16 +795,16 @@ func TestTitleSearchHandler(t *testing.T) {
 		{
 			name: "success with activeOnly filter",
 			args: map[string]interface{}{"query": "test", "active_only": true},
-			mockFunc: func(query string, activeOnly bool) []todo.TodoItem {
+			mockFunc: func(query string, activeOnly bool) ([]todo.TodoItem, error) {
 				if activeOnly {
 					return []todo.TodoItem{
 						{ID: "1", Title: "test todo", CompletedAt: nil, CreatedDate: now},
-					}
+					}, nil
 				}
 				return []todo.TodoItem{
 					{ID: "1", Title: "test todo", CompletedAt: nil, CreatedDate: now},
 					{ID: "2", Title: "another test", CompletedAt: &now, CreatedDate: now},
-				}
+				}, nil
 			},
 			expectedText: "ID: 1, Title: test todo, CompletedAt: <nil>, Due Date: ",
 			expectError: false,
@@ -543,8 +812,8 @@ func TestTitleSearchHandler(t *testing.T) {
 		{
 			name: "success no results",
 			args: map[string]interface{}{"query": "nonexistent"},
-			mockFunc: func(query string, activeOnly bool) []todo.TodoItem {
-				return []todo.TodoItem{}
+			mockFunc: func(query string, activeOnly bool) ([]todo.TodoItem, error) {
+				return []todo.TodoItem{}, nil
 			},
 			expectedText: "",
 			expectError: false,
@@ -552,16 +821,16 @@ func TestTitleSearchHandler(t *testing.T) {
 		{
 			name: "missing query param",
 			args: map[string]interface{}{},
-			mockFunc: func(query string, activeOnly bool) []todo.TodoItem {
-				return []todo.TodoItem{}
+			mockFunc: func(query string, activeOnly bool) ([]todo.TodoItem, error) {
+				return []todo.TodoItem{}, nil
 			},
 			expectError: true,
 		},
 		{
 			name: "invalid query type",
 			args: map[string]interface{}{"query": 123},
-			mockFunc: func(query string, activeOnly bool) []todo.TodoItem {
-				return []todo.TodoItem{}
+			mockFunc: func(query string, activeOnly bool) ([]todo.TodoItem, error) {
+				return []todo.TodoItem{}, nil
 			},
 			expectError: true,
 		},
@@ -595,3 +864,1121 @@ func TestTitleSearchHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestToolError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedText string
+	}{
+		{
+			name:         "validation",
+			err:          &todo.ValidationError{Field: "title", Message: "title cannot be empty"},
+			expectedText: "Invalid title: title cannot be empty",
+		},
+		{
+			name:         "todo not found",
+			err:          fmt.Errorf("%w: id 42", todo.ErrTodoNotFound),
+			expectedText: "Todo not found: id 42. Use list_todos or title_search to find the todo ID.",
+		},
+		{
+			name:         "project not found",
+			err:          fmt.Errorf("%w: id 7", todo.ErrProjectNotFound),
+			expectedText: "Project not found: id 7. Use get_all_projects to find the project ID.",
+		},
+		{
+			name:         "category not found",
+			err:          fmt.Errorf("%w: id 3", todo.ErrCategoryNotFound),
+			expectedText: "Category not found: id 3. Use get_all_categories to find the category ID.",
+		},
+		{
+			name:         "duplicate name",
+			err:          fmt.Errorf("%w: project with name 'Garden' already exists", todo.ErrDuplicateName),
+			expectedText: "Duplicate name: project with name 'Garden' already exists. Choose a different name or use the existing one.",
+		},
+		{
+			name:         "other",
+			err:          errors.New("connection refused"),
+			expectedText: "failed to get todo: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := toolError("get todo", tt.err)
+			assert.NoError(t, err)
+			assert.True(t, result.IsError)
+			assert.Equal(t, tt.expectedText, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	slow := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return mcp.NewToolResultText("done"), nil
+		}
+	}
+
+	_, err := TimeoutMiddleware(10*time.Millisecond)(slow)(context.Background(), mcp.CallToolRequest{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	fast := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		return mcp.NewToolResultText("done"), nil
+	}
+	result, err := TimeoutMiddleware(0)(fast)(context.Background(), mcp.CallToolRequest{})
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+}
+
+func TestHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithProjectAndCategory(storage.Todos, storage.Projects, storage.Categories)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	project, err := storage.Projects.CreateProject(ctx, "Garden", nil)
+	assert.NoError(t, err)
+
+	result, err := h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Plant tomatoes", "project_id": float64(project.ID)}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Plant tomatoes added to project todo list", text(result))
+
+	result, err = h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "ID: 1, Title: Plant tomatoes")
+	assert.Contains(t, text(result), "Project: Garden")
+
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo Plant tomatoes completed", text(result))
+
+	result, err = h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "No active todos found", text(result))
+}
+
+func TestRecurrenceHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithProjectAndCategory(storage.Todos, storage.Projects, storage.Categories)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	due := time.Date(2030, time.March, 4, 9, 0, 0, 0, time.UTC)
+	item, err := storage.Todos.AddTodo(ctx, "Gym", &due)
+	assert.NoError(t, err)
+	result, err := h.AddRecurrencePatternHandler(ctx, call(map[string]interface{}{"todo_id": item.ID, "rrule": "FREQ=WEEKLY;BYDAY=MO,FR"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Recurrence pattern added with ID: 1", text(result))
+
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": item.ID}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Repeats: every week on Monday and Friday, PatternID: 1")
+
+	result, err = h.ListRecurrencePatternsHandler(ctx, call(map[string]interface{}{"todo_id": item.ID}))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 1, TodoID: 1, Repeats: every week on Monday and Friday, RRULE: FREQ=WEEKLY;BYDAY=MO,FR", text(result))
+
+	result, err = h.PauseRecurrencePatternHandler(ctx, call(map[string]interface{}{"id": 1.0}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	result, err = h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Repeats: every week on Monday and Friday (paused), PatternID: 1")
+
+	result, err = h.ResumeRecurrencePatternHandler(ctx, call(map[string]interface{}{"id": 1.0}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Recurrence pattern resumed: ID: 1, TodoID: 1, Repeats: every week on Monday and Friday, RRULE: FREQ=WEEKLY;BYDAY=MO,FR", text(result))
+
+	// Switching to a plain frequency drops the RRULE
+	result, err = h.UpdateRecurrencePatternHandler(ctx, call(map[string]interface{}{"id": 1.0, "frequency": "daily", "interval": 2.0}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Recurrence pattern updated: ID: 1, TodoID: 1, Repeats: every 2 days", text(result))
+
+	result, err = h.UpdateRecurrencePatternHandler(ctx, call(map[string]interface{}{"id": 1.0, "rrule": "FREQ=DAILY", "count": 3.0}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = h.DeleteRecurrencePatternHandler(ctx, call(map[string]interface{}{"id": 1.0}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": item.ID}))
+	assert.NoError(t, err)
+	assert.NotContains(t, text(result), "Repeats")
+
+	result, err = h.PauseRecurrencePatternHandler(ctx, call(map[string]interface{}{"id": 1.0}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Recurrence pattern not found: id 1. Use list_recurrence_patterns to find the pattern ID.", text(result))
+}
+
+func TestPriorityHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithProjectAndCategory(storage.Todos, storage.Projects, storage.Categories)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	project, err := storage.Projects.CreateProject(ctx, "Home", nil)
+	assert.NoError(t, err)
+	result, err := h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Dust shelves", "project_id": float64(project.ID), "priority": "low"}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	result, err = h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Fix leak", "project_id": float64(project.ID), "priority": "P1"}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	result, err = h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Nap", "priority": "whenever"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Invalid priority: unknown priority 'whenever'; use none, low, medium, high, urgent or P1-P4", text(result))
+	all, err := storage.Todos.GetAllTodos(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2, "a todo with a bad priority is not added")
+
+	// The priority and project go in with the todo, so a missing project
+	// leaves nothing behind
+	result, err = h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Nap", "project_id": float64(999), "priority": "high"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	all, err = storage.Todos.GetAllTodos(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2, "a todo in a missing project is not added")
+
+	result, err = h.GetProjectTodosHandler(ctx, call(map[string]interface{}{"id": float64(project.ID)}))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 2, Title: Fix leak, Status: Incomplete, Due Date: none, Priority: urgent\n"+
+		"ID: 1, Title: Dust shelves, Status: Incomplete, Due Date: none, Priority: low\n", text(result))
+
+	result, err = h.SetPriorityHandler(ctx, call(map[string]interface{}{"id": "1", "priority": "high"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID=1, Title=Dust shelves, Priority=high", text(result))
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Priority: high")
+
+	result, err = h.SetPriorityHandler(ctx, call(map[string]interface{}{"id": "1", "priority": "none"}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.NotContains(t, text(result), "Priority")
+
+	result, err = h.SetPriorityHandler(ctx, call(map[string]interface{}{"id": "999", "priority": "high"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestTagHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	flights, err := storage.Todos.AddTodo(ctx, "Book flights", nil)
+	assert.NoError(t, err)
+	report, err := storage.Todos.AddTodo(ctx, "Write report", nil)
+	assert.NoError(t, err)
+
+	result, err := h.AddTagsHandler(ctx, call(map[string]interface{}{"todo_id": flights.ID, "tags": []interface{}{"#Travel", "work"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 1 tagged: travel, work", text(result))
+	// A comma-separated string works too
+	result, err = h.AddTagsHandler(ctx, call(map[string]interface{}{"todo_id": report.ID, "tags": "work, writing"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 2 tagged: work, writing", text(result))
+
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": flights.ID}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Tags: travel, work")
+	result, err = h.ListTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Title: Write report, Status: Incomplete")
+	assert.Contains(t, text(result), ", Tags: work, writing")
+
+	result, err = h.GetTodosByTagsHandler(ctx, call(map[string]interface{}{"tags": []interface{}{"travel", "work"}, "match": "all"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "ID: 1, Title: Book flights")
+	assert.NotContains(t, text(result), "Write report")
+	result, err = h.GetTodosByTagsHandler(ctx, call(map[string]interface{}{"tags": []interface{}{"travel", "work"}}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Write report")
+
+	result, err = h.RenameTagHandler(ctx, call(map[string]interface{}{"name": "travel", "new_name": "work"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Duplicate name: tag with name 'work' already exists. Use merge_tags to combine the two tags.", text(result))
+
+	result, err = h.MergeTagsHandler(ctx, call(map[string]interface{}{"from": "writing", "into": "work"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Tag writing merged into: ID=2, Name=work", text(result))
+	result, err = h.ListTagsHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 1, Name: travel, Todos: 1\nID: 2, Name: work, Todos: 2\n", text(result))
+
+	result, err = h.RemoveTagsHandler(ctx, call(map[string]interface{}{"todo_id": flights.ID, "tags": []interface{}{"nonexistent"}}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tag not found: name 'nonexistent'. Use list_tags to find the tag name.", text(result))
+	result, err = h.RemoveTagsHandler(ctx, call(map[string]interface{}{"todo_id": flights.ID, "tags": []interface{}{"travel", "work"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 1 has no tags left", text(result))
+}
+
+func TestNotesHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	read := func(uri string) []mcp.ResourceContents {
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = uri
+		contents, err := h.GetSingleTodoResourceHandler(ctx, request)
+		assert.NoError(t, err)
+		return contents
+	}
+
+	notes := "## Steps\n\n1. Call the venue\n2. See https://example.com/menu"
+	result, err := h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Plan dinner", "notes": notes}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Plan dinner added to todo list", text(result))
+
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Title: Plan dinner")
+	assert.True(t, strings.HasSuffix(text(result), "\nNotes:\n"+notes+"\n"))
+
+	result, err = h.SearchTodosHandler(ctx, call(map[string]interface{}{"query": "venue", "active_only": true}))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 1, Title: Plan dinner, Status: Incomplete, Notes: ## Steps ...", text(result))
+
+	contents := read("todos://1")
+	assert.Len(t, contents, 2)
+	assert.Equal(t, "application/json", contents[0].(mcp.TextResourceContents).MIMEType)
+	assert.Contains(t, contents[0].(mcp.TextResourceContents).Text, `"notes":"## Steps`)
+	assert.Equal(t, mcp.TextResourceContents{URI: "todos://1/notes", MIMEType: "text/markdown", Text: notes}, contents[1])
+	assert.Equal(t, []mcp.ResourceContents{contents[1]}, read("todos://1/notes"))
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1", "notes": "Booked"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID: 1, Title: Plan dinner, Status: Incomplete, Due Date: none\nNotes:\nBooked", text(result))
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1", "notes": ""}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID: 1, Title: Plan dinner, Status: Incomplete, Due Date: none", text(result))
+	assert.Len(t, read("todos://1"), 1, "a todo without notes has no Markdown contents")
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "999", "notes": "x"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestUpdateTodoHandler_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	project, err := storage.Projects.CreateProject(ctx, "Garden", nil)
+	assert.NoError(t, err)
+	due := time.Date(2030, time.April, 1, 0, 0, 0, 0, time.UTC)
+	item, err := storage.Todos.AddTodo(ctx, "Plant tomatos", &due)
+	assert.NoError(t, err)
+
+	result, err := h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": item.ID, "title": "Plant tomatoes", "project_id": float64(project.ID)}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID: 1, Title: Plant tomatoes, Status: Incomplete, Due Date: 2030-04-01T00:00:00Z, ProjectID: 1", text(result))
+
+	// null and empty values clear fields
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": item.ID, "due_date": nil, "project_id": 0.0}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID: 1, Title: Plant tomatoes, Status: Incomplete, Due Date: none", text(result))
+	stored, err := storage.Todos.GetTodo(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, stored.DueDate)
+	assert.Nil(t, stored.ProjectID)
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": item.ID, "category_id": 42.0}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Category not found: id 42. Use get_all_categories to find the category ID.", text(result))
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": item.ID, "title": ""}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Invalid title: title cannot be empty", text(result))
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": item.ID}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+}
+
+func TestSubtaskHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	parent, err := storage.Todos.AddTodo(ctx, "Plan trip", nil)
+	assert.NoError(t, err)
+	result, err := h.AddSubtaskHandler(ctx, call(map[string]interface{}{"parent_id": parent.ID, "title": "Book flights", "priority": "high"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Subtask added: ID=2, Title=Book flights, Parent=1", text(result))
+	_, err = h.AddSubtaskHandler(ctx, call(map[string]interface{}{"parent_id": "2", "title": "Compare prices"}))
+	assert.NoError(t, err)
+	_, err = h.AddSubtaskHandler(ctx, call(map[string]interface{}{"parent_id": parent.ID, "title": "Pack"}))
+	assert.NoError(t, err)
+	_, err = storage.Todos.CompleteTodo(ctx, "3")
+	assert.NoError(t, err)
+
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": parent.ID}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Progress: 0/2 subtasks done\n")
+	assert.True(t, strings.HasSuffix(text(result), "Subtasks:\n- [ ] ID: 2, Title: Book flights, Priority: high\n  - [x] ID: 3, Title: Compare prices\n- [ ] ID: 4, Title: Pack\n"), text(result))
+
+	result, err = h.ListTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Title: Book flights, Status: Incomplete")
+	assert.Contains(t, text(result), ", Parent: 1, Progress: 1/1 subtasks done\n")
+
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": parent.ID}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Todo has open subtasks: id 1 has 2. Complete the subtasks first, or call complete_todo with subtasks set to complete.", text(result))
+
+	result, err = h.MoveSubtaskHandler(ctx, call(map[string]interface{}{"id": parent.ID, "parent_id": "3"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Invalid parent_id: todo 3 is a subtask of todo 1", text(result))
+	result, err = h.MoveSubtaskHandler(ctx, call(map[string]interface{}{"id": "4", "parent_id": ""}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 4 is now a top-level todo", text(result))
+
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": parent.ID, "subtasks": "complete"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo Plan trip completed", text(result))
+	active, err := storage.Todos.GetActiveTodos(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, active, 1, "only the moved todo is left open")
+
+	// The handler default applies when the call does not choose
+	h.SetCompletionPolicy(todo.CompleteOpenSubtasks)
+	_, err = h.AddSubtaskHandler(ctx, call(map[string]interface{}{"parent_id": "4", "title": "Buy adapter"}))
+	assert.NoError(t, err)
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": "4"}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": "4", "subtasks": "sometimes"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Invalid subtasks: unknown subtask completion policy 'sometimes'; use block or complete", text(result))
+}
+
+func TestDependencyHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	for _, title := range []string{"Buy paint", "Sand walls", "Paint walls"} {
+		_, err := storage.Todos.AddTodo(ctx, title, nil)
+		assert.NoError(t, err)
+	}
+	result, err := h.AddBlockerHandler(ctx, call(map[string]interface{}{"todo_id": "3", "blocker_id": "1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 3 is blocked by: #1 Buy paint", text(result))
+	result, err = h.AddBlockerHandler(ctx, call(map[string]interface{}{"todo_id": "3", "blocker_id": "2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 3 is blocked by: #1 Buy paint, #2 Sand walls", text(result))
+
+	result, err = h.AddBlockerHandler(ctx, call(map[string]interface{}{"todo_id": "1", "blocker_id": "3"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "Invalid blocker_id: ")
+
+	result, err = h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Title: Paint walls, Status: Incomplete")
+	assert.Contains(t, text(result), ", Blocked by: #1, #2\n")
+	result, err = h.GetActiveTodosHandler(ctx, call(map[string]interface{}{"hide_blocked": true}))
+	assert.NoError(t, err)
+	assert.NotContains(t, text(result), "Paint walls")
+
+	result, err = h.GetNextActionsHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 1, Title: Buy paint, Due Date: none\nID: 2, Title: Sand walls, Due Date: none\n", text(result))
+
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo Buy paint completed", text(result), "todo 3 still waits on todo 2")
+	result, err = h.CompleteTodoHandler(ctx, call(map[string]interface{}{"id": "2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo Sand walls completed\nUnblocked: #3 Paint walls", text(result))
+
+	result, err = h.RemoveBlockerHandler(ctx, call(map[string]interface{}{"todo_id": "3", "blocker_id": "1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 3 is blocked by: #2 Sand walls (done)", text(result))
+	result, err = h.RemoveBlockerHandler(ctx, call(map[string]interface{}{"todo_id": "3", "blocker_id": "2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 3 has no blockers left", text(result))
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90m", 90 * time.Minute, false},
+		{"2h", 2 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"1w", 7 * 24 * time.Hour, false},
+		{"1d 12h", 36 * time.Hour, false},
+		{"2D", 48 * time.Hour, false},
+		{"", 0, true},
+		{"0d", 0, true},
+		{"soon", 0, true},
+		{"3 days", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDuration(tt.in)
+		if tt.wantErr {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		assert.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestSnoozeHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	_, err := storage.Todos.AddTodo(ctx, "Water plants", nil)
+	assert.NoError(t, err)
+	_, err = storage.Todos.AddTodo(ctx, "Renew passport", nil)
+	assert.NoError(t, err)
+
+	result, err := h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "2", "duration": "1w"}))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(text(result), "Todo 2 snoozed until "), text(result))
+	item, err := storage.Todos.GetTodo(ctx, "2")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), *item.StartDate, time.Minute)
+
+	result, err = h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Water plants")
+	assert.NotContains(t, text(result), "Renew passport")
+	result, err = h.GetActiveTodosHandler(ctx, call(map[string]interface{}{"include_deferred": true}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Deferred until: "+item.StartDate.Format(time.RFC3339)+"\n")
+	result, err = h.GetNextActionsHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 1, Title: Water plants, Due Date: none\n", text(result))
+
+	// A start date in the past brings the todo straight back
+	result, err = h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "2", "until": "2020-01-01T09:00:00Z"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo 2 snoozed until 2020-01-01T09:00:00Z", text(result))
+	result, err = h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Renew passport")
+	assert.NotContains(t, text(result), "Deferred until")
+
+	result, err = h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "2"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	result, err = h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "2", "duration": "a while"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	result, err = h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "999", "duration": "1d"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "2", "start_date": ""}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID: 2, Title: Renew passport, Status: Incomplete, Due Date: none", text(result))
+}
+
+func TestGetActiveTodosEmptyAfterFiltering_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	for _, title := range []string{"Book flights", "Pack bags"} {
+		_, err := storage.Todos.AddTodo(ctx, title, nil)
+		assert.NoError(t, err)
+	}
+	_, err := h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "1", "duration": "1w"}))
+	assert.NoError(t, err)
+	_, err = h.AddBlockerHandler(ctx, call(map[string]interface{}{"todo_id": "2", "blocker_id": "1"}))
+	assert.NoError(t, err)
+
+	result, err := h.GetActiveTodosHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Pack bags")
+	result, err = h.GetActiveTodosHandler(ctx, call(map[string]interface{}{"hide_blocked": true}))
+	assert.NoError(t, err)
+	assert.Equal(t, "No active todos found", text(result), "one todo is deferred and the other blocked")
+}
+
+func TestDateArguments_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	// Wednesday 2030-03-06 14:15
+	h.SetDateParser(dateparse.Parser{
+		Now:      func() time.Time { return time.Date(2030, time.March, 6, 14, 15, 0, 0, zone) },
+		Location: zone,
+	})
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	_, err := h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "File taxes", "due_date": "2030-11-03"}))
+	assert.NoError(t, err)
+	item, err := storage.Todos.GetTodo(ctx, "1")
+	assert.NoError(t, err)
+	assert.True(t, item.DueAllDay, "a date alone is an all-day due date")
+	assert.Equal(t, time.Date(2030, time.November, 3, 0, 0, 0, 0, time.UTC), *item.DueDate)
+
+	result, err := h.UpdateDueDateHandler(ctx, call(map[string]interface{}{"id": "1", "due_date": "next friday 5pm"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID=1, Title=File taxes, Due Date=2030-03-08T17:00:00+02:00", text(result))
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1", "start_date": "tomorrow"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Start Date: 2030-03-07T00:00:00+02:00")
+
+	_, err = h.UpdateDueDateHandler(ctx, call(map[string]interface{}{"id": "1", "due_date": "whenever"}))
+	assert.ErrorIs(t, err, dateparse.ErrInvalidDate)
+}
+
+func TestTimezone_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	h.SetTimezone(tokyo)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	// Timed due dates are stored in UTC and shown in the configured zone
+	result, err := h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Call the bank", "due_date": "2030-11-03 09:30"}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	item, err := storage.Todos.GetTodo(ctx, "1")
+	assert.NoError(t, err)
+	assert.False(t, item.DueAllDay)
+	assert.Equal(t, time.UTC, item.DueDate.Location())
+	assert.Equal(t, time.Date(2030, time.November, 3, 0, 30, 0, 0, time.UTC), *item.DueDate)
+
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1", "title": "Call the bank"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Due Date: 2030-11-03T09:30:00+09:00")
+
+	// All-day due dates keep their calendar date in every zone
+	_, err = h.AddSubtaskHandler(ctx, call(map[string]interface{}{"parent_id": "1", "title": "Find the account number", "due_date": "2030-11-02"}))
+	assert.NoError(t, err)
+	result, err = h.UpdateDueDateHandler(ctx, call(map[string]interface{}{"id": "1", "due_date": "2030-11-03"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Todo updated: ID=1, Title=Call the bank, Due Date=2030-11-03", text(result))
+	h.SetTimezone(time.FixedZone("UTC-8", -8*60*60))
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "2", "notes": "On the card"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Due Date: 2030-11-02\n")
+
+	// A timed due date replaces an all-day one
+	_, err = h.UpdateDueDateHandler(ctx, call(map[string]interface{}{"id": "1", "due_date": "2030-11-03T17:00:00Z"}))
+	assert.NoError(t, err)
+	item, err = storage.Todos.GetTodo(ctx, "1")
+	assert.NoError(t, err)
+	assert.False(t, item.DueAllDay)
+
+	// The JSON resources show times in the configured zone too
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "todos://1"
+	contents, err := h.GetSingleTodoResourceHandler(ctx, request)
+	assert.NoError(t, err)
+	assert.Contains(t, contents[0].(mcp.TextResourceContents).Text, `"due_date":"2030-11-03T09:00:00-08:00"`)
+	assert.Regexp(t, `"created_date":"[^"]+-08:00"`, contents[0].(mcp.TextResourceContents).Text)
+	request.Params.URI = "todos://all"
+	contents, err = h.ListTodosResourceHandler(ctx, request)
+	assert.NoError(t, err)
+	assert.Contains(t, contents[0].(mcp.TextResourceContents).Text, `"due_date":"2030-11-03T09:00:00-08:00"`)
+	assert.Contains(t, contents[0].(mcp.TextResourceContents).Text, `"due_date":"2030-11-02T00:00:00Z","due_all_day":true`)
+}
+
+func TestDueInfo(t *testing.T) {
+	h := NewHandlerWithServices(todo.NewMemoryStorage(nil).Services)
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	h.SetTimezone(tokyo)
+	// 2030-03-06 20:00 UTC is already March 7 in Tokyo
+	now := time.Date(2030, time.March, 6, 20, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day, hour int, loc *time.Location) *time.Time {
+		d := time.Date(year, month, day, hour, 0, 0, 0, loc)
+		return &d
+	}
+
+	tests := []struct {
+		name string
+		item todo.TodoItem
+		want string
+	}{
+		{"no due date", todo.TodoItem{}, ""},
+		{"all-day today", todo.TodoItem{DueDate: date(2030, time.March, 7, 0, time.UTC), DueAllDay: true}, ", Due today"},
+		{"all-day yesterday", todo.TodoItem{DueDate: date(2030, time.March, 6, 0, time.UTC), DueAllDay: true}, ", Overdue"},
+		{"all-day tomorrow", todo.TodoItem{DueDate: date(2030, time.March, 8, 0, time.UTC), DueAllDay: true}, ""},
+		{"later today", todo.TodoItem{DueDate: date(2030, time.March, 7, 18, tokyo)}, ", Due today"},
+		{"earlier today", todo.TodoItem{DueDate: date(2030, time.March, 7, 4, tokyo)}, ", Overdue"},
+		{"completed", todo.TodoItem{DueDate: date(2030, time.March, 1, 0, time.UTC), CompletedAt: &now}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, h.dueInfo(tt.item, now), tt.name)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "0m", formatDuration(0))
+	assert.Equal(t, "45m", formatDuration(45*time.Minute))
+	assert.Equal(t, "2h", formatDuration(2*time.Hour))
+	assert.Equal(t, "1h30m", formatDuration(90*time.Minute+20*time.Second))
+	assert.Equal(t, "26h", formatDuration(26*time.Hour))
+}
+
+func TestTimeHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+	h.SetTimezone(time.UTC)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	project, err := storage.Projects.CreateProject(ctx, "Taxes", nil)
+	assert.NoError(t, err)
+	_, err = h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "File return", "project_id": float64(project.ID)}))
+	assert.NoError(t, err)
+	_, err = h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Sort receipts"}))
+	assert.NoError(t, err)
+
+	// Estimates are set and cleared through update_todo
+	result, err := h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1", "estimate": "2h"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Estimate: 2h")
+	result, err = h.UpdateTodoHandler(ctx, call(map[string]interface{}{"id": "1", "estimate": "soon"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	// One timer runs at a time
+	result, err = h.StartTimerHandler(ctx, call(map[string]interface{}{"todo_id": "1"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Timer started on todo 1")
+	result, err = h.StartTimerHandler(ctx, call(map[string]interface{}{"todo_id": "2"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "Call stop_timer first.")
+	result, err = h.StopTimerHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Timer stopped on todo 1 after 0m")
+	result, err = h.StopTimerHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Call start_timer to start one.")
+
+	// Logged time shows up on the todo and in the report
+	result, err = h.LogTimeHandler(ctx, call(map[string]interface{}{"todo_id": "1", "duration": "1h30m", "started_at": "2020-03-02T09:00:00Z", "note": "forms"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Logged 1h30m on todo 1 from 2020-03-02T09:00:00Z (entry 2)", text(result))
+	_, err = h.LogTimeHandler(ctx, call(map[string]interface{}{"todo_id": "2", "duration": "45m", "started_at": "2020-03-03T09:00:00Z"}))
+	assert.NoError(t, err)
+	_, err = h.LogTimeHandler(ctx, call(map[string]interface{}{"todo_id": "2", "duration": "1h", "started_at": "2020-03-09T09:00:00Z"}))
+	assert.NoError(t, err)
+
+	result, err = h.GetTimeEntriesHandler(ctx, call(map[string]interface{}{"todo_id": "1"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Entry 2: 2020-03-02T09:00:00Z to 2020-03-02T10:30:00Z, 1h30m, Note: forms\n")
+	assert.Contains(t, text(result), "Total: 1h30m, Estimate: 2h\n")
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Estimate: 2h, Tracked: 1h30m")
+
+	result, err = h.TimeReportHandler(ctx, call(map[string]interface{}{"from": "2020-03-02", "to": "2020-03-03"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Time tracked from 2020-03-02T00:00:00Z to 2020-03-04T00:00:00Z: 2h15m\n"+
+		"By todo:\n- #1 File return: 1h30m, Estimate: 2h\n- #2 Sort receipts: 45m\n"+
+		"By project:\n- #1 Taxes: 1h30m\n- No project: 45m\n"+
+		"By category:\n- No category: 2h15m\n", text(result))
+	result, err = h.TimeReportHandler(ctx, call(map[string]interface{}{"from": "2020-03-03", "to": "2020-03-02"}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	result, err = h.DeleteTimeEntryHandler(ctx, call(map[string]interface{}{"id": float64(2)}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Time entry 2 deleted from todo 1", text(result))
+	result, err = h.DeleteTimeEntryHandler(ctx, call(map[string]interface{}{"id": float64(2)}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Use get_time_entries to find the entry ID.")
+}
+
+// recordingNotifier keeps the notifications sent to it while clients are
+// connected
+type recordingNotifier struct {
+	clients int
+	sent    []map[string]any
+}
+
+func (n *recordingNotifier) NotifyClients(method string, params map[string]any) int {
+	if method == reminderNotificationMethod && n.clients > 0 {
+		n.sent = append(n.sent, params)
+	}
+	return n.clients
+}
+
+func TestReminderHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+	h.SetTimezone(time.UTC)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	message := func(params map[string]any) string {
+		return params["data"].(map[string]any)["message"].(string)
+	}
+
+	_, err := h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Call the bank", "due_date": "2030-11-03T09:30:00Z"}))
+	assert.NoError(t, err)
+	_, err = h.AddTodoHandler(ctx, call(map[string]interface{}{"title": "Someday"}))
+	assert.NoError(t, err)
+
+	result, err := h.SetRemindersHandler(ctx, call(map[string]interface{}{"todo_id": "1", "offsets": []interface{}{"0", "1d", "15m"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Reminders for todo 1: 1d before, 15m before, at the due date", text(result))
+	result, err = h.GetTodoHandler(ctx, call(map[string]interface{}{"id": "1"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), ", Reminders: 1d before, 15m before, at the due date")
+	result, err = h.SetRemindersHandler(ctx, call(map[string]interface{}{"todo_id": "2", "offsets": []interface{}{"1h"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Reminders for todo 2: 1h before. They fire once the todo has a due date", text(result))
+	result, err = h.SetRemindersHandler(ctx, call(map[string]interface{}{"todo_id": "2", "offsets": []interface{}{"soon"}}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	result, err = h.SetRemindersHandler(ctx, call(map[string]interface{}{"todo_id": "2", "offsets": []interface{}{}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Reminders removed from todo 2", text(result))
+
+	// Each reminder fires once, when its time comes
+	due := time.Date(2030, time.November, 3, 9, 30, 0, 0, time.UTC)
+	notifier := &recordingNotifier{}
+	sent, err := h.SendDueReminders(ctx, notifier, due.Add(-25*time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// A reminder nobody receives stays pending until a client connects
+	sent, err = h.SendDueReminders(ctx, notifier, due.Add(-20*time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+	notifier.clients = 1
+	sent, err = h.SendDueReminders(ctx, notifier, due.Add(-20*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	if !assert.Len(t, notifier.sent, 1) {
+		return
+	}
+	assert.Equal(t, mcp.LoggingLevelInfo, notifier.sent[0]["level"])
+	assert.Equal(t, "Reminder: todo 1, Call the bank, is due in 20h (2030-11-03T09:30:00Z)", message(notifier.sent[0]))
+	assert.Equal(t, "1", notifier.sent[0]["data"].(map[string]any)["todo_id"])
+
+	sent, err = h.SendDueReminders(ctx, notifier, due.Add(-20*time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, sent, "a sent reminder does not repeat")
+
+	// Reminders missed while the server was down fire once for the todo
+	sent, err = h.SendDueReminders(ctx, notifier, due.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	if !assert.Len(t, notifier.sent, 2) {
+		return
+	}
+	assert.Equal(t, mcp.LoggingLevelWarning, notifier.sent[1]["level"])
+	assert.Equal(t, "Reminder: todo 1, Call the bank, is overdue (2030-11-03T09:30:00Z)", message(notifier.sent[1]))
+	assert.Equal(t, 0, notifier.sent[1]["data"].(map[string]any)["offset_minutes"])
+	sent, err = h.SendDueReminders(ctx, notifier, due.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, sent, "the skipped reminders count as sent")
+
+	// Moving the due date re-arms the reminders
+	_, err = h.UpdateDueDateHandler(ctx, call(map[string]interface{}{"id": "1", "due_date": "2030-11-10T09:30:00Z"}))
+	assert.NoError(t, err)
+	sent, err = h.SendDueReminders(ctx, notifier, time.Date(2030, time.November, 10, 9, 31, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, "Reminder: todo 1, Call the bank, is due now (2030-11-10T09:30:00Z)", message(notifier.sent[2]))
+}
+
+// fakeSession is a client session whose notifications go to a buffered
+// channel
+type fakeSession struct {
+	id            string
+	initialized   bool
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *fakeSession) SessionID() string { return s.id }
+func (s *fakeSession) Initialize()       { s.initialized = true }
+func (s *fakeSession) Initialized() bool { return s.initialized }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestSessions_NotifyClients(t *testing.T) {
+	ctx := context.Background()
+	hooks := &server.Hooks{}
+	sessions := NewSessions(hooks)
+	assert.Zero(t, sessions.NotifyClients("notifications/message", nil), "no client is connected")
+
+	ready := &fakeSession{id: "ready", initialized: true, notifications: make(chan mcp.JSONRPCNotification, 1)}
+	starting := &fakeSession{id: "starting", notifications: make(chan mcp.JSONRPCNotification, 1)}
+	for _, hook := range hooks.OnRegisterSession {
+		hook(ctx, ready)
+		hook(ctx, starting)
+	}
+	assert.Equal(t, 1, sessions.NotifyClients("notifications/message", map[string]any{"level": "info"}))
+	assert.Len(t, ready.notifications, 1)
+	assert.Empty(t, starting.notifications)
+	assert.Zero(t, sessions.NotifyClients("notifications/message", nil), "a full channel does not count")
+
+	for _, hook := range hooks.OnUnregisterSession {
+		hook(ctx, ready)
+	}
+	<-ready.notifications
+	assert.Zero(t, sessions.NotifyClients("notifications/message", nil))
+}
+
+func TestDeferredTodosHidden_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	project, err := storage.Projects.CreateProject(ctx, "Home", nil)
+	assert.NoError(t, err)
+	category, err := storage.Categories.CreateCategory(ctx, "Errands", nil, nil)
+	assert.NoError(t, err)
+	for _, title := range []string{"Water plants", "Renew passport"} {
+		item, err := storage.Todos.CreateTodo(ctx, todo.NewTodo{Title: title, ProjectID: &project.ID})
+		assert.NoError(t, err)
+		_, err = storage.Todos.AssignTodoToCategory(ctx, item.ID, category.ID)
+		assert.NoError(t, err)
+	}
+	_, err = h.SnoozeTodoHandler(ctx, call(map[string]interface{}{"id": "2", "duration": "1w"}))
+	assert.NoError(t, err)
+	_, err = h.CreateFilterHandler(ctx, call(map[string]interface{}{"name": "Home", "project_id": float64(project.ID)}))
+	assert.NoError(t, err)
+
+	for name, list := range map[string]func(args map[string]interface{}) (*mcp.CallToolResult, error){
+		"get_project_todos": func(args map[string]interface{}) (*mcp.CallToolResult, error) {
+			args["id"] = float64(project.ID)
+			return h.GetProjectTodosHandler(ctx, call(args))
+		},
+		"get_category_todos": func(args map[string]interface{}) (*mcp.CallToolResult, error) {
+			args["id"] = float64(category.ID)
+			return h.GetCategoryTodosHandler(ctx, call(args))
+		},
+		"run_filter": func(args map[string]interface{}) (*mcp.CallToolResult, error) {
+			args["name"] = "Home"
+			return h.RunFilterHandler(ctx, call(args))
+		},
+	} {
+		result, err := list(map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Contains(t, text(result), "Water plants", name)
+		assert.NotContains(t, text(result), "Renew passport", name)
+
+		result, err = list(map[string]interface{}{"include_deferred": true})
+		assert.NoError(t, err)
+		assert.Contains(t, text(result), "Renew passport", name)
+		assert.Contains(t, strings.ToLower(text(result)), "deferred until", name)
+	}
+
+	// A completed todo is listed whatever its start date
+	_, err = storage.Todos.CompleteTodo(ctx, "2")
+	assert.NoError(t, err)
+	result, err := h.GetProjectTodosHandler(ctx, call(map[string]interface{}{"id": float64(project.ID)}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "ID: 2, Title: Renew passport, Status: Complete, Due Date: none\n")
+}
+
+func TestSavedFilterHandlers_MemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	storage := todo.NewMemoryStorage(nil)
+	h := NewHandlerWithServices(storage.Services)
+	now := time.Date(2030, time.March, 6, 12, 0, 0, 0, time.UTC) // a Wednesday
+	h.SetDateParser(dateparse.Parser{Now: func() time.Time { return now }, Location: time.UTC})
+
+	call := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	project, err := storage.Projects.CreateProject(ctx, "Work", nil)
+	assert.NoError(t, err)
+	for _, args := range []map[string]interface{}{
+		{"title": "Write report", "project_id": float64(project.ID), "due_date": "2030-03-08", "priority": "high"},
+		{"title": "Plan offsite", "project_id": float64(project.ID), "due_date": "2030-03-20"},
+		{"title": "Renew passport", "due_date": "2030-03-01", "notes": "Bring the old one"},
+	} {
+		_, err = h.AddTodoHandler(ctx, call(args))
+		assert.NoError(t, err)
+	}
+	_, err = h.AddTagsHandler(ctx, call(map[string]interface{}{"todo_id": "1", "tags": "writing"}))
+	assert.NoError(t, err)
+
+	result, err := h.CreateFilterHandler(ctx, call(map[string]interface{}{"name": "Work this week", "project_id": float64(project.ID), "due_before": "end of week"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Filter created: ID=1, Name=Work this week, Criteria: project #1 Work, due by end of week, open", text(result))
+	_, err = h.CreateFilterHandler(ctx, call(map[string]interface{}{"name": "Overdue", "due_before": "now"}))
+	assert.NoError(t, err)
+
+	result, err = h.RunFilterHandler(ctx, call(map[string]interface{}{"name": "work this week"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "Filter Work this week matches 1 todos:\n")
+	assert.Contains(t, text(result), "ID: 1, Title: Write report, Status: Incomplete, Due Date: 2030-03-08, Priority: high, Project: #1 Work, Tags: writing\n")
+	result, err = h.RunFilterHandler(ctx, call(map[string]interface{}{"id": float64(2)}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), "ID: 3, Title: Renew passport")
+	assert.NotContains(t, text(result), "Write report")
+
+	// Only the criteria given change
+	result, err = h.UpdateFilterHandler(ctx, call(map[string]interface{}{"id": float64(1), "new_name": "Work", "due_before": "", "tags": []interface{}{"writing"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Filter updated: ID=1, Name=Work, Criteria: project #1 Work, tags writing, open", text(result))
+	result, err = h.UpdateFilterHandler(ctx, call(map[string]interface{}{"name": "Overdue", "query": "OLD ONE", "priority": "low"}))
+	assert.NoError(t, err)
+	assert.Contains(t, text(result), `due by now, priority low or higher, matching "OLD ONE", open`)
+	result, err = h.RunFilterHandler(ctx, call(map[string]interface{}{"name": "Overdue"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "No todos match filter Overdue", text(result), "the passport has no priority")
+
+	result, err = h.ListFiltersHandler(ctx, call(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID: 2, Name: Overdue, Criteria: due by now, priority low or higher, matching \"OLD ONE\", open\n"+
+		"ID: 1, Name: Work, Criteria: project #1 Work, tags writing, open\n", text(result))
+
+	// Bad input is reported as a tool error
+	for _, args := range []map[string]interface{}{
+		{"name": "Someday", "due_after": "someday"},
+		{"name": "Later", "status": "pending"},
+		{"name": "work"},
+	} {
+		result, err = h.CreateFilterHandler(ctx, call(args))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError, args)
+	}
+
+	result, err = h.DeleteFilterHandler(ctx, call(map[string]interface{}{"name": "Work"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Filter deleted: ID=1, Name=Work", text(result))
+	result, err = h.RunFilterHandler(ctx, call(map[string]interface{}{"id": float64(1)}))
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, text(result), "Use list_filters to find the filter.")
+}
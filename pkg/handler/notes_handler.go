@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SearchTodosHandler handles the search_todos MCP tool
+func (h *Handler) SearchTodosHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, ok := request.GetArguments()["query"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid query")
+	}
+	activeOnly, _ := request.GetArguments()["active_only"].(bool)
+	todos, err := h.todoService.SearchTodos(ctx, query, activeOnly)
+	if err != nil {
+		return toolError("search todos", err)
+	}
+	if len(todos) == 0 {
+		return mcp.NewToolResultText("No todos found"), nil
+	}
+	var results []string
+	for _, todo := range todos {
+		status := "Incomplete"
+		if todo.CompletedAt != nil {
+			status = "Complete"
+		}
+		results = append(results, fmt.Sprintf("ID: %s, Title: %s, Status: %s%s%s",
+			todo.ID, todo.Title, status, priorityInfo(todo), notesInfo(todo)))
+	}
+	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
+}
+
+// notesArgument reads the optional "notes" argument and reports whether it
+// was given; an empty string clears a todo's notes
+func notesArgument(request mcp.CallToolRequest) (string, bool, error) {
+	raw, ok := request.GetArguments()["notes"]
+	if !ok {
+		return "", false, nil
+	}
+	notes, ok := raw.(string)
+	if !ok {
+		return "", false, errors.New("notes must be a string")
+	}
+	return notes, true, nil
+}
+
+// notesInfo returns the ", Notes: ..." part of a one-line todo listing, with
+// the first line of the notes, or "" when item has none
+func notesInfo(item todo.TodoItem) string {
+	if item.Notes == "" {
+		return ""
+	}
+	first, _, more := strings.Cut(strings.TrimSpace(item.Notes), "\n")
+	if more {
+		first += " ..."
+	}
+	return ", Notes: " + first
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SetPriorityHandler handles the set_priority MCP tool
+func (h *Handler) SetPriorityHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid id")
+	}
+	priority, err := priorityArgument(request)
+	if err != nil {
+		return toolError("set priority", err)
+	}
+	item, err := h.todoService.SetPriority(ctx, id, priority)
+	if err != nil {
+		return toolError("set priority", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo updated: ID=%s, Title=%s, Priority=%s", item.ID, item.Title, item.Priority)), nil
+}
+
+// priorityArgument parses the "priority" argument, which is a name such as
+// "high" or P1-P4. A missing argument is PriorityNone.
+func priorityArgument(request mcp.CallToolRequest) (todo.Priority, error) {
+	raw, ok := request.GetArguments()["priority"]
+	if !ok {
+		return todo.PriorityNone, nil
+	}
+	name, ok := raw.(string)
+	if !ok {
+		return todo.PriorityNone, errors.New("priority must be a string")
+	}
+	return todo.ParsePriority(name)
+}
+
+// priorityInfo returns the ", Priority: ..." part of a todo listing, or ""
+// when item has no priority
+func priorityInfo(item todo.TodoItem) string {
+	if item.Priority == todo.PriorityNone {
+		return ""
+	}
+	return fmt.Sprintf(", Priority: %s", item.Priority)
+}
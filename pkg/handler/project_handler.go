@@ -29,9 +29,9 @@ func (h *Handler) CreateProjectHandler(ctx context.Context, request mcp.CallTool
 		return mcp.NewToolResultText(fmt.Sprintf("Project '%s' created successfully (placeholder - project service not initialized)", name)), nil
 	}
 
-	project, err := h.projectService.CreateProject(name, description)
+	project, err := h.projectService.CreateProject(ctx, name, description)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create project: %w", err)
+		return toolError("create project", err)
 	}
 
 	return mcp.NewToolResultText(fmt.Sprintf("Project created: ID=%d, Name=%s", project.ID, project.Name)), nil
@@ -43,7 +43,10 @@ func (h *Handler) GetAllProjectsHandler(ctx context.Context, request mcp.CallToo
 		return mcp.NewToolResultText("No projects found (project service not initialized)"), nil
 	}
 
-	projects := h.projectService.GetAllProjects()
+	projects, err := h.projectService.GetAllProjects(ctx)
+	if err != nil {
+		return toolError("get projects", err)
+	}
 	if len(projects) == 0 {
 		return mcp.NewToolResultText("No projects found"), nil
 	}
@@ -73,9 +76,9 @@ func (h *Handler) GetProjectHandler(ctx context.Context, request mcp.CallToolReq
 		return mcp.NewToolResultText(fmt.Sprintf("Project ID: %d (project service not initialized)", id)), nil
 	}
 
-	project, err := h.projectService.GetProject(id)
+	project, err := h.projectService.GetProject(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project: %w", err)
+		return toolError("get project", err)
 	}
 
 	description := ""
@@ -139,9 +142,9 @@ func (h *Handler) DeleteProjectHandler(ctx context.Context, request mcp.CallTool
 		return nil, fmt.Errorf("project service not initialized")
 	}
 
-	project, err := h.projectService.DeleteProject(id)
+	project, err := h.projectService.DeleteProject(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete project: %w", err)
+		return toolError("delete project", err)
 	}
 
 	return mcp.NewToolResultText(fmt.Sprintf("Project deleted: ID=%d, Name=%s", project.ID, project.Name)), nil
@@ -155,8 +158,38 @@ func (h *Handler) GetProjectTodosHandler(ctx context.Context, request mcp.CallTo
 	}
 	id := int64(idRaw)
 
-	// For now, return a placeholder message
-	return mcp.NewToolResultText(fmt.Sprintf("Todos for project %d", id)), nil
+	if h.projectService == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("No todos found for project %d (project service not initialized)", id)), nil
+	}
+
+	todos, err := h.projectService.GetProjectTodos(ctx, id)
+	if err != nil {
+		return toolError("get project todos", err)
+	}
+	now := time.Now()
+	todos = hideDeferred(request, todos, now)
+	if len(todos) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No todos found for project %d", id)), nil
+	}
+
+	// Todos come highest priority first, then soonest due
+	tags := h.allTodoTags(ctx)
+	progress := h.subtaskProgress(ctx)
+	var resultText string
+	for _, todo := range todos {
+		status := "Incomplete"
+		if todo.CompletedAt != nil {
+			status = "Complete"
+		}
+		dueDate := "none"
+		if todo.DueDate != nil {
+			dueDate = h.formatDueDate(todo)
+		}
+		resultText += fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s%s%s%s%s\n",
+			todo.ID, todo.Title, status, dueDate, priorityInfo(todo), tagInfo(todo, tags), subtaskInfo(todo, progress), h.deferredInfo(todo, now))
+	}
+
+	return mcp.NewToolResultText(resultText), nil
 }
 
 // AddTodoToProjectHandler handles the add_todo_to_project MCP tool
@@ -179,7 +212,7 @@ func (h *Handler) AddTodoToProjectHandler(ctx context.Context, request mcp.CallT
 		if !ok {
 			return nil, fmt.Errorf("due_date must be a string")
 		}
-		parsedDueDate, err := time.Parse(time.RFC3339, dueDateStr)
+		parsedDueDate, err := h.parseDate(dueDateStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse due_date: %w", err)
 		}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ListRecurrencePatternsHandler handles the list_recurrence_patterns MCP tool
+func (h *Handler) ListRecurrencePatternsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var patterns []todo.RecurrencePattern
+	var err error
+	todoID, _ := request.GetArguments()["todo_id"].(string)
+	if todoID != "" {
+		patterns, err = h.todoService.GetRecurrencePatternsForTodo(ctx, todoID)
+	} else {
+		patterns, err = h.todoService.ListRecurrencePatterns(ctx)
+	}
+	if err != nil {
+		return toolError("list recurrence patterns", err)
+	}
+	if len(patterns) == 0 {
+		if todoID != "" {
+			return mcp.NewToolResultText(fmt.Sprintf("Todo %s does not recur", todoID)), nil
+		}
+		return mcp.NewToolResultText("No recurrence patterns found"), nil
+	}
+
+	var lines []string
+	for _, pattern := range patterns {
+		lines = append(lines, formatRecurrencePattern(pattern))
+	}
+	if todoID != "" && len(patterns) > 1 {
+		lines = append(lines, fmt.Sprintf("The series follows the most recent pattern, ID %d", patterns[len(patterns)-1].ID))
+	}
+	return mcp.NewToolResultText(strings.Join(lines, "\n")), nil
+}
+
+// UpdateRecurrencePatternHandler handles the update_recurrence_pattern MCP
+// tool. Arguments that are left out keep their current value.
+func (h *Handler) UpdateRecurrencePatternHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := recurrencePatternID(request)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := h.todoService.GetRecurrencePatternByID(ctx, id)
+	if err != nil {
+		return toolError("update recurrence pattern", err)
+	}
+
+	args := request.GetArguments()
+	if rruleRaw, ok := args["rrule"]; ok {
+		rrule, ok := rruleRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid rrule")
+		}
+		pattern.RRule = rrule
+	}
+	if frequencyRaw, ok := args["frequency"]; ok {
+		frequency, ok := frequencyRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid frequency")
+		}
+		pattern.Frequency = frequency
+		if _, ok := args["rrule"]; !ok {
+			pattern.RRule = ""
+		}
+	}
+	if intervalRaw, ok := args["interval"]; ok {
+		interval, ok := intervalRaw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid interval")
+		}
+		pattern.Interval = int(interval)
+		if _, ok := args["rrule"]; !ok {
+			pattern.RRule = ""
+		}
+	}
+	_, hasUntil := args["until"]
+	_, hasCount := args["count"]
+	if pattern.RRule != "" && (hasUntil || hasCount) {
+		return mcp.NewToolResultError("Invalid rrule: the pattern is an RRULE, so change UNTIL or COUNT inside the rrule instead"), nil
+	}
+	if hasUntil {
+		untilStr, ok := args["until"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid until")
+		}
+		pattern.Until = nil
+		if untilStr != "" {
+			until, err := h.parseDate(untilStr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse until: %w", err)
+			}
+			pattern.Until = &until
+		}
+	}
+	if hasCount {
+		countVal, ok := args["count"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid count")
+		}
+		pattern.Count = nil
+		if countVal != 0 {
+			count := int(countVal)
+			pattern.Count = &count
+		}
+	}
+
+	updated, err := h.todoService.UpdateRecurrencePattern(ctx, pattern)
+	if err != nil {
+		return toolError("update recurrence pattern", err)
+	}
+	return mcp.NewToolResultText("Recurrence pattern updated: " + formatRecurrencePattern(updated)), nil
+}
+
+// DeleteRecurrencePatternHandler handles the delete_recurrence_pattern MCP tool
+func (h *Handler) DeleteRecurrencePatternHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := recurrencePatternID(request)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := h.todoService.DeleteRecurrencePattern(ctx, id)
+	if err != nil {
+		return toolError("delete recurrence pattern", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted recurrence pattern: ID=%d, TodoID=%s. Todos already created are kept, but no new occurrences will be", pattern.ID, pattern.TodoID)), nil
+}
+
+// PauseRecurrencePatternHandler handles the pause_recurrence_pattern MCP tool
+func (h *Handler) PauseRecurrencePatternHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := recurrencePatternID(request)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := h.todoService.PauseRecurrencePattern(ctx, id)
+	if err != nil {
+		return toolError("pause recurrence pattern", err)
+	}
+	return mcp.NewToolResultText("Recurrence pattern paused: " + formatRecurrencePattern(pattern)), nil
+}
+
+// ResumeRecurrencePatternHandler handles the resume_recurrence_pattern MCP tool
+func (h *Handler) ResumeRecurrencePatternHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := recurrencePatternID(request)
+	if err != nil {
+		return nil, err
+	}
+	pattern, next, err := h.todoService.ResumeRecurrencePattern(ctx, id)
+	if err != nil {
+		return toolError("resume recurrence pattern", err)
+	}
+	resultText := "Recurrence pattern resumed: " + formatRecurrencePattern(pattern)
+	if next != nil && next.DueDate != nil {
+		resultText += fmt.Sprintf("\nNext occurrence created: ID=%s, Title=%s, Due Date=%s",
+			next.ID, next.Title, h.formatDueDate(*next))
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+func recurrencePatternID(request mcp.CallToolRequest) (int64, error) {
+	idRaw, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid id")
+	}
+	return int64(idRaw), nil
+}
+
+// formatRecurrencePattern describes a pattern on one line for tool results
+func formatRecurrencePattern(pattern todo.RecurrencePattern) string {
+	text := fmt.Sprintf("ID: %d, TodoID: %s, Repeats: %s", pattern.ID, pattern.TodoID, pattern.Summary())
+	if pattern.RRule != "" {
+		text += ", RRULE: " + pattern.RRule
+	}
+	return text
+}
+
+// seriesPatterns maps the first todo of each recurring series to the pattern
+// it follows. Like the project and category lookups in listings, a failed
+// lookup just leaves the recurrence out.
+func seriesPatterns(patterns []todo.RecurrencePattern, err error) map[string]todo.RecurrencePattern {
+	if err != nil {
+		return nil
+	}
+	bySeries := make(map[string]todo.RecurrencePattern, len(patterns))
+	// Patterns come oldest first and the most recent one is in effect
+	for _, pattern := range patterns {
+		bySeries[pattern.TodoID] = pattern
+	}
+	return bySeries
+}
+
+// recurrenceInfo returns the ", Repeats: ..." part of a todo listing, or ""
+// when item does not recur
+func recurrenceInfo(item todo.TodoItem, patterns map[string]todo.RecurrencePattern) string {
+	root := item.ID
+	if item.ReferenceID != nil {
+		root = strconv.FormatInt(*item.ReferenceID, 10)
+	}
+	pattern, ok := patterns[root]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(", Repeats: %s, PatternID: %d", pattern.Summary(), pattern.ID)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errReminderServiceNotInitialized is returned by set_reminders and the
+// scheduler when the handler was built without a ReminderService
+var errReminderServiceNotInitialized = errors.New("reminder service not initialized")
+
+// reminderNotificationMethod is the MCP logging notification reminders are
+// sent as, so clients show them without knowing about this server
+const reminderNotificationMethod = "notifications/message"
+
+// reminderGrace is how late a reminder at the due date may fire and still
+// say the todo is due now rather than overdue
+const reminderGrace = 5 * time.Minute
+
+// Notifier sends a notification to every connected client and returns how
+// many received it; *Sessions is one
+type Notifier interface {
+	NotifyClients(method string, params map[string]any) int
+}
+
+// SetRemindersHandler handles the set_reminders MCP tool. Each offset is how
+// long before the due date a reminder fires, with 0 at the due date; no
+// offsets removes the todo's reminders.
+func (h *Handler) SetRemindersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.reminderService == nil {
+		return nil, errReminderServiceNotInitialized
+	}
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	rawOffsets, ok := request.GetArguments()["offsets"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("offsets must be an array")
+	}
+	offsets := make([]int, 0, len(rawOffsets))
+	for _, raw := range rawOffsets {
+		offsetStr, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("offsets must be strings")
+		}
+		offset, err := parseReminderOffset(offsetStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid offsets: %v", err)), nil
+		}
+		offsets = append(offsets, offset)
+	}
+
+	reminders, err := h.reminderService.SetReminders(ctx, todoID, offsets)
+	if err != nil {
+		return toolError("set reminders", err)
+	}
+	if len(reminders) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Reminders removed from todo %s", todoID)), nil
+	}
+	resultText := fmt.Sprintf("Reminders for todo %s: %s", todoID, formatReminders(reminders))
+	if item, err := h.todoService.GetTodo(ctx, todoID); err == nil && item.DueDate == nil {
+		resultText += ". They fire once the todo has a due date"
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// parseReminderOffset parses a set_reminders offset: 0 for the due date, or
+// a duration such as 15m, 1h or 1d before it
+func parseReminderOffset(s string) (int, error) {
+	if strings.TrimSpace(s) == "0" {
+		return 0, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return estimateMinutes(d), nil
+}
+
+// formatReminders lists reminders such as "1d before, 15m before, at the due
+// date"
+func formatReminders(reminders []todo.Reminder) string {
+	parts := make([]string, 0, len(reminders))
+	for _, reminder := range reminders {
+		if reminder.Offset == 0 {
+			parts = append(parts, "at the due date")
+			continue
+		}
+		parts = append(parts, formatOffset(reminder.Offset)+" before")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatOffset shows a reminder offset in minutes, using days when it is a
+// whole number of them
+func formatOffset(minutes int) string {
+	if minutes%(24*60) == 0 {
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	}
+	return formatDuration(time.Duration(minutes) * time.Minute)
+}
+
+// reminderInfo returns the ", Reminders: ..." part of a todo listing, or ""
+// when the todo has none or they cannot be looked up
+func (h *Handler) reminderInfo(ctx context.Context, item todo.TodoItem) string {
+	if h.reminderService == nil {
+		return ""
+	}
+	reminders, err := h.reminderService.GetReminders(ctx, item.ID)
+	if err != nil || len(reminders) == 0 {
+		return ""
+	}
+	return ", Reminders: " + formatReminders(reminders)
+}
+
+// RunReminders sends due reminders every interval until ctx is done. The
+// first check runs right away, so reminders that came due while the server
+// was down are sent on startup, or once a client connects.
+func (h *Handler) RunReminders(ctx context.Context, notifier Notifier, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := h.SendDueReminders(ctx, notifier, time.Now()); err != nil {
+			log.Printf("Sending reminders failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDueReminders notifies every client of the reminders that fire by now
+// and marks them sent, so each fires once per due date. A todo with several
+// reminders due at once, such as after the server was down, gets one
+// notification for the latest of them. Reminders stay pending while no client
+// receives them. It returns how many notifications were sent.
+func (h *Handler) SendDueReminders(ctx context.Context, notifier Notifier, now time.Time) (int, error) {
+	if h.reminderService == nil {
+		return 0, errReminderServiceNotInitialized
+	}
+	pending, err := h.reminderService.GetPendingReminders(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var todoIDs []string
+	due := make(map[string][]todo.PendingReminder)
+	for _, reminder := range pending {
+		if reminder.FiresAt(h.timezone()).After(now) {
+			continue
+		}
+		if _, ok := due[reminder.TodoID]; !ok {
+			todoIDs = append(todoIDs, reminder.TodoID)
+		}
+		due[reminder.TodoID] = append(due[reminder.TodoID], reminder)
+	}
+
+	sent := 0
+	for _, todoID := range todoIDs {
+		reminders := due[todoID]
+		latest := reminders[0]
+		for _, reminder := range reminders[1:] {
+			if reminder.FiresAt(h.timezone()).After(latest.FiresAt(h.timezone())) {
+				latest = reminder
+			}
+		}
+		level, message := h.reminderMessage(latest, now)
+		reached := notifier.NotifyClients(reminderNotificationMethod, map[string]any{
+			"level":  level,
+			"logger": "reminders",
+			"data": map[string]any{
+				"message":        message,
+				"todo_id":        latest.TodoID,
+				"title":          latest.Todo.Title,
+				"due_date":       h.formatDueDate(latest.Todo),
+				"offset_minutes": latest.Offset,
+			},
+		})
+		if reached == 0 {
+			continue
+		}
+		for _, reminder := range reminders {
+			if err := h.reminderService.MarkReminderSent(ctx, reminder.ID, *reminder.Todo.DueDate); err != nil {
+				return sent, err
+			}
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// reminderMessage describes a reminder that fires at now, with its logging
+// level: a warning once the todo is overdue
+func (h *Handler) reminderMessage(reminder todo.PendingReminder, now time.Time) (mcp.LoggingLevel, string) {
+	due := reminder.FiresAt(h.timezone()).Add(time.Duration(reminder.Offset) * time.Minute)
+	prefix := fmt.Sprintf("Reminder: todo %s, %s,", reminder.TodoID, reminder.Todo.Title)
+	switch {
+	case now.Before(due):
+		return mcp.LoggingLevelInfo, fmt.Sprintf("%s is due in %s (%s)", prefix, formatDuration(due.Sub(now)), h.formatDueDate(reminder.Todo))
+	case reminder.Todo.DueAllDay && now.Before(due.AddDate(0, 0, 1)):
+		return mcp.LoggingLevelInfo, fmt.Sprintf("%s is due today (%s)", prefix, h.formatDueDate(reminder.Todo))
+	case !reminder.Todo.DueAllDay && now.Sub(due) < reminderGrace:
+		return mcp.LoggingLevelInfo, fmt.Sprintf("%s is due now (%s)", prefix, h.formatDueDate(reminder.Todo))
+	}
+	return mcp.LoggingLevelWarning, fmt.Sprintf("%s is overdue (%s)", prefix, h.formatDueDate(reminder.Todo))
+}
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errSavedFilterServiceNotInitialized is returned by the filter tools when the
+// handler was built without a SavedFilterService
+var errSavedFilterServiceNotInitialized = errors.New("saved filter service not initialized")
+
+// CreateFilterHandler handles the create_filter MCP tool
+func (h *Handler) CreateFilterHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.savedFilterService == nil {
+		return nil, errSavedFilterServiceNotInitialized
+	}
+	name, ok := request.GetArguments()["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid name")
+	}
+	criteria, err := h.filterArguments(request, todo.FilterCriteria{})
+	if err != nil {
+		return toolError("create filter", err)
+	}
+
+	filter, err := h.savedFilterService.CreateFilter(ctx, name, criteria)
+	if err != nil {
+		return toolError("create filter", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Filter created: ID=%d, Name=%s, Criteria: %s",
+		filter.ID, filter.Name, h.describeCriteria(ctx, filter.Criteria))), nil
+}
+
+// ListFiltersHandler handles the list_filters MCP tool
+func (h *Handler) ListFiltersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.savedFilterService == nil {
+		return nil, errSavedFilterServiceNotInitialized
+	}
+	filters, err := h.savedFilterService.ListFilters(ctx)
+	if err != nil {
+		return toolError("list filters", err)
+	}
+	if len(filters) == 0 {
+		return mcp.NewToolResultText("No saved filters found"), nil
+	}
+	var resultText string
+	for _, filter := range filters {
+		resultText += fmt.Sprintf("ID: %d, Name: %s, Criteria: %s\n", filter.ID, filter.Name, h.describeCriteria(ctx, filter.Criteria))
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// RunFilterHandler handles the run_filter MCP tool. The filter's due bounds
+// are resolved now, so "Work this week" always means the current week.
+func (h *Handler) RunFilterHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.savedFilterService == nil {
+		return nil, errSavedFilterServiceNotInitialized
+	}
+	filter, err := h.filterArgument(ctx, request)
+	if err != nil {
+		return toolError("run filter", err)
+	}
+	due, err := h.dueRange(filter.Criteria)
+	if err != nil {
+		return toolError("run filter", err)
+	}
+	todos, err := h.todoService.GetAllTodos(ctx)
+	if err != nil {
+		return toolError("run filter", err)
+	}
+
+	tags := h.allTodoTags(ctx)
+	now := time.Now()
+	matched := hideDeferred(request, todo.FilterTodos(todos, tags, filter.Criteria, due, h.timezone()), now)
+	if len(matched) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No todos match filter %s", filter.Name)), nil
+	}
+	projects := h.projectNames(ctx)
+	categories := h.categoryNames(ctx)
+	resultText := fmt.Sprintf("Filter %s matches %d todos:\n", filter.Name, len(matched))
+	for _, item := range matched {
+		status := "Incomplete"
+		if item.CompletedAt != nil {
+			status = "Complete"
+		}
+		groupInfo := ""
+		if item.ProjectID != nil {
+			groupInfo += ", Project: " + groupName(item.ProjectID, projects, "")
+		}
+		if item.CategoryID != nil {
+			groupInfo += ", Category: " + groupName(item.CategoryID, categories, "")
+		}
+		resultText += fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s%s%s%s%s%s\n",
+			item.ID, item.Title, status, h.formatDueDate(item), priorityInfo(item), groupInfo, tagInfo(item, tags), h.deferredInfo(item, now), h.dueInfo(item, now))
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// UpdateFilterHandler handles the update_filter MCP tool. Only the criteria
+// given are changed; an empty value or 0 clears one.
+func (h *Handler) UpdateFilterHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.savedFilterService == nil {
+		return nil, errSavedFilterServiceNotInitialized
+	}
+	filter, err := h.filterArgument(ctx, request)
+	if err != nil {
+		return toolError("update filter", err)
+	}
+	name := filter.Name
+	if newName, ok := request.GetArguments()["new_name"].(string); ok {
+		name = newName
+	}
+	criteria, err := h.filterArguments(request, filter.Criteria)
+	if err != nil {
+		return toolError("update filter", err)
+	}
+
+	filter, err = h.savedFilterService.UpdateFilter(ctx, filter.ID, name, criteria)
+	if err != nil {
+		return toolError("update filter", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Filter updated: ID=%d, Name=%s, Criteria: %s",
+		filter.ID, filter.Name, h.describeCriteria(ctx, filter.Criteria))), nil
+}
+
+// DeleteFilterHandler handles the delete_filter MCP tool. The todos the
+// filter matched are left alone.
+func (h *Handler) DeleteFilterHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.savedFilterService == nil {
+		return nil, errSavedFilterServiceNotInitialized
+	}
+	filter, err := h.filterArgument(ctx, request)
+	if err != nil {
+		return toolError("delete filter", err)
+	}
+	filter, err = h.savedFilterService.DeleteFilter(ctx, filter.ID)
+	if err != nil {
+		return toolError("delete filter", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Filter deleted: ID=%d, Name=%s", filter.ID, filter.Name)), nil
+}
+
+// filterArgument looks up the filter named by the "id" argument or, failing
+// that, the "name" argument
+func (h *Handler) filterArgument(ctx context.Context, request mcp.CallToolRequest) (todo.SavedFilter, error) {
+	args := request.GetArguments()
+	if raw, ok := args["id"]; ok {
+		id, ok := raw.(float64)
+		if !ok {
+			return todo.SavedFilter{}, errors.New("id must be a number")
+		}
+		return h.savedFilterService.GetFilter(ctx, int64(id))
+	}
+	name, ok := args["name"].(string)
+	if !ok {
+		return todo.SavedFilter{}, errors.New("id or name is required")
+	}
+	return h.savedFilterService.GetFilterByName(ctx, name)
+}
+
+// filterArguments reads the criteria arguments over base, so that a missing
+// argument keeps base's value. The due bounds are parsed once here to reject
+// expressions that could never run.
+func (h *Handler) filterArguments(request mcp.CallToolRequest, base todo.FilterCriteria) (todo.FilterCriteria, error) {
+	args := request.GetArguments()
+	criteria := base
+
+	for _, name := range []string{"project_id", "category_id"} {
+		id, clear, err := optionalIDArgument(args, name)
+		if err != nil {
+			return todo.FilterCriteria{}, err
+		}
+		if id == nil && !clear {
+			continue
+		}
+		if name == "project_id" {
+			criteria.ProjectID = id
+		} else {
+			criteria.CategoryID = id
+		}
+	}
+
+	if _, ok := args["tags"]; ok {
+		names, err := tagsArgument(request)
+		if err != nil {
+			return todo.FilterCriteria{}, err
+		}
+		criteria.Tags = nil
+		for _, name := range names {
+			if strings.TrimSpace(name) != "" {
+				criteria.Tags = append(criteria.Tags, name)
+			}
+		}
+	}
+
+	for _, name := range []string{"due_after", "due_before"} {
+		raw, ok := args[name]
+		if !ok {
+			continue
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return todo.FilterCriteria{}, fmt.Errorf("%s must be a string", name)
+		}
+		if strings.TrimSpace(value) != "" {
+			if _, err := h.parseDueDate(value); err != nil {
+				return todo.FilterCriteria{}, fmt.Errorf("failed to parse %s: %w", name, err)
+			}
+		}
+		if name == "due_after" {
+			criteria.DueAfter = value
+		} else {
+			criteria.DueBefore = value
+		}
+	}
+
+	if _, ok := args["priority"]; ok {
+		priority, err := priorityArgument(request)
+		if err != nil {
+			return todo.FilterCriteria{}, err
+		}
+		criteria.MinPriority = nil
+		if priority != todo.PriorityNone {
+			criteria.MinPriority = &priority
+		}
+	}
+
+	if raw, ok := args["status"]; ok {
+		status, ok := raw.(string)
+		if !ok {
+			return todo.FilterCriteria{}, errors.New("status must be a string")
+		}
+		criteria.Status = todo.FilterStatus(strings.ToLower(strings.TrimSpace(status)))
+	}
+
+	if raw, ok := args["query"]; ok {
+		query, ok := raw.(string)
+		if !ok {
+			return todo.FilterCriteria{}, errors.New("query must be a string")
+		}
+		criteria.Query = query
+	}
+	return criteria, nil
+}
+
+// dueRange resolves criteria's due bounds against now. A bound that names a
+// day covers all of it: due_after from its start, due_before to its end.
+func (h *Handler) dueRange(criteria todo.FilterCriteria) (todo.DueRange, error) {
+	var due todo.DueRange
+	if criteria.DueAfter != "" {
+		after, err := h.parseDueDate(criteria.DueAfter)
+		if err != nil {
+			return todo.DueRange{}, fmt.Errorf("failed to parse due_after: %w", err)
+		}
+		due.After = &after.Time
+	}
+	if criteria.DueBefore != "" {
+		before, err := h.parseDueDate(criteria.DueBefore)
+		if err != nil {
+			return todo.DueRange{}, fmt.Errorf("failed to parse due_before: %w", err)
+		}
+		end := before.Time
+		if before.AllDay {
+			end = end.AddDate(0, 0, 1)
+		}
+		due.Before = &end
+	}
+	return due, nil
+}
+
+// describeCriteria lists a filter's criteria, such as "project #1 Work, tags
+// work, due before end of week, open"
+func (h *Handler) describeCriteria(ctx context.Context, criteria todo.FilterCriteria) string {
+	var parts []string
+	if criteria.ProjectID != nil {
+		parts = append(parts, "project "+groupName(criteria.ProjectID, h.projectNames(ctx), ""))
+	}
+	if criteria.CategoryID != nil {
+		parts = append(parts, "category "+groupName(criteria.CategoryID, h.categoryNames(ctx), ""))
+	}
+	if len(criteria.Tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(criteria.Tags, " and "))
+	}
+	if criteria.DueAfter != "" {
+		parts = append(parts, "due from "+criteria.DueAfter)
+	}
+	if criteria.DueBefore != "" {
+		parts = append(parts, "due by "+criteria.DueBefore)
+	}
+	if criteria.MinPriority != nil {
+		parts = append(parts, fmt.Sprintf("priority %s or higher", *criteria.MinPriority))
+	}
+	if criteria.Query != "" {
+		parts = append(parts, fmt.Sprintf("matching %q", criteria.Query))
+	}
+	status := criteria.Status
+	if status == "" {
+		status = todo.FilterStatusOpen
+	}
+	parts = append(parts, string(status))
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Sessions keeps track of the connected clients so that notifications can
+// tell whether anyone received them. It learns of clients through the
+// server's session hooks.
+type Sessions struct {
+	mu       sync.Mutex
+	sessions map[string]server.ClientSession
+}
+
+// NewSessions returns a Sessions that hooks adds and removes clients from
+func NewSessions(hooks *server.Hooks) *Sessions {
+	s := &Sessions{sessions: make(map[string]server.ClientSession)}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.sessions[session.SessionID()] = session
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.sessions, session.SessionID())
+	})
+	return s
+}
+
+// NotifyClients sends a notification to every initialized client whose
+// notification channel has room, and returns how many it reached
+func (s *Sessions) NotifyClients(method string, params map[string]any) int {
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+			Params: mcp.NotificationParams{AdditionalFields: params},
+		},
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reached := 0
+	for _, session := range s.sessions {
+		if !session.Initialized() {
+			continue
+		}
+		select {
+		case session.NotificationChannel() <- notification:
+			reached++
+		default:
+		}
+	}
+	return reached
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SnoozeTodoHandler handles the snooze_todo MCP tool. It defers a todo for a
+// duration from now or until a given time.
+func (h *Handler) SnoozeTodoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid id")
+	}
+	durationStr, _ := request.GetArguments()["duration"].(string)
+	untilStr, _ := request.GetArguments()["until"].(string)
+	if (durationStr == "") == (untilStr == "") {
+		return mcp.NewToolResultError("Give either duration or until"), nil
+	}
+
+	var until time.Time
+	if durationStr != "" {
+		duration, err := parseDuration(durationStr)
+		if err != nil {
+			return toolError("snooze todo", err)
+		}
+		until = time.Now().Add(duration)
+	} else {
+		parsed, err := h.parseDate(untilStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse until: %w", err)
+		}
+		until = parsed
+	}
+
+	item, err := h.todoService.UpdateTodo(ctx, id, todo.TodoPatch{StartDate: &until})
+	if err != nil {
+		return toolError("snooze todo", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo %s snoozed until %s", item.ID, h.formatTime(*item.StartDate))), nil
+}
+
+var durationUnits = map[string]time.Duration{
+	"w": 7 * 24 * time.Hour,
+	"d": 24 * time.Hour,
+	"h": time.Hour,
+	"m": time.Minute,
+}
+
+var durationPart = regexp.MustCompile(`^(\d+)([wdhm])`)
+
+// parseDuration parses durations such as 90m, 2h, 3d, 1w or 1d12h, as
+// given to snooze_todo, log_time and estimates
+func parseDuration(s string) (time.Duration, error) {
+	rest := strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	if rest == "" {
+		return 0, errors.New("duration cannot be empty")
+	}
+	var total time.Duration
+	for rest != "" {
+		match := durationPart.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("duration '%s' must look like 90m, 2h, 3d or 1w", s)
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, fmt.Errorf("duration '%s' is too long", s)
+		}
+		total += time.Duration(n) * durationUnits[match[2]]
+		rest = rest[len(match[0]):]
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("duration '%s' must be positive", s)
+	}
+	return total, nil
+}
+
+// isDeferred reports whether item is open and its start date is still to come
+func isDeferred(item todo.TodoItem, now time.Time) bool {
+	return item.CompletedAt == nil && item.StartDate != nil && item.StartDate.After(now)
+}
+
+// hideDeferred leaves the deferred todos out of todos unless the call sets
+// include_deferred. Deferred todos come back by themselves once their start
+// date passes.
+func hideDeferred(request mcp.CallToolRequest, todos []todo.TodoItem, now time.Time) []todo.TodoItem {
+	if includeDeferred, _ := request.GetArguments()["include_deferred"].(bool); includeDeferred {
+		return todos
+	}
+	var shown []todo.TodoItem
+	for _, item := range todos {
+		if !isDeferred(item, now) {
+			shown = append(shown, item)
+		}
+	}
+	return shown
+}
+
+// deferredInfo returns the ", Deferred until: ..." part of a todo listing, or
+// "" when item is not deferred
+func (h *Handler) deferredInfo(item todo.TodoItem, now time.Time) string {
+	if !isDeferred(item, now) {
+		return ""
+	}
+	return ", Deferred until: " + h.formatTime(*item.StartDate)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/dateparse"
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SetCompletionPolicy sets what complete_todo does with open subtasks when the
+// call does not say. The default is todo.BlockOpenSubtasks.
+func (h *Handler) SetCompletionPolicy(policy todo.CompletionPolicy) {
+	h.completionPolicy = policy
+}
+
+// AddSubtaskHandler handles the add_subtask MCP tool
+func (h *Handler) AddSubtaskHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	parentID, ok := request.GetArguments()["parent_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid parent_id")
+	}
+	title, ok := request.GetArguments()["title"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid title")
+	}
+	var dueDate *time.Time
+	var parsedDueDate dateparse.Date
+	if dueDateStr, ok := request.GetArguments()["due_date"].(string); ok && dueDateStr != "" {
+		var err error
+		parsedDueDate, err = h.parseDueDate(dueDateStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse due date: %w", err)
+		}
+		dueDate = &parsedDueDate.Time
+	}
+	// Parse the priority first so a bad one adds nothing
+	priority, err := priorityArgument(request)
+	if err != nil {
+		return toolError("add subtask", err)
+	}
+
+	item, err := h.todoService.CreateTodo(ctx, todo.NewTodo{
+		Title:     title,
+		DueDate:   dueDate,
+		DueAllDay: parsedDueDate.AllDay,
+		ParentID:  &parentID,
+		Priority:  priority,
+	})
+	if err != nil {
+		return toolError("add subtask", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Subtask added: ID=%s, Title=%s, Parent=%s", item.ID, item.Title, parentID)), nil
+}
+
+// MoveSubtaskHandler handles the move_subtask MCP tool. An empty or null
+// parent_id makes the todo a top-level todo.
+func (h *Handler) MoveSubtaskHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid id")
+	}
+	var parentID *string
+	switch raw := request.GetArguments()["parent_id"].(type) {
+	case nil:
+	case string:
+		if raw != "" {
+			parentID = &raw
+		}
+	default:
+		return nil, fmt.Errorf("invalid parent_id")
+	}
+
+	item, err := h.todoService.MoveTodo(ctx, id, parentID)
+	if err != nil {
+		return toolError("move subtask", err)
+	}
+	if item.ParentID == nil {
+		return mcp.NewToolResultText(fmt.Sprintf("Todo %s is now a top-level todo", item.ID)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo %s moved under todo %d", item.ID, *item.ParentID)), nil
+}
+
+// completionPolicyArgument reads the optional "subtasks" argument of
+// complete_todo, falling back to the handler's policy
+func (h *Handler) completionPolicyArgument(request mcp.CallToolRequest) (todo.CompletionPolicy, error) {
+	raw, ok := request.GetArguments()["subtasks"]
+	if !ok || raw == nil {
+		return h.completionPolicy, nil
+	}
+	name, ok := raw.(string)
+	if !ok {
+		return h.completionPolicy, errors.New("subtasks must be a string")
+	}
+	if name == "" {
+		return h.completionPolicy, nil
+	}
+	return todo.ParseCompletionPolicy(name)
+}
+
+// subtaskProgress returns the subtask counts of every todo with subtasks.
+// Like the tag lookup in listings, a failed lookup just leaves progress out.
+func (h *Handler) subtaskProgress(ctx context.Context) map[string]todo.SubtaskProgress {
+	progress, err := h.todoService.GetSubtaskProgress(ctx)
+	if err != nil {
+		return nil
+	}
+	return progress
+}
+
+// subtaskInfo returns the ", Parent: ..." and ", Progress: ..." parts of a
+// todo listing, or "" for a top-level todo without subtasks
+func subtaskInfo(item todo.TodoItem, progress map[string]todo.SubtaskProgress) string {
+	info := ""
+	if item.ParentID != nil {
+		info += fmt.Sprintf(", Parent: %d", *item.ParentID)
+	}
+	if p, ok := progress[item.ID]; ok {
+		info += ", Progress: " + p.String()
+	}
+	return info
+}
+
+// subtaskTree lists the subtasks below parentID, one per line and indented
+// by depth, or returns "" when it has none. A failed lookup leaves that
+// branch out.
+func (h *Handler) subtaskTree(ctx context.Context, parentID string, depth int) string {
+	subtasks, err := h.todoService.GetSubtasks(ctx, parentID)
+	if err != nil {
+		return ""
+	}
+	var tree strings.Builder
+	for _, item := range subtasks {
+		check := " "
+		if item.CompletedAt != nil {
+			check = "x"
+		}
+		fmt.Fprintf(&tree, "%s- [%s] ID: %s, Title: %s%s\n", strings.Repeat("  ", depth), check, item.ID, item.Title, priorityInfo(item))
+		tree.WriteString(h.subtaskTree(ctx, item.ID, depth+1))
+	}
+	return tree.String()
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errTagServiceNotInitialized is returned by the tag tools when the handler was
+// built without a TagService
+var errTagServiceNotInitialized = errors.New("tag service not initialized")
+
+// AddTagsHandler handles the add_tags MCP tool
+func (h *Handler) AddTagsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.tagService == nil {
+		return nil, errTagServiceNotInitialized
+	}
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	names, err := tagsArgument(request)
+	if err != nil {
+		return toolError("add tags", err)
+	}
+	tags, err := h.tagService.AddTags(ctx, todoID, names)
+	if err != nil {
+		return toolError("add tags", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo %s tagged: %s", todoID, joinTagNames(tags))), nil
+}
+
+// RemoveTagsHandler handles the remove_tags MCP tool
+func (h *Handler) RemoveTagsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.tagService == nil {
+		return nil, errTagServiceNotInitialized
+	}
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	names, err := tagsArgument(request)
+	if err != nil {
+		return toolError("remove tags", err)
+	}
+	tags, err := h.tagService.RemoveTags(ctx, todoID, names)
+	if err != nil {
+		return toolError("remove tags", err)
+	}
+	if len(tags) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Todo %s has no tags left", todoID)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Todo %s tags: %s", todoID, joinTagNames(tags))), nil
+}
+
+// ListTagsHandler handles the list_tags MCP tool
+func (h *Handler) ListTagsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.tagService == nil {
+		return nil, errTagServiceNotInitialized
+	}
+	tags, err := h.tagService.ListTags(ctx)
+	if err != nil {
+		return toolError("list tags", err)
+	}
+	if len(tags) == 0 {
+		return mcp.NewToolResultText("No tags found"), nil
+	}
+	var resultText string
+	for _, tag := range tags {
+		resultText += fmt.Sprintf("ID: %d, Name: %s, Todos: %d\n", tag.ID, tag.Name, tag.Todos)
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// RenameTagHandler handles the rename_tag MCP tool
+func (h *Handler) RenameTagHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.tagService == nil {
+		return nil, errTagServiceNotInitialized
+	}
+	name, ok := request.GetArguments()["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid name")
+	}
+	newName, ok := request.GetArguments()["new_name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid new_name")
+	}
+	tag, err := h.tagService.RenameTag(ctx, name, newName)
+	if errors.Is(err, todo.ErrDuplicateName) {
+		return mcp.NewToolResultError(fmt.Sprintf("%v. Use merge_tags to combine the two tags.", capitalize(err))), nil
+	}
+	if err != nil {
+		return toolError("rename tag", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Tag renamed: ID=%d, Name=%s", tag.ID, tag.Name)), nil
+}
+
+// MergeTagsHandler handles the merge_tags MCP tool
+func (h *Handler) MergeTagsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.tagService == nil {
+		return nil, errTagServiceNotInitialized
+	}
+	from, ok := request.GetArguments()["from"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid from")
+	}
+	into, ok := request.GetArguments()["into"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid into")
+	}
+	tag, err := h.tagService.MergeTags(ctx, from, into)
+	if err != nil {
+		return toolError("merge tags", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Tag %s merged into: ID=%d, Name=%s", from, tag.ID, tag.Name)), nil
+}
+
+// GetTodosByTagsHandler handles the get_todos_by_tags MCP tool
+func (h *Handler) GetTodosByTagsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.tagService == nil {
+		return nil, errTagServiceNotInitialized
+	}
+	names, err := tagsArgument(request)
+	if err != nil {
+		return toolError("get todos by tags", err)
+	}
+	matchAll := false
+	if matchRaw, ok := request.GetArguments()["match"]; ok {
+		match, ok := matchRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid match")
+		}
+		switch strings.ToLower(match) {
+		case "any", "":
+		case "all":
+			matchAll = true
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid match: unknown match '%s'; use any or all", match)), nil
+		}
+	}
+
+	todos, err := h.tagService.GetTodosByTags(ctx, names, matchAll)
+	if err != nil {
+		return toolError("get todos by tags", err)
+	}
+	if len(todos) == 0 {
+		return mcp.NewToolResultText("No todos found with those tags"), nil
+	}
+	tags := h.allTodoTags(ctx)
+	var resultText string
+	for _, todo := range todos {
+		status := "Incomplete"
+		if todo.CompletedAt != nil {
+			status = "Complete"
+		}
+		resultText += fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s%s%s\n",
+			todo.ID, todo.Title, status, h.formatDueDate(todo), priorityInfo(todo), tagInfo(todo, tags))
+	}
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// tagsArgument reads the "tags" argument, which is an array of names or a
+// single comma-separated string
+func tagsArgument(request mcp.CallToolRequest) ([]string, error) {
+	switch raw := request.GetArguments()["tags"].(type) {
+	case string:
+		return strings.Split(raw, ","), nil
+	case []interface{}:
+		names := make([]string, 0, len(raw))
+		for _, name := range raw {
+			s, ok := name.(string)
+			if !ok {
+				return nil, errors.New("tags must be strings")
+			}
+			names = append(names, s)
+		}
+		return names, nil
+	case nil:
+		return nil, errors.New("tags is required")
+	default:
+		return nil, errors.New("tags must be an array of strings")
+	}
+}
+
+// allTodoTags returns every todo's tags keyed by todo ID. Like the project
+// and category lookups in listings, a failed lookup just leaves tags out.
+func (h *Handler) allTodoTags(ctx context.Context) map[string][]todo.Tag {
+	if h.tagService == nil {
+		return nil
+	}
+	tags, err := h.tagService.GetAllTodoTags(ctx)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
+// todoTags returns one todo's tags in the form allTodoTags uses
+func (h *Handler) todoTags(ctx context.Context, todoID string) map[string][]todo.Tag {
+	if h.tagService == nil {
+		return nil
+	}
+	tags, err := h.tagService.GetTodoTags(ctx, todoID)
+	if err != nil {
+		return nil
+	}
+	return map[string][]todo.Tag{todoID: tags}
+}
+
+// tagInfo returns the ", Tags: ..." part of a todo listing, or "" when item
+// has no tags
+func tagInfo(item todo.TodoItem, tags map[string][]todo.Tag) string {
+	if len(tags[item.ID]) == 0 {
+		return ""
+	}
+	return ", Tags: " + joinTagNames(tags[item.ID])
+}
+
+// joinTagNames lists tag names separated by commas
+func joinTagNames(tags []todo.Tag) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errTimeServiceNotInitialized is returned by the time tracking tools when the
+// handler was built without a TimeService
+var errTimeServiceNotInitialized = errors.New("time service not initialized")
+
+// StartTimerHandler handles the start_timer MCP tool
+func (h *Handler) StartTimerHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.timeService == nil {
+		return nil, errTimeServiceNotInitialized
+	}
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	note, _ := request.GetArguments()["note"].(string)
+	entry, err := h.timeService.StartTimer(ctx, todoID, note)
+	if err != nil {
+		return toolError("start timer", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Timer started on todo %s at %s (entry %d)", entry.TodoID, h.formatTime(entry.StartedAt), entry.ID)), nil
+}
+
+// StopTimerHandler handles the stop_timer MCP tool
+func (h *Handler) StopTimerHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.timeService == nil {
+		return nil, errTimeServiceNotInitialized
+	}
+	entry, err := h.timeService.StopTimer(ctx)
+	if err != nil {
+		return toolError("stop timer", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Timer stopped on todo %s after %s (entry %d)", entry.TodoID, formatDuration(entry.Duration(time.Now())), entry.ID)), nil
+}
+
+// LogTimeHandler handles the log_time MCP tool. Without started_at the time
+// is logged as just finished.
+func (h *Handler) LogTimeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.timeService == nil {
+		return nil, errTimeServiceNotInitialized
+	}
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	durationStr, ok := request.GetArguments()["duration"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid duration")
+	}
+	duration, err := parseDuration(durationStr)
+	if err != nil {
+		return toolError("log time", err)
+	}
+	startedAt := time.Now().Add(-duration)
+	if startedStr, ok := request.GetArguments()["started_at"].(string); ok && startedStr != "" {
+		startedAt, err = h.parseDate(startedStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse started_at: %w", err)
+		}
+	}
+	note, _ := request.GetArguments()["note"].(string)
+
+	entry, err := h.timeService.LogTime(ctx, todoID, startedAt, duration, note)
+	if err != nil {
+		return toolError("log time", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Logged %s on todo %s from %s (entry %d)", formatDuration(duration), entry.TodoID, h.formatTime(entry.StartedAt), entry.ID)), nil
+}
+
+// GetTimeEntriesHandler handles the get_time_entries MCP tool
+func (h *Handler) GetTimeEntriesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.timeService == nil {
+		return nil, errTimeServiceNotInitialized
+	}
+	todoID, ok := request.GetArguments()["todo_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid todo_id")
+	}
+	item, err := h.todoService.GetTodo(ctx, todoID)
+	if err != nil {
+		return toolError("get time entries", err)
+	}
+	entries, err := h.timeService.GetTodoTimeEntries(ctx, todoID)
+	if err != nil {
+		return toolError("get time entries", err)
+	}
+	if len(entries) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No time tracked on todo %s%s", todoID, estimateInfo(item))), nil
+	}
+
+	now := time.Now()
+	var total time.Duration
+	var resultText string
+	for _, entry := range entries {
+		total += entry.Duration(now)
+		resultText += h.formatTimeEntry(entry, now) + "\n"
+	}
+	resultText += fmt.Sprintf("Total: %s%s\n", formatDuration(total), estimateInfo(item))
+	return mcp.NewToolResultText(resultText), nil
+}
+
+// DeleteTimeEntryHandler handles the delete_time_entry MCP tool
+func (h *Handler) DeleteTimeEntryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.timeService == nil {
+		return nil, errTimeServiceNotInitialized
+	}
+	idFloat, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("id must be a number")
+	}
+	entry, err := h.timeService.DeleteTimeEntry(ctx, int64(idFloat))
+	if err != nil {
+		return toolError("delete time entry", err)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Time entry %d deleted from todo %s", entry.ID, entry.TodoID)), nil
+}
+
+// TimeReportHandler handles the time_report MCP tool. It sums the time spent
+// from from until to, or until now, by todo, project and category. A to date
+// without a time of day includes that whole day.
+func (h *Handler) TimeReportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.timeService == nil {
+		return nil, errTimeServiceNotInitialized
+	}
+	fromStr, ok := request.GetArguments()["from"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid from")
+	}
+	from, err := h.parseDate(fromStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse from: %w", err)
+	}
+	now := time.Now()
+	to := now
+	if toStr, ok := request.GetArguments()["to"].(string); ok && toStr != "" {
+		parsed, err := h.parseDueDate(toStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse to: %w", err)
+		}
+		to = parsed.Time
+		if parsed.AllDay {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	if !to.After(from) {
+		return mcp.NewToolResultError("Invalid to: it must be after from"), nil
+	}
+
+	entries, err := h.timeService.GetTimeEntries(ctx, from, to)
+	if err != nil {
+		return toolError("get time report", err)
+	}
+	todos, err := h.todoService.GetAllTodos(ctx)
+	if err != nil {
+		return toolError("get time report", err)
+	}
+	report := todo.NewTimeReport(entries, todos, from, to, now)
+	if report.Total == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No time tracked from %s to %s", h.formatTime(from), h.formatTime(to))), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Time tracked from %s to %s: %s\n", h.formatTime(from), h.formatTime(to), formatDuration(report.Total))
+	b.WriteString("By todo:\n")
+	for _, spent := range report.Todos {
+		fmt.Fprintf(&b, "- #%s %s: %s%s\n", spent.Todo.ID, spent.Todo.Title, formatDuration(spent.Spent), estimateInfo(spent.Todo))
+	}
+	b.WriteString("By project:\n")
+	projectNames := h.projectNames(ctx)
+	for _, group := range report.Projects {
+		fmt.Fprintf(&b, "- %s: %s\n", groupName(group.ID, projectNames, "No project"), formatDuration(group.Spent))
+	}
+	b.WriteString("By category:\n")
+	categoryNames := h.categoryNames(ctx)
+	for _, group := range report.Categories {
+		fmt.Fprintf(&b, "- %s: %s\n", groupName(group.ID, categoryNames, "No category"), formatDuration(group.Spent))
+	}
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// formatTimeEntry describes one time entry
+func (h *Handler) formatTimeEntry(entry todo.TimeEntry, now time.Time) string {
+	end := "now (running)"
+	if entry.EndedAt != nil {
+		end = h.formatTime(*entry.EndedAt)
+	}
+	text := fmt.Sprintf("Entry %d: %s to %s, %s", entry.ID, h.formatTime(entry.StartedAt), end, formatDuration(entry.Duration(now)))
+	if entry.Note != "" {
+		text += ", Note: " + entry.Note
+	}
+	return text
+}
+
+// trackedInfo returns the ", Estimate: ..., Tracked: ..." part of a todo
+// listing. Like the tag lookup, a failed lookup just leaves Tracked out.
+func (h *Handler) trackedInfo(ctx context.Context, item todo.TodoItem) string {
+	info := estimateInfo(item)
+	if h.timeService == nil {
+		return info
+	}
+	entries, err := h.timeService.GetTodoTimeEntries(ctx, item.ID)
+	if err != nil || len(entries) == 0 {
+		return info
+	}
+	now := time.Now()
+	var total time.Duration
+	for _, entry := range entries {
+		total += entry.Duration(now)
+	}
+	return info + ", Tracked: " + formatDuration(total)
+}
+
+// estimateInfo returns the ", Estimate: ..." part of a todo listing, or ""
+// when item has no estimate
+func estimateInfo(item todo.TodoItem) string {
+	if item.Estimate == nil {
+		return ""
+	}
+	return ", Estimate: " + formatDuration(time.Duration(*item.Estimate)*time.Minute)
+}
+
+// estimateMinutes converts an estimate to whole minutes, rounding to the
+// nearest
+func estimateMinutes(d time.Duration) int {
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
+// formatDuration shows d in hours and minutes, such as 1h30m, 2h or 45m
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	hours, minutes := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh%dm", hours, minutes)
+}
+
+// projectNames returns the name of every project by ID, or nil when they
+// cannot be listed
+func (h *Handler) projectNames(ctx context.Context) map[int64]string {
+	if h.projectService == nil {
+		return nil
+	}
+	projects, err := h.projectService.GetAllProjects(ctx)
+	if err != nil {
+		return nil
+	}
+	names := make(map[int64]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names
+}
+
+// categoryNames returns the name of every category by ID, or nil when they
+// cannot be listed
+func (h *Handler) categoryNames(ctx context.Context) map[int64]string {
+	if h.categoryService == nil {
+		return nil
+	}
+	categories, err := h.categoryService.GetAllCategories(ctx)
+	if err != nil {
+		return nil
+	}
+	names := make(map[int64]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	return names
+}
+
+// groupName labels a report group as "#ID Name", or none for a nil ID
+func groupName(id *int64, names map[int64]string, none string) string {
+	if id == nil {
+		return none
+	}
+	if name, ok := names[*id]; ok {
+		return fmt.Sprintf("#%d %s", *id, name)
+	}
+	return fmt.Sprintf("#%d", *id)
+}
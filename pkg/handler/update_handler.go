@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// UpdateTodoHandler handles the update_todo MCP tool. Only the arguments
+// given are changed; null, an empty due_date or estimate or a zero
+// project_id or category_id clears that field.
+func (h *Handler) UpdateTodoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid id")
+	}
+	patch, err := h.todoPatchArguments(request)
+	if err != nil {
+		return toolError("update todo", err)
+	}
+	if patch.IsEmpty() {
+		return mcp.NewToolResultError("Nothing to update: give at least one of title, due_date, start_date, project_id, category_id, notes or estimate"), nil
+	}
+	item, err := h.todoService.UpdateTodo(ctx, id, patch)
+	if err != nil {
+		return toolError("update todo", err)
+	}
+	return mcp.NewToolResultText("Todo updated: " + h.formatTodoDetails(item)), nil
+}
+
+// todoPatchArguments reads the update_todo arguments into a patch
+func (h *Handler) todoPatchArguments(request mcp.CallToolRequest) (todo.TodoPatch, error) {
+	var patch todo.TodoPatch
+	args := request.GetArguments()
+
+	if raw, ok := args["title"]; ok {
+		title, ok := raw.(string)
+		if !ok {
+			return todo.TodoPatch{}, errors.New("title must be a string")
+		}
+		patch.Title = &title
+	}
+	if raw, ok := args["due_date"]; ok {
+		dueDateStr, ok := raw.(string)
+		if raw != nil && !ok {
+			return todo.TodoPatch{}, errors.New("due_date must be a string")
+		}
+		if dueDateStr == "" {
+			patch.ClearDueDate = true
+		} else {
+			dueDate, err := h.parseDueDate(dueDateStr)
+			if err != nil {
+				return todo.TodoPatch{}, fmt.Errorf("failed to parse due date: %w", err)
+			}
+			patch.DueDate = &dueDate.Time
+			patch.DueAllDay = dueDate.AllDay
+		}
+	}
+	if raw, ok := args["start_date"]; ok {
+		startDateStr, ok := raw.(string)
+		if raw != nil && !ok {
+			return todo.TodoPatch{}, errors.New("start_date must be a string")
+		}
+		if startDateStr == "" {
+			patch.ClearStartDate = true
+		} else {
+			startDate, err := h.parseDate(startDateStr)
+			if err != nil {
+				return todo.TodoPatch{}, fmt.Errorf("failed to parse start date: %w", err)
+			}
+			patch.StartDate = &startDate
+		}
+	}
+	var err error
+	patch.ProjectID, patch.ClearProject, err = optionalIDArgument(args, "project_id")
+	if err != nil {
+		return todo.TodoPatch{}, err
+	}
+	patch.CategoryID, patch.ClearCategory, err = optionalIDArgument(args, "category_id")
+	if err != nil {
+		return todo.TodoPatch{}, err
+	}
+	notes, ok, err := notesArgument(request)
+	if err != nil {
+		return todo.TodoPatch{}, err
+	}
+	if ok {
+		patch.Notes = &notes
+	}
+	if raw, ok := args["estimate"]; ok {
+		estimateStr, ok := raw.(string)
+		if raw != nil && !ok {
+			return todo.TodoPatch{}, errors.New("estimate must be a string")
+		}
+		if estimateStr == "" {
+			patch.ClearEstimate = true
+		} else {
+			estimate, err := parseDuration(estimateStr)
+			if err != nil {
+				return todo.TodoPatch{}, fmt.Errorf("failed to parse estimate: %w", err)
+			}
+			minutes := estimateMinutes(estimate)
+			patch.Estimate = &minutes
+		}
+	}
+	return patch, nil
+}
+
+// optionalIDArgument reads a numeric ID argument that may be cleared. It
+// returns the ID when one is given, or clear when the argument is null or 0.
+func optionalIDArgument(args map[string]interface{}, name string) (id *int64, clear bool, err error) {
+	raw, ok := args[name]
+	if !ok {
+		return nil, false, nil
+	}
+	if raw == nil {
+		return nil, true, nil
+	}
+	idFloat, ok := raw.(float64)
+	if !ok {
+		return nil, false, fmt.Errorf("%s must be a number", name)
+	}
+	if idFloat == 0 {
+		return nil, true, nil
+	}
+	value := int64(idFloat)
+	return &value, false, nil
+}
+
+// formatTodoDetails describes every field of a todo, with its notes on the
+// lines that follow
+func (h *Handler) formatTodoDetails(item todo.TodoItem) string {
+	status := "Incomplete"
+	if item.CompletedAt != nil {
+		status = "Complete"
+	}
+	dueDate := "none"
+	if item.DueDate != nil {
+		dueDate = h.formatDueDate(item)
+	}
+	text := fmt.Sprintf("ID: %s, Title: %s, Status: %s, Due Date: %s%s%s", item.ID, item.Title, status, dueDate, priorityInfo(item), estimateInfo(item))
+	if item.StartDate != nil {
+		text += ", Start Date: " + h.formatTime(*item.StartDate)
+	}
+	if item.ProjectID != nil {
+		text += fmt.Sprintf(", ProjectID: %d", *item.ProjectID)
+	}
+	if item.CategoryID != nil {
+		text += fmt.Sprintf(", CategoryID: %d", *item.CategoryID)
+	}
+	if item.Notes != "" {
+		text += "\nNotes:\n" + item.Notes
+	}
+	return text
+}
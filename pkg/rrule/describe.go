@@ -0,0 +1,155 @@
+package rrule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var frequencyUnits = map[Frequency]string{
+	Daily:   "day",
+	Weekly:  "week",
+	Monthly: "month",
+	Yearly:  "year",
+}
+
+// Describe returns an English summary of the rule, such as "every 2 weeks on
+// Monday and Friday" or "every month on the last weekday, 6 times"
+func (r Rule) Describe() string {
+	var b strings.Builder
+	unit := frequencyUnits[r.Freq]
+	if r.Interval > 1 {
+		fmt.Fprintf(&b, "every %d %ss", r.Interval, unit)
+	} else {
+		b.WriteString("every " + unit)
+	}
+
+	switch {
+	case len(r.BySetPos) > 0 && len(r.ByDay) > 0 && len(r.ByMonthDay) == 0 && !hasOrdinals(r.ByDay):
+		positions := make([]string, len(r.BySetPos))
+		for i, pos := range r.BySetPos {
+			positions[i] = ordinal(pos)
+		}
+		b.WriteString(" on the " + joinWords(positions, "and") + " " + daySetName(r.ByDay))
+	case len(r.ByDay) > 0:
+		b.WriteString(" on " + describeDays(r.ByDay))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = ordinal(day)
+		}
+		if len(r.ByDay) > 0 {
+			b.WriteString(" falling on the " + joinWords(days, "or"))
+		} else {
+			b.WriteString(" on the " + joinWords(days, "and"))
+		}
+		if r.ByMonthDay[len(r.ByMonthDay)-1] < 0 {
+			b.WriteString(" day")
+		}
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			months[i] = month.String()
+		}
+		b.WriteString(" in " + joinWords(months, "and"))
+	}
+
+	if r.Count == 1 {
+		b.WriteString(", once")
+	} else if r.Count > 1 {
+		fmt.Fprintf(&b, ", %d times", r.Count)
+	}
+	if r.Until != nil {
+		b.WriteString(", until " + r.Until.Format("2006-01-02"))
+	}
+	return b.String()
+}
+
+func hasOrdinals(days []Weekday) bool {
+	for _, day := range days {
+		if day.N != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// describeDays names BYDAY entries, such as "Monday and Wednesday" or "the
+// 2nd Tuesday"
+func describeDays(days []Weekday) string {
+	ordinals := hasOrdinals(days)
+	if !ordinals {
+		switch name := daySetName(days); name {
+		case "day", "weekday", "weekend day":
+			return name + "s"
+		}
+	}
+	names := make([]string, len(days))
+	for i, day := range days {
+		switch {
+		case !ordinals:
+			names[i] = day.Day.String()
+		case day.N == 0:
+			names[i] = "every " + day.Day.String()
+		default:
+			names[i] = "the " + ordinal(day.N) + " " + day.Day.String()
+		}
+	}
+	return joinWords(names, "and")
+}
+
+// daySetName names a set of weekdays as one kind of day, such as "weekday",
+// falling back to "Monday or Friday"
+func daySetName(days []Weekday) string {
+	set := make(map[time.Weekday]bool, len(days))
+	for _, day := range days {
+		set[day.Day] = true
+	}
+	weekdays := set[time.Monday] && set[time.Tuesday] && set[time.Wednesday] && set[time.Thursday] && set[time.Friday]
+	weekend := set[time.Saturday] && set[time.Sunday]
+	switch {
+	case len(set) == 7:
+		return "day"
+	case len(set) == 5 && weekdays:
+		return "weekday"
+	case len(set) == 2 && weekend:
+		return "weekend day"
+	}
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = day.Day.String()
+	}
+	return joinWords(names, "or")
+}
+
+// ordinal formats n as "1st", "22nd", "last" or "2nd to last"
+func ordinal(n int) string {
+	if n == -1 {
+		return "last"
+	}
+	if n < 0 {
+		return ordinal(-n) + " to last"
+	}
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// joinWords joins items as "a", "a and b" or "a, b and c"
+func joinWords(items []string, conjunction string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
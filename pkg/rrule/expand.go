@@ -0,0 +1,311 @@
+package rrule
+
+import (
+	"sort"
+	"time"
+)
+
+// giveUpAfter bounds, in years, the search for a rule that matches no more dates, such
+// as FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30. No valid rule goes this long
+// between occurrences; February 29 repeats at least every eight years.
+const giveUpAfter = 400
+
+// Iterator yields the occurrences of a rule in order, starting at the series
+// start. Like most iCalendar libraries it only yields dates the rule matches,
+// so the start itself is skipped when the rule does not select it.
+type Iterator struct {
+	rule    Rule
+	start   time.Time
+	until   *time.Time
+	period  int
+	pending []time.Time
+	emitted int
+	last    time.Time
+	done    bool
+}
+
+// Iterator returns an iterator over the occurrences of r beginning at start.
+// Occurrences take the time of day and location of start.
+func (r Rule) Iterator(start time.Time) *Iterator {
+	it := &Iterator{rule: r, start: start, last: start}
+	if it.rule.Interval < 1 {
+		it.rule.Interval = 1
+	}
+	if r.Until != nil {
+		until := *r.Until
+		if r.untilFloating {
+			until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, start.Location())
+		}
+		it.until = &until
+	}
+	return it
+}
+
+// Next returns the next occurrence, or false when the series has ended
+func (it *Iterator) Next() (time.Time, bool) {
+	for !it.done {
+		if len(it.pending) > 0 {
+			next := it.pending[0]
+			it.pending = it.pending[1:]
+			if next.Before(it.start) {
+				continue
+			}
+			if it.until != nil && next.After(*it.until) {
+				it.done = true
+				break
+			}
+			it.emitted++
+			if it.rule.Count > 0 && it.emitted >= it.rule.Count {
+				it.done = true
+			}
+			it.last = next
+			return next, true
+		}
+
+		first := it.periodStart(it.period)
+		if it.until != nil && first.After(*it.until) || first.Year()-it.last.Year() > giveUpAfter {
+			it.done = true
+			break
+		}
+		it.pending = it.expand(it.period)
+		it.period++
+	}
+	return time.Time{}, false
+}
+
+// Occurrences returns up to n occurrences of r beginning at start
+func (r Rule) Occurrences(start time.Time, n int) []time.Time {
+	var result []time.Time
+	it := r.Iterator(start)
+	for len(result) < n {
+		next, ok := it.Next()
+		if !ok {
+			break
+		}
+		result = append(result, next)
+	}
+	return result
+}
+
+// After returns the first occurrence of the series beginning at start that
+// falls strictly after t. COUNT still counts from start.
+func (r Rule) After(start time.Time, t time.Time) (time.Time, bool) {
+	it := r.Iterator(start)
+	for {
+		next, ok := it.Next()
+		if !ok || next.After(t) {
+			return next, ok
+		}
+	}
+}
+
+// day returns the given date at the start's time of day. The date may
+// overflow, as with time.Date.
+func (it *Iterator) day(year int, month time.Month, day int) time.Time {
+	s := it.start
+	return time.Date(year, month, day, s.Hour(), s.Minute(), s.Second(), s.Nanosecond(), s.Location())
+}
+
+// periodStart returns the first day of the n-th period
+func (it *Iterator) periodStart(n int) time.Time {
+	step := n * it.rule.Interval
+	year, month, day := it.start.Date()
+	switch it.rule.Freq {
+	case Weekly:
+		offset := (int(it.start.Weekday()) - int(it.rule.WeekStart) + 7) % 7
+		return it.day(year, month, day-offset+7*step)
+	case Monthly:
+		return it.day(year, month+time.Month(step), 1)
+	case Yearly:
+		return it.day(year+step, time.January, 1)
+	}
+	return it.day(year, month, day+step)
+}
+
+// expand returns the occurrences in the n-th period, sorted and with BYSETPOS
+// applied, including any that fall before the start
+func (it *Iterator) expand(n int) []time.Time {
+	r := it.rule
+	first := it.periodStart(n)
+	year, month, day := first.Date()
+
+	var candidates []time.Time
+	switch r.Freq {
+	case Daily:
+		if (len(r.ByMonthDay) == 0 || matchesMonthDay(r.ByMonthDay, first)) && (len(r.ByDay) == 0 || matchesWeekday(r.ByDay, first)) {
+			candidates = []time.Time{first}
+		}
+	case Weekly:
+		for i := 0; i < 7; i++ {
+			date := it.day(year, month, day+i)
+			if len(r.ByDay) == 0 && date.Weekday() == it.start.Weekday() || len(r.ByDay) > 0 && matchesWeekday(r.ByDay, date) {
+				candidates = append(candidates, date)
+			}
+		}
+	case Monthly:
+		candidates = it.monthDays(year, month)
+	case Yearly:
+		switch {
+		case len(r.ByMonth) > 0:
+			for m := time.January; m <= time.December; m++ {
+				if containsMonth(r.ByMonth, m) {
+					candidates = append(candidates, it.monthDays(year, m)...)
+				}
+			}
+		case len(r.ByMonthDay) > 0:
+			for m := time.January; m <= time.December; m++ {
+				candidates = append(candidates, it.monthDays(year, m)...)
+			}
+		case len(r.ByDay) > 0:
+			candidates = it.weekdaysIn(it.day(year, time.January, 1), daysInYear(year))
+		default:
+			if date := it.day(year, it.start.Month(), it.start.Day()); date.Day() == it.start.Day() {
+				candidates = []time.Time{date}
+			}
+		}
+	}
+
+	if len(r.ByMonth) > 0 && r.Freq != Yearly {
+		kept := candidates[:0]
+		for _, date := range candidates {
+			if containsMonth(r.ByMonth, date.Month()) {
+				kept = append(kept, date)
+			}
+		}
+		candidates = kept
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	if len(r.BySetPos) > 0 {
+		candidates = selectPositions(candidates, r.BySetPos)
+	}
+	return candidates
+}
+
+// monthDays returns the days of a month selected by BYMONTHDAY and BYDAY,
+// with BYDAY ordinals counted within the month. Without either it is the
+// start's day of month, which some months do not have.
+func (it *Iterator) monthDays(year int, month time.Month) []time.Time {
+	r := it.rule
+	length := daysIn(year, month)
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if it.start.Day() > length {
+			return nil
+		}
+		return []time.Time{it.day(year, month, it.start.Day())}
+	}
+
+	if len(r.ByDay) > 0 {
+		days := it.weekdaysIn(it.day(year, month, 1), length)
+		if len(r.ByMonthDay) == 0 {
+			return days
+		}
+		kept := days[:0]
+		for _, date := range days {
+			if matchesMonthDay(r.ByMonthDay, date) {
+				kept = append(kept, date)
+			}
+		}
+		return kept
+	}
+
+	var days []time.Time
+	for _, md := range r.ByMonthDay {
+		if md < 0 {
+			md = length + md + 1
+		}
+		if md >= 1 && md <= length {
+			days = append(days, it.day(year, month, md))
+		}
+	}
+	return dedupe(days)
+}
+
+// weekdaysIn returns the days among the length days from first that BYDAY
+// selects, with ordinals counted within that span
+func (it *Iterator) weekdaysIn(first time.Time, length int) []time.Time {
+	year, month, day := first.Date()
+	var result []time.Time
+	for _, w := range it.rule.ByDay {
+		var matches []time.Time
+		for i := 0; i < length; i++ {
+			if date := it.day(year, month, day+i); date.Weekday() == w.Day {
+				matches = append(matches, date)
+			}
+		}
+		switch {
+		case w.N == 0:
+			result = append(result, matches...)
+		case w.N > 0 && w.N <= len(matches):
+			result = append(result, matches[w.N-1])
+		case w.N < 0 && -w.N <= len(matches):
+			result = append(result, matches[len(matches)+w.N])
+		}
+	}
+	return dedupe(result)
+}
+
+// selectPositions applies BYSETPOS to a sorted set of dates
+func selectPositions(dates []time.Time, positions []int) []time.Time {
+	var result []time.Time
+	for _, pos := range positions {
+		switch {
+		case pos > 0 && pos <= len(dates):
+			result = append(result, dates[pos-1])
+		case pos < 0 && -pos <= len(dates):
+			result = append(result, dates[len(dates)+pos])
+		}
+	}
+	result = dedupe(result)
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+func matchesWeekday(days []Weekday, date time.Time) bool {
+	for _, w := range days {
+		if w.Day == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMonthDay(days []int, date time.Time) bool {
+	length := daysIn(date.Year(), date.Month())
+	for _, md := range days {
+		if md == date.Day() || md < 0 && length+md+1 == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(dates []time.Time) []time.Time {
+	seen := make(map[time.Time]bool, len(dates))
+	kept := dates[:0]
+	for _, date := range dates {
+		if !seen[date] {
+			seen[date] = true
+			kept = append(kept, date)
+		}
+	}
+	return kept
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
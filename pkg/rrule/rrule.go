@@ -0,0 +1,326 @@
+// Package rrule parses and expands iCalendar recurrence rules (RFC 5545
+// section 3.3.10) at day granularity: FREQ of DAILY, WEEKLY, MONTHLY or
+// YEARLY with INTERVAL, COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY, BYSETPOS and
+// WKST. Every occurrence keeps the time of day of the series start.
+//
+//	rule, err := rrule.Parse("FREQ=MONTHLY;BYDAY=TU;BYSETPOS=2")
+//	next := rule.Occurrences(start, 5) // the next five 2nd Tuesdays
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a rule
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Daily:   "DAILY",
+	Weekly:  "WEEKLY",
+	Monthly: "MONTHLY",
+	Yearly:  "YEARLY",
+}
+
+func (f Frequency) String() string {
+	return frequencyNames[f]
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+func weekdayName(day time.Weekday) string {
+	return strings.ToUpper(day.String()[:2])
+}
+
+// Weekday is one BYDAY entry. N selects the N-th such weekday of the month or
+// year, counting from the end when negative; zero means every one.
+type Weekday struct {
+	Day time.Weekday
+	N   int
+}
+
+func (w Weekday) String() string {
+	if w.N == 0 {
+		return weekdayName(w.Day)
+	}
+	return strconv.Itoa(w.N) + weekdayName(w.Day)
+}
+
+// Rule is a parsed recurrence rule. The zero value of an optional part means
+// it is absent.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByMonth    []time.Month
+	ByMonthDay []int
+	ByDay      []Weekday
+	BySetPos   []int
+	WeekStart  time.Weekday
+
+	// untilFloating is set when UNTIL had no UTC designator; the expander
+	// then reads it in the location of the series start
+	untilFloating bool
+	// untilDate is set when UNTIL was a date without a time
+	untilDate bool
+}
+
+// ErrSyntax is wrapped by every error Parse returns
+var ErrSyntax = errors.New("invalid rrule")
+
+func syntaxError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrSyntax, fmt.Sprintf(format, args...))
+}
+
+// Parse parses the value of an RRULE property, such as
+// "FREQ=WEEKLY;BYDAY=MO,WE,FR". A leading "RRULE:" is accepted. Parts are
+// case-insensitive and may appear in any order.
+func Parse(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return Rule{}, syntaxError("empty rule")
+	}
+
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return Rule{}, syntaxError("malformed part '%s', expected NAME=VALUE", part)
+		}
+		if seen[name] {
+			return Rule{}, syntaxError("%s appears more than once", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			err = rule.parseFreq(value)
+		case "INTERVAL":
+			rule.Interval, err = parseInt(name, value, 1, 0)
+		case "COUNT":
+			rule.Count, err = parseInt(name, value, 1, 0)
+		case "UNTIL":
+			err = rule.parseUntil(value)
+		case "BYMONTH":
+			err = eachInt(name, value, 1, 12, false, func(v int) { rule.ByMonth = append(rule.ByMonth, time.Month(v)) })
+		case "BYMONTHDAY":
+			err = eachInt(name, value, 1, 31, true, func(v int) { rule.ByMonthDay = append(rule.ByMonthDay, v) })
+		case "BYSETPOS":
+			err = eachInt(name, value, 1, 366, true, func(v int) { rule.BySetPos = append(rule.BySetPos, v) })
+		case "BYDAY":
+			err = rule.parseByDay(value)
+		case "WKST":
+			day, ok := weekdayNames[value]
+			if !ok {
+				err = syntaxError("WKST must be a weekday such as MO, got '%s'", value)
+			}
+			rule.WeekStart = day
+		case "BYSECOND", "BYMINUTE", "BYHOUR", "BYYEARDAY", "BYWEEKNO":
+			err = syntaxError("%s is not supported, recurrences are calculated in whole days", name)
+		default:
+			err = syntaxError("unknown part %s", name)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+
+	if !seen["FREQ"] {
+		return Rule{}, syntaxError("FREQ is required")
+	}
+	if seen["COUNT"] && seen["UNTIL"] {
+		return Rule{}, syntaxError("COUNT and UNTIL cannot both be set")
+	}
+	if rule.Freq == Weekly && len(rule.ByMonthDay) > 0 {
+		return Rule{}, syntaxError("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	if len(rule.BySetPos) > 0 && len(rule.ByMonth)+len(rule.ByMonthDay)+len(rule.ByDay) == 0 {
+		return Rule{}, syntaxError("BYSETPOS needs BYDAY, BYMONTHDAY or BYMONTH")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly && rule.Freq != Yearly {
+			return Rule{}, syntaxError("BYDAY ordinals such as %s need FREQ=MONTHLY or FREQ=YEARLY", day)
+		}
+		if day.N != 0 && rule.Freq == Monthly && (day.N > 5 || day.N < -5) {
+			return Rule{}, syntaxError("BYDAY ordinal %s is out of range for FREQ=MONTHLY", day)
+		}
+	}
+	return rule, nil
+}
+
+func (r *Rule) parseFreq(value string) error {
+	for freq, name := range frequencyNames {
+		if name == value {
+			r.Freq = freq
+			return nil
+		}
+	}
+	switch value {
+	case "SECONDLY", "MINUTELY", "HOURLY":
+		return syntaxError("FREQ=%s is not supported, use DAILY, WEEKLY, MONTHLY or YEARLY", value)
+	}
+	return syntaxError("unknown FREQ '%s'", value)
+}
+
+// untilLayouts are the UNTIL forms RFC 5545 allows: UTC, floating and date
+var untilLayouts = []struct {
+	layout   string
+	floating bool
+	date     bool
+}{
+	{"20060102T150405Z", false, false},
+	{"20060102T150405", true, false},
+	{"20060102", true, true},
+}
+
+func (r *Rule) parseUntil(value string) error {
+	for _, l := range untilLayouts {
+		until, err := time.Parse(l.layout, value)
+		if err != nil {
+			continue
+		}
+		if l.date {
+			// A date-only UNTIL includes the whole day
+			until = until.Add(24*time.Hour - time.Second)
+		}
+		r.Until, r.untilFloating, r.untilDate = &until, l.floating, l.date
+		return nil
+	}
+	return syntaxError("UNTIL must look like 20301231T235959Z or 20301231, got '%s'", value)
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) < 2 {
+			return syntaxError("BYDAY entry '%s' is not a weekday", item)
+		}
+		day, ok := weekdayNames[item[len(item)-2:]]
+		if !ok {
+			return syntaxError("BYDAY entry '%s' is not a weekday", item)
+		}
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n > 53 || n < -53 {
+				return syntaxError("BYDAY entry '%s' has an invalid ordinal", item)
+			}
+		}
+		r.ByDay = append(r.ByDay, Weekday{Day: day, N: n})
+	}
+	return nil
+}
+
+// parseInt parses a single integer of at least min, and at most max when max
+// is positive
+func parseInt(name string, value string, min int, max int) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < min || (max > 0 && v > max) {
+		if max > 0 {
+			return 0, syntaxError("%s must be a number from %d to %d, got '%s'", name, min, max, value)
+		}
+		return 0, syntaxError("%s must be a number of at least %d, got '%s'", name, min, value)
+	}
+	return v, nil
+}
+
+// eachInt parses a comma-separated list of integers in [min, max], also
+// allowing [-max, -min] when negative is set
+func eachInt(name string, value string, min int, max int, negative bool, fn func(v int)) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		v, err := strconv.Atoi(item)
+		abs := v
+		if abs < 0 && negative {
+			abs = -abs
+		}
+		if err != nil || abs < min || abs > max {
+			if negative {
+				return syntaxError("%s values must be from %d to %d or -%d to -%d, got '%s'", name, min, max, max, min, item)
+			}
+			return syntaxError("%s values must be from %d to %d, got '%s'", name, min, max, item)
+		}
+		fn(v)
+	}
+	return nil
+}
+
+// String formats the rule as an RRULE value with its parts in a fixed order,
+// so equivalent rules format the same way
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		switch {
+		case r.untilDate:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		case r.untilFloating:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		default:
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayName(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+func joinInts[T ~int](values []T) string {
+	sorted := append([]T(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	items := make([]string, len(sorted))
+	for i, v := range sorted {
+		items[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,189 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			"daily", "FREQ=DAILY", day(2030, time.March, 30), 3,
+			[]time.Time{day(2030, time.March, 30), day(2030, time.March, 31), day(2030, time.April, 1)},
+		},
+		{
+			"every other week", "FREQ=WEEKLY;INTERVAL=2", day(2030, time.March, 4), 3,
+			[]time.Time{day(2030, time.March, 4), day(2030, time.March, 18), day(2030, time.April, 1)},
+		},
+		{
+			// 2030-03-06 is a Wednesday
+			"mon wed fri", "FREQ=WEEKLY;BYDAY=MO,WE,FR", day(2030, time.March, 6), 4,
+			[]time.Time{day(2030, time.March, 6), day(2030, time.March, 8), day(2030, time.March, 11), day(2030, time.March, 13)},
+		},
+		{
+			"second tuesday", "FREQ=MONTHLY;BYDAY=2TU", day(2030, time.March, 1), 3,
+			[]time.Time{day(2030, time.March, 12), day(2030, time.April, 9), day(2030, time.May, 14)},
+		},
+		{
+			"second tuesday by setpos", "FREQ=MONTHLY;BYDAY=TU;BYSETPOS=2", day(2030, time.March, 1), 2,
+			[]time.Time{day(2030, time.March, 12), day(2030, time.April, 9)},
+		},
+		{
+			"last weekday of the month", "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", day(2030, time.March, 1), 3,
+			[]time.Time{day(2030, time.March, 29), day(2030, time.April, 30), day(2030, time.May, 31)},
+		},
+		{
+			"last day of the month", "FREQ=MONTHLY;BYMONTHDAY=-1", day(2030, time.January, 15), 3,
+			[]time.Time{day(2030, time.January, 31), day(2030, time.February, 28), day(2030, time.March, 31)},
+		},
+		{
+			"skips months without the day", "FREQ=MONTHLY", day(2030, time.January, 31), 3,
+			[]time.Time{day(2030, time.January, 31), day(2030, time.March, 31), day(2030, time.May, 31)},
+		},
+		{
+			"first friday of june and december", "FREQ=YEARLY;BYMONTH=6,12;BYDAY=1FR", day(2030, time.January, 1), 3,
+			[]time.Time{day(2030, time.June, 7), day(2030, time.December, 6), day(2031, time.June, 6)},
+		},
+		{
+			"last sunday of the year", "FREQ=YEARLY;BYDAY=-1SU", day(2030, time.January, 1), 2,
+			[]time.Time{day(2030, time.December, 29), day(2031, time.December, 28)},
+		},
+		{
+			"leap day", "FREQ=YEARLY", day(2028, time.February, 29), 2,
+			[]time.Time{day(2028, time.February, 29), day(2032, time.February, 29)},
+		},
+		{
+			"count", "FREQ=DAILY;COUNT=2", day(2030, time.March, 1), 5,
+			[]time.Time{day(2030, time.March, 1), day(2030, time.March, 2)},
+		},
+		{
+			"until includes the whole day", "FREQ=WEEKLY;UNTIL=20300315", day(2030, time.March, 1), 5,
+			[]time.Time{day(2030, time.March, 1), day(2030, time.March, 8), day(2030, time.March, 15)},
+		},
+		{
+			"never matches", "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", day(2030, time.January, 1), 1,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rule.Occurrences(tt.start, tt.n))
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=4")
+	require.NoError(t, err)
+	start := day(2030, time.March, 6)
+
+	next, ok := rule.After(start, start)
+	require.True(t, ok)
+	assert.Equal(t, day(2030, time.March, 8), next)
+
+	next, ok = rule.After(start, day(2030, time.March, 12))
+	require.True(t, ok)
+	assert.Equal(t, day(2030, time.March, 13), next)
+
+	_, ok = rule.After(start, day(2030, time.March, 13))
+	assert.False(t, ok, "count reached")
+}
+
+func TestUntilFloatingUsesStartLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	rule, err := Parse("FREQ=DAILY;UNTIL=20300302T093000")
+	require.NoError(t, err)
+
+	start := time.Date(2030, time.March, 1, 9, 30, 0, 0, loc)
+	assert.Len(t, rule.Occurrences(start, 5), 2)
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("rrule:byday=-1fr;freq=monthly;interval=2")
+	require.NoError(t, err)
+	assert.Equal(t, Monthly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []Weekday{{Day: time.Friday, N: -1}}, rule.ByDay)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR", rule.String())
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;COUNT=10;BYDAY=MO,WE,FR;WKST=SU",
+		"FREQ=MONTHLY;UNTIL=20301231T235959Z;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=MONTHLY;BYMONTHDAY=-1,1",
+		"FREQ=YEARLY;UNTIL=20301231;BYMONTH=1,6",
+		"FREQ=YEARLY;UNTIL=20301231T120000;BYDAY=1MO",
+	} {
+		rule, err := Parse(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, rule.String())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20301231",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYSETPOS=1",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COLOR=RED",
+		"FREQ",
+	} {
+		_, err := Parse(s)
+		assert.True(t, errors.Is(err, ErrSyntax), "expected a syntax error for %q, got %v", s, err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY", "every day"},
+		{"FREQ=WEEKLY;INTERVAL=2", "every 2 weeks"},
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR", "every week on Monday, Wednesday and Friday"},
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "every week on weekdays"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "every month on the 2nd Tuesday"},
+		{"FREQ=MONTHLY;BYDAY=TU;BYSETPOS=2", "every month on the 2nd Tuesday"},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", "every month on the last weekday"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "every month on the last day"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15;COUNT=6", "every month on the 1st and 15th, 6 times"},
+		{"FREQ=YEARLY;BYMONTH=6,12;BYDAY=1FR", "every year on the 1st Friday in June and December"},
+		{"FREQ=MONTHLY;BYDAY=-2SA", "every month on the 2nd to last Saturday"},
+		{"FREQ=DAILY;UNTIL=20301231", "every day, until 2030-12-31"},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		require.NoError(t, err, tt.rule)
+		assert.Equal(t, tt.want, rule.Describe(), tt.rule)
+	}
+}
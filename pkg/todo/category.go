@@ -1,7 +1,7 @@
 package todo
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"time"
 )
@@ -19,35 +19,36 @@ type Category struct {
 // CategoryService defines the interface for category operations
 type CategoryService interface {
 	// Category CRUD operations
-	CreateCategory(name string, description *string, color *string) (Category, error)
-	GetAllCategories() ([]Category, error)
-	GetCategoryByID(id int64) (Category, error)
-	UpdateCategory(id int64, name *string, description *string, color *string) (Category, error)
-	DeleteCategory(id int64) error
+	CreateCategory(ctx context.Context, name string, description *string, color *string) (Category, error)
+	GetAllCategories(ctx context.Context) ([]Category, error)
+	GetCategoryByID(ctx context.Context, id int64) (Category, error)
+	UpdateCategory(ctx context.Context, id int64, name *string, description *string, color *string) (Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
 	
 	// Category-todo relationship operations
-	GetTodosByCategory(categoryID int64) ([]TodoItem, error)
-	GetUncategorizedTodos() ([]TodoItem, error)
+	GetTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error)
+	GetUncategorizedTodos(ctx context.Context) ([]TodoItem, error)
 }
 
 // CategoryRepository defines the database operations for categories
 type CategoryRepository interface {
 	// Category CRUD operations
-	Create(category Category) (Category, error)
-	FindAll() ([]Category, error)
-	FindByID(id int64) (Category, error)
-	FindByName(name string) (Category, error)
-	Update(category Category) (Category, error)
-	Delete(id int64) error
+	Create(ctx context.Context, category Category) (Category, error)
+	FindAll(ctx context.Context) ([]Category, error)
+	FindByID(ctx context.Context, id int64) (Category, error)
+	FindByName(ctx context.Context, name string) (Category, error)
+	Update(ctx context.Context, category Category) (Category, error)
+	Delete(ctx context.Context, id int64) error
 	
 	// Category-todo relationship operations
-	FindTodosByCategory(categoryID int64) ([]TodoItem, error)
-	FindUncategorizedTodos() ([]TodoItem, error)
+	FindTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error)
+	FindUncategorizedTodos(ctx context.Context) ([]TodoItem, error)
 }
 
 // categoryService implements the CategoryService interface
 type categoryService struct {
 	repo CategoryRepository
+	uow  UnitOfWork // optional; runs read-then-write operations atomically
 }
 
 // NewCategoryService creates a new category service instance
@@ -55,44 +56,66 @@ func NewCategoryService(repo CategoryRepository) CategoryService {
 	return &categoryService{repo: repo}
 }
 
+// NewTransactionalCategoryService creates a category service that runs each
+// multi-step operation, such as the duplicate check before an insert, as one
+// unit of work. uow must be bound to the same database as repo.
+func NewTransactionalCategoryService(repo CategoryRepository, uow UnitOfWork) CategoryService {
+	return &categoryService{repo: repo, uow: uow}
+}
+
+// inTx runs fn in the service's unit of work, or straight against the
+// repository when the service has none
+func (s *categoryService) inTx(ctx context.Context, fn func(repo CategoryRepository) error) error {
+	if s.uow == nil {
+		return fn(s.repo)
+	}
+	return s.uow.Do(ctx, func(tx Repositories) error {
+		return fn(tx.Categories)
+	})
+}
+
 // CreateCategory creates a new category with validation
-func (s *categoryService) CreateCategory(name string, description *string, color *string) (Category, error) {
+func (s *categoryService) CreateCategory(ctx context.Context, name string, description *string, color *string) (Category, error) {
 	log.Printf("Creating category: name=%s", name)
 	
 	// Validate name
 	if name == "" {
 		log.Printf("Category creation failed: empty name")
-		return Category{}, fmt.Errorf("category name cannot be empty")
+		return Category{}, newValidationError("name", "category name cannot be empty")
 	}
 	
 	// Validate name length
 	if len(name) > 255 {
 		log.Printf("Category creation failed: name too long (>%d characters)", 255)
-		return Category{}, fmt.Errorf("category name cannot exceed 255 characters")
+		return Category{}, newValidationError("name", "category name cannot exceed 255 characters")
 	}
 	
 	// Validate color format if provided
 	if color != nil && *color != "" {
 		if !isValidHexColor(*color) {
 			log.Printf("Category creation failed: invalid color format %s", *color)
-			return Category{}, fmt.Errorf("invalid hex color format")
+			return Category{}, newValidationError("color", "invalid hex color format, expected #RRGGBB")
 		}
 	}
 	
-	// Check for duplicate name
-	existing, err := s.repo.FindByName(name)
-	if err == nil && existing.ID != 0 {
-		log.Printf("Category creation failed: duplicate name '%s'", name)
-		return Category{}, fmt.Errorf("category with name '%s' already exists", name)
-	}
-	
 	category := Category{
 		Name:        name,
 		Description: description,
 		Color:       color,
 	}
 	
-	result, err := s.repo.Create(category)
+	// Check for a duplicate name and insert in one unit of work, so two
+	// sessions cannot both pass the check
+	var result Category
+	err := s.inTx(ctx, func(repo CategoryRepository) error {
+		existing, err := repo.FindByName(ctx, name)
+		if err == nil && existing.ID != 0 {
+			log.Printf("Category creation failed: duplicate name '%s'", name)
+			return duplicateName("category", name)
+		}
+		result, err = repo.Create(ctx, category)
+		return err
+	})
 	if err != nil {
 		log.Printf("Category creation failed: %v", err)
 		return Category{}, err
@@ -103,52 +126,60 @@ func (s *categoryService) CreateCategory(name string, description *string, color
 }
 
 // GetAllCategories retrieves all categories
-func (s *categoryService) GetAllCategories() ([]Category, error) {
-	return s.repo.FindAll()
+func (s *categoryService) GetAllCategories(ctx context.Context) ([]Category, error) {
+	return s.repo.FindAll(ctx)
 }
 
 // GetCategoryByID retrieves a category by ID
-func (s *categoryService) GetCategoryByID(id int64) (Category, error) {
-	return s.repo.FindByID(id)
+func (s *categoryService) GetCategoryByID(ctx context.Context, id int64) (Category, error) {
+	return s.repo.FindByID(ctx, id)
 }
 
 // UpdateCategory updates an existing category with validation
-func (s *categoryService) UpdateCategory(id int64, name *string, description *string, color *string) (Category, error) {
+func (s *categoryService) UpdateCategory(ctx context.Context, id int64, name *string, description *string, color *string) (Category, error) {
 	log.Printf("Updating category: id=%d", id)
 	
-	// Get existing category
-	existing, err := s.repo.FindByID(id)
-	if err != nil {
-		log.Printf("Category update failed: category not found id=%d", id)
-		return Category{}, err
-	}
-	
-	// Update fields if provided
+	// Validate fields if provided
 	if name != nil {
 		if *name == "" {
 			log.Printf("Category update failed: empty name for id=%d", id)
-			return Category{}, fmt.Errorf("category name cannot be empty")
+			return Category{}, newValidationError("name", "category name cannot be empty")
 		}
 		if len(*name) > 255 {
 			log.Printf("Category update failed: name too long for id=%d", id)
-			return Category{}, fmt.Errorf("category name cannot exceed 255 characters")
+			return Category{}, newValidationError("name", "category name cannot exceed 255 characters")
 		}
-		existing.Name = *name
 	}
 	
-	if description != nil {
-		existing.Description = description
+	if color != nil && *color != "" && !isValidHexColor(*color) {
+		log.Printf("Category update failed: invalid color format %s for id=%d", *color, id)
+		return Category{}, newValidationError("color", "invalid hex color format, expected #RRGGBB")
 	}
 	
-	if color != nil {
-		if *color != "" && !isValidHexColor(*color) {
-			log.Printf("Category update failed: invalid color format %s for id=%d", *color, id)
-			return Category{}, fmt.Errorf("invalid hex color format")
+	// Read, merge and write back in one unit of work so a concurrent update
+	// of other fields is not lost
+	var result Category
+	err := s.inTx(ctx, func(repo CategoryRepository) error {
+		existing, err := repo.FindByID(ctx, id)
+		if err != nil {
+			log.Printf("Category update failed: category not found id=%d", id)
+			return err
 		}
-		existing.Color = color
-	}
-	
-	result, err := s.repo.Update(existing)
+		
+		// Update fields if provided
+		if name != nil {
+			existing.Name = *name
+		}
+		if description != nil {
+			existing.Description = description
+		}
+		if color != nil {
+			existing.Color = color
+		}
+		
+		result, err = repo.Update(ctx, existing)
+		return err
+	})
 	if err != nil {
 		log.Printf("Category update failed: %v for id=%d", err, id)
 		return Category{}, err
@@ -159,10 +190,10 @@ func (s *categoryService) UpdateCategory(id int64, name *string, description *st
 }
 
 // DeleteCategory deletes a category by ID
-func (s *categoryService) DeleteCategory(id int64) error {
+func (s *categoryService) DeleteCategory(ctx context.Context, id int64) error {
 	log.Printf("Deleting category: id=%d", id)
 	
-	err := s.repo.Delete(id)
+	err := s.repo.Delete(ctx, id)
 	if err != nil {
 		log.Printf("Category deletion failed: %v for id=%d", err, id)
 		return err
@@ -173,10 +204,10 @@ func (s *categoryService) DeleteCategory(id int64) error {
 }
 
 // GetTodosByCategory retrieves all todos assigned to a specific category
-func (s *categoryService) GetTodosByCategory(categoryID int64) ([]TodoItem, error) {
+func (s *categoryService) GetTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error) {
 	log.Printf("Getting todos by category: category_id=%d", categoryID)
 	
-	result, err := s.repo.FindTodosByCategory(categoryID)
+	result, err := s.repo.FindTodosByCategory(ctx, categoryID)
 	if err != nil {
 		log.Printf("Failed to get todos by category: %v for category_id=%d", err, categoryID)
 		return nil, err
@@ -187,10 +218,10 @@ func (s *categoryService) GetTodosByCategory(categoryID int64) ([]TodoItem, erro
 }
 
 // GetUncategorizedTodos retrieves all todos without a category
-func (s *categoryService) GetUncategorizedTodos() ([]TodoItem, error) {
+func (s *categoryService) GetUncategorizedTodos(ctx context.Context) ([]TodoItem, error) {
 	log.Printf("Getting uncategorized todos")
 	
-	result, err := s.repo.FindUncategorizedTodos()
+	result, err := s.repo.FindUncategorizedTodos(ctx)
 	if err != nil {
 		log.Printf("Failed to get uncategorized todos: %v", err)
 		return nil, err
@@ -0,0 +1,158 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// NewCategoryMemory creates a new in-memory implementation of CategoryRepository
+func NewCategoryMemory(store *MemoryStore) CategoryRepository {
+	return &category_memory{store: store}
+}
+
+type category_memory struct {
+	store *MemoryStore
+}
+
+// Create creates a new category with the given name, description, and color
+func (c *category_memory) Create(ctx context.Context, category Category) (Category, error) {
+	if category.Name == "" {
+		return Category{}, newValidationError("name", "category name cannot be empty")
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if c.nameTaken(category.Name, 0) {
+		return Category{}, duplicateName("category", category.Name)
+	}
+
+	createdAt := time.Now()
+	c.store.lastCategoryID++
+	newCategory := Category{
+		ID:          c.store.lastCategoryID,
+		Name:        category.Name,
+		Description: category.Description,
+		Color:       category.Color,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	c.store.categories[newCategory.ID] = cloneCategory(newCategory)
+	return cloneCategory(newCategory), nil
+}
+
+// FindAll returns all categories
+func (c *category_memory) FindAll(ctx context.Context) ([]Category, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	var categories []Category
+	for _, category := range c.store.categories {
+		categories = append(categories, cloneCategory(category))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].CreatedAt.Equal(categories[j].CreatedAt) {
+			return categories[i].ID > categories[j].ID
+		}
+		return categories[i].CreatedAt.After(categories[j].CreatedAt)
+	})
+	return categories, nil
+}
+
+// FindByID returns a specific category by ID
+func (c *category_memory) FindByID(ctx context.Context, id int64) (Category, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	category, ok := c.store.categories[id]
+	if !ok {
+		return Category{}, categoryNotFound(id)
+	}
+	return cloneCategory(category), nil
+}
+
+// FindByName returns a category by name
+func (c *category_memory) FindByName(ctx context.Context, name string) (Category, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	for _, category := range c.store.categories {
+		if category.Name == name {
+			return cloneCategory(category), nil
+		}
+	}
+	return Category{}, categoryNameNotFound(name)
+}
+
+// Update updates an existing category
+func (c *category_memory) Update(ctx context.Context, category Category) (Category, error) {
+	if category.Name == "" {
+		return Category{}, newValidationError("name", "category name cannot be empty")
+	}
+
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	existing, ok := c.store.categories[category.ID]
+	if !ok {
+		return Category{}, categoryNotFound(category.ID)
+	}
+	if c.nameTaken(category.Name, category.ID) {
+		return Category{}, duplicateName("category", category.Name)
+	}
+
+	existing.Name = category.Name
+	existing.Description = clonePtr(category.Description)
+	existing.Color = clonePtr(category.Color)
+	existing.UpdatedAt = time.Now()
+	c.store.categories[existing.ID] = existing
+	return cloneCategory(existing), nil
+}
+
+// Delete deletes a category by ID and removes it from any todos, like the
+// ON DELETE SET NULL on todos.category_id
+func (c *category_memory) Delete(ctx context.Context, id int64) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+
+	if _, ok := c.store.categories[id]; !ok {
+		return categoryNotFound(id)
+	}
+	for key, item := range c.store.todos {
+		if item.CategoryID != nil && *item.CategoryID == id {
+			item.CategoryID = nil
+			c.store.todos[key] = item
+		}
+	}
+	delete(c.store.categories, id)
+	return nil
+}
+
+// FindTodosByCategory returns all todos associated with a specific category
+func (c *category_memory) FindTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	return byPriority(c.store.selectTodosNewestFirst(func(item TodoItem) bool {
+		return item.CategoryID != nil && *item.CategoryID == categoryID
+	})), nil
+}
+
+// FindUncategorizedTodos returns all todos that are not assigned to any category
+func (c *category_memory) FindUncategorizedTodos(ctx context.Context) ([]TodoItem, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+
+	return c.store.selectTodosNewestFirst(func(item TodoItem) bool { return item.CategoryID == nil }), nil
+}
+
+// nameTaken mirrors the UNIQUE constraint on categories.name; the caller must hold the lock
+func (c *category_memory) nameTaken(name string, exceptID int64) bool {
+	for _, category := range c.store.categories {
+		if category.Name == name && category.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
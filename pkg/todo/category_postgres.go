@@ -0,0 +1,177 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewCategoryPostgres creates a new PostgreSQL implementation of CategoryRepository
+func NewCategoryPostgres(db DBTX) CategoryRepository {
+	return &category_postgres{db: db}
+}
+
+type category_postgres struct {
+	db DBTX
+}
+
+// Create creates a new category with the given name, description, and color
+func (c *category_postgres) Create(ctx context.Context, category Category) (Category, error) {
+	if category.Name == "" {
+		return Category{}, newValidationError("name", "category name cannot be empty")
+	}
+
+	createdAt := time.Now()
+	updatedAt := createdAt
+
+	stmt, err := c.db.PrepareContext(ctx, "INSERT INTO categories (name, description, color, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id")
+	if err != nil {
+		return Category{}, err
+	}
+	defer stmt.Close()
+
+	var id int64
+	err = stmt.QueryRowContext(ctx, category.Name, category.Description, category.Color, createdAt, updatedAt).Scan(&id)
+	if isUniqueViolation(err) {
+		return Category{}, duplicateName("category", category.Name)
+	}
+	if err != nil {
+		return Category{}, err
+	}
+
+	newCategory := Category{
+		ID:          id,
+		Name:        category.Name,
+		Description: category.Description,
+		Color:       category.Color,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+	return newCategory, nil
+}
+
+// FindAll returns all categories
+func (c *category_postgres) FindAll(ctx context.Context) ([]Category, error) {
+	stmt, err := c.db.PrepareContext(ctx, "SELECT id, name, description, color, created_at, updated_at FROM categories ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []Category
+	for rows.Next() {
+		var category Category
+		err = rows.Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// FindByID returns a specific category by ID
+func (c *category_postgres) FindByID(ctx context.Context, id int64) (Category, error) {
+	var category Category
+	stmt, err := c.db.PrepareContext(ctx, "SELECT id, name, description, color, created_at, updated_at FROM categories WHERE id = $1")
+	if err != nil {
+		return Category{}, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, id).Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return Category{}, orNotFound(err, categoryNotFound(id))
+	}
+	return category, nil
+}
+
+// FindByName returns a category by name
+func (c *category_postgres) FindByName(ctx context.Context, name string) (Category, error) {
+	var category Category
+	stmt, err := c.db.PrepareContext(ctx, "SELECT id, name, description, color, created_at, updated_at FROM categories WHERE name = $1")
+	if err != nil {
+		return Category{}, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, name).Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return Category{}, orNotFound(err, categoryNameNotFound(name))
+	}
+	return category, nil
+}
+
+// Update updates an existing category
+func (c *category_postgres) Update(ctx context.Context, category Category) (Category, error) {
+	if category.Name == "" {
+		return Category{}, newValidationError("name", "category name cannot be empty")
+	}
+
+	updatedAt := time.Now()
+
+	// Update and re-read in one transaction so the returned category is the one written
+	var updated Category
+	err := withTx(ctx, c.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE categories SET name = $1, description = $2, color = $3, updated_at = $4 WHERE id = $5")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		_, err = stmt.ExecContext(ctx, category.Name, category.Description, category.Color, updatedAt, category.ID)
+		if isUniqueViolation(err) {
+			return duplicateName("category", category.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Return the updated category
+		updated, err = (&category_postgres{db: tx}).FindByID(ctx, category.ID)
+		return err
+	})
+	if err != nil {
+		return Category{}, err
+	}
+	return updated, nil
+}
+
+// Delete deletes a category by ID
+func (c *category_postgres) Delete(ctx context.Context, id int64) error {
+	stmt, err := c.db.PrepareContext(ctx, "DELETE FROM categories WHERE id = $1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return categoryNotFound(id)
+	}
+	return nil
+}
+
+// FindTodosByCategory returns all todos associated with a specific category
+func (c *category_postgres) FindTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error) {
+	return queryTodos(ctx, c.db, "SELECT "+todoColumns+" FROM todos WHERE category_id = $1 ORDER BY priority DESC, due_date IS NULL, due_date, created_date DESC", categoryID)
+}
+
+// FindUncategorizedTodos returns all todos that are not assigned to any category
+func (c *category_postgres) FindUncategorizedTodos(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, c.db, "SELECT "+todoColumns+" FROM todos WHERE category_id IS NULL ORDER BY created_date DESC")
+}
\ No newline at end of file
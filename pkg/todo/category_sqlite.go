@@ -1,38 +1,40 @@
 package todo
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 // NewCategorySQLite creates a new SQLite implementation of CategoryRepository
-func NewCategorySQLite(db *sql.DB) CategoryRepository {
+func NewCategorySQLite(db DBTX) CategoryRepository {
 	return &category_sqlite{db: db}
 }
 
 type category_sqlite struct {
-	db *sql.DB
+	db DBTX
 }
 
 // Create creates a new category with the given name, description, and color
-func (c *category_sqlite) Create(category Category) (Category, error) {
+func (c *category_sqlite) Create(ctx context.Context, category Category) (Category, error) {
 	if category.Name == "" {
-		return Category{}, fmt.Errorf("category name cannot be empty")
+		return Category{}, newValidationError("name", "category name cannot be empty")
 	}
 
 	createdAt := time.Now()
 	updatedAt := createdAt
 
-	stmt, err := c.db.Prepare("INSERT INTO categories (name, description, color, created_at, updated_at) VALUES (?, ?, ?, ?, ?)")
+	stmt, err := c.db.PrepareContext(ctx, "INSERT INTO categories (name, description, color, created_at, updated_at) VALUES (?, ?, ?, ?, ?)")
 	if err != nil {
 		return Category{}, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(category.Name, category.Description, category.Color, createdAt, updatedAt)
+	res, err := stmt.ExecContext(ctx, category.Name, category.Description, category.Color, createdAt, updatedAt)
+	if isUniqueViolation(err) {
+		return Category{}, duplicateName("category", category.Name)
+	}
 	if err != nil {
 		return Category{}, err
 	}
@@ -54,14 +56,14 @@ func (c *category_sqlite) Create(category Category) (Category, error) {
 }
 
 // FindAll returns all categories
-func (c *category_sqlite) FindAll() ([]Category, error) {
-	stmt, err := c.db.Prepare("SELECT id, name, description, color, created_at, updated_at FROM categories ORDER BY created_at DESC")
+func (c *category_sqlite) FindAll(ctx context.Context) ([]Category, error) {
+	stmt, err := c.db.PrepareContext(ctx, "SELECT id, name, description, color, created_at, updated_at FROM categories ORDER BY created_at DESC")
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -80,126 +82,100 @@ func (c *category_sqlite) FindAll() ([]Category, error) {
 }
 
 // FindByID returns a specific category by ID
-func (c *category_sqlite) FindByID(id int64) (Category, error) {
+func (c *category_sqlite) FindByID(ctx context.Context, id int64) (Category, error) {
 	var category Category
-	stmt, err := c.db.Prepare("SELECT id, name, description, color, created_at, updated_at FROM categories WHERE id = ?")
+	stmt, err := c.db.PrepareContext(ctx, "SELECT id, name, description, color, created_at, updated_at FROM categories WHERE id = ?")
 	if err != nil {
 		return Category{}, err
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRow(id).Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
+	err = stmt.QueryRowContext(ctx, id).Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return Category{}, fmt.Errorf("category not found")
-		}
-		return Category{}, err
+		return Category{}, orNotFound(err, categoryNotFound(id))
 	}
 	return category, nil
 }
 
 // FindByName returns a category by name
-func (c *category_sqlite) FindByName(name string) (Category, error) {
+func (c *category_sqlite) FindByName(ctx context.Context, name string) (Category, error) {
 	var category Category
-	stmt, err := c.db.Prepare("SELECT id, name, description, color, created_at, updated_at FROM categories WHERE name = ?")
+	stmt, err := c.db.PrepareContext(ctx, "SELECT id, name, description, color, created_at, updated_at FROM categories WHERE name = ?")
 	if err != nil {
 		return Category{}, err
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRow(name).Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
+	err = stmt.QueryRowContext(ctx, name).Scan(&category.ID, &category.Name, &category.Description, &category.Color, &category.CreatedAt, &category.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return Category{}, fmt.Errorf("category not found")
-		}
-		return Category{}, err
+		return Category{}, orNotFound(err, categoryNameNotFound(name))
 	}
 	return category, nil
 }
 
 // Update updates an existing category
-func (c *category_sqlite) Update(category Category) (Category, error) {
+func (c *category_sqlite) Update(ctx context.Context, category Category) (Category, error) {
 	if category.Name == "" {
-		return Category{}, fmt.Errorf("category name cannot be empty")
+		return Category{}, newValidationError("name", "category name cannot be empty")
 	}
 
 	updatedAt := time.Now()
 
-	stmt, err := c.db.Prepare("UPDATE categories SET name = ?, description = ?, color = ?, updated_at = ? WHERE id = ?")
-	if err != nil {
-		return Category{}, err
-	}
-	defer stmt.Close()
+	// Update and re-read in one transaction so the returned category is the one written
+	var updated Category
+	err := withTx(ctx, c.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE categories SET name = ?, description = ?, color = ?, updated_at = ? WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
 
-	_, err = stmt.Exec(category.Name, category.Description, category.Color, updatedAt, category.ID)
+		_, err = stmt.ExecContext(ctx, category.Name, category.Description, category.Color, updatedAt, category.ID)
+		if isUniqueViolation(err) {
+			return duplicateName("category", category.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Return the updated category
+		updated, err = (&category_sqlite{db: tx}).FindByID(ctx, category.ID)
+		return err
+	})
 	if err != nil {
 		return Category{}, err
 	}
-
-	// Return the updated category
-	return c.FindByID(category.ID)
+	return updated, nil
 }
 
 // Delete deletes a category by ID
-func (c *category_sqlite) Delete(id int64) error {
-	stmt, err := c.db.Prepare("DELETE FROM categories WHERE id = ?")
+func (c *category_sqlite) Delete(ctx context.Context, id int64) error {
+	stmt, err := c.db.PrepareContext(ctx, "DELETE FROM categories WHERE id = ?")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(id)
-	return err
-}
-
-// FindTodosByCategory returns all todos associated with a specific category
-func (c *category_sqlite) FindTodosByCategory(categoryID int64) ([]TodoItem, error) {
-	stmt, err := c.db.Prepare("SELECT id, title, completed_at, due_date, created_date, reference_id, project_id, category_id FROM todos WHERE category_id = ? ORDER BY created_date DESC")
+	res, err := stmt.ExecContext(ctx, id)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer stmt.Close()
-
-	rows, err := stmt.Query(categoryID)
+	deleted, err := res.RowsAffected()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	var todos []TodoItem
-	for rows.Next() {
-		var todo TodoItem
-		err = rows.Scan(&todo.ID, &todo.Title, &todo.CompletedAt, &todo.DueDate, &todo.CreatedDate, &todo.ReferenceID, &todo.ProjectID, &todo.CategoryID)
-		if err != nil {
-			return nil, err
-		}
-		todos = append(todos, todo)
+	if deleted == 0 {
+		return categoryNotFound(id)
 	}
-	return todos, nil
+	return nil
 }
 
-// FindUncategorizedTodos returns all todos that are not assigned to any category
-func (c *category_sqlite) FindUncategorizedTodos() ([]TodoItem, error) {
-	stmt, err := c.db.Prepare("SELECT id, title, completed_at, due_date, created_date, reference_id, project_id, category_id FROM todos WHERE category_id IS NULL ORDER BY created_date DESC")
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
-
-	rows, err := stmt.Query()
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// FindTodosByCategory returns all todos associated with a specific category
+func (c *category_sqlite) FindTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error) {
+	return queryTodos(ctx, c.db, "SELECT "+todoColumns+" FROM todos WHERE category_id = ? ORDER BY priority DESC, due_date IS NULL, due_date, created_date DESC", categoryID)
+}
 
-	var todos []TodoItem
-	for rows.Next() {
-		var todo TodoItem
-		err = rows.Scan(&todo.ID, &todo.Title, &todo.CompletedAt, &todo.DueDate, &todo.CreatedDate, &todo.ReferenceID, &todo.ProjectID, &todo.CategoryID)
-		if err != nil {
-			return nil, err
-		}
-		todos = append(todos, todo)
-	}
-	return todos, nil
+// FindUncategorizedTodos returns all todos that are not assigned to any category
+func (c *category_sqlite) FindUncategorizedTodos(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, c.db, "SELECT "+todoColumns+" FROM todos WHERE category_id IS NULL ORDER BY created_date DESC")
 }
\ No newline at end of file
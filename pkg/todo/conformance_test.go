@@ -0,0 +1,157 @@
+package todo_test
+
+import (
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+	"mcp-godo/pkg/todo/todotest"
+	_ "time/tzdata" // the timezone cases run on hosts without a zoneinfo database
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteConformance(t *testing.T) {
+	todotest.RunSuite(t, func(t *testing.T) todotest.Backend {
+		db, err := todo.OpenSQLite(filepath.Join(t.TempDir(), "todos.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		migrate(t, db, todo.DialectSQLite)
+
+		return todotest.Backend{
+			Todos:       todo.NewTodoSQLite(db, nil),
+			TodosIn:     func(loc *time.Location) todo.TodoService { return todo.NewTodoSQLite(db, loc) },
+			Projects:    todo.NewProjectSQLite(db),
+			Categories:  todo.NewCategorySQLite(db),
+			Tags:        todo.NewTagSQLite(db),
+			TimeEntries: todo.NewTimeSQLite(db),
+			Reminders:   todo.NewReminderSQLite(db),
+			Filters:     todo.NewSavedFilterSQLite(db),
+		}
+	})
+}
+
+func TestMemoryConformance(t *testing.T) {
+	todotest.RunSuite(t, func(t *testing.T) todotest.Backend {
+		store := todo.NewMemoryStore()
+		return todotest.Backend{
+			Todos:       todo.NewTodoMemory(store, nil),
+			TodosIn:     func(loc *time.Location) todo.TodoService { return todo.NewTodoMemory(store, loc) },
+			Projects:    todo.NewProjectMemory(store),
+			Categories:  todo.NewCategoryMemory(store),
+			Tags:        todo.NewTagMemory(store),
+			TimeEntries: todo.NewTimeMemory(store),
+			Reminders:   todo.NewReminderMemory(store),
+			Filters:     todo.NewSavedFilterMemory(store),
+		}
+	})
+}
+
+func TestMariaDBConformance(t *testing.T) {
+	// Uses the same test server as the other MariaDB tests (see scripts/test-mariadb.sh)
+	db, err := sql.Open("mysql", "root:password@tcp(localhost:3306)/testdb?parseTime=true")
+	require.NoError(t, err)
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		t.Skipf("MariaDB test database unavailable: %v", err)
+	}
+	migrate(t, db, todo.DialectMariaDB)
+
+	todotest.RunSuite(t, func(t *testing.T) todotest.Backend {
+		// Every case starts from empty tables
+		for _, table := range []string{"saved_filters", "reminders", "time_entries", "todo_dependencies", "todo_tags", "tags", "recurrence_patterns", "todos", "projects", "categories"} {
+			_, err := db.Exec("DELETE FROM " + table)
+			require.NoError(t, err)
+		}
+		return todotest.Backend{
+			Todos:       todo.NewTodoMariaDB(db, nil),
+			TodosIn:     func(loc *time.Location) todo.TodoService { return todo.NewTodoMariaDB(db, loc) },
+			Projects:    todo.NewProjectMariaDB(db),
+			Categories:  todo.NewCategoryMariaDB(db),
+			Tags:        todo.NewTagMariaDB(db),
+			TimeEntries: todo.NewTimeMariaDB(db),
+			Reminders:   todo.NewReminderMariaDB(db),
+			Filters:     todo.NewSavedFilterMariaDB(db),
+		}
+	})
+}
+
+func TestPostgresConformance(t *testing.T) {
+	db := openPostgres(t)
+	migrate(t, db, todo.DialectPostgres)
+
+	todotest.RunSuite(t, func(t *testing.T) todotest.Backend {
+		// Every case starts from empty tables with fresh id sequences
+		_, err := db.Exec("TRUNCATE saved_filters, reminders, time_entries, todo_dependencies, todo_tags, tags, recurrence_patterns, todos, projects, categories RESTART IDENTITY")
+		require.NoError(t, err)
+		return todotest.Backend{
+			Todos:       todo.NewTodoPostgres(db, nil),
+			TodosIn:     func(loc *time.Location) todo.TodoService { return todo.NewTodoPostgres(db, loc) },
+			Projects:    todo.NewProjectPostgres(db),
+			Categories:  todo.NewCategoryPostgres(db),
+			Tags:        todo.NewTagPostgres(db),
+			TimeEntries: todo.NewTimePostgres(db),
+			Reminders:   todo.NewReminderPostgres(db),
+			Filters:     todo.NewSavedFilterPostgres(db),
+		}
+	})
+}
+
+func TestPostgresMigrationsRoundTrip(t *testing.T) {
+	db := openPostgres(t)
+	migrator, err := todo.NewMigrator(db, todo.DialectPostgres)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	rolledBack, err := migrator.Down(len(applied))
+	require.NoError(t, err)
+	require.Len(t, rolledBack, len(applied))
+
+	// Every down script leaves a schema the up scripts can rebuild
+	reapplied, err := migrator.Up()
+	require.NoError(t, err)
+	require.Len(t, reapplied, len(applied))
+}
+
+// openPostgres connects to the server named by POSTGRES_TEST_DSN, or starts an
+// embedded PostgreSQL for the test when it is unset. The test is skipped when
+// neither is available.
+func openPostgres(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		config := embeddedpostgres.DefaultConfig().
+			Port(54329).
+			RuntimePath(t.TempDir()).
+			Logger(io.Discard)
+		server := embeddedpostgres.NewDatabase(config)
+		if err := server.Start(); err != nil {
+			t.Skipf("PostgreSQL unavailable (set POSTGRES_TEST_DSN to use a local server): %v", err)
+		}
+		t.Cleanup(func() { server.Stop() })
+		dsn = config.GetConnectionURL() + "?sslmode=disable"
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Skipf("PostgreSQL test database unavailable: %v", err)
+	}
+	return db
+}
+
+// migrate applies all migrations for dialect to db
+func migrate(t *testing.T, db *sql.DB, dialect string) {
+	t.Helper()
+	migrator, err := todo.NewMigrator(db, dialect)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+}
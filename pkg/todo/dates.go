@@ -0,0 +1,19 @@
+package todo
+
+import "time"
+
+// utcTime returns a copy of t in UTC, or nil. Due and start dates are stored
+// in UTC so the instant does not depend on the server's zone.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// calendarDate returns midnight UTC on t's date as seen in t's own location,
+// which is how an all-day due date is stored
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,43 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// checkNewBlocker rejects making blockerID a blocker of todoID when that would
+// close a loop. chain holds every todo blockerID already waits on, directly or
+// through other todos.
+func checkNewBlocker(todoID string, blockerID string, chain []int64) error {
+	if todoID == blockerID {
+		return newValidationError("blocker_id", "a todo cannot block itself")
+	}
+	for _, id := range chain {
+		if strconv.FormatInt(id, 10) == todoID {
+			return newValidationError("blocker_id", fmt.Sprintf("todo %s already waits on todo %s", blockerID, todoID))
+		}
+	}
+	return nil
+}
+
+// queryOpenBlockers runs a query selecting todo_id and blocker_id, and groups
+// the blocker IDs by todo ID
+func queryOpenBlockers(ctx context.Context, db DBTX, query string, args ...interface{}) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blockers := make(map[string][]string)
+	for rows.Next() {
+		var todoID, blockerID int64
+		if err := rows.Scan(&todoID, &blockerID); err != nil {
+			return nil, err
+		}
+		key := strconv.FormatInt(todoID, 10)
+		blockers[key] = append(blockers[key], strconv.FormatInt(blockerID, 10))
+	}
+	return blockers, rows.Err()
+}
@@ -0,0 +1,148 @@
+package todo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Errors returned by every backend. Match them with errors.Is; the returned
+// error adds the ID or name that was looked up.
+var (
+	ErrTodoNotFound     = errors.New("todo not found")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrDuplicateName    = errors.New("duplicate name")
+	ErrValidation       = errors.New("validation failed")
+
+	ErrRecurrencePatternNotFound = errors.New("recurrence pattern not found")
+	ErrTagNotFound               = errors.New("tag not found")
+	ErrOpenSubtasks              = errors.New("todo has open subtasks")
+	ErrTimeEntryNotFound         = errors.New("time entry not found")
+	ErrTimerRunning              = errors.New("a timer is already running")
+	ErrNoTimerRunning            = errors.New("no timer is running")
+	ErrSavedFilterNotFound       = errors.New("saved filter not found")
+)
+
+// ValidationError reports an invalid input. It matches ErrValidation, and
+// Field names the input that was rejected.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is(err, ErrValidation) true for any ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// newValidationError returns a ValidationError for field
+func newValidationError(field string, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func todoNotFound(id string) error {
+	return fmt.Errorf("%w: id %s", ErrTodoNotFound, id)
+}
+
+func projectNotFound(id int64) error {
+	return fmt.Errorf("%w: id %d", ErrProjectNotFound, id)
+}
+
+func categoryNotFound(id int64) error {
+	return fmt.Errorf("%w: id %d", ErrCategoryNotFound, id)
+}
+
+func recurrencePatternNotFound(id int64) error {
+	return fmt.Errorf("%w: id %d", ErrRecurrencePatternNotFound, id)
+}
+
+func tagNotFound(name string) error {
+	return fmt.Errorf("%w: name '%s'", ErrTagNotFound, name)
+}
+
+// openSubtasks reports that the todo with id cannot be completed while open
+// subtasks remain below it
+func openSubtasks(id string, open int) error {
+	return fmt.Errorf("%w: id %s has %d", ErrOpenSubtasks, id, open)
+}
+
+func timeEntryNotFound(id int64) error {
+	return fmt.Errorf("%w: id %d", ErrTimeEntryNotFound, id)
+}
+
+// timerRunning reports that running has to be stopped before another timer
+// can start
+func timerRunning(running TimeEntry) error {
+	return fmt.Errorf("%w: entry %d on todo %s", ErrTimerRunning, running.ID, running.TodoID)
+}
+
+func savedFilterNotFound(id int64) error {
+	return fmt.Errorf("%w: id %d", ErrSavedFilterNotFound, id)
+}
+
+func savedFilterNameNotFound(name string) error {
+	return fmt.Errorf("%w: name '%s'", ErrSavedFilterNotFound, name)
+}
+
+func categoryNameNotFound(name string) error {
+	return fmt.Errorf("%w: name '%s'", ErrCategoryNotFound, name)
+}
+
+// duplicateName reports that a project, category or saved filter called name
+// already exists
+func duplicateName(kind string, name string) error {
+	return fmt.Errorf("%w: %s with name '%s' already exists", ErrDuplicateName, kind, name)
+}
+
+// orNotFound returns notFound in place of sql.ErrNoRows and err otherwise
+func orNotFound(err error, notFound error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is a unique constraint failure from
+// any of the supported drivers
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062 // ER_DUP_ENTRY
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
+	}
+	return false
+}
+
+// isForeignKeyViolation reports whether err is a foreign key failure from any
+// of the supported drivers
+func isForeignKeyViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1452 // ER_NO_REFERENCED_ROW_2
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23503" // foreign_key_violation
+	}
+	return false
+}
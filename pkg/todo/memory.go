@@ -0,0 +1,263 @@
+package todo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore holds the data behind the in-memory services. Services built on
+// the same store share its data, the way the SQL services share a database.
+// Nothing is persisted.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	todos      map[int64]TodoItem
+	patterns   map[int64]RecurrencePattern
+	projects   map[int64]Project
+	categories map[int64]Category
+	tags       map[int64]Tag
+	todoTags   map[int64]map[int64]bool // tag IDs by todo ID, like todo_tags
+	blockers   map[int64]map[int64]bool // blocker IDs by todo ID, like todo_dependencies
+
+	timeEntries map[int64]TimeEntry
+	reminders   map[int64]Reminder
+	filters     map[int64]SavedFilter
+
+	// Last assigned IDs; like AUTOINCREMENT they are never reused
+	lastTodoID     int64
+	lastPatternID  int64
+	lastProjectID  int64
+	lastCategoryID int64
+	lastTagID      int64
+
+	lastTimeEntryID int64
+	lastReminderID  int64
+	lastFilterID    int64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		todos:      make(map[int64]TodoItem),
+		patterns:   make(map[int64]RecurrencePattern),
+		projects:   make(map[int64]Project),
+		categories: make(map[int64]Category),
+		tags:       make(map[int64]Tag),
+		todoTags:   make(map[int64]map[int64]bool),
+		blockers:   make(map[int64]map[int64]bool),
+
+		timeEntries: make(map[int64]TimeEntry),
+		reminders:   make(map[int64]Reminder),
+		filters:     make(map[int64]SavedFilter),
+	}
+}
+
+// withTx runs fn against a copy of the store while holding the write lock and
+// keeps the copy only if fn succeeds, so a failed unit of work leaves no trace.
+// Other callers wait until fn returns, much like a SQLite write transaction.
+func (s *MemoryStore) withTx(fn func(tx *MemoryStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := s.copyLocked()
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.todos, s.patterns, s.projects, s.categories = tx.todos, tx.patterns, tx.projects, tx.categories
+	s.tags, s.todoTags, s.blockers, s.timeEntries = tx.tags, tx.todoTags, tx.blockers, tx.timeEntries
+	s.lastTodoID, s.lastPatternID, s.lastProjectID, s.lastCategoryID = tx.lastTodoID, tx.lastPatternID, tx.lastProjectID, tx.lastCategoryID
+	s.reminders, s.filters = tx.reminders, tx.filters
+	s.lastTagID, s.lastTimeEntryID, s.lastReminderID, s.lastFilterID = tx.lastTagID, tx.lastTimeEntryID, tx.lastReminderID, tx.lastFilterID
+	return nil
+}
+
+// copyLocked returns a deep copy of the store; the caller must hold the lock
+func (s *MemoryStore) copyLocked() *MemoryStore {
+	c := NewMemoryStore()
+	for id, item := range s.todos {
+		c.todos[id] = cloneTodo(item)
+	}
+	for id, pattern := range s.patterns {
+		c.patterns[id] = clonePattern(pattern)
+	}
+	for id, project := range s.projects {
+		c.projects[id] = cloneProject(project)
+	}
+	for id, category := range s.categories {
+		c.categories[id] = cloneCategory(category)
+	}
+	for id, tag := range s.tags {
+		c.tags[id] = tag
+	}
+	for todoID, tagIDs := range s.todoTags {
+		c.todoTags[todoID] = make(map[int64]bool, len(tagIDs))
+		for tagID := range tagIDs {
+			c.todoTags[todoID][tagID] = true
+		}
+	}
+	for todoID, blockerIDs := range s.blockers {
+		c.blockers[todoID] = make(map[int64]bool, len(blockerIDs))
+		for blockerID := range blockerIDs {
+			c.blockers[todoID][blockerID] = true
+		}
+	}
+	for id, entry := range s.timeEntries {
+		c.timeEntries[id] = cloneTimeEntry(entry)
+	}
+	for id, reminder := range s.reminders {
+		c.reminders[id] = cloneReminder(reminder)
+	}
+	for id, filter := range s.filters {
+		c.filters[id] = cloneFilter(filter)
+	}
+	c.lastTodoID, c.lastPatternID, c.lastProjectID, c.lastCategoryID = s.lastTodoID, s.lastPatternID, s.lastProjectID, s.lastCategoryID
+	c.lastTagID, c.lastTimeEntryID, c.lastReminderID, c.lastFilterID = s.lastTagID, s.lastTimeEntryID, s.lastReminderID, s.lastFilterID
+	return c
+}
+
+// newMemoryServices builds the in-memory service implementations on store,
+// expanding recurring todos in loc
+func newMemoryServices(store *MemoryStore, loc *time.Location) Services {
+	return Services{
+		Todos:      NewTodoMemory(store, loc),
+		Projects:   NewProjectMemory(store),
+		Categories: NewTransactionalCategoryService(NewCategoryMemory(store), memoryUnitOfWork{store: store, loc: loc}),
+		Tags:       NewTagMemory(store),
+
+		TimeEntries: NewTimeMemory(store),
+		Reminders:   NewReminderMemory(store),
+		Filters:     NewSavedFilterMemory(store),
+	}
+}
+
+// newMemoryRepositories builds the in-memory repositories on store,
+// expanding recurring todos in loc
+func newMemoryRepositories(store *MemoryStore, loc *time.Location) Repositories {
+	return Repositories{
+		Todos:      NewTodoMemory(store, loc),
+		Projects:   NewProjectMemory(store),
+		Categories: NewCategoryMemory(store),
+		Tags:       NewTagMemory(store),
+
+		TimeEntries: NewTimeMemory(store),
+		Reminders:   NewReminderMemory(store),
+		Filters:     NewSavedFilterMemory(store),
+	}
+}
+
+// selectTodos returns copies of the todos matching keep, ordered by ID like an
+// unordered SQL query on an AUTOINCREMENT key. The caller must hold the lock.
+func (s *MemoryStore) selectTodos(keep func(item TodoItem) bool) []TodoItem {
+	ids := make([]int64, 0, len(s.todos))
+	for id := range s.todos {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var items []TodoItem
+	for _, id := range ids {
+		if item := s.todos[id]; keep(item) {
+			items = append(items, cloneTodo(item))
+		}
+	}
+	return items
+}
+
+// selectTodosNewestFirst is selectTodos ordered by created_date DESC
+func (s *MemoryStore) selectTodosNewestFirst(keep func(item TodoItem) bool) []TodoItem {
+	items := s.selectTodos(keep)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedDate.After(items[j].CreatedDate) })
+	return items
+}
+
+// byPriority sorts items by priority DESC, due_date IS NULL, due_date,
+// keeping the order they came in for ties, like the SQL listings
+func byPriority(items []TodoItem) []TodoItem {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if (a.DueDate == nil) != (b.DueDate == nil) {
+			return b.DueDate == nil
+		}
+		return a.DueDate != nil && a.DueDate.Before(*b.DueDate)
+	})
+	return items
+}
+
+// selectPatterns returns copies of the recurrence patterns matching keep,
+// ordered by ID. The caller must hold the lock.
+func (s *MemoryStore) selectPatterns(keep func(pattern RecurrencePattern) bool) []RecurrencePattern {
+	ids := make([]int64, 0, len(s.patterns))
+	for id := range s.patterns {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var patterns []RecurrencePattern
+	for _, id := range ids {
+		if pattern := s.patterns[id]; keep(pattern) {
+			patterns = append(patterns, clonePattern(pattern))
+		}
+	}
+	return patterns
+}
+
+// clonePtr copies the value behind p so stored items never share memory with callers
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneTodo(item TodoItem) TodoItem {
+	item.CompletedAt = clonePtr(item.CompletedAt)
+	item.DueDate = clonePtr(item.DueDate)
+	item.ReferenceID = clonePtr(item.ReferenceID)
+	item.ProjectID = clonePtr(item.ProjectID)
+	item.CategoryID = clonePtr(item.CategoryID)
+	item.ParentID = clonePtr(item.ParentID)
+	item.StartDate = clonePtr(item.StartDate)
+	item.Estimate = clonePtr(item.Estimate)
+	return item
+}
+
+func cloneTimeEntry(entry TimeEntry) TimeEntry {
+	entry.EndedAt = clonePtr(entry.EndedAt)
+	return entry
+}
+
+func cloneReminder(reminder Reminder) Reminder {
+	reminder.NotifiedFor = clonePtr(reminder.NotifiedFor)
+	return reminder
+}
+
+func cloneFilter(filter SavedFilter) SavedFilter {
+	filter.Criteria.ProjectID = clonePtr(filter.Criteria.ProjectID)
+	filter.Criteria.CategoryID = clonePtr(filter.Criteria.CategoryID)
+	filter.Criteria.MinPriority = clonePtr(filter.Criteria.MinPriority)
+	filter.Criteria.Tags = append([]string(nil), filter.Criteria.Tags...)
+	return filter
+}
+
+func clonePattern(pattern RecurrencePattern) RecurrencePattern {
+	pattern.Until = clonePtr(pattern.Until)
+	pattern.Count = clonePtr(pattern.Count)
+	return pattern
+}
+
+func cloneProject(project Project) Project {
+	project.Description = clonePtr(project.Description)
+	return project
+}
+
+func cloneCategory(category Category) Category {
+	category.Description = clonePtr(category.Description)
+	category.Color = clonePtr(category.Color)
+	return category
+}
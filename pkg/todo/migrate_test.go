@@ -0,0 +1,133 @@
+package todo
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_DialectsInSync(t *testing.T) {
+	mariadb, err := LoadMigrations(DialectMariaDB)
+	require.NoError(t, err)
+	sqlite, err := LoadMigrations(DialectSQLite)
+	require.NoError(t, err)
+	postgres, err := LoadMigrations(DialectPostgres)
+	require.NoError(t, err)
+
+	require.Equal(t, len(mariadb), len(sqlite), "every migration needs a variant for each dialect")
+	require.Equal(t, len(mariadb), len(postgres), "every migration needs a variant for each dialect")
+	for i := range mariadb {
+		assert.Equal(t, mariadb[i].Version, sqlite[i].Version)
+		assert.Equal(t, mariadb[i].Name, sqlite[i].Name)
+		assert.Equal(t, mariadb[i].Version, postgres[i].Version)
+		assert.Equal(t, mariadb[i].Name, postgres[i].Name)
+		assert.NotEmpty(t, mariadb[i].Down, "mariadb migration %d has no down", mariadb[i].Version)
+		assert.NotEmpty(t, sqlite[i].Down, "sqlite migration %d has no down", sqlite[i].Version)
+		assert.NotEmpty(t, postgres[i].Down, "postgres migration %d has no down", postgres[i].Version)
+	}
+	for i := 1; i < len(sqlite); i++ {
+		assert.Less(t, sqlite[i-1].Version, sqlite[i].Version)
+	}
+}
+
+func TestMigrator_BindPostgres(t *testing.T) {
+	m := &Migrator{dialect: DialectPostgres}
+	assert.Equal(t, "INSERT INTO t (a, b) VALUES ($1, $2)", m.bind("INSERT INTO t (a, b) VALUES (?, ?)"))
+
+	m = &Migrator{dialect: DialectSQLite}
+	assert.Equal(t, "DELETE FROM t WHERE a = ?", m.bind("DELETE FROM t WHERE a = ?"))
+}
+
+func TestLoadMigrations_UnknownDialect(t *testing.T) {
+	_, err := LoadMigrations("oracle")
+	assert.Error(t, err)
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, DialectSQLite)
+	require.NoError(t, err)
+	all, err := LoadMigrations(DialectSQLite)
+	require.NoError(t, err)
+
+	// Fresh database: everything is pending
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, len(all))
+	for _, s := range statuses {
+		assert.Nil(t, s.AppliedAt, "migration %d should be pending", s.Version)
+	}
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+
+	// Running again is a no-op
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err = migrator.Status()
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.NotNil(t, s.AppliedAt, "migration %d should be applied", s.Version)
+	}
+
+	// The migrated schema supports the SQLite services
+	svc := NewTodoSQLite(db, nil)
+	_, err = svc.AddTodo(context.Background(), "After migration", nil)
+	require.NoError(t, err)
+
+	// Roll back the most recent migration only
+	rolledBack, err := migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, all[len(all)-1].Version, rolledBack[0].Version)
+
+	statuses, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+	assert.NotNil(t, statuses[len(statuses)-2].AppliedAt)
+
+	// Roll back everything, then re-apply from scratch
+	rolledBack, err = migrator.Down(len(all))
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, len(all)-1)
+
+	var tables int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'todos'").Scan(&tables))
+	assert.Equal(t, 0, tables)
+
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(all))
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- header comment
+BEGIN;
+
+-- Add a column
+ALTER TABLE todos ADD COLUMN x INT; -- trailing comment
+INSERT INTO notes (body) VALUES ('semi;colon -- not a comment');
+
+CREATE TRIGGER t AFTER DELETE ON categories
+BEGIN
+    UPDATE todos SET category_id = NULL WHERE category_id = OLD.id;
+END;
+
+COMMIT;`
+
+	statements := splitStatements(script)
+	require.Len(t, statements, 3)
+	assert.Equal(t, "ALTER TABLE todos ADD COLUMN x INT", statements[0])
+	assert.Equal(t, "INSERT INTO notes (body) VALUES ('semi;colon -- not a comment')", statements[1])
+	assert.Contains(t, statements[2], "UPDATE todos SET category_id = NULL WHERE category_id = OLD.id;")
+	assert.True(t, len(statements[2]) > 0 && statements[2][len(statements[2])-3:] == "END")
+}
@@ -0,0 +1,13 @@
+package todo
+
+import "fmt"
+
+// maxNotesLength is the most a MariaDB TEXT column holds, in bytes
+const maxNotesLength = 65535
+
+func validateNotes(notes string) error {
+	if len(notes) > maxNotesLength {
+		return newValidationError("notes", fmt.Sprintf("notes cannot exceed %d bytes", maxNotesLength))
+	}
+	return nil
+}
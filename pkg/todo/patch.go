@@ -0,0 +1,184 @@
+package todo
+
+import "time"
+
+// NewTodo lists the fields of a todo added by CreateTodo. Only Title is
+// required. A subtask, one with a ParentID, goes in its parent's project and
+// category, so it cannot name a project of its own.
+type NewTodo struct {
+	Title     string
+	DueDate   *time.Time
+	DueAllDay bool // DueDate is a calendar date; its time of day is dropped
+	ProjectID *int64
+	ParentID  *string
+	Priority  Priority
+	Notes     string
+}
+
+func (n NewTodo) validate() error {
+	switch {
+	case n.Title == "":
+		return newValidationError("title", "title cannot be empty")
+	case n.DueAllDay && n.DueDate == nil:
+		return newValidationError("due_date", "an all-day due date needs a date")
+	case n.ParentID != nil && n.ProjectID != nil:
+		return newValidationError("project_id", "a subtask goes in its parent's project")
+	}
+	if err := validatePriority(n.Priority); err != nil {
+		return err
+	}
+	return validateNotes(n.Notes)
+}
+
+// dueDate returns the due date the new todo stores, in UTC, or nil
+func (n NewTodo) dueDate() *time.Time {
+	return TodoPatch{DueDate: n.DueDate, DueAllDay: n.DueAllDay}.dueDate()
+}
+
+// TodoPatch lists the fields UpdateTodo changes. A nil field is left as it
+// is; the Clear fields remove an optional value instead.
+type TodoPatch struct {
+	Title          *string
+	DueDate        *time.Time
+	DueAllDay      bool // DueDate is a calendar date; its time of day is dropped
+	ClearDueDate   bool
+	ProjectID      *int64
+	ClearProject   bool
+	CategoryID     *int64
+	ClearCategory  bool
+	Notes          *string
+	StartDate      *time.Time
+	ClearStartDate bool
+	Estimate       *int // minutes
+	ClearEstimate  bool
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p TodoPatch) IsEmpty() bool {
+	return p.Title == nil && p.DueDate == nil && !p.ClearDueDate &&
+		p.ProjectID == nil && !p.ClearProject &&
+		p.CategoryID == nil && !p.ClearCategory && p.Notes == nil &&
+		p.StartDate == nil && !p.ClearStartDate &&
+		p.Estimate == nil && !p.ClearEstimate
+}
+
+func (p TodoPatch) validate() error {
+	switch {
+	case p.Title != nil && *p.Title == "":
+		return newValidationError("title", "title cannot be empty")
+	case p.DueDate != nil && p.ClearDueDate:
+		return newValidationError("due_date", "cannot both set and clear the due date")
+	case p.DueAllDay && p.DueDate == nil:
+		return newValidationError("due_date", "an all-day due date needs a date")
+	case p.ProjectID != nil && p.ClearProject:
+		return newValidationError("project_id", "cannot both set and clear the project")
+	case p.CategoryID != nil && p.ClearCategory:
+		return newValidationError("category_id", "cannot both set and clear the category")
+	case p.StartDate != nil && p.ClearStartDate:
+		return newValidationError("start_date", "cannot both set and clear the start date")
+	case p.Estimate != nil && p.ClearEstimate:
+		return newValidationError("estimate", "cannot both set and clear the estimate")
+	case p.Estimate != nil && *p.Estimate <= 0:
+		return newValidationError("estimate", "estimate must be positive")
+	}
+	if p.Notes != nil {
+		return validateNotes(*p.Notes)
+	}
+	return nil
+}
+
+// columns returns the todos columns the patch sets and their new values, in
+// matching order
+func (p TodoPatch) columns() ([]string, []interface{}) {
+	var columns []string
+	var values []interface{}
+	set := func(column string, value interface{}) {
+		columns = append(columns, column)
+		values = append(values, value)
+	}
+	if p.Title != nil {
+		set("title", *p.Title)
+	}
+	if due := p.dueDate(); due != nil {
+		set("due_date", *due)
+		set("due_all_day", p.DueAllDay)
+	} else if p.ClearDueDate {
+		set("due_date", nil)
+		set("due_all_day", false)
+	}
+	if p.ProjectID != nil {
+		set("project_id", *p.ProjectID)
+	} else if p.ClearProject {
+		set("project_id", nil)
+	}
+	if p.CategoryID != nil {
+		set("category_id", *p.CategoryID)
+	} else if p.ClearCategory {
+		set("category_id", nil)
+	}
+	if p.Notes != nil {
+		set("notes", *p.Notes)
+	}
+	if p.StartDate != nil {
+		set("start_date", p.StartDate.UTC())
+	} else if p.ClearStartDate {
+		set("start_date", nil)
+	}
+	if p.Estimate != nil {
+		set("estimate_minutes", *p.Estimate)
+	} else if p.ClearEstimate {
+		set("estimate_minutes", nil)
+	}
+	return columns, values
+}
+
+// apply makes the patch's changes to item
+func (p TodoPatch) apply(item *TodoItem) {
+	if p.Title != nil {
+		item.Title = *p.Title
+	}
+	if due := p.dueDate(); due != nil {
+		item.DueDate = due
+		item.DueAllDay = p.DueAllDay
+	} else if p.ClearDueDate {
+		item.DueDate = nil
+		item.DueAllDay = false
+	}
+	if p.ProjectID != nil {
+		projectID := *p.ProjectID
+		item.ProjectID = &projectID
+	} else if p.ClearProject {
+		item.ProjectID = nil
+	}
+	if p.CategoryID != nil {
+		categoryID := *p.CategoryID
+		item.CategoryID = &categoryID
+	} else if p.ClearCategory {
+		item.CategoryID = nil
+	}
+	if p.Notes != nil {
+		item.Notes = *p.Notes
+	}
+	if p.StartDate != nil {
+		item.StartDate = utcTime(p.StartDate)
+	} else if p.ClearStartDate {
+		item.StartDate = nil
+	}
+	if p.Estimate != nil {
+		item.Estimate = clonePtr(p.Estimate)
+	} else if p.ClearEstimate {
+		item.Estimate = nil
+	}
+}
+
+// dueDate returns the due date the patch stores, in UTC, or nil
+func (p TodoPatch) dueDate() *time.Time {
+	if p.DueDate == nil {
+		return nil
+	}
+	if p.DueAllDay {
+		due := calendarDate(*p.DueDate)
+		return &due
+	}
+	return utcTime(p.DueDate)
+}
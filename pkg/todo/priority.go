@@ -0,0 +1,83 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority ranks how urgent a todo is. The zero value means no priority, and
+// higher values sort first.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = map[Priority]string{
+	PriorityNone:   "none",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+// ParsePriority accepts a priority name (none, low, medium, high, urgent) or
+// P1-P4, where P1 is urgent and P4 is low. Case and surrounding space are
+// ignored.
+func ParsePriority(s string) (Priority, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for priority, candidate := range priorityNames {
+		if name == candidate {
+			return priority, nil
+		}
+	}
+	if len(name) == 2 && name[0] == 'p' && name[1] >= '1' && name[1] <= '4' {
+		return PriorityUrgent - Priority(name[1]-'1'), nil
+	}
+	return PriorityNone, newValidationError("priority", fmt.Sprintf("unknown priority '%s'; use none, low, medium, high, urgent or P1-P4", s))
+}
+
+// String returns the priority's name, such as "high"
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// valid reports whether p is one of the defined priorities
+func (p Priority) valid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+// MarshalText encodes the priority by name, so JSON shows "high" rather than 3
+func (p Priority) MarshalText() ([]byte, error) {
+	if !p.valid() {
+		return nil, fmt.Errorf("invalid priority %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText accepts anything ParsePriority does
+func (p *Priority) UnmarshalText(text []byte) error {
+	priority, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = priority
+	return nil
+}
+
+// validatePriority rejects values outside the defined priorities, which can
+// only come from a conversion such as Priority(7)
+func validatePriority(p Priority) error {
+	if !p.valid() {
+		return newValidationError("priority", fmt.Sprintf("unknown priority %d", int(p)))
+	}
+	return nil
+}
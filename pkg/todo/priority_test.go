@@ -0,0 +1,49 @@
+package todo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Priority
+	}{
+		{"none", PriorityNone},
+		{"low", PriorityLow},
+		{"Medium", PriorityMedium},
+		{" HIGH ", PriorityHigh},
+		{"urgent", PriorityUrgent},
+		{"P1", PriorityUrgent},
+		{"p2", PriorityHigh},
+		{"P3", PriorityMedium},
+		{"P4", PriorityLow},
+	}
+	for _, tt := range tests {
+		got, err := ParsePriority(tt.input)
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+
+	for _, input := range []string{"", "P0", "P5", "critical", "3"} {
+		_, err := ParsePriority(input)
+		var validationErr *ValidationError
+		if assert.ErrorAs(t, err, &validationErr, input) {
+			assert.Equal(t, "priority", validationErr.Field)
+		}
+	}
+}
+
+func TestPriorityJSON(t *testing.T) {
+	data, err := json.Marshal(TodoItem{ID: "1", Priority: PriorityHigh})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"priority":"high"`)
+
+	var item TodoItem
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"1","priority":"P1"}`), &item))
+	assert.Equal(t, PriorityUrgent, item.Priority)
+}
@@ -1,6 +1,7 @@
 package todo
 
 import (
+	"context"
 	"time"
 )
 
@@ -16,20 +17,20 @@ type Project struct {
 // ProjectService defines the interface for project management operations
 type ProjectService interface {
 	// CreateProject creates a new project with the given name and description
-	CreateProject(name string, description *string) (Project, error)
+	CreateProject(ctx context.Context, name string, description *string) (Project, error)
 	
 	// GetAllProjects returns all projects
-	GetAllProjects() []Project
+	GetAllProjects(ctx context.Context) ([]Project, error)
 	
 	// GetProject returns a specific project by ID
-	GetProject(id int64) (Project, error)
+	GetProject(ctx context.Context, id int64) (Project, error)
 	
 	// UpdateProject updates an existing project
-	UpdateProject(id int64, name string, description *string) (Project, error)
+	UpdateProject(ctx context.Context, id int64, name string, description *string) (Project, error)
 	
 	// DeleteProject deletes a project by ID
-	DeleteProject(id int64) (Project, error)
+	DeleteProject(ctx context.Context, id int64) (Project, error)
 	
 	// GetProjectTodos returns all todos associated with a specific project
-	GetProjectTodos(id int64) []TodoItem
+	GetProjectTodos(ctx context.Context, id int64) ([]TodoItem, error)
 }
\ No newline at end of file
@@ -1,39 +1,40 @@
 package todo
 
 import (
-	"database/sql"
-	"fmt"
-	"log"
+	"context"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 // NewProjectMariaDB creates a new MySQL implementation of ProjectService
-func NewProjectMariaDB(db *sql.DB) ProjectService {
+func NewProjectMariaDB(db DBTX) ProjectService {
 	return &project_mariadb{db: db}
 }
 
 type project_mariadb struct {
-	db *sql.DB
+	db DBTX
 }
 
 // CreateProject creates a new project with the given name and description
-func (p *project_mariadb) CreateProject(name string, description *string) (Project, error) {
+func (p *project_mariadb) CreateProject(ctx context.Context, name string, description *string) (Project, error) {
 	if name == "" {
-		return Project{}, fmt.Errorf("project name cannot be empty")
+		return Project{}, newValidationError("name", "project name cannot be empty")
 	}
 
 	createdAt := time.Now()
 	updatedAt := createdAt
 
-	stmt, err := p.db.Prepare("INSERT INTO projects (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)")
+	stmt, err := p.db.PrepareContext(ctx, "INSERT INTO projects (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)")
 	if err != nil {
 		return Project{}, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(name, description, createdAt, updatedAt)
+	res, err := stmt.ExecContext(ctx, name, description, createdAt, updatedAt)
+	if isUniqueViolation(err) {
+		return Project{}, duplicateName("project", name)
+	}
 	if err != nil {
 		return Project{}, err
 	}
@@ -54,16 +55,16 @@ func (p *project_mariadb) CreateProject(name string, description *string) (Proje
 }
 
 // GetAllProjects returns all projects
-func (p *project_mariadb) GetAllProjects() []Project {
-	stmt, err := p.db.Prepare("SELECT id, name, description, created_at, updated_at FROM projects ORDER BY created_at DESC")
+func (p *project_mariadb) GetAllProjects(ctx context.Context) ([]Project, error) {
+	stmt, err := p.db.PrepareContext(ctx, "SELECT id, name, description, created_at, updated_at FROM projects ORDER BY created_at DESC")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer stmt.Close()
 
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -72,100 +73,102 @@ func (p *project_mariadb) GetAllProjects() []Project {
 		var project Project
 		err = rows.Scan(&project.ID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		projects = append(projects, project)
 	}
-	return projects
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projects, nil
 }
 
 // GetProject returns a specific project by ID
-func (p *project_mariadb) GetProject(id int64) (Project, error) {
+func (p *project_mariadb) GetProject(ctx context.Context, id int64) (Project, error) {
 	var project Project
-	stmt, err := p.db.Prepare("SELECT id, name, description, created_at, updated_at FROM projects WHERE id = ?")
+	stmt, err := p.db.PrepareContext(ctx, "SELECT id, name, description, created_at, updated_at FROM projects WHERE id = ?")
 	if err != nil {
 		return Project{}, err
 	}
 	defer stmt.Close()
 
-	err = stmt.QueryRow(id).Scan(&project.ID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
+	err = stmt.QueryRowContext(ctx, id).Scan(&project.ID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return Project{}, fmt.Errorf("project not found")
-		}
-		return Project{}, err
+		return Project{}, orNotFound(err, projectNotFound(id))
 	}
 	return project, nil
 }
 
 // UpdateProject updates an existing project
-func (p *project_mariadb) UpdateProject(id int64, name string, description *string) (Project, error) {
+func (p *project_mariadb) UpdateProject(ctx context.Context, id int64, name string, description *string) (Project, error) {
 	if name == "" {
-		return Project{}, fmt.Errorf("project name cannot be empty")
+		return Project{}, newValidationError("name", "project name cannot be empty")
 	}
 
 	updatedAt := time.Now()
 
-	stmt, err := p.db.Prepare("UPDATE projects SET name = ?, description = ?, updated_at = ? WHERE id = ?")
-	if err != nil {
-		return Project{}, err
-	}
-	defer stmt.Close()
+	// Update and re-read in one transaction so the returned project is the one written
+	var project Project
+	err := withTx(ctx, p.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE projects SET name = ?, description = ?, updated_at = ? WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		_, err = stmt.ExecContext(ctx, name, description, updatedAt, id)
+		if isUniqueViolation(err) {
+			return duplicateName("project", name)
+		}
+		if err != nil {
+			return err
+		}
 
-	_, err = stmt.Exec(name, description, updatedAt, id)
+		// Return the updated project
+		project, err = (&project_mariadb{db: tx}).GetProject(ctx, id)
+		return err
+	})
 	if err != nil {
 		return Project{}, err
 	}
-
-	// Return the updated project
-	return p.GetProject(id)
+	return project, nil
 }
 
 // DeleteProject deletes a project by ID
-func (p *project_mariadb) DeleteProject(id int64) (Project, error) {
-	// First get the project to return it after deletion
-	project, err := p.GetProject(id)
-	if err != nil {
-		return Project{}, err
-	}
-
-	// Start a transaction to ensure atomicity
-	tx, err := p.db.Begin()
-	if err != nil {
-		return Project{}, err
-	}
-	defer func() {
+func (p *project_mariadb) DeleteProject(ctx context.Context, id int64) (Project, error) {
+	// Run the read and both statements in one transaction to ensure
+	// atomicity, joining the caller's transaction if there is one
+	var project Project
+	err := withTx(ctx, p.db, func(tx DBTX) error {
+		// First get the project to return it after deletion
+		var err error
+		project, err = (&project_mariadb{db: tx}).GetProject(ctx, id)
 		if err != nil {
-			tx.Rollback()
+			return err
 		}
-	}()
-
-	// Update active todos (completed_at IS NULL) to set project_id = NULL
-	updateStmt, err := tx.Prepare("UPDATE todos SET project_id = NULL WHERE project_id = ? AND completed_at IS NULL")
-	if err != nil {
-		return Project{}, err
-	}
-	defer updateStmt.Close()
 
-	_, err = updateStmt.Exec(id)
-	if err != nil {
-		return Project{}, err
-	}
+		// Update active todos (completed_at IS NULL) to set project_id = NULL
+		updateStmt, err := tx.PrepareContext(ctx, "UPDATE todos SET project_id = NULL WHERE project_id = ? AND completed_at IS NULL")
+		if err != nil {
+			return err
+		}
+		defer updateStmt.Close()
 
-	// Delete the project
-	deleteStmt, err := tx.Prepare("DELETE FROM projects WHERE id = ?")
-	if err != nil {
-		return Project{}, err
-	}
-	defer deleteStmt.Close()
+		_, err = updateStmt.ExecContext(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	_, err = deleteStmt.Exec(id)
-	if err != nil {
-		return Project{}, err
-	}
+		// Delete the project
+		deleteStmt, err := tx.PrepareContext(ctx, "DELETE FROM projects WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer deleteStmt.Close()
 
-	// Commit the transaction
-	err = tx.Commit()
+		_, err = deleteStmt.ExecContext(ctx, id)
+		return err
+	})
 	if err != nil {
 		return Project{}, err
 	}
@@ -174,27 +177,6 @@ func (p *project_mariadb) DeleteProject(id int64) (Project, error) {
 }
 
 // GetProjectTodos returns all todos associated with a specific project
-func (p *project_mariadb) GetProjectTodos(id int64) []TodoItem {
-	stmt, err := p.db.Prepare("SELECT id, title, completed_at, due_date, created_date, reference_id, project_id FROM todos WHERE project_id = ? ORDER BY created_date DESC")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer stmt.Close()
-
-	rows, err := stmt.Query(id)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer rows.Close()
-
-	var todos []TodoItem
-	for rows.Next() {
-		var todo TodoItem
-		err = rows.Scan(&todo.ID, &todo.Title, &todo.CompletedAt, &todo.DueDate, &todo.CreatedDate, &todo.ReferenceID, &todo.ProjectID)
-		if err != nil {
-			log.Fatal(err)
-		}
-		todos = append(todos, todo)
-	}
-	return todos
+func (p *project_mariadb) GetProjectTodos(ctx context.Context, id int64) ([]TodoItem, error) {
+	return queryTodos(ctx, p.db, "SELECT "+todoColumns+" FROM todos WHERE project_id = ? ORDER BY priority DESC, due_date IS NULL, due_date, created_date DESC", id)
 }
\ No newline at end of file
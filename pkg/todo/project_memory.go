@@ -0,0 +1,137 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// NewProjectMemory creates a new in-memory implementation of ProjectService
+func NewProjectMemory(store *MemoryStore) ProjectService {
+	return &project_memory{store: store}
+}
+
+type project_memory struct {
+	store *MemoryStore
+}
+
+// CreateProject creates a new project with the given name and description
+func (p *project_memory) CreateProject(ctx context.Context, name string, description *string) (Project, error) {
+	if name == "" {
+		return Project{}, newValidationError("name", "project name cannot be empty")
+	}
+
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	if p.nameTaken(name, 0) {
+		return Project{}, duplicateName("project", name)
+	}
+
+	createdAt := time.Now()
+	p.store.lastProjectID++
+	project := Project{
+		ID:          p.store.lastProjectID,
+		Name:        name,
+		Description: description,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+	}
+	p.store.projects[project.ID] = cloneProject(project)
+	return cloneProject(project), nil
+}
+
+// GetAllProjects returns all projects
+func (p *project_memory) GetAllProjects(ctx context.Context) ([]Project, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	var projects []Project
+	for _, project := range p.store.projects {
+		projects = append(projects, cloneProject(project))
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].CreatedAt.Equal(projects[j].CreatedAt) {
+			return projects[i].ID > projects[j].ID
+		}
+		return projects[i].CreatedAt.After(projects[j].CreatedAt)
+	})
+	return projects, nil
+}
+
+// GetProject returns a specific project by ID
+func (p *project_memory) GetProject(ctx context.Context, id int64) (Project, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	project, ok := p.store.projects[id]
+	if !ok {
+		return Project{}, projectNotFound(id)
+	}
+	return cloneProject(project), nil
+}
+
+// UpdateProject updates an existing project
+func (p *project_memory) UpdateProject(ctx context.Context, id int64, name string, description *string) (Project, error) {
+	if name == "" {
+		return Project{}, newValidationError("name", "project name cannot be empty")
+	}
+
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	project, ok := p.store.projects[id]
+	if !ok {
+		return Project{}, projectNotFound(id)
+	}
+	if p.nameTaken(name, id) {
+		return Project{}, duplicateName("project", name)
+	}
+
+	project.Name = name
+	project.Description = clonePtr(description)
+	project.UpdatedAt = time.Now()
+	p.store.projects[id] = project
+	return cloneProject(project), nil
+}
+
+// DeleteProject deletes a project by ID. Active todos are detached from the
+// project; completed todos keep their project_id as history.
+func (p *project_memory) DeleteProject(ctx context.Context, id int64) (Project, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	project, ok := p.store.projects[id]
+	if !ok {
+		return Project{}, projectNotFound(id)
+	}
+
+	for key, item := range p.store.todos {
+		if item.ProjectID != nil && *item.ProjectID == id && item.CompletedAt == nil {
+			item.ProjectID = nil
+			p.store.todos[key] = item
+		}
+	}
+	delete(p.store.projects, id)
+	return cloneProject(project), nil
+}
+
+// GetProjectTodos returns all todos associated with a specific project
+func (p *project_memory) GetProjectTodos(ctx context.Context, id int64) ([]TodoItem, error) {
+	p.store.mu.RLock()
+	defer p.store.mu.RUnlock()
+
+	return byPriority(p.store.selectTodosNewestFirst(func(item TodoItem) bool {
+		return item.ProjectID != nil && *item.ProjectID == id
+	})), nil
+}
+
+// nameTaken mirrors the UNIQUE constraint on projects.name; the caller must hold the lock
+func (p *project_memory) nameTaken(name string, exceptID int64) bool {
+	for _, project := range p.store.projects {
+		if project.Name == name && project.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewProjectPostgres creates a new PostgreSQL implementation of ProjectService
+func NewProjectPostgres(db DBTX) ProjectService {
+	return &project_postgres{db: db}
+}
+
+type project_postgres struct {
+	db DBTX
+}
+
+// CreateProject creates a new project with the given name and description
+func (p *project_postgres) CreateProject(ctx context.Context, name string, description *string) (Project, error) {
+	if name == "" {
+		return Project{}, newValidationError("name", "project name cannot be empty")
+	}
+
+	createdAt := time.Now()
+	updatedAt := createdAt
+
+	stmt, err := p.db.PrepareContext(ctx, "INSERT INTO projects (name, description, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id")
+	if err != nil {
+		return Project{}, err
+	}
+	defer stmt.Close()
+
+	var id int64
+	err = stmt.QueryRowContext(ctx, name, description, createdAt, updatedAt).Scan(&id)
+	if isUniqueViolation(err) {
+		return Project{}, duplicateName("project", name)
+	}
+	if err != nil {
+		return Project{}, err
+	}
+
+	newProject := Project{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+	return newProject, nil
+}
+
+// GetAllProjects returns all projects
+func (p *project_postgres) GetAllProjects(ctx context.Context) ([]Project, error) {
+	stmt, err := p.db.PrepareContext(ctx, "SELECT id, name, description, created_at, updated_at FROM projects ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		var project Project
+		err = rows.Scan(&project.ID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetProject returns a specific project by ID
+func (p *project_postgres) GetProject(ctx context.Context, id int64) (Project, error) {
+	var project Project
+	stmt, err := p.db.PrepareContext(ctx, "SELECT id, name, description, created_at, updated_at FROM projects WHERE id = $1")
+	if err != nil {
+		return Project{}, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, id).Scan(&project.ID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		return Project{}, orNotFound(err, projectNotFound(id))
+	}
+	return project, nil
+}
+
+// UpdateProject updates an existing project
+func (p *project_postgres) UpdateProject(ctx context.Context, id int64, name string, description *string) (Project, error) {
+	if name == "" {
+		return Project{}, newValidationError("name", "project name cannot be empty")
+	}
+
+	updatedAt := time.Now()
+
+	// Update and re-read in one transaction so the returned project is the one written
+	var project Project
+	err := withTx(ctx, p.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE projects SET name = $1, description = $2, updated_at = $3 WHERE id = $4")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		_, err = stmt.ExecContext(ctx, name, description, updatedAt, id)
+		if isUniqueViolation(err) {
+			return duplicateName("project", name)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Return the updated project
+		project, err = (&project_postgres{db: tx}).GetProject(ctx, id)
+		return err
+	})
+	if err != nil {
+		return Project{}, err
+	}
+	return project, nil
+}
+
+// DeleteProject deletes a project by ID
+func (p *project_postgres) DeleteProject(ctx context.Context, id int64) (Project, error) {
+	// Run the read and both statements in one transaction to ensure
+	// atomicity, joining the caller's transaction if there is one
+	var project Project
+	err := withTx(ctx, p.db, func(tx DBTX) error {
+		// First get the project to return it after deletion
+		var err error
+		project, err = (&project_postgres{db: tx}).GetProject(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Update active todos (completed_at IS NULL) to set project_id = NULL
+		updateStmt, err := tx.PrepareContext(ctx, "UPDATE todos SET project_id = NULL WHERE project_id = $1 AND completed_at IS NULL")
+		if err != nil {
+			return err
+		}
+		defer updateStmt.Close()
+
+		_, err = updateStmt.ExecContext(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Delete the project
+		deleteStmt, err := tx.PrepareContext(ctx, "DELETE FROM projects WHERE id = $1")
+		if err != nil {
+			return err
+		}
+		defer deleteStmt.Close()
+
+		_, err = deleteStmt.ExecContext(ctx, id)
+		return err
+	})
+	if err != nil {
+		return Project{}, err
+	}
+
+	return project, nil
+}
+
+// GetProjectTodos returns all todos associated with a specific project
+func (p *project_postgres) GetProjectTodos(ctx context.Context, id int64) ([]TodoItem, error) {
+	return queryTodos(ctx, p.db, "SELECT "+todoColumns+" FROM todos WHERE project_id = $1 ORDER BY priority DESC, due_date IS NULL, due_date, created_date DESC", id)
+}
\ No newline at end of file
@@ -1,6 +1,7 @@
 package todo
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -13,34 +14,34 @@ type MockProjectService struct {
 	mock.Mock
 }
 
-func (m *MockProjectService) CreateProject(name string, description *string) (Project, error) {
+func (m *MockProjectService) CreateProject(ctx context.Context, name string, description *string) (Project, error) {
 	args := m.Called(name, description)
 	return args.Get(0).(Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetAllProjects() []Project {
+func (m *MockProjectService) GetAllProjects(ctx context.Context) ([]Project, error) {
 	args := m.Called()
-	return args.Get(0).([]Project)
+	return args.Get(0).([]Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetProject(id int64) (Project, error) {
+func (m *MockProjectService) GetProject(ctx context.Context, id int64) (Project, error) {
 	args := m.Called(id)
 	return args.Get(0).(Project), args.Error(1)
 }
 
-func (m *MockProjectService) UpdateProject(id int64, name string, description *string) (Project, error) {
+func (m *MockProjectService) UpdateProject(ctx context.Context, id int64, name string, description *string) (Project, error) {
 	args := m.Called(id, name, description)
 	return args.Get(0).(Project), args.Error(1)
 }
 
-func (m *MockProjectService) DeleteProject(id int64) (Project, error) {
+func (m *MockProjectService) DeleteProject(ctx context.Context, id int64) (Project, error) {
 	args := m.Called(id)
 	return args.Get(0).(Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetProjectTodos(id int64) []TodoItem {
+func (m *MockProjectService) GetProjectTodos(ctx context.Context, id int64) ([]TodoItem, error) {
 	args := m.Called(id)
-	return args.Get(0).([]TodoItem)
+	return args.Get(0).([]TodoItem), args.Error(1)
 }
 
 func TestProjectModel(t *testing.T) {
@@ -83,7 +84,7 @@ func TestProjectServiceMock(t *testing.T) {
 	
 	mockService.On("CreateProject", "Test Project", &description).Return(expectedProject, nil)
 	
-	project, err := mockService.CreateProject("Test Project", &description)
+	project, err := mockService.CreateProject(context.Background(), "Test Project", &description)
 	
 	assert.NoError(t, err)
 	assert.Equal(t, expectedProject.ID, project.ID)
@@ -111,10 +112,11 @@ func TestProjectServiceGetAllProjects(t *testing.T) {
 		},
 	}
 	
-	mockService.On("GetAllProjects").Return(expectedProjects)
+	mockService.On("GetAllProjects").Return(expectedProjects, nil)
 	
-	projects := mockService.GetAllProjects()
+	projects, err := mockService.GetAllProjects(context.Background())
 	
+	assert.NoError(t, err)
 	assert.Len(t, projects, 2)
 	assert.Equal(t, "Project 1", projects[0].Name)
 	assert.Equal(t, "Project 2", projects[1].Name)
@@ -136,7 +138,7 @@ func TestProjectServiceGetProject(t *testing.T) {
 	
 	mockService.On("GetProject", int64(1)).Return(expectedProject, nil)
 	
-	project, err := mockService.GetProject(1)
+	project, err := mockService.GetProject(context.Background(), 1)
 	
 	assert.NoError(t, err)
 	assert.Equal(t, expectedProject.ID, project.ID)
@@ -161,10 +163,11 @@ func TestProjectServiceGetProjectTodos(t *testing.T) {
 		},
 	}
 	
-	mockService.On("GetProjectTodos", int64(1)).Return(expectedTodos)
+	mockService.On("GetProjectTodos", int64(1)).Return(expectedTodos, nil)
 	
-	todos := mockService.GetProjectTodos(1)
+	todos, err := mockService.GetProjectTodos(context.Background(), 1)
 	
+	assert.NoError(t, err)
 	assert.Len(t, todos, 2)
 	assert.Equal(t, "Todo 1", todos[0].Title)
 	assert.Equal(t, "Todo 2", todos[1].Title)
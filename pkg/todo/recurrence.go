@@ -0,0 +1,233 @@
+package todo
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcp-godo/pkg/rrule"
+)
+
+// Frequencies accepted in RecurrencePattern.Frequency. Patterns that need
+// more than a frequency and interval set RecurrencePattern.RRule instead.
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// normalizeRecurrencePattern checks that a pattern can be used to generate
+// occurrences before it is stored. A pattern with an RRule has its Frequency,
+// Interval, Until and Count filled in from the rule, and the rule itself
+// rewritten in canonical form.
+func normalizeRecurrencePattern(pattern RecurrencePattern) (RecurrencePattern, error) {
+	if pattern.TodoID == "" {
+		return RecurrencePattern{}, newValidationError("todo_id", "todo_id cannot be empty")
+	}
+	return canonicalRecurrence(pattern)
+}
+
+// canonicalRecurrence is normalizeRecurrencePattern without the todo check,
+// for patterns that are only previewed
+func canonicalRecurrence(pattern RecurrencePattern) (RecurrencePattern, error) {
+	if pattern.RRule != "" {
+		rule, err := rrule.Parse(pattern.RRule)
+		if err != nil {
+			return RecurrencePattern{}, newValidationError("rrule", err.Error())
+		}
+		pattern.RRule = rule.String()
+		pattern.Frequency = strings.ToLower(rule.Freq.String())
+		pattern.Interval = rule.Interval
+		pattern.Until = rule.Until
+		pattern.Count = nil
+		if rule.Count > 0 {
+			count := rule.Count
+			pattern.Count = &count
+		}
+		return pattern, nil
+	}
+
+	if _, ok := frequencies[strings.ToLower(pattern.Frequency)]; !ok {
+		return RecurrencePattern{}, newValidationError("frequency", fmt.Sprintf("unsupported frequency '%s', expected daily, weekly, monthly or yearly", pattern.Frequency))
+	}
+	if pattern.Interval < 1 {
+		return RecurrencePattern{}, newValidationError("interval", "interval must be at least 1")
+	}
+	if pattern.Count != nil && *pattern.Count < 1 {
+		return RecurrencePattern{}, newValidationError("count", "count must be at least 1")
+	}
+	return pattern, nil
+}
+
+var frequencies = map[string]rrule.Frequency{
+	FrequencyDaily:   rrule.Daily,
+	FrequencyWeekly:  rrule.Weekly,
+	FrequencyMonthly: rrule.Monthly,
+	FrequencyYearly:  rrule.Yearly,
+}
+
+// recurrenceRule returns the rule pattern follows for a series starting at
+// start. A pattern without an RRule is a plain FREQ and INTERVAL, except that
+// monthly and yearly steps keep start's day of month clamped to the end of
+// shorter months, so a series starting on Jan 31 continues on Feb 28 and
+// Mar 31. An RRule is followed exactly, so FREQ=MONTHLY from Jan 31 skips
+// February as RFC 5545 requires.
+func recurrenceRule(pattern RecurrencePattern, start time.Time) (rrule.Rule, error) {
+	if pattern.RRule != "" {
+		return rrule.Parse(pattern.RRule)
+	}
+
+	freq, ok := frequencies[strings.ToLower(pattern.Frequency)]
+	if !ok {
+		return rrule.Rule{}, fmt.Errorf("unsupported recurrence frequency '%s'", pattern.Frequency)
+	}
+	rule := rrule.Rule{Freq: freq, Interval: pattern.Interval, Until: pattern.Until, WeekStart: time.Monday}
+	if pattern.Count != nil {
+		rule.Count = *pattern.Count
+	}
+
+	day := start.Day()
+	switch {
+	case freq == rrule.Monthly && day > 28:
+		for d := 28; d <= day; d++ {
+			rule.ByMonthDay = append(rule.ByMonthDay, d)
+		}
+		rule.BySetPos = []int{-1}
+	case freq == rrule.Yearly && start.Month() == time.February && day == 29:
+		rule.ByMonth = []time.Month{time.February}
+		rule.ByMonthDay = []int{28, 29}
+		rule.BySetPos = []int{-1}
+	}
+	return rule, nil
+}
+
+// nextOccurrence returns the first date of the series starting at start that
+// falls after from. ok is false once the pattern's Until has passed; Count is
+// left to the caller, which knows how many instances already exist.
+func nextOccurrence(pattern RecurrencePattern, start time.Time, from time.Time) (time.Time, bool, error) {
+	rule, err := recurrenceRule(pattern, start)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	rule.Count = 0
+	next, ok := rule.After(start, from)
+	return next, ok, nil
+}
+
+// PreviewRecurrence returns up to n dates of pattern's series starting at
+// start, without storing anything. The pattern is validated as
+// AddRecurrencePattern would, except that it needs no TodoID.
+func PreviewRecurrence(pattern RecurrencePattern, start time.Time, n int) ([]time.Time, error) {
+	pattern, err := canonicalRecurrence(pattern)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := recurrenceRule(pattern, start)
+	if err != nil {
+		return nil, err
+	}
+	return rule.Occurrences(start, n), nil
+}
+
+// Summary describes the pattern in English, such as "every week on Monday
+// and Friday" or "every month, until 2030-12-31 (paused)"
+func (p RecurrencePattern) Summary() string {
+	var summary string
+	if rule, err := recurrenceRule(p, time.Time{}); err == nil {
+		summary = rule.Describe()
+	} else {
+		summary = fmt.Sprintf("every %d %s", p.Interval, p.Frequency)
+	}
+	if p.Paused {
+		summary += " (paused)"
+	}
+	return summary
+}
+
+// scanRecurrencePattern scans the columns id, todo_id, frequency, interval,
+// until, count, rrule and paused of recurrence_patterns, in that order
+func scanRecurrencePattern(row interface{ Scan(dest ...interface{}) error }) (RecurrencePattern, error) {
+	var pattern RecurrencePattern
+	var rule sql.NullString
+	err := row.Scan(&pattern.ID, &pattern.TodoID, &pattern.Frequency, &pattern.Interval, &pattern.Until, &pattern.Count, &rule, &pattern.Paused)
+	if err != nil {
+		return RecurrencePattern{}, err
+	}
+	pattern.RRule = rule.String
+	return pattern, nil
+}
+
+// nullableRRule returns the value to store in recurrence_patterns.rrule,
+// which is NULL for a pattern without an RRule
+func nullableRRule(pattern RecurrencePattern) any {
+	if pattern.RRule == "" {
+		return nil
+	}
+	return pattern.RRule
+}
+
+// seriesRoot returns the ID of the first todo in current's recurring series.
+// The first todo owns the pattern and every later instance points back to it
+// through ReferenceID.
+func seriesRoot(current TodoItem) (int64, error) {
+	if current.ReferenceID != nil {
+		return *current.ReferenceID, nil
+	}
+	return strconv.ParseInt(current.ID, 10, 64)
+}
+
+// nextInstance builds the todo that follows current in pattern's series.
+// start is the due date of the series' first todo, which anchors every
+// occurrence; nil falls back to current's. generated is the number of
+// instances the series already has, current included. The successor is due at
+// the first occurrence after current's due date, or after completedAt when
+// current had none, and never before notBefore, which skips the dates missed
+// while a pattern was paused. ok is false once Until or Count ends the series.
+// A timed series is expanded on the calendar of loc, so BYDAY and the time of
+// day hold there across DST changes; nil means UTC. All-day dates are
+// calendar dates and are expanded as stored.
+func nextInstance(pattern RecurrencePattern, current TodoItem, start *time.Time, generated int, completedAt time.Time, notBefore time.Time, loc *time.Location) (TodoItem, bool, error) {
+	if pattern.Count != nil && generated >= *pattern.Count {
+		return TodoItem{}, false, nil
+	}
+
+	from := completedAt
+	if current.DueDate != nil {
+		from = *current.DueDate
+	}
+	anchor := from
+	if start != nil && current.DueDate != nil {
+		anchor = *start
+	}
+	if from.Before(notBefore) {
+		from = notBefore
+	}
+	if loc != nil && !current.DueAllDay {
+		anchor = anchor.In(loc)
+	}
+	due, ok, err := nextOccurrence(pattern, anchor, from)
+	if err != nil || !ok {
+		return TodoItem{}, false, err
+	}
+	due = due.UTC()
+
+	root, err := seriesRoot(current)
+	if err != nil {
+		return TodoItem{}, false, err
+	}
+	return TodoItem{
+		Title:       current.Title,
+		DueDate:     &due,
+		DueAllDay:   current.DueAllDay,
+		Estimate:    clonePtr(current.Estimate),
+		ReferenceID: &root,
+		ProjectID:   current.ProjectID,
+		CategoryID:  current.CategoryID,
+		Priority:    current.Priority,
+		Notes:       current.Notes,
+		ParentID:    current.ParentID,
+	}, true, nil
+}
@@ -0,0 +1,151 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 9, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		frequency string
+		interval  int
+		start     time.Time
+		from      time.Time
+		want      time.Time
+	}{
+		{"daily", "daily", 1, day(2030, time.March, 1), day(2030, time.March, 1), day(2030, time.March, 2)},
+		{"every third day", "daily", 3, day(2030, time.March, 1), day(2030, time.March, 1), day(2030, time.March, 4)},
+		{"fortnightly", "weekly", 2, day(2030, time.March, 1), day(2030, time.March, 1), day(2030, time.March, 15)},
+		{"monthly clamps to month end", "monthly", 1, day(2030, time.January, 31), day(2030, time.January, 31), day(2030, time.February, 28)},
+		{"monthly keeps the start day", "monthly", 1, day(2030, time.January, 31), day(2030, time.February, 28), day(2030, time.March, 31)},
+		{"quarterly", "monthly", 3, day(2030, time.November, 30), day(2030, time.November, 30), day(2031, time.February, 28)},
+		{"yearly from leap day", "yearly", 1, day(2028, time.February, 29), day(2028, time.February, 29), day(2029, time.February, 28)},
+		{"yearly back to leap day", "yearly", 1, day(2028, time.February, 29), day(2031, time.March, 1), day(2032, time.February, 29)},
+		{"skips missed occurrences", "weekly", 1, day(2030, time.March, 1), day(2030, time.March, 20), day(2030, time.March, 22)},
+		{"case-insensitive", "Weekly", 1, day(2030, time.March, 1), day(2030, time.March, 1), day(2030, time.March, 8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := RecurrencePattern{Frequency: tt.frequency, Interval: tt.interval}
+			got, ok, err := nextOccurrence(pattern, tt.start, tt.from)
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, _, err := nextOccurrence(RecurrencePattern{Frequency: "hourly", Interval: 1}, day(2030, time.March, 1), day(2030, time.March, 1))
+	assert.Error(t, err)
+}
+
+func TestNextInstance(t *testing.T) {
+	due := time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2030, time.March, 3, 18, 0, 0, 0, time.UTC)
+	projectID := int64(4)
+	current := TodoItem{ID: "7", Title: "Water plants", DueDate: &due, ProjectID: &projectID}
+
+	next, ok, err := nextInstance(RecurrencePattern{Frequency: "weekly", Interval: 1}, current, nil, 1, completedAt, time.Time{}, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "Water plants", next.Title)
+	assert.Equal(t, due.AddDate(0, 0, 7), *next.DueDate)
+	assert.Equal(t, int64(7), *next.ReferenceID)
+	assert.Equal(t, &projectID, next.ProjectID)
+
+	// Without a due date the series steps from the completion time
+	undated := TodoItem{ID: "7", Title: "Water plants"}
+	next, ok, err = nextInstance(RecurrencePattern{Frequency: "daily", Interval: 1}, undated, nil, 1, completedAt, time.Time{}, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, completedAt.AddDate(0, 0, 1), *next.DueDate)
+
+	count := 2
+	_, ok, err = nextInstance(RecurrencePattern{Frequency: "daily", Interval: 1, Count: &count}, current, nil, 2, completedAt, time.Time{}, nil)
+	require.NoError(t, err)
+	assert.False(t, ok, "count reached")
+
+	until := due.AddDate(0, 0, 6)
+	_, ok, err = nextInstance(RecurrencePattern{Frequency: "weekly", Interval: 1, Until: &until}, current, nil, 1, completedAt, time.Time{}, nil)
+	require.NoError(t, err)
+	assert.False(t, ok, "past until")
+
+	// Resuming a paused series skips the dates it missed
+	notBefore := due.AddDate(0, 0, 20)
+	next, ok, err = nextInstance(RecurrencePattern{Frequency: "weekly", Interval: 1}, current, &due, 1, completedAt, notBefore, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, due.AddDate(0, 0, 21), *next.DueDate)
+}
+
+func TestRecurrencePatternSummary(t *testing.T) {
+	count := 3
+	assert.Equal(t, "every 2 weeks, 3 times", RecurrencePattern{Frequency: "weekly", Interval: 2, Count: &count}.Summary())
+	assert.Equal(t, "every month on the last weekday (paused)", RecurrencePattern{RRule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", Paused: true}.Summary())
+}
+
+func TestNextOccurrenceRRule(t *testing.T) {
+	// 2030-03-06 is a Wednesday
+	start := time.Date(2030, time.March, 6, 9, 0, 0, 0, time.UTC)
+	pattern := RecurrencePattern{RRule: "FREQ=WEEKLY;BYDAY=MO,WE,FR"}
+
+	next, ok, err := nextOccurrence(pattern, start, start)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, start.AddDate(0, 0, 2), next)
+
+	// An RRULE is followed exactly, so a monthly rule skips short months
+	monthEnd := time.Date(2030, time.January, 31, 9, 0, 0, 0, time.UTC)
+	next, ok, err = nextOccurrence(RecurrencePattern{RRule: "FREQ=MONTHLY"}, monthEnd, monthEnd)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2030, time.March, 31, 9, 0, 0, 0, time.UTC), next)
+}
+
+func TestPreviewRecurrence(t *testing.T) {
+	start := time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+	dates, err := PreviewRecurrence(RecurrencePattern{RRule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=2"}, start, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2030, time.March, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2030, time.April, 30, 9, 0, 0, 0, time.UTC),
+	}, dates)
+
+	dates, err = PreviewRecurrence(RecurrencePattern{Frequency: "monthly", Interval: 1}, time.Date(2030, time.January, 31, 9, 0, 0, 0, time.UTC), 2)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2030, time.February, 28, 9, 0, 0, 0, time.UTC), dates[1])
+
+	_, err = PreviewRecurrence(RecurrencePattern{RRule: "FREQ=HOURLY"}, start, 5)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "rrule", validationErr.Field)
+}
+
+func TestNormalizeRecurrencePattern(t *testing.T) {
+	_, err := normalizeRecurrencePattern(RecurrencePattern{TodoID: "1", Frequency: "monthly", Interval: 1})
+	assert.NoError(t, err)
+
+	_, err = normalizeRecurrencePattern(RecurrencePattern{TodoID: "1", Frequency: "hourly", Interval: 1})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "frequency", validationErr.Field)
+
+	_, err = normalizeRecurrencePattern(RecurrencePattern{TodoID: "1", Frequency: "daily", Interval: 0})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "interval", validationErr.Field)
+
+	// The rule replaces whatever frequency and interval were given
+	pattern, err := normalizeRecurrencePattern(RecurrencePattern{TodoID: "1", Frequency: "daily", RRule: "rrule:byday=2tu;freq=monthly;interval=2;count=6"})
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;COUNT=6;BYDAY=2TU", pattern.RRule)
+	assert.Equal(t, "monthly", pattern.Frequency)
+	assert.Equal(t, 2, pattern.Interval)
+	require.NotNil(t, pattern.Count)
+	assert.Equal(t, 6, *pattern.Count)
+}
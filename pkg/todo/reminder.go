@@ -0,0 +1,171 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Reminder fires Offset minutes before its todo's due date. Moving the due
+// date re-arms it.
+type Reminder struct {
+	ID          int64      `json:"id"`
+	TodoID      string     `json:"todo_id"`
+	Offset      int        `json:"offset"`       // minutes before the due date; 0 fires at the due date
+	NotifiedFor *time.Time `json:"notified_for"` // the due date it last fired for; nil until it fires
+}
+
+// PendingReminder is a reminder that has not fired for its todo's current
+// due date. Todo carries the todo's ID, title and due date.
+type PendingReminder struct {
+	Reminder
+	Todo TodoItem
+}
+
+// FiresAt returns when the reminder fires. An all-day due date counts from
+// the start of its day in loc.
+func (p PendingReminder) FiresAt(loc *time.Location) time.Time {
+	due := *p.Todo.DueDate
+	if p.Todo.DueAllDay {
+		due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, loc)
+	}
+	return due.Add(-time.Duration(p.Offset) * time.Minute)
+}
+
+// ReminderService defines the interface for todo reminders. Reminders are
+// stored so that they survive restarts; a scheduler polls
+// GetPendingReminders and marks the ones it sends.
+type ReminderService interface {
+	// SetReminders replaces a todo's reminders with one per offset, in
+	// minutes before its due date. Reminders whose offset is kept are not
+	// re-armed; no offsets removes them all.
+	SetReminders(ctx context.Context, todoID string, offsets []int) ([]Reminder, error)
+
+	// GetReminders returns a todo's reminders, earliest first
+	GetReminders(ctx context.Context, todoID string) ([]Reminder, error)
+
+	// GetPendingReminders returns the reminders of open todos with a due
+	// date that have not fired for that due date
+	GetPendingReminders(ctx context.Context) ([]PendingReminder, error)
+
+	// MarkReminderSent records that a reminder fired for dueDate. A reminder
+	// deleted in the meantime is ignored.
+	MarkReminderSent(ctx context.Context, id int64, dueDate time.Time) error
+}
+
+const (
+	maxReminders      = 10
+	maxReminderOffset = 30 * 24 * 60 // 30 days in minutes
+)
+
+// normalizeReminderOffsets validates offsets and returns them without
+// duplicates, largest first
+func normalizeReminderOffsets(offsets []int) ([]int, error) {
+	seen := make(map[int]bool, len(offsets))
+	var result []int
+	for _, offset := range offsets {
+		if offset < 0 {
+			return nil, newValidationError("offsets", "reminder offsets cannot be negative")
+		}
+		if offset > maxReminderOffset {
+			return nil, newValidationError("offsets", "reminder offsets cannot exceed 30 days")
+		}
+		if !seen[offset] {
+			seen[offset] = true
+			result = append(result, offset)
+		}
+	}
+	if len(result) > maxReminders {
+		return nil, newValidationError("offsets", fmt.Sprintf("a todo cannot have more than %d reminders", maxReminders))
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(result)))
+	return result, nil
+}
+
+// diffReminders compares a todo's reminders with the offsets it should have
+// and returns the IDs of the reminders to delete and the offsets to add
+func diffReminders(existing []Reminder, offsets []int) (remove []int64, add []int) {
+	keep := make(map[int]bool, len(offsets))
+	for _, offset := range offsets {
+		keep[offset] = true
+	}
+	have := make(map[int]bool, len(existing))
+	for _, reminder := range existing {
+		have[reminder.Offset] = true
+		if !keep[reminder.Offset] {
+			remove = append(remove, reminder.ID)
+		}
+	}
+	for _, offset := range offsets {
+		if !have[offset] {
+			add = append(add, offset)
+		}
+	}
+	return remove, add
+}
+
+// reminderColumns are the reminders columns scanReminder reads
+const reminderColumns = "id, todo_id, offset_minutes, notified_for"
+
+// scanReminder scans the columns id, todo_id, offset_minutes and
+// notified_for of reminders, in that order
+func scanReminder(row interface {
+	Scan(dest ...interface{}) error
+}) (Reminder, error) {
+	var reminder Reminder
+	err := row.Scan(&reminder.ID, &reminder.TodoID, &reminder.Offset, &reminder.NotifiedFor)
+	return reminder, err
+}
+
+// queryReminders runs a query selecting the reminders columns in the order
+// scanReminder reads them
+func queryReminders(ctx context.Context, db DBTX, query string, args ...interface{}) ([]Reminder, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, rows.Err()
+}
+
+// pendingRemindersQuery selects every reminder of an open todo with a due
+// date, with the todo's title and due date, for queryPendingReminders
+const pendingRemindersQuery = "SELECT r.id, r.todo_id, r.offset_minutes, r.notified_for, t.title, t.due_date, t.due_all_day FROM reminders r JOIN todos t ON t.id = r.todo_id WHERE t.completed_at IS NULL AND t.due_date IS NOT NULL ORDER BY t.due_date, r.offset_minutes DESC, r.id"
+
+// queryPendingReminders runs pendingRemindersQuery and keeps the reminders
+// that have not fired for their todo's current due date
+func queryPendingReminders(ctx context.Context, db DBTX) ([]PendingReminder, error) {
+	rows, err := db.QueryContext(ctx, pendingRemindersQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []PendingReminder
+	for rows.Next() {
+		var p PendingReminder
+		if err := rows.Scan(&p.ID, &p.TodoID, &p.Offset, &p.NotifiedFor, &p.Todo.Title, &p.Todo.DueDate, &p.Todo.DueAllDay); err != nil {
+			return nil, err
+		}
+		p.Todo.ID = p.TodoID
+		if !p.sent() {
+			pending = append(pending, p)
+		}
+	}
+	return pending, rows.Err()
+}
+
+// sent reports whether the reminder already fired for its todo's due date
+func (p PendingReminder) sent() bool {
+	return p.NotifiedFor != nil && p.NotifiedFor.Equal(*p.Todo.DueDate)
+}
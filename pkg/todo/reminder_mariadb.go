@@ -0,0 +1,85 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// NewReminderMariaDB creates a new MariaDB implementation of ReminderService
+func NewReminderMariaDB(db DBTX) ReminderService {
+	return &reminder_mariadb{db: db}
+}
+
+type reminder_mariadb struct {
+	db DBTX
+}
+
+// SetReminders replaces a todo's reminders with one per offset
+func (r *reminder_mariadb) SetReminders(ctx context.Context, todoID string, offsets []int) ([]Reminder, error) {
+	offsets, err := normalizeReminderOffsets(offsets)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []Reminder
+	err = withTx(ctx, r.db, func(tx DBTX) error {
+		if err := r.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		existing, err := r.reminders(ctx, tx, todoID)
+		if err != nil {
+			return err
+		}
+		remove, add := diffReminders(existing, offsets)
+		for _, id := range remove {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM reminders WHERE id = ?", id); err != nil {
+				return err
+			}
+		}
+		for _, offset := range add {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO reminders (todo_id, offset_minutes) VALUES (?, ?)", todoID, offset); err != nil {
+				return err
+			}
+		}
+		reminders, err = r.reminders(ctx, tx, todoID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// GetReminders returns a todo's reminders, earliest first
+func (r *reminder_mariadb) GetReminders(ctx context.Context, todoID string) ([]Reminder, error) {
+	if err := r.todoExists(ctx, r.db, todoID); err != nil {
+		return nil, err
+	}
+	return r.reminders(ctx, r.db, todoID)
+}
+
+// GetPendingReminders returns the reminders that have not fired for their
+// todo's due date
+func (r *reminder_mariadb) GetPendingReminders(ctx context.Context) ([]PendingReminder, error) {
+	return queryPendingReminders(ctx, r.db)
+}
+
+// MarkReminderSent records that a reminder fired for dueDate
+func (r *reminder_mariadb) MarkReminderSent(ctx context.Context, id int64, dueDate time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE reminders SET notified_for = ? WHERE id = ?", dueDate.UTC(), id)
+	return err
+}
+
+// reminders returns a todo's reminders, largest offset first
+func (r *reminder_mariadb) reminders(ctx context.Context, db DBTX, todoID string) ([]Reminder, error) {
+	return queryReminders(ctx, db, "SELECT "+reminderColumns+" FROM reminders WHERE todo_id = ? ORDER BY offset_minutes DESC", todoID)
+}
+
+// todoExists returns ErrTodoNotFound unless the todo exists
+func (r *reminder_mariadb) todoExists(ctx context.Context, db DBTX, todoID string) error {
+	var found int
+	err := db.QueryRowContext(ctx, "SELECT 1 FROM todos WHERE id = ?", todoID).Scan(&found)
+	return orNotFound(err, todoNotFound(todoID))
+}
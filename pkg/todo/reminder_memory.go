@@ -0,0 +1,130 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// NewReminderMemory creates a new in-memory implementation of ReminderService
+func NewReminderMemory(store *MemoryStore) ReminderService {
+	return &reminder_memory{store: store}
+}
+
+type reminder_memory struct {
+	store *MemoryStore
+}
+
+// SetReminders replaces a todo's reminders with one per offset
+func (r *reminder_memory) SetReminders(ctx context.Context, todoID string, offsets []int) ([]Reminder, error) {
+	offsets, err := normalizeReminderOffsets(offsets)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.todoExists(todoID) {
+		return nil, todoNotFound(todoID)
+	}
+	remove, add := diffReminders(r.store.remindersOf(todoID), offsets)
+	for _, id := range remove {
+		delete(r.store.reminders, id)
+	}
+	for _, offset := range add {
+		r.store.lastReminderID++
+		r.store.reminders[r.store.lastReminderID] = Reminder{ID: r.store.lastReminderID, TodoID: todoID, Offset: offset}
+	}
+	return r.store.remindersOf(todoID), nil
+}
+
+// GetReminders returns a todo's reminders, earliest first
+func (r *reminder_memory) GetReminders(ctx context.Context, todoID string) ([]Reminder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.todoExists(todoID) {
+		return nil, todoNotFound(todoID)
+	}
+	return r.store.remindersOf(todoID), nil
+}
+
+// GetPendingReminders returns the reminders that have not fired for their
+// todo's due date, ordered like the SQL backends
+func (r *reminder_memory) GetPendingReminders(ctx context.Context) ([]PendingReminder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var pending []PendingReminder
+	for _, reminder := range r.store.reminders {
+		key, err := strconv.ParseInt(reminder.TodoID, 10, 64)
+		if err != nil {
+			continue
+		}
+		item, ok := r.store.todos[key]
+		if !ok || item.CompletedAt != nil || item.DueDate == nil {
+			continue
+		}
+		p := PendingReminder{
+			Reminder: cloneReminder(reminder),
+			Todo:     TodoItem{ID: item.ID, Title: item.Title, DueDate: clonePtr(item.DueDate), DueAllDay: item.DueAllDay},
+		}
+		if !p.sent() {
+			pending = append(pending, p)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if !a.Todo.DueDate.Equal(*b.Todo.DueDate) {
+			return a.Todo.DueDate.Before(*b.Todo.DueDate)
+		}
+		if a.Offset != b.Offset {
+			return a.Offset > b.Offset
+		}
+		return a.ID < b.ID
+	})
+	return pending, nil
+}
+
+// MarkReminderSent records that a reminder fired for dueDate
+func (r *reminder_memory) MarkReminderSent(ctx context.Context, id int64, dueDate time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	reminder, ok := r.store.reminders[id]
+	if !ok {
+		return nil
+	}
+	notified := dueDate.UTC()
+	reminder.NotifiedFor = &notified
+	r.store.reminders[id] = reminder
+	return nil
+}
+
+// todoExists reports whether the todo with todoID exists; the caller must
+// hold the lock
+func (r *reminder_memory) todoExists(todoID string) bool {
+	key, err := strconv.ParseInt(todoID, 10, 64)
+	if err != nil {
+		return false
+	}
+	_, ok := r.store.todos[key]
+	return ok
+}
+
+// remindersOf returns copies of a todo's reminders, largest offset first; the
+// caller must hold the lock
+func (s *MemoryStore) remindersOf(todoID string) []Reminder {
+	var reminders []Reminder
+	for _, reminder := range s.reminders {
+		if reminder.TodoID == todoID {
+			reminders = append(reminders, cloneReminder(reminder))
+		}
+	}
+	sort.Slice(reminders, func(i, j int) bool {
+		return reminders[i].Offset > reminders[j].Offset
+	})
+	return reminders
+}
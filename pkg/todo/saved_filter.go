@@ -0,0 +1,255 @@
+package todo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FilterStatus is which todos a saved filter lists by completion
+type FilterStatus string
+
+const (
+	FilterStatusOpen      FilterStatus = "open"
+	FilterStatusCompleted FilterStatus = "completed"
+	FilterStatusAll       FilterStatus = "all"
+)
+
+// FilterCriteria are the conditions of a saved filter; a todo must meet every
+// one that is set. The due bounds are date expressions such as "today" or
+// "end of week", resolved each time the filter runs.
+type FilterCriteria struct {
+	ProjectID   *int64       `json:"project_id,omitempty"`
+	CategoryID  *int64       `json:"category_id,omitempty"`
+	Tags        []string     `json:"tags,omitempty"` // the todo must carry every tag
+	DueAfter    string       `json:"due_after,omitempty"`
+	DueBefore   string       `json:"due_before,omitempty"`
+	MinPriority *Priority    `json:"min_priority,omitempty"`
+	Status      FilterStatus `json:"status,omitempty"` // empty means open
+	Query       string       `json:"query,omitempty"`  // in the title or notes, ignoring case
+}
+
+// SavedFilter is a named set of criteria, such as "Work this week"
+type SavedFilter struct {
+	ID        int64          `json:"id"`
+	Name      string         `json:"name"`
+	Criteria  FilterCriteria `json:"criteria"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// SavedFilterService defines the interface for storing saved filters.
+// Running a filter is left to the caller, which resolves the due bounds and
+// passes them to FilterTodos.
+type SavedFilterService interface {
+	// CreateFilter saves criteria under a new name
+	CreateFilter(ctx context.Context, name string, criteria FilterCriteria) (SavedFilter, error)
+
+	// GetFilter returns a filter by ID
+	GetFilter(ctx context.Context, id int64) (SavedFilter, error)
+
+	// GetFilterByName returns a filter by name, ignoring case
+	GetFilterByName(ctx context.Context, name string) (SavedFilter, error)
+
+	// ListFilters returns every filter ordered by name
+	ListFilters(ctx context.Context) ([]SavedFilter, error)
+
+	// UpdateFilter replaces a filter's name and criteria
+	UpdateFilter(ctx context.Context, id int64, name string, criteria FilterCriteria) (SavedFilter, error)
+
+	// DeleteFilter deletes a filter
+	DeleteFilter(ctx context.Context, id int64) (SavedFilter, error)
+}
+
+const (
+	maxFilterNameLength  = 100
+	maxFilterQueryLength = 200
+	maxFilterDateLength  = 100
+)
+
+// normalizeFilter validates a filter's name and criteria and returns them in
+// their stored form: trimmed, with tag names normalized and the status set
+func normalizeFilter(name string, criteria FilterCriteria) (string, FilterCriteria, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", FilterCriteria{}, newValidationError("name", "filter name cannot be empty")
+	case len(name) > maxFilterNameLength:
+		return "", FilterCriteria{}, newValidationError("name", fmt.Sprintf("filter name cannot exceed %d characters", maxFilterNameLength))
+	}
+
+	if len(criteria.Tags) > 0 {
+		tags, err := normalizeTagNames(criteria.Tags)
+		if err != nil {
+			return "", FilterCriteria{}, err
+		}
+		criteria.Tags = tags
+	}
+	criteria.DueAfter = strings.TrimSpace(criteria.DueAfter)
+	criteria.DueBefore = strings.TrimSpace(criteria.DueBefore)
+	if len(criteria.DueAfter) > maxFilterDateLength || len(criteria.DueBefore) > maxFilterDateLength {
+		return "", FilterCriteria{}, newValidationError("due", fmt.Sprintf("due bounds cannot exceed %d characters", maxFilterDateLength))
+	}
+	if criteria.MinPriority != nil {
+		if err := validatePriority(*criteria.MinPriority); err != nil {
+			return "", FilterCriteria{}, err
+		}
+	}
+	switch criteria.Status {
+	case "":
+		criteria.Status = FilterStatusOpen
+	case FilterStatusOpen, FilterStatusCompleted, FilterStatusAll:
+	default:
+		return "", FilterCriteria{}, newValidationError("status", fmt.Sprintf("unknown status '%s'; use open, completed or all", criteria.Status))
+	}
+	criteria.Query = strings.TrimSpace(criteria.Query)
+	if len(criteria.Query) > maxFilterQueryLength {
+		return "", FilterCriteria{}, newValidationError("query", fmt.Sprintf("query cannot exceed %d characters", maxFilterQueryLength))
+	}
+	return name, criteria, nil
+}
+
+// DueRange is a filter's due bounds resolved for one run. A nil bound is
+// open. After is inclusive and Before exclusive, so a bound that names a day
+// should be the start of that day for After and of the next day for Before.
+type DueRange struct {
+	After  *time.Time
+	Before *time.Time
+}
+
+// IsZero reports whether the range is unbounded
+func (r DueRange) IsZero() bool {
+	return r.After == nil && r.Before == nil
+}
+
+// contains reports whether item's due date falls in the range. An all-day
+// due date is the whole of its day in loc and must fit in the range.
+func (r DueRange) contains(item TodoItem, loc *time.Location) bool {
+	if r.IsZero() {
+		return true
+	}
+	if item.DueDate == nil {
+		return false
+	}
+	start, end := *item.DueDate, *item.DueDate
+	if item.DueAllDay {
+		day := item.DueDate.UTC()
+		start = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 0, 1)
+	}
+	if r.After != nil && start.Before(*r.After) {
+		return false
+	}
+	if r.Before == nil {
+		return true
+	}
+	if item.DueAllDay {
+		return !end.After(*r.Before)
+	}
+	return end.Before(*r.Before)
+}
+
+// FilterTodos returns the todos that meet criteria, highest priority first
+// and then soonest due. tags are the todos' tags by todo ID, due the
+// criteria's resolved due bounds, and loc the timezone of all-day due dates.
+func FilterTodos(todos []TodoItem, tags map[string][]Tag, criteria FilterCriteria, due DueRange, loc *time.Location) []TodoItem {
+	query := strings.ToLower(criteria.Query)
+	var matched []TodoItem
+	for _, item := range todos {
+		switch criteria.Status {
+		case FilterStatusCompleted:
+			if item.CompletedAt == nil {
+				continue
+			}
+		case FilterStatusAll:
+		default:
+			if item.CompletedAt != nil {
+				continue
+			}
+		}
+		if criteria.ProjectID != nil && (item.ProjectID == nil || *item.ProjectID != *criteria.ProjectID) {
+			continue
+		}
+		if criteria.CategoryID != nil && (item.CategoryID == nil || *item.CategoryID != *criteria.CategoryID) {
+			continue
+		}
+		if criteria.MinPriority != nil && item.Priority < *criteria.MinPriority {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(item.Title), query) && !strings.Contains(strings.ToLower(item.Notes), query) {
+			continue
+		}
+		if !hasTags(tags[item.ID], criteria.Tags) {
+			continue
+		}
+		if !due.contains(item, loc) {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	return byPriority(matched)
+}
+
+// hasTags reports whether have includes every tag named in want
+func hasTags(have []Tag, want []string) bool {
+	for _, name := range want {
+		found := false
+		for _, tag := range have {
+			if tag.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// marshalCriteria encodes criteria for the criteria column
+func marshalCriteria(criteria FilterCriteria) (string, error) {
+	data, err := json.Marshal(criteria)
+	return string(data), err
+}
+
+// filterColumns are the saved_filters columns scanFilter reads
+const filterColumns = "id, name, criteria, created_at, updated_at"
+
+// scanFilter scans the columns id, name, criteria, created_at and updated_at
+// of saved_filters, in that order
+func scanFilter(row interface {
+	Scan(dest ...interface{}) error
+}) (SavedFilter, error) {
+	var filter SavedFilter
+	var criteria string
+	if err := row.Scan(&filter.ID, &filter.Name, &criteria, &filter.CreatedAt, &filter.UpdatedAt); err != nil {
+		return SavedFilter{}, err
+	}
+	if err := json.Unmarshal([]byte(criteria), &filter.Criteria); err != nil {
+		return SavedFilter{}, fmt.Errorf("saved filter %d: %w", filter.ID, err)
+	}
+	return filter, nil
+}
+
+// queryFilters runs a query selecting the saved_filters columns in the order
+// scanFilter reads them
+func queryFilters(ctx context.Context, db DBTX, query string, args ...interface{}) ([]SavedFilter, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filters []SavedFilter
+	for rows.Next() {
+		filter, err := scanFilter(rows)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, rows.Err()
+}
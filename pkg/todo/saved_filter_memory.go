@@ -0,0 +1,129 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewSavedFilterMemory creates a new in-memory implementation of
+// SavedFilterService
+func NewSavedFilterMemory(store *MemoryStore) SavedFilterService {
+	return &saved_filter_memory{store: store}
+}
+
+type saved_filter_memory struct {
+	store *MemoryStore
+}
+
+// CreateFilter saves criteria under a new name
+func (f *saved_filter_memory) CreateFilter(ctx context.Context, name string, criteria FilterCriteria) (SavedFilter, error) {
+	name, criteria, err := normalizeFilter(name, criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	if err := f.nameFree(name, 0); err != nil {
+		return SavedFilter{}, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	f.store.lastFilterID++
+	filter := SavedFilter{ID: f.store.lastFilterID, Name: name, Criteria: criteria, CreatedAt: now, UpdatedAt: now}
+	f.store.filters[filter.ID] = cloneFilter(filter)
+	return filter, nil
+}
+
+// GetFilter returns a filter by ID
+func (f *saved_filter_memory) GetFilter(ctx context.Context, id int64) (SavedFilter, error) {
+	f.store.mu.RLock()
+	defer f.store.mu.RUnlock()
+
+	filter, ok := f.store.filters[id]
+	if !ok {
+		return SavedFilter{}, savedFilterNotFound(id)
+	}
+	return cloneFilter(filter), nil
+}
+
+// GetFilterByName returns a filter by name, ignoring case
+func (f *saved_filter_memory) GetFilterByName(ctx context.Context, name string) (SavedFilter, error) {
+	f.store.mu.RLock()
+	defer f.store.mu.RUnlock()
+
+	for _, filter := range f.store.filters {
+		if strings.EqualFold(filter.Name, name) {
+			return cloneFilter(filter), nil
+		}
+	}
+	return SavedFilter{}, savedFilterNameNotFound(name)
+}
+
+// ListFilters returns every filter ordered by name
+func (f *saved_filter_memory) ListFilters(ctx context.Context) ([]SavedFilter, error) {
+	f.store.mu.RLock()
+	defer f.store.mu.RUnlock()
+
+	var filters []SavedFilter
+	for _, filter := range f.store.filters {
+		filters = append(filters, cloneFilter(filter))
+	}
+	sort.Slice(filters, func(i, j int) bool {
+		a, b := strings.ToLower(filters[i].Name), strings.ToLower(filters[j].Name)
+		if a != b {
+			return a < b
+		}
+		return filters[i].ID < filters[j].ID
+	})
+	return filters, nil
+}
+
+// UpdateFilter replaces a filter's name and criteria
+func (f *saved_filter_memory) UpdateFilter(ctx context.Context, id int64, name string, criteria FilterCriteria) (SavedFilter, error) {
+	name, criteria, err := normalizeFilter(name, criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	filter, ok := f.store.filters[id]
+	if !ok {
+		return SavedFilter{}, savedFilterNotFound(id)
+	}
+	if err := f.nameFree(name, id); err != nil {
+		return SavedFilter{}, err
+	}
+	filter.Name, filter.Criteria = name, criteria
+	filter.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	f.store.filters[id] = cloneFilter(filter)
+	return cloneFilter(filter), nil
+}
+
+// DeleteFilter deletes a filter
+func (f *saved_filter_memory) DeleteFilter(ctx context.Context, id int64) (SavedFilter, error) {
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	filter, ok := f.store.filters[id]
+	if !ok {
+		return SavedFilter{}, savedFilterNotFound(id)
+	}
+	delete(f.store.filters, id)
+	return cloneFilter(filter), nil
+}
+
+// nameFree returns ErrDuplicateName if a filter other than id is called
+// name, ignoring case; the caller must hold the lock
+func (f *saved_filter_memory) nameFree(name string, id int64) error {
+	for _, filter := range f.store.filters {
+		if filter.ID != id && strings.EqualFold(filter.Name, name) {
+			return duplicateName("saved filter", filter.Name)
+		}
+	}
+	return nil
+}
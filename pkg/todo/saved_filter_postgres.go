@@ -0,0 +1,136 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewSavedFilterPostgres creates a new PostgreSQL implementation of
+// SavedFilterService
+func NewSavedFilterPostgres(db DBTX) SavedFilterService {
+	return &saved_filter_postgres{db: db}
+}
+
+type saved_filter_postgres struct {
+	db DBTX
+}
+
+// CreateFilter saves criteria under a new name
+func (f *saved_filter_postgres) CreateFilter(ctx context.Context, name string, criteria FilterCriteria) (SavedFilter, error) {
+	name, criteria, err := normalizeFilter(name, criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	encoded, err := marshalCriteria(criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	filter := SavedFilter{Name: name, Criteria: criteria, CreatedAt: now, UpdatedAt: now}
+	err = withTx(ctx, f.db, func(tx DBTX) error {
+		if err := f.nameFree(ctx, tx, name, 0); err != nil {
+			return err
+		}
+		err := tx.QueryRowContext(ctx, "INSERT INTO saved_filters (name, criteria, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id", name, encoded, now, now).Scan(&filter.ID)
+		if isUniqueViolation(err) {
+			return duplicateName("saved filter", name)
+		}
+		return err
+	})
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	return filter, nil
+}
+
+// GetFilter returns a filter by ID
+func (f *saved_filter_postgres) GetFilter(ctx context.Context, id int64) (SavedFilter, error) {
+	filter, err := scanFilter(f.db.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE id = $1", id))
+	if err != nil {
+		return SavedFilter{}, orNotFound(err, savedFilterNotFound(id))
+	}
+	return filter, nil
+}
+
+// GetFilterByName returns a filter by name, ignoring case
+func (f *saved_filter_postgres) GetFilterByName(ctx context.Context, name string) (SavedFilter, error) {
+	filter, err := scanFilter(f.db.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE LOWER(name) = LOWER($1)", name))
+	if err != nil {
+		return SavedFilter{}, orNotFound(err, savedFilterNameNotFound(name))
+	}
+	return filter, nil
+}
+
+// ListFilters returns every filter ordered by name
+func (f *saved_filter_postgres) ListFilters(ctx context.Context) ([]SavedFilter, error) {
+	return queryFilters(ctx, f.db, "SELECT "+filterColumns+" FROM saved_filters ORDER BY LOWER(name), id")
+}
+
+// UpdateFilter replaces a filter's name and criteria
+func (f *saved_filter_postgres) UpdateFilter(ctx context.Context, id int64, name string, criteria FilterCriteria) (SavedFilter, error) {
+	name, criteria, err := normalizeFilter(name, criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	encoded, err := marshalCriteria(criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+
+	var filter SavedFilter
+	err = withTx(ctx, f.db, func(tx DBTX) error {
+		var err error
+		filter, err = scanFilter(tx.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE id = $1", id))
+		if err != nil {
+			return orNotFound(err, savedFilterNotFound(id))
+		}
+		if err := f.nameFree(ctx, tx, name, id); err != nil {
+			return err
+		}
+		filter.Name, filter.Criteria = name, criteria
+		filter.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		_, err = tx.ExecContext(ctx, "UPDATE saved_filters SET name = $1, criteria = $2, updated_at = $3 WHERE id = $4", name, encoded, filter.UpdatedAt, id)
+		if isUniqueViolation(err) {
+			return duplicateName("saved filter", name)
+		}
+		return err
+	})
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	return filter, nil
+}
+
+// DeleteFilter deletes a filter
+func (f *saved_filter_postgres) DeleteFilter(ctx context.Context, id int64) (SavedFilter, error) {
+	var filter SavedFilter
+	err := withTx(ctx, f.db, func(tx DBTX) error {
+		var err error
+		filter, err = scanFilter(tx.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE id = $1", id))
+		if err != nil {
+			return orNotFound(err, savedFilterNotFound(id))
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM saved_filters WHERE id = $1", id)
+		return err
+	})
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	return filter, nil
+}
+
+// nameFree returns ErrDuplicateName if a filter other than id is called
+// name, ignoring case
+func (f *saved_filter_postgres) nameFree(ctx context.Context, db DBTX, name string, id int64) error {
+	filters, err := queryFilters(ctx, db, "SELECT "+filterColumns+" FROM saved_filters WHERE LOWER(name) = LOWER($1) AND id <> $2", name, id)
+	if err != nil {
+		return err
+	}
+	if len(filters) > 0 {
+		return duplicateName("saved filter", filters[0].Name)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// NewSavedFilterSQLite creates a new SQLite implementation of
+// SavedFilterService
+func NewSavedFilterSQLite(db DBTX) SavedFilterService {
+	return &saved_filter_sqlite{db: db}
+}
+
+type saved_filter_sqlite struct {
+	db DBTX
+}
+
+// CreateFilter saves criteria under a new name
+func (f *saved_filter_sqlite) CreateFilter(ctx context.Context, name string, criteria FilterCriteria) (SavedFilter, error) {
+	name, criteria, err := normalizeFilter(name, criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	encoded, err := marshalCriteria(criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	filter := SavedFilter{Name: name, Criteria: criteria, CreatedAt: now, UpdatedAt: now}
+	err = withTx(ctx, f.db, func(tx DBTX) error {
+		if err := f.nameFree(ctx, tx, name, 0); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO saved_filters (name, criteria, created_at, updated_at) VALUES (?, ?, ?, ?)", name, encoded, now, now)
+		if isUniqueViolation(err) {
+			return duplicateName("saved filter", name)
+		}
+		if err != nil {
+			return err
+		}
+		filter.ID, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	return filter, nil
+}
+
+// GetFilter returns a filter by ID
+func (f *saved_filter_sqlite) GetFilter(ctx context.Context, id int64) (SavedFilter, error) {
+	filter, err := scanFilter(f.db.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE id = ?", id))
+	if err != nil {
+		return SavedFilter{}, orNotFound(err, savedFilterNotFound(id))
+	}
+	return filter, nil
+}
+
+// GetFilterByName returns a filter by name, ignoring case
+func (f *saved_filter_sqlite) GetFilterByName(ctx context.Context, name string) (SavedFilter, error) {
+	filter, err := scanFilter(f.db.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE LOWER(name) = LOWER(?)", name))
+	if err != nil {
+		return SavedFilter{}, orNotFound(err, savedFilterNameNotFound(name))
+	}
+	return filter, nil
+}
+
+// ListFilters returns every filter ordered by name
+func (f *saved_filter_sqlite) ListFilters(ctx context.Context) ([]SavedFilter, error) {
+	return queryFilters(ctx, f.db, "SELECT "+filterColumns+" FROM saved_filters ORDER BY LOWER(name), id")
+}
+
+// UpdateFilter replaces a filter's name and criteria
+func (f *saved_filter_sqlite) UpdateFilter(ctx context.Context, id int64, name string, criteria FilterCriteria) (SavedFilter, error) {
+	name, criteria, err := normalizeFilter(name, criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	encoded, err := marshalCriteria(criteria)
+	if err != nil {
+		return SavedFilter{}, err
+	}
+
+	var filter SavedFilter
+	err = withTx(ctx, f.db, func(tx DBTX) error {
+		var err error
+		filter, err = scanFilter(tx.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE id = ?", id))
+		if err != nil {
+			return orNotFound(err, savedFilterNotFound(id))
+		}
+		if err := f.nameFree(ctx, tx, name, id); err != nil {
+			return err
+		}
+		filter.Name, filter.Criteria = name, criteria
+		filter.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		_, err = tx.ExecContext(ctx, "UPDATE saved_filters SET name = ?, criteria = ?, updated_at = ? WHERE id = ?", name, encoded, filter.UpdatedAt, id)
+		if isUniqueViolation(err) {
+			return duplicateName("saved filter", name)
+		}
+		return err
+	})
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	return filter, nil
+}
+
+// DeleteFilter deletes a filter
+func (f *saved_filter_sqlite) DeleteFilter(ctx context.Context, id int64) (SavedFilter, error) {
+	var filter SavedFilter
+	err := withTx(ctx, f.db, func(tx DBTX) error {
+		var err error
+		filter, err = scanFilter(tx.QueryRowContext(ctx, "SELECT "+filterColumns+" FROM saved_filters WHERE id = ?", id))
+		if err != nil {
+			return orNotFound(err, savedFilterNotFound(id))
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM saved_filters WHERE id = ?", id)
+		return err
+	})
+	if err != nil {
+		return SavedFilter{}, err
+	}
+	return filter, nil
+}
+
+// nameFree returns ErrDuplicateName if a filter other than id is called
+// name, ignoring case
+func (f *saved_filter_sqlite) nameFree(ctx context.Context, db DBTX, name string, id int64) error {
+	filters, err := queryFilters(ctx, db, "SELECT "+filterColumns+" FROM saved_filters WHERE LOWER(name) = LOWER(?) AND id <> ?", name, id)
+	if err != nil {
+		return err
+	}
+	if len(filters) > 0 {
+		return duplicateName("saved filter", filters[0].Name)
+	}
+	return nil
+}
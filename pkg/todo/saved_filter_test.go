@@ -0,0 +1,74 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterTodos(t *testing.T) {
+	work, home := int64(1), int64(2)
+	monday := time.Date(2030, time.March, 4, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2030, time.March, 8, 17, 0, 0, 0, time.UTC)
+	done := monday.Add(-time.Hour)
+	todos := []TodoItem{
+		{ID: "1", Title: "Write report", ProjectID: &work, Priority: PriorityLow, DueDate: &friday},
+		{ID: "2", Title: "Review budget", Notes: "Check the REPORT numbers", ProjectID: &work, Priority: PriorityHigh, DueDate: &monday},
+		{ID: "3", Title: "Call plumber", CategoryID: &home, Priority: PriorityUrgent},
+		{ID: "4", Title: "File taxes", CategoryID: &home, CompletedAt: &done, DueDate: &monday},
+	}
+	tags := map[string][]Tag{
+		"1": {{Name: "work"}, {Name: "writing"}},
+		"2": {{Name: "work"}},
+	}
+	high := PriorityHigh
+	before := friday.Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		criteria FilterCriteria
+		due      DueRange
+		want     []string
+	}{
+		{"OpenByPriority", FilterCriteria{}, DueRange{}, []string{"3", "2", "1"}},
+		{"Completed", FilterCriteria{Status: FilterStatusCompleted}, DueRange{}, []string{"4"}},
+		{"All", FilterCriteria{Status: FilterStatusAll}, DueRange{}, []string{"3", "2", "1", "4"}},
+		{"Project", FilterCriteria{ProjectID: &work}, DueRange{}, []string{"2", "1"}},
+		{"Category", FilterCriteria{CategoryID: &home, Status: FilterStatusAll}, DueRange{}, []string{"3", "4"}},
+		{"MinPriority", FilterCriteria{MinPriority: &high}, DueRange{}, []string{"3", "2"}},
+		{"QueryTitleOrNotes", FilterCriteria{Query: "report"}, DueRange{}, []string{"2", "1"}},
+		{"EveryTag", FilterCriteria{Tags: []string{"work", "writing"}}, DueRange{}, []string{"1"}},
+		{"DueBefore", FilterCriteria{}, DueRange{Before: &before}, []string{"2"}},
+		{"DueAfter", FilterCriteria{}, DueRange{After: &before}, []string{"1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range FilterTodos(todos, tags, tt.criteria, tt.due, time.UTC) {
+				got = append(got, item.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDueRangeContains(t *testing.T) {
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	day := time.Date(2030, time.March, 8, 0, 0, 0, 0, time.UTC)
+	allDay := TodoItem{DueDate: &day, DueAllDay: true}
+	start := time.Date(2030, time.March, 8, 0, 0, 0, 0, tokyo)
+	end := start.AddDate(0, 0, 1)
+	midday := start.Add(12 * time.Hour)
+	timed := TodoItem{DueDate: &midday}
+
+	assert.True(t, DueRange{}.contains(TodoItem{}, tokyo), "an unbounded range holds undated todos")
+	assert.False(t, DueRange{Before: &end}.contains(TodoItem{}, tokyo), "a bounded range does not")
+
+	assert.True(t, DueRange{After: &start, Before: &end}.contains(allDay, tokyo), "the day fits its own bounds")
+	assert.False(t, DueRange{After: &midday}.contains(allDay, tokyo), "the day starts before the bound")
+	assert.False(t, DueRange{Before: &midday}.contains(allDay, tokyo), "the day ends after the bound")
+
+	assert.True(t, DueRange{After: &midday, Before: &end}.contains(timed, tokyo), "After is inclusive")
+	assert.False(t, DueRange{Before: &midday}.contains(timed, tokyo), "Before is exclusive")
+}
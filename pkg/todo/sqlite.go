@@ -0,0 +1,41 @@
+package todo
+
+import (
+	"database/sql"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// OpenSQLite opens the SQLite database at path with foreign keys enabled
+// and a busy timeout so concurrent writers wait rather than fail.
+// It does not create the schema; see Migrator.
+func OpenSQLite(path string) (*sql.DB, error) {
+	return sql.Open("sqlite3", sqliteDSN(path))
+}
+
+// sqliteDSN appends the connection options the SQLite implementations need
+// unless the caller already set them
+func sqliteDSN(path string) string {
+	var opts []string
+	if !strings.Contains(path, "_foreign_keys") && !strings.Contains(path, "_fk") {
+		opts = append(opts, "_foreign_keys=on")
+	}
+	if !strings.Contains(path, "_busy_timeout") && !strings.Contains(path, "_timeout") {
+		opts = append(opts, "_busy_timeout=5000")
+	}
+	// Take the write lock when a transaction begins. Deferred transactions
+	// that read and then write fail with SQLITE_BUSY instead of waiting
+	// when another connection is writing.
+	if !strings.Contains(path, "_txlock") {
+		opts = append(opts, "_txlock=immediate")
+	}
+	if len(opts) == 0 {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + strings.Join(opts, "&")
+}
@@ -0,0 +1,246 @@
+package todo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DBTX is the subset of *sql.DB and *sql.Tx used by the SQL implementations,
+// so the same service code can run on the connection pool or inside a transaction
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txBeginner is implemented by *sql.DB but not *sql.Tx
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// withTx runs fn inside a transaction. If db is already a transaction fn joins
+// it, and committing or rolling back is left to whoever started it.
+func withTx(ctx context.Context, db DBTX, fn func(tx DBTX) error) error {
+	beginner, ok := db.(txBeginner)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Services groups the todo, project, category, tag, time tracking, reminder
+// and saved filter services built on one database handle
+type Services struct {
+	Todos       TodoService
+	Projects    ProjectService
+	Categories  CategoryService
+	Tags        TagService
+	TimeEntries TimeService
+	Reminders   ReminderService
+	Filters     SavedFilterService
+}
+
+// Repositories groups the todo, project, category, tag, time tracking,
+// reminder and saved filter data access built on one database handle. Unlike Services it exposes categories
+// without validation.
+type Repositories struct {
+	Todos       TodoService
+	Projects    ProjectService
+	Categories  CategoryRepository
+	Tags        TagService
+	TimeEntries TimeService
+	Reminders   ReminderService
+	Filters     SavedFilterService
+}
+
+// UnitOfWork runs fn with repositories bound to a single transaction, so a
+// read followed by a write, or writes through several repositories, either all
+// take effect or none do. Calling Do on repositories that are already inside a
+// transaction joins it.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx Repositories) error) error
+}
+
+// sqlUnitOfWork is the UnitOfWork for the SQL dialects
+type sqlUnitOfWork struct {
+	db      DBTX
+	dialect string
+	loc     *time.Location
+}
+
+func (u sqlUnitOfWork) Do(ctx context.Context, fn func(tx Repositories) error) error {
+	return withTx(ctx, u.db, func(tx DBTX) error {
+		repos, err := newRepositories(tx, u.dialect, u.loc)
+		if err != nil {
+			return err
+		}
+		return fn(repos)
+	})
+}
+
+// memoryUnitOfWork is the UnitOfWork for the in-memory store
+type memoryUnitOfWork struct {
+	store *MemoryStore
+	loc   *time.Location
+}
+
+func (u memoryUnitOfWork) Do(ctx context.Context, fn func(tx Repositories) error) error {
+	return u.store.withTx(func(tx *MemoryStore) error {
+		if err := fn(newMemoryRepositories(tx, u.loc)); err != nil {
+			return err
+		}
+		return ctx.Err()
+	})
+}
+
+// Storage owns the single connection pool shared by all services, or the
+// in-memory store when STORAGE_TYPE is memory
+type Storage struct {
+	Services
+	db  *sql.DB
+	uow UnitOfWork
+}
+
+// NewStorage opens the configured database, applies pending migrations unless
+// disabled, tunes the connection pool and builds every service on top of it
+func NewStorage(cfg Config) (*Storage, error) {
+	if cfg.StorageType == "memory" {
+		return NewMemoryStorage(cfg.Location), nil
+	}
+
+	db, dialect, err := openDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+
+	return NewStorageFromDB(db, dialect, cfg.Location)
+}
+
+// NewStorageFromDB builds the services for an already opened and migrated
+// database. Recurring todos are expanded in loc; nil means UTC.
+func NewStorageFromDB(db *sql.DB, dialect string, loc *time.Location) (*Storage, error) {
+	services, err := newServices(db, dialect, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &Storage{Services: services, db: db, uow: sqlUnitOfWork{db: db, dialect: dialect, loc: loc}}, nil
+}
+
+// NewMemoryStorage builds the services on a fresh in-memory store. Data lives
+// only as long as the process. Recurring todos are expanded in loc; nil means
+// UTC.
+func NewMemoryStorage(loc *time.Location) *Storage {
+	store := NewMemoryStore()
+	return &Storage{Services: newMemoryServices(store, loc), uow: memoryUnitOfWork{store: store, loc: loc}}
+}
+
+// Close closes the shared connection pool
+func (s *Storage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Do runs fn with repositories bound to a single transaction; see UnitOfWork
+func (s *Storage) Do(ctx context.Context, fn func(tx Repositories) error) error {
+	return s.uow.Do(ctx, fn)
+}
+
+// WithTx runs fn with services bound to a single transaction, so changes made
+// through several services are committed together or not at all.
+// The transaction is rolled back if fn returns an error or ctx is cancelled.
+func (s *Storage) WithTx(ctx context.Context, fn func(tx Services) error) error {
+	return s.uow.Do(ctx, func(tx Repositories) error {
+		// Every call already runs inside the transaction, so the category
+		// service needs no unit of work of its own
+		return fn(Services{
+			Todos:       tx.Todos,
+			Projects:    tx.Projects,
+			Categories:  NewCategoryService(tx.Categories),
+			Tags:        tx.Tags,
+			TimeEntries: tx.TimeEntries,
+			Reminders:   tx.Reminders,
+			Filters:     tx.Filters,
+		})
+	})
+}
+
+// newServices builds the dialect's services on db. Category creation runs its
+// duplicate check and insert as one unit of work on the same handle.
+func newServices(db DBTX, dialect string, loc *time.Location) (Services, error) {
+	repos, err := newRepositories(db, dialect, loc)
+	if err != nil {
+		return Services{}, err
+	}
+	return Services{
+		Todos:       repos.Todos,
+		Projects:    repos.Projects,
+		Categories:  NewTransactionalCategoryService(repos.Categories, sqlUnitOfWork{db: db, dialect: dialect, loc: loc}),
+		Tags:        repos.Tags,
+		TimeEntries: repos.TimeEntries,
+		Reminders:   repos.Reminders,
+		Filters:     repos.Filters,
+	}, nil
+}
+
+// newRepositories builds the dialect's implementations on db, expanding
+// recurring todos in loc
+func newRepositories(db DBTX, dialect string, loc *time.Location) (Repositories, error) {
+	switch dialect {
+	case DialectMariaDB:
+		return Repositories{
+			Todos:       NewTodoMariaDB(db, loc),
+			Projects:    NewProjectMariaDB(db),
+			Categories:  NewCategoryMariaDB(db),
+			Tags:        NewTagMariaDB(db),
+			TimeEntries: NewTimeMariaDB(db),
+			Reminders:   NewReminderMariaDB(db),
+			Filters:     NewSavedFilterMariaDB(db),
+		}, nil
+	case DialectPostgres:
+		return Repositories{
+			Todos:       NewTodoPostgres(db, loc),
+			Projects:    NewProjectPostgres(db),
+			Categories:  NewCategoryPostgres(db),
+			Tags:        NewTagPostgres(db),
+			TimeEntries: NewTimePostgres(db),
+			Reminders:   NewReminderPostgres(db),
+			Filters:     NewSavedFilterPostgres(db),
+		}, nil
+	case DialectSQLite:
+		return Repositories{
+			Todos:       NewTodoSQLite(db, loc),
+			Projects:    NewProjectSQLite(db),
+			Categories:  NewCategorySQLite(db),
+			Tags:        NewTagSQLite(db),
+			TimeEntries: NewTimeSQLite(db),
+			Reminders:   NewReminderSQLite(db),
+			Filters:     NewSavedFilterSQLite(db),
+		}, nil
+	default:
+		return Repositories{}, fmt.Errorf("%w: %s", ErrUnknownStorageType, dialect)
+	}
+}
@@ -0,0 +1,212 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteTestStorage(t *testing.T, cfg Config) *Storage {
+	t.Helper()
+	cfg.StorageType = "sqlite"
+	cfg.SQLDBPath = filepath.Join(t.TempDir(), "todos.db")
+	storage, err := NewStorage(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	return storage
+}
+
+func TestNewStorage_SharesOnePool(t *testing.T) {
+	storage := newSQLiteTestStorage(t, Config{MaxOpenConns: 3, MaxIdleConns: 2, ConnMaxLifetime: time.Minute})
+
+	assert.Equal(t, 3, storage.db.Stats().MaxOpenConnections)
+
+	category, err := storage.Categories.CreateCategory(context.Background(), "Home", nil, nil)
+	require.NoError(t, err)
+	project, err := storage.Projects.CreateProject(context.Background(), "Kitchen", nil)
+	require.NoError(t, err)
+	item, err := storage.Todos.AddTodoToProject(context.Background(), "Paint walls", project.ID, nil)
+	require.NoError(t, err)
+	_, err = storage.Todos.AssignTodoToCategory(context.Background(), item.ID, category.ID)
+	require.NoError(t, err)
+
+	// Writes through one service are visible through the others
+	todos, err := storage.Categories.GetTodosByCategory(context.Background(), category.ID)
+	require.NoError(t, err)
+	require.Len(t, todos, 1)
+	assert.Equal(t, item.ID, todos[0].ID)
+	projectTodos, err := storage.Projects.GetProjectTodos(context.Background(), project.ID)
+	require.NoError(t, err)
+	assert.Len(t, projectTodos, 1)
+}
+
+func TestNewStorage_UnknownStorageType(t *testing.T) {
+	_, err := NewStorage(Config{StorageType: "unknown"})
+	assert.ErrorIs(t, err, ErrUnknownStorageType)
+}
+
+func TestStorage_WithTxCommits(t *testing.T) {
+	storage := newSQLiteTestStorage(t, Config{})
+
+	var projectID int64
+	err := storage.WithTx(context.Background(), func(tx Services) error {
+		project, err := tx.Projects.CreateProject(context.Background(), "Move house", nil)
+		if err != nil {
+			return err
+		}
+		projectID = project.ID
+		_, err = tx.Todos.AddTodoToProject(context.Background(), "Book van", project.ID, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = storage.Projects.GetProject(context.Background(), projectID)
+	assert.NoError(t, err)
+	todos, err := storage.Todos.GetTodosByProject(context.Background(), projectID)
+	require.NoError(t, err)
+	assert.Len(t, todos, 1)
+}
+
+func TestStorage_WithTxRollsBack(t *testing.T) {
+	storage := newSQLiteTestStorage(t, Config{})
+	project, err := storage.Projects.CreateProject(context.Background(), "Garden", nil)
+	require.NoError(t, err)
+	item, err := storage.Todos.AddTodoToProject(context.Background(), "Mow lawn", project.ID, nil)
+	require.NoError(t, err)
+
+	errAbort := errors.New("abort")
+	err = storage.WithTx(context.Background(), func(tx Services) error {
+		if _, err := tx.Todos.AddTodo(context.Background(), "Never saved", nil); err != nil {
+			return err
+		}
+		// DeleteProject joins the outer transaction instead of committing its own
+		if _, err := tx.Projects.DeleteProject(context.Background(), project.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	_, err = storage.Projects.GetProject(context.Background(), project.ID)
+	assert.NoError(t, err, "project delete should have been rolled back")
+	reloaded, err := storage.Todos.GetTodo(context.Background(), item.ID)
+	require.NoError(t, err)
+	require.NotNil(t, reloaded.ProjectID, "todo should still belong to the project")
+	found, err := storage.Todos.TitleSearchTodo(context.Background(), "Never saved", false)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestStorage_DoRollsBack(t *testing.T) {
+	for name, storage := range map[string]*Storage{
+		"sqlite": newSQLiteTestStorage(t, Config{}),
+		"memory": NewMemoryStorage(nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			errAbort := errors.New("abort")
+			err := storage.Do(ctx, func(tx Repositories) error {
+				category, err := tx.Categories.Create(ctx, Category{Name: "Errands"})
+				if err != nil {
+					return err
+				}
+				if _, err := tx.Todos.AddTodoToCategory(ctx, "Post letter", category.ID, nil); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+
+			categories, err := storage.Categories.GetAllCategories(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, categories)
+			todos, err := storage.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, todos)
+		})
+	}
+}
+
+func TestStorage_ConcurrentCreateCategory(t *testing.T) {
+	ctx := context.Background()
+	storage := newSQLiteTestStorage(t, Config{})
+
+	const sessions = 10
+	errs := make(chan error, sessions)
+	var wg sync.WaitGroup
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := storage.Categories.CreateCategory(ctx, "Work", nil, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		// Losers see the duplicate check fail, not a constraint or lock error
+		assert.Contains(t, err.Error(), "already exists")
+	}
+	assert.Equal(t, 1, created)
+
+	categories, err := storage.Categories.GetAllCategories(ctx)
+	require.NoError(t, err)
+	assert.Len(t, categories, 1)
+}
+
+func TestStorage_ConcurrentDeleteTodo(t *testing.T) {
+	ctx := context.Background()
+	storage := newSQLiteTestStorage(t, Config{})
+	item, err := storage.Todos.AddTodo(ctx, "Only once", nil)
+	require.NoError(t, err)
+
+	const sessions = 10
+	errs := make(chan error, sessions)
+	var wg sync.WaitGroup
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := storage.Todos.DeleteTodo(ctx, item.ID)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	deleted := 0
+	for err := range errs {
+		if err == nil {
+			deleted++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrTodoNotFound)
+	}
+	assert.Equal(t, 1, deleted, "only one session may report the todo as deleted")
+}
+
+func TestNewStorage_Memory(t *testing.T) {
+	storage, err := NewStorage(Config{StorageType: "memory"})
+	require.NoError(t, err)
+
+	_, err = storage.Todos.AddTodo(context.Background(), "Ephemeral", nil)
+	require.NoError(t, err)
+	todos, err := storage.Todos.GetAllTodos(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, todos, 1)
+
+	assert.NoError(t, storage.Close())
+}
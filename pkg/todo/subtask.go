@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CompletionPolicy decides what completing a todo does to its open subtasks
+type CompletionPolicy int
+
+const (
+	// BlockOpenSubtasks refuses to complete a todo while any subtask below it
+	// is open
+	BlockOpenSubtasks CompletionPolicy = iota
+	// CompleteOpenSubtasks completes every open subtask below the todo along
+	// with it. Recurring subtasks completed this way spawn no next occurrence.
+	CompleteOpenSubtasks
+)
+
+// ParseCompletionPolicy parses "block" or "complete"; an empty string is
+// BlockOpenSubtasks
+func ParseCompletionPolicy(s string) (CompletionPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "block":
+		return BlockOpenSubtasks, nil
+	case "complete":
+		return CompleteOpenSubtasks, nil
+	}
+	return 0, newValidationError("subtasks", fmt.Sprintf("unknown subtask completion policy '%s'; use block or complete", s))
+}
+
+func (p CompletionPolicy) String() string {
+	if p == CompleteOpenSubtasks {
+		return "complete"
+	}
+	return "block"
+}
+
+// SubtaskProgress counts a todo's direct subtasks
+type SubtaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func (p SubtaskProgress) String() string {
+	return fmt.Sprintf("%d/%d subtasks done", p.Done, p.Total)
+}
+
+// parseTodoID converts a todo ID to its stored form. An ID that is not a
+// number cannot name a todo.
+func parseTodoID(id string) (int64, error) {
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, todoNotFound(id)
+	}
+	return key, nil
+}
+
+// checkNewParent rejects moving the todo with id under parentID when that
+// would make it its own ancestor. descendants are the IDs below the todo.
+func checkNewParent(id string, parentID int64, descendants []int64) error {
+	if strconv.FormatInt(parentID, 10) == id {
+		return newValidationError("parent_id", "a todo cannot be its own parent")
+	}
+	for _, descendant := range descendants {
+		if descendant == parentID {
+			return newValidationError("parent_id", fmt.Sprintf("todo %d is a subtask of todo %s", parentID, id))
+		}
+	}
+	return nil
+}
+
+// queryIDs runs a query selecting a single id column
+func queryIDs(ctx context.Context, db DBTX, query string, args ...interface{}) ([]int64, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// querySubtaskProgress runs a query selecting parent_id, a subtask count and
+// a completed subtask count, and keys the progress by parent ID
+func querySubtaskProgress(ctx context.Context, db DBTX, query string, args ...interface{}) (map[string]SubtaskProgress, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[string]SubtaskProgress)
+	for rows.Next() {
+		var parentID int64
+		var p SubtaskProgress
+		if err := rows.Scan(&parentID, &p.Total, &p.Done); err != nil {
+			return nil, err
+		}
+		progress[strconv.FormatInt(parentID, 10)] = p
+	}
+	return progress, rows.Err()
+}
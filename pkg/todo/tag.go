@@ -0,0 +1,198 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag is a free-form label. Unlike a category, a todo can have any number of
+// tags.
+type Tag struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagCount is a tag with the number of todos that carry it
+type TagCount struct {
+	Tag
+	Todos int `json:"todos"`
+}
+
+// TagService defines the interface for tagging todos. Tag names are matched
+// case-insensitively and stored lower-cased without a leading '#'.
+type TagService interface {
+	// AddTags tags a todo, creating tags that do not exist yet, and returns
+	// all of the todo's tags
+	AddTags(ctx context.Context, todoID string, names []string) ([]Tag, error)
+
+	// RemoveTags untags a todo and returns the tags it has left. A tag stays
+	// when no todo uses it any more.
+	RemoveTags(ctx context.Context, todoID string, names []string) ([]Tag, error)
+
+	// GetTodoTags returns a todo's tags ordered by name
+	GetTodoTags(ctx context.Context, todoID string) ([]Tag, error)
+
+	// GetAllTodoTags returns the tags of every tagged todo keyed by todo ID,
+	// so listings do not need a query per todo
+	GetAllTodoTags(ctx context.Context) (map[string][]Tag, error)
+
+	// ListTags returns every tag with the number of todos that carry it,
+	// ordered by name
+	ListTags(ctx context.Context) ([]TagCount, error)
+
+	// RenameTag renames a tag; the new name must not be taken
+	RenameTag(ctx context.Context, name string, newName string) (Tag, error)
+
+	// MergeTags moves every todo tagged from onto into, then deletes from
+	MergeTags(ctx context.Context, from string, into string) (Tag, error)
+
+	// GetTodosByTags returns the todos that have any of the tags, or all of
+	// them when matchAll is set, ordered like GetActiveTodos
+	GetTodosByTags(ctx context.Context, names []string, matchAll bool) ([]TodoItem, error)
+}
+
+const maxTagLength = 64
+
+// normalizeTagName returns the stored form of a tag name
+func normalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	switch {
+	case name == "":
+		return "", newValidationError("tag", "tag name cannot be empty")
+	case len(name) > maxTagLength:
+		return "", newValidationError("tag", fmt.Sprintf("tag name cannot exceed %d characters", maxTagLength))
+	case strings.Contains(name, ","):
+		return "", newValidationError("tag", "tag name cannot contain a comma")
+	}
+	return name, nil
+}
+
+// normalizeTagNames normalizes names, dropping duplicates. At least one name
+// is required.
+func normalizeTagNames(names []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, newValidationError("tags", "at least one tag is required")
+	}
+	return normalized, nil
+}
+
+// normalizeMerge normalizes the tag names given to MergeTags
+func normalizeMerge(from string, into string) (string, string, error) {
+	from, err := normalizeTagName(from)
+	if err != nil {
+		return "", "", err
+	}
+	into, err = normalizeTagName(into)
+	if err != nil {
+		return "", "", err
+	}
+	if from == into {
+		return "", "", newValidationError("tag", "cannot merge a tag into itself")
+	}
+	return from, into, nil
+}
+
+// placeholders returns n comma-separated "?" placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// postgresPlaceholders returns n comma-separated placeholders starting at $first
+func postgresPlaceholders(n int, first int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = fmt.Sprintf("$%d", first+i)
+	}
+	return strings.Join(params, ", ")
+}
+
+// stringArgs converts names to query arguments
+func stringArgs(names []string) []interface{} {
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+	return args
+}
+
+// sortTags orders tags by name
+func sortTags(tags []Tag) {
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+}
+
+// queryTags runs a query selecting id, name and created_at from tags
+func queryTags(ctx context.Context, db DBTX, query string, args ...interface{}) ([]Tag, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []Tag
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// queryTagCounts runs a query selecting id, name, created_at and a todo count
+func queryTagCounts(ctx context.Context, db DBTX, query string, args ...interface{}) ([]TagCount, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []TagCount
+	for rows.Next() {
+		var tag TagCount
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.Todos); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// queryTodoTags runs a query selecting todo_id, then id, name and created_at
+// from tags, and groups the tags by todo ID
+func queryTodoTags(ctx context.Context, db DBTX, query string, args ...interface{}) (map[string][]Tag, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byTodo := make(map[string][]Tag)
+	for rows.Next() {
+		var todoID int64
+		var tag Tag
+		if err := rows.Scan(&todoID, &tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		key := strconv.FormatInt(todoID, 10)
+		byTodo[key] = append(byTodo[key], tag)
+	}
+	return byTodo, rows.Err()
+}
@@ -0,0 +1,191 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// NewTagMariaDB creates a new MariaDB implementation of TagService
+func NewTagMariaDB(db DBTX) TagService {
+	return &tag_mariadb{db: db}
+}
+
+type tag_mariadb struct {
+	db DBTX
+}
+
+// AddTags tags a todo, creating tags that do not exist yet
+func (t *tag_mariadb) AddTags(ctx context.Context, todoID string, names []string) ([]Tag, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	err = withTx(ctx, t.db, func(tx DBTX) error {
+		if err := t.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		for _, name := range names {
+			_, err := tx.ExecContext(ctx, "INSERT IGNORE INTO tags (name, created_at) VALUES (?, ?)", name, time.Now())
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, "INSERT IGNORE INTO todo_tags (todo_id, tag_id) SELECT ?, id FROM tags WHERE name = ?", todoID, name)
+			if err != nil {
+				return err
+			}
+		}
+		tags, err = (&tag_mariadb{db: tx}).GetTodoTags(ctx, todoID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// RemoveTags untags a todo and returns the tags it has left
+func (t *tag_mariadb) RemoveTags(ctx context.Context, todoID string, names []string) ([]Tag, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+	err = withTx(ctx, t.db, func(tx DBTX) error {
+		if err := t.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		for _, name := range names {
+			tag, err := t.findByName(ctx, tx, name)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, "DELETE FROM todo_tags WHERE todo_id = ? AND tag_id = ?", todoID, tag.ID)
+			if err != nil {
+				return err
+			}
+		}
+		tags, err = (&tag_mariadb{db: tx}).GetTodoTags(ctx, todoID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetTodoTags returns a todo's tags ordered by name
+func (t *tag_mariadb) GetTodoTags(ctx context.Context, todoID string) ([]Tag, error) {
+	if err := t.todoExists(ctx, t.db, todoID); err != nil {
+		return nil, err
+	}
+	return queryTags(ctx, t.db, "SELECT t.id, t.name, t.created_at FROM tags t JOIN todo_tags tt ON tt.tag_id = t.id WHERE tt.todo_id = ? ORDER BY t.name", todoID)
+}
+
+// GetAllTodoTags returns the tags of every tagged todo keyed by todo ID
+func (t *tag_mariadb) GetAllTodoTags(ctx context.Context) (map[string][]Tag, error) {
+	return queryTodoTags(ctx, t.db, "SELECT tt.todo_id, t.id, t.name, t.created_at FROM todo_tags tt JOIN tags t ON t.id = tt.tag_id ORDER BY t.name")
+}
+
+// ListTags returns every tag with the number of todos that carry it
+func (t *tag_mariadb) ListTags(ctx context.Context) ([]TagCount, error) {
+	return queryTagCounts(ctx, t.db, "SELECT t.id, t.name, t.created_at, COUNT(tt.todo_id) FROM tags t LEFT JOIN todo_tags tt ON tt.tag_id = t.id GROUP BY t.id, t.name, t.created_at ORDER BY t.name")
+}
+
+// RenameTag renames a tag; the new name must not be taken
+func (t *tag_mariadb) RenameTag(ctx context.Context, name string, newName string) (Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return Tag{}, err
+	}
+	newName, err = normalizeTagName(newName)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	var tag Tag
+	err = withTx(ctx, t.db, func(tx DBTX) error {
+		tag, err = t.findByName(ctx, tx, name)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE tags SET name = ? WHERE id = ?", newName, tag.ID)
+		if isUniqueViolation(err) {
+			return duplicateName("tag", newName)
+		}
+		tag.Name = newName
+		return err
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+	return tag, nil
+}
+
+// MergeTags moves every todo tagged from onto into, then deletes from
+func (t *tag_mariadb) MergeTags(ctx context.Context, from string, into string) (Tag, error) {
+	from, into, err := normalizeMerge(from, into)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	var target Tag
+	err = withTx(ctx, t.db, func(tx DBTX) error {
+		source, err := t.findByName(ctx, tx, from)
+		if err != nil {
+			return err
+		}
+		target, err = t.findByName(ctx, tx, into)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT IGNORE INTO todo_tags (todo_id, tag_id) SELECT todo_id, ? FROM todo_tags WHERE tag_id = ?", target.ID, source.ID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM todo_tags WHERE tag_id = ?", source.ID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM tags WHERE id = ?", source.ID)
+		return err
+	})
+	if err != nil {
+		return Tag{}, err
+	}
+	return target, nil
+}
+
+// GetTodosByTags returns the todos that have any, or all, of the tags
+func (t *tag_mariadb) GetTodosByTags(ctx context.Context, names []string, matchAll bool) ([]TodoItem, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	required := 1
+	if matchAll {
+		required = len(names)
+	}
+	query := "SELECT "+todoColumns+" FROM todos WHERE id IN (" +
+		"SELECT tt.todo_id FROM todo_tags tt JOIN tags t ON t.id = tt.tag_id WHERE t.name IN (" + placeholders(len(names)) + ") " +
+		"GROUP BY tt.todo_id HAVING COUNT(*) >= ?) ORDER BY priority DESC, due_date IS NULL, due_date, id"
+	return queryTodos(ctx, t.db, query, append(stringArgs(names), required)...)
+}
+
+// findByName returns the tag called name, which must already be normalized
+func (t *tag_mariadb) findByName(ctx context.Context, db DBTX, name string) (Tag, error) {
+	var tag Tag
+	err := db.QueryRowContext(ctx, "SELECT id, name, created_at FROM tags WHERE name = ?", name).Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+	return tag, orNotFound(err, tagNotFound(name))
+}
+
+// todoExists returns ErrTodoNotFound unless the todo exists
+func (t *tag_mariadb) todoExists(ctx context.Context, db DBTX, todoID string) error {
+	var found int
+	err := db.QueryRowContext(ctx, "SELECT 1 FROM todos WHERE id = ?", todoID).Scan(&found)
+	return orNotFound(err, todoNotFound(todoID))
+}
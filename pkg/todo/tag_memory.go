@@ -0,0 +1,239 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// NewTagMemory creates a new in-memory implementation of TagService
+func NewTagMemory(store *MemoryStore) TagService {
+	return &tag_memory{store: store}
+}
+
+type tag_memory struct {
+	store *MemoryStore
+}
+
+// AddTags tags a todo, creating tags that do not exist yet
+func (t *tag_memory) AddTags(ctx context.Context, todoID string, names []string) ([]Tag, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, ok := t.todoKey(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	for _, name := range names {
+		tag, ok := t.findByName(name)
+		if !ok {
+			t.store.lastTagID++
+			tag = Tag{ID: t.store.lastTagID, Name: name, CreatedAt: time.Now()}
+			t.store.tags[tag.ID] = tag
+		}
+		if t.store.todoTags[key] == nil {
+			t.store.todoTags[key] = make(map[int64]bool)
+		}
+		t.store.todoTags[key][tag.ID] = true
+	}
+	return t.tagsOf(key), nil
+}
+
+// RemoveTags untags a todo and returns the tags it has left
+func (t *tag_memory) RemoveTags(ctx context.Context, todoID string, names []string) ([]Tag, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, ok := t.todoKey(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	// Look every tag up first so an unknown one removes nothing
+	var remove []int64
+	for _, name := range names {
+		tag, ok := t.findByName(name)
+		if !ok {
+			return nil, tagNotFound(name)
+		}
+		remove = append(remove, tag.ID)
+	}
+	for _, tagID := range remove {
+		delete(t.store.todoTags[key], tagID)
+	}
+	return t.tagsOf(key), nil
+}
+
+// GetTodoTags returns a todo's tags ordered by name
+func (t *tag_memory) GetTodoTags(ctx context.Context, todoID string) ([]Tag, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	key, ok := t.todoKey(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	return t.tagsOf(key), nil
+}
+
+// GetAllTodoTags returns the tags of every tagged todo keyed by todo ID
+func (t *tag_memory) GetAllTodoTags(ctx context.Context) (map[string][]Tag, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	byTodo := make(map[string][]Tag)
+	for key := range t.store.todoTags {
+		if tags := t.tagsOf(key); len(tags) > 0 {
+			byTodo[strconv.FormatInt(key, 10)] = tags
+		}
+	}
+	return byTodo, nil
+}
+
+// ListTags returns every tag with the number of todos that carry it
+func (t *tag_memory) ListTags(ctx context.Context) ([]TagCount, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	var tags []TagCount
+	for _, tag := range t.store.tags {
+		count := 0
+		for _, tagIDs := range t.store.todoTags {
+			if tagIDs[tag.ID] {
+				count++
+			}
+		}
+		tags = append(tags, TagCount{Tag: tag, Todos: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// RenameTag renames a tag; the new name must not be taken
+func (t *tag_memory) RenameTag(ctx context.Context, name string, newName string) (Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return Tag{}, err
+	}
+	newName, err = normalizeTagName(newName)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	tag, ok := t.findByName(name)
+	if !ok {
+		return Tag{}, tagNotFound(name)
+	}
+	// Mirror the UNIQUE constraint on tags.name
+	if other, ok := t.findByName(newName); ok && other.ID != tag.ID {
+		return Tag{}, duplicateName("tag", newName)
+	}
+	tag.Name = newName
+	t.store.tags[tag.ID] = tag
+	return tag, nil
+}
+
+// MergeTags moves every todo tagged from onto into, then deletes from
+func (t *tag_memory) MergeTags(ctx context.Context, from string, into string) (Tag, error) {
+	from, into, err := normalizeMerge(from, into)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	source, ok := t.findByName(from)
+	if !ok {
+		return Tag{}, tagNotFound(from)
+	}
+	target, ok := t.findByName(into)
+	if !ok {
+		return Tag{}, tagNotFound(into)
+	}
+	for _, tagIDs := range t.store.todoTags {
+		if tagIDs[source.ID] {
+			delete(tagIDs, source.ID)
+			tagIDs[target.ID] = true
+		}
+	}
+	delete(t.store.tags, source.ID)
+	return target, nil
+}
+
+// GetTodosByTags returns the todos that have any, or all, of the tags
+func (t *tag_memory) GetTodosByTags(ctx context.Context, names []string, matchAll bool) ([]TodoItem, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	required := 1
+	if matchAll {
+		required = len(names)
+	}
+
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	var wanted []int64
+	for _, name := range names {
+		if tag, ok := t.findByName(name); ok {
+			wanted = append(wanted, tag.ID)
+		}
+	}
+	return byPriority(t.store.selectTodos(func(item TodoItem) bool {
+		key, _ := strconv.ParseInt(item.ID, 10, 64)
+		matched := 0
+		for _, tagID := range wanted {
+			if t.store.todoTags[key][tagID] {
+				matched++
+			}
+		}
+		return matched >= required
+	})), nil
+}
+
+// findByName returns the tag called name; the caller must hold the lock
+func (t *tag_memory) findByName(name string) (Tag, bool) {
+	for _, tag := range t.store.tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+// todoKey returns the store key of the todo with todoID; the caller must hold
+// the lock
+func (t *tag_memory) todoKey(todoID string) (int64, bool) {
+	key, err := strconv.ParseInt(todoID, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	_, ok := t.store.todos[key]
+	return key, ok
+}
+
+// tagsOf returns the tags of the todo stored under key, ordered by name; the
+// caller must hold the lock
+func (t *tag_memory) tagsOf(key int64) []Tag {
+	var tags []Tag
+	for tagID := range t.store.todoTags[key] {
+		tags = append(tags, t.store.tags[tagID])
+	}
+	sortTags(tags)
+	return tags
+}
@@ -0,0 +1,222 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// TimeEntry is time spent on a todo, tracked with a timer or logged by hand.
+// The running timer is the entry without an end.
+type TimeEntry struct {
+	ID        int64      `json:"id"`
+	TodoID    string     `json:"todo_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"` // nil while the timer runs
+	Note      string     `json:"note"`
+}
+
+// Running reports whether the entry is the running timer
+func (e TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Duration returns the time the entry covers; a running timer counts up to now
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
+
+// TimeService defines the interface for tracking time spent on todos. Todos
+// have no owner, so the whole database is one user's: only one timer runs at
+// a time across it, which a unique index on the running entry enforces.
+type TimeService interface {
+	// StartTimer starts timing todoID. It fails with ErrTimerRunning while
+	// another timer runs.
+	StartTimer(ctx context.Context, todoID string, note string) (TimeEntry, error)
+
+	// StopTimer stops the running timer and returns its entry, or fails with
+	// ErrNoTimerRunning
+	StopTimer(ctx context.Context) (TimeEntry, error)
+
+	// GetRunningTimer returns the running timer, or nil when none runs
+	GetRunningTimer(ctx context.Context) (*TimeEntry, error)
+
+	// LogTime records duration spent on todoID from startedAt
+	LogTime(ctx context.Context, todoID string, startedAt time.Time, duration time.Duration, note string) (TimeEntry, error)
+
+	// GetTodoTimeEntries returns a todo's entries, oldest first
+	GetTodoTimeEntries(ctx context.Context, todoID string) ([]TimeEntry, error)
+
+	// GetTimeEntries returns the entries that overlap from to to, including
+	// the running timer, oldest first
+	GetTimeEntries(ctx context.Context, from time.Time, to time.Time) ([]TimeEntry, error)
+
+	// DeleteTimeEntry deletes an entry, such as one logged by mistake
+	DeleteTimeEntry(ctx context.Context, id int64) (TimeEntry, error)
+}
+
+const maxTimeNoteLength = 500
+
+func validateTimeNote(note string) error {
+	if len(note) > maxTimeNoteLength {
+		return newValidationError("note", "note cannot exceed 500 characters")
+	}
+	return nil
+}
+
+// validateLogTime checks the arguments of LogTime and returns when the entry
+// ends
+func validateLogTime(startedAt time.Time, duration time.Duration, note string) (time.Time, error) {
+	if duration <= 0 {
+		return time.Time{}, newValidationError("duration", "duration must be positive")
+	}
+	if err := validateTimeNote(note); err != nil {
+		return time.Time{}, err
+	}
+	return startedAt.Add(duration).UTC(), nil
+}
+
+// timeEntryColumns are the time_entries columns scanTimeEntry reads
+const timeEntryColumns = "id, todo_id, started_at, ended_at, note"
+
+// scanTimeEntry scans the columns id, todo_id, started_at, ended_at and note
+// of time_entries, in that order
+func scanTimeEntry(row interface {
+	Scan(dest ...interface{}) error
+}) (TimeEntry, error) {
+	var entry TimeEntry
+	err := row.Scan(&entry.ID, &entry.TodoID, &entry.StartedAt, &entry.EndedAt, &entry.Note)
+	return entry, err
+}
+
+// queryTimeEntries runs a query selecting the time_entries columns in the
+// order scanTimeEntry reads them
+func queryTimeEntries(ctx context.Context, db DBTX, query string, args ...interface{}) ([]TimeEntry, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// TimeReport sums the time spent between From and To by todo, project and
+// category. Entries are cut off at the edges of the range.
+type TimeReport struct {
+	From       time.Time
+	To         time.Time
+	Total      time.Duration
+	Todos      []TodoTime  // most time first
+	Projects   []GroupTime // most time first
+	Categories []GroupTime // most time first
+}
+
+// TodoTime is the time spent on one todo
+type TodoTime struct {
+	Todo  TodoItem
+	Spent time.Duration
+}
+
+// GroupTime is the time spent on the todos of one project or category. A nil
+// ID groups the todos without one.
+type GroupTime struct {
+	ID    *int64
+	Spent time.Duration
+}
+
+// NewTimeReport builds the report of entries between from and to. todos
+// supplies each entry's title, project and category; a running timer counts
+// up to now.
+func NewTimeReport(entries []TimeEntry, todos []TodoItem, from time.Time, to time.Time, now time.Time) TimeReport {
+	report := TimeReport{From: from, To: to}
+	byID := make(map[string]TodoItem, len(todos))
+	for _, item := range todos {
+		byID[item.ID] = item
+	}
+
+	spent := make(map[string]time.Duration)
+	for _, entry := range entries {
+		start, end := entry.StartedAt, now
+		if entry.EndedAt != nil {
+			end = *entry.EndedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+		spent[entry.TodoID] += end.Sub(start)
+		report.Total += end.Sub(start)
+	}
+
+	projects := make(map[int64]time.Duration)
+	categories := make(map[int64]time.Duration)
+	var noProject, noCategory time.Duration
+	for todoID, d := range spent {
+		item, ok := byID[todoID]
+		if !ok {
+			item = TodoItem{ID: todoID}
+		}
+		report.Todos = append(report.Todos, TodoTime{Todo: item, Spent: d})
+		if item.ProjectID != nil {
+			projects[*item.ProjectID] += d
+		} else {
+			noProject += d
+		}
+		if item.CategoryID != nil {
+			categories[*item.CategoryID] += d
+		} else {
+			noCategory += d
+		}
+	}
+	sort.Slice(report.Todos, func(i, j int) bool {
+		a, b := report.Todos[i], report.Todos[j]
+		if a.Spent != b.Spent {
+			return a.Spent > b.Spent
+		}
+		return a.Todo.ID < b.Todo.ID
+	})
+	report.Projects = groupTimes(projects, noProject)
+	report.Categories = groupTimes(categories, noCategory)
+	return report
+}
+
+// groupTimes lists the totals by ID, most time first, with the total of the
+// todos without an ID last
+func groupTimes(byID map[int64]time.Duration, none time.Duration) []GroupTime {
+	var groups []GroupTime
+	for id, d := range byID {
+		id := id
+		groups = append(groups, GroupTime{ID: &id, Spent: d})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Spent != groups[j].Spent {
+			return groups[i].Spent > groups[j].Spent
+		}
+		return *groups[i].ID < *groups[j].ID
+	})
+	if none > 0 {
+		groups = append(groups, GroupTime{Spent: none})
+	}
+	return groups
+}
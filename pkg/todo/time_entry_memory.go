@@ -0,0 +1,160 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// NewTimeMemory creates a new in-memory implementation of TimeService
+func NewTimeMemory(store *MemoryStore) TimeService {
+	return &time_memory{store: store}
+}
+
+type time_memory struct {
+	store *MemoryStore
+}
+
+// StartTimer starts timing todoID unless another timer runs
+func (t *time_memory) StartTimer(ctx context.Context, todoID string, note string) (TimeEntry, error) {
+	if err := validateTimeNote(note); err != nil {
+		return TimeEntry{}, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	if !t.todoExists(todoID) {
+		return TimeEntry{}, todoNotFound(todoID)
+	}
+	if running := t.running(); running != nil {
+		return TimeEntry{}, timerRunning(*running)
+	}
+	return t.insert(TimeEntry{TodoID: todoID, StartedAt: time.Now().UTC(), Note: note}), nil
+}
+
+// StopTimer stops the running timer
+func (t *time_memory) StopTimer(ctx context.Context) (TimeEntry, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	running := t.running()
+	if running == nil {
+		return TimeEntry{}, ErrNoTimerRunning
+	}
+	ended := time.Now().UTC()
+	running.EndedAt = &ended
+	t.store.timeEntries[running.ID] = cloneTimeEntry(*running)
+	return *running, nil
+}
+
+// GetRunningTimer returns the running timer, or nil
+func (t *time_memory) GetRunningTimer(ctx context.Context) (*TimeEntry, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return t.running(), nil
+}
+
+// LogTime records duration spent on todoID from startedAt
+func (t *time_memory) LogTime(ctx context.Context, todoID string, startedAt time.Time, duration time.Duration, note string) (TimeEntry, error) {
+	ended, err := validateLogTime(startedAt, duration, note)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	if !t.todoExists(todoID) {
+		return TimeEntry{}, todoNotFound(todoID)
+	}
+	return t.insert(TimeEntry{TodoID: todoID, StartedAt: startedAt.UTC(), EndedAt: &ended, Note: note}), nil
+}
+
+// GetTodoTimeEntries returns a todo's entries, oldest first
+func (t *time_memory) GetTodoTimeEntries(ctx context.Context, todoID string) ([]TimeEntry, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	if !t.todoExists(todoID) {
+		return nil, todoNotFound(todoID)
+	}
+	return t.selectEntries(func(entry TimeEntry) bool {
+		return entry.TodoID == todoID
+	}), nil
+}
+
+// GetTimeEntries returns the entries that overlap from to to, oldest first
+func (t *time_memory) GetTimeEntries(ctx context.Context, from time.Time, to time.Time) ([]TimeEntry, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return t.selectEntries(func(entry TimeEntry) bool {
+		return entry.StartedAt.Before(to) && (entry.EndedAt == nil || entry.EndedAt.After(from))
+	}), nil
+}
+
+// DeleteTimeEntry deletes an entry
+func (t *time_memory) DeleteTimeEntry(ctx context.Context, id int64) (TimeEntry, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	entry, ok := t.store.timeEntries[id]
+	if !ok {
+		return TimeEntry{}, timeEntryNotFound(id)
+	}
+	delete(t.store.timeEntries, id)
+	return cloneTimeEntry(entry), nil
+}
+
+// insert stores entry under the next ID and returns it; the caller must hold
+// the lock
+func (t *time_memory) insert(entry TimeEntry) TimeEntry {
+	t.store.lastTimeEntryID++
+	entry.ID = t.store.lastTimeEntryID
+	t.store.timeEntries[entry.ID] = cloneTimeEntry(entry)
+	return entry
+}
+
+// running returns a copy of the running timer, or nil; the caller must hold
+// the lock
+func (t *time_memory) running() *TimeEntry {
+	for _, entry := range t.store.timeEntries {
+		if entry.Running() {
+			running := cloneTimeEntry(entry)
+			return &running
+		}
+	}
+	return nil
+}
+
+// selectEntries returns copies of the entries matching keep ordered by
+// started_at, then ID; the caller must hold the lock
+func (t *time_memory) selectEntries(keep func(entry TimeEntry) bool) []TimeEntry {
+	var entries []TimeEntry
+	for _, entry := range t.store.timeEntries {
+		if keep(entry) {
+			entries = append(entries, cloneTimeEntry(entry))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].StartedAt.Equal(entries[j].StartedAt) {
+			return entries[i].StartedAt.Before(entries[j].StartedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// todoExists reports whether the todo with todoID exists; the caller must
+// hold the lock
+func (t *time_memory) todoExists(todoID string) bool {
+	key, err := strconv.ParseInt(todoID, 10, 64)
+	if err != nil {
+		return false
+	}
+	_, ok := t.store.todos[key]
+	return ok
+}
@@ -0,0 +1,147 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// NewTimePostgres creates a new PostgreSQL implementation of TimeService
+func NewTimePostgres(db DBTX) TimeService {
+	return &time_postgres{db: db}
+}
+
+type time_postgres struct {
+	db DBTX
+}
+
+// StartTimer starts timing todoID unless another timer runs
+func (t *time_postgres) StartTimer(ctx context.Context, todoID string, note string) (TimeEntry, error) {
+	if err := validateTimeNote(note); err != nil {
+		return TimeEntry{}, err
+	}
+
+	entry := TimeEntry{TodoID: todoID, StartedAt: time.Now().UTC(), Note: note}
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		if err := t.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		running, err := t.running(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			return timerRunning(*running)
+		}
+		err = tx.QueryRowContext(ctx, "INSERT INTO time_entries (todo_id, started_at, note) VALUES ($1::bigint, $2, $3) RETURNING id", todoID, entry.StartedAt, note).Scan(&entry.ID)
+		if isUniqueViolation(err) {
+			// Another timer started since the check
+			return ErrTimerRunning
+		}
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// StopTimer stops the running timer
+func (t *time_postgres) StopTimer(ctx context.Context) (TimeEntry, error) {
+	var entry TimeEntry
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		running, err := t.running(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if running == nil {
+			return ErrNoTimerRunning
+		}
+		entry = *running
+		ended := time.Now().UTC()
+		entry.EndedAt = &ended
+		_, err = tx.ExecContext(ctx, "UPDATE time_entries SET ended_at = $1 WHERE id = $2", ended, entry.ID)
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetRunningTimer returns the running timer, or nil
+func (t *time_postgres) GetRunningTimer(ctx context.Context) (*TimeEntry, error) {
+	return t.running(ctx, t.db)
+}
+
+// LogTime records duration spent on todoID from startedAt
+func (t *time_postgres) LogTime(ctx context.Context, todoID string, startedAt time.Time, duration time.Duration, note string) (TimeEntry, error) {
+	ended, err := validateLogTime(startedAt, duration, note)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	entry := TimeEntry{TodoID: todoID, StartedAt: startedAt.UTC(), EndedAt: &ended, Note: note}
+	err = withTx(ctx, t.db, func(tx DBTX) error {
+		if err := t.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		return tx.QueryRowContext(ctx, "INSERT INTO time_entries (todo_id, started_at, ended_at, note) VALUES ($1::bigint, $2, $3, $4) RETURNING id", todoID, entry.StartedAt, ended, note).Scan(&entry.ID)
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetTodoTimeEntries returns a todo's entries, oldest first
+func (t *time_postgres) GetTodoTimeEntries(ctx context.Context, todoID string) ([]TimeEntry, error) {
+	if err := t.todoExists(ctx, t.db, todoID); err != nil {
+		return nil, err
+	}
+	return queryTimeEntries(ctx, t.db, "SELECT "+timeEntryColumns+" FROM time_entries WHERE todo_id = $1 ORDER BY started_at, id", todoID)
+}
+
+// GetTimeEntries returns the entries that overlap from to to, oldest first
+func (t *time_postgres) GetTimeEntries(ctx context.Context, from time.Time, to time.Time) ([]TimeEntry, error) {
+	return queryTimeEntries(ctx, t.db, "SELECT "+timeEntryColumns+" FROM time_entries WHERE started_at < $1 AND (ended_at IS NULL OR ended_at > $2) ORDER BY started_at, id", to.UTC(), from.UTC())
+}
+
+// DeleteTimeEntry deletes an entry
+func (t *time_postgres) DeleteTimeEntry(ctx context.Context, id int64) (TimeEntry, error) {
+	var entry TimeEntry
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		var err error
+		entry, err = scanTimeEntry(tx.QueryRowContext(ctx, "SELECT "+timeEntryColumns+" FROM time_entries WHERE id = $1", id))
+		if err != nil {
+			return orNotFound(err, timeEntryNotFound(id))
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM time_entries WHERE id = $1", id)
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// running returns the running timer, or nil
+func (t *time_postgres) running(ctx context.Context, db DBTX) (*TimeEntry, error) {
+	entries, err := queryTimeEntries(ctx, db, "SELECT "+timeEntryColumns+" FROM time_entries WHERE ended_at IS NULL")
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// todoExists returns ErrTodoNotFound unless the todo exists
+func (t *time_postgres) todoExists(ctx context.Context, db DBTX, todoID string) error {
+	key, err := parseTodoID(todoID)
+	if err != nil {
+		return err
+	}
+	var found int
+	err = db.QueryRowContext(ctx, "SELECT 1 FROM todos WHERE id = $1", key).Scan(&found)
+	return orNotFound(err, todoNotFound(todoID))
+}
@@ -0,0 +1,152 @@
+package todo
+
+import (
+	"context"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// NewTimeSQLite creates a new SQLite implementation of TimeService
+func NewTimeSQLite(db DBTX) TimeService {
+	return &time_sqlite{db: db}
+}
+
+type time_sqlite struct {
+	db DBTX
+}
+
+// StartTimer starts timing todoID unless another timer runs
+func (t *time_sqlite) StartTimer(ctx context.Context, todoID string, note string) (TimeEntry, error) {
+	if err := validateTimeNote(note); err != nil {
+		return TimeEntry{}, err
+	}
+
+	entry := TimeEntry{TodoID: todoID, StartedAt: time.Now().UTC(), Note: note}
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		if err := t.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		running, err := t.running(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			return timerRunning(*running)
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO time_entries (todo_id, started_at, note) VALUES (?, ?, ?)", todoID, entry.StartedAt, note)
+		if isUniqueViolation(err) {
+			// Another timer started since the check
+			return ErrTimerRunning
+		}
+		if err != nil {
+			return err
+		}
+		entry.ID, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// StopTimer stops the running timer
+func (t *time_sqlite) StopTimer(ctx context.Context) (TimeEntry, error) {
+	var entry TimeEntry
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		running, err := t.running(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if running == nil {
+			return ErrNoTimerRunning
+		}
+		entry = *running
+		ended := time.Now().UTC()
+		entry.EndedAt = &ended
+		_, err = tx.ExecContext(ctx, "UPDATE time_entries SET ended_at = ? WHERE id = ?", ended, entry.ID)
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetRunningTimer returns the running timer, or nil
+func (t *time_sqlite) GetRunningTimer(ctx context.Context) (*TimeEntry, error) {
+	return t.running(ctx, t.db)
+}
+
+// LogTime records duration spent on todoID from startedAt
+func (t *time_sqlite) LogTime(ctx context.Context, todoID string, startedAt time.Time, duration time.Duration, note string) (TimeEntry, error) {
+	ended, err := validateLogTime(startedAt, duration, note)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	entry := TimeEntry{TodoID: todoID, StartedAt: startedAt.UTC(), EndedAt: &ended, Note: note}
+	err = withTx(ctx, t.db, func(tx DBTX) error {
+		if err := t.todoExists(ctx, tx, todoID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO time_entries (todo_id, started_at, ended_at, note) VALUES (?, ?, ?, ?)", todoID, entry.StartedAt, ended, note)
+		if err != nil {
+			return err
+		}
+		entry.ID, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// GetTodoTimeEntries returns a todo's entries, oldest first
+func (t *time_sqlite) GetTodoTimeEntries(ctx context.Context, todoID string) ([]TimeEntry, error) {
+	if err := t.todoExists(ctx, t.db, todoID); err != nil {
+		return nil, err
+	}
+	return queryTimeEntries(ctx, t.db, "SELECT "+timeEntryColumns+" FROM time_entries WHERE todo_id = ? ORDER BY started_at, id", todoID)
+}
+
+// GetTimeEntries returns the entries that overlap from to to, oldest first
+func (t *time_sqlite) GetTimeEntries(ctx context.Context, from time.Time, to time.Time) ([]TimeEntry, error) {
+	return queryTimeEntries(ctx, t.db, "SELECT "+timeEntryColumns+" FROM time_entries WHERE started_at < ? AND (ended_at IS NULL OR ended_at > ?) ORDER BY started_at, id", to.UTC(), from.UTC())
+}
+
+// DeleteTimeEntry deletes an entry
+func (t *time_sqlite) DeleteTimeEntry(ctx context.Context, id int64) (TimeEntry, error) {
+	var entry TimeEntry
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		var err error
+		entry, err = scanTimeEntry(tx.QueryRowContext(ctx, "SELECT "+timeEntryColumns+" FROM time_entries WHERE id = ?", id))
+		if err != nil {
+			return orNotFound(err, timeEntryNotFound(id))
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM time_entries WHERE id = ?", id)
+		return err
+	})
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return entry, nil
+}
+
+// running returns the running timer, or nil
+func (t *time_sqlite) running(ctx context.Context, db DBTX) (*TimeEntry, error) {
+	entries, err := queryTimeEntries(ctx, db, "SELECT "+timeEntryColumns+" FROM time_entries WHERE ended_at IS NULL")
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// todoExists returns ErrTodoNotFound unless the todo exists
+func (t *time_sqlite) todoExists(ctx context.Context, db DBTX, todoID string) error {
+	var found int
+	err := db.QueryRowContext(ctx, "SELECT 1 FROM todos WHERE id = ?", todoID).Scan(&found)
+	return orNotFound(err, todoNotFound(todoID))
+}
@@ -0,0 +1,64 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimeReport(t *testing.T) {
+	monday := time.Date(2030, time.March, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time {
+		return monday.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	entry := func(todoID string, from, to float64) TimeEntry {
+		ended := at(to)
+		return TimeEntry{TodoID: todoID, StartedAt: at(from), EndedAt: &ended}
+	}
+	project, category := int64(7), int64(3)
+	todos := []TodoItem{
+		{ID: "1", Title: "Write report", ProjectID: &project, CategoryID: &category},
+		{ID: "2", Title: "Review budget", ProjectID: &project},
+		{ID: "3", Title: "Call plumber"},
+	}
+	entries := []TimeEntry{
+		entry("1", -1, 1),                // only the hour after the start counts
+		entry("1", 9, 11),                // 2h
+		entry("2", 12, 13),               // 1h
+		entry("3", 23, 26),               // only the hour before the end counts
+		entry("3", -5, -4),               // outside the range
+		{TodoID: "2", StartedAt: at(20)}, // running until now, 1h30m
+	}
+
+	report := NewTimeReport(entries, todos, monday, at(24), at(21.5))
+	assert.Equal(t, 6*time.Hour+30*time.Minute, report.Total)
+
+	require.Len(t, report.Todos, 3)
+	assert.Equal(t, "1", report.Todos[0].Todo.ID)
+	assert.Equal(t, "Write report", report.Todos[0].Todo.Title)
+	assert.Equal(t, 3*time.Hour, report.Todos[0].Spent)
+	assert.Equal(t, "2", report.Todos[1].Todo.ID)
+	assert.Equal(t, 2*time.Hour+30*time.Minute, report.Todos[1].Spent)
+	assert.Equal(t, "3", report.Todos[2].Todo.ID)
+	assert.Equal(t, time.Hour, report.Todos[2].Spent)
+
+	assert.Equal(t, []GroupTime{
+		{ID: &project, Spent: 5*time.Hour + 30*time.Minute},
+		{Spent: time.Hour},
+	}, report.Projects)
+	assert.Equal(t, []GroupTime{
+		{ID: &category, Spent: 3 * time.Hour},
+		{Spent: 3*time.Hour + 30*time.Minute},
+	}, report.Categories, "todos without a category come last")
+}
+
+func TestNewTimeReportEmpty(t *testing.T) {
+	from := time.Date(2030, time.March, 4, 0, 0, 0, 0, time.UTC)
+	report := NewTimeReport(nil, nil, from, from.AddDate(0, 0, 7), from)
+	assert.Zero(t, report.Total)
+	assert.Empty(t, report.Todos)
+	assert.Empty(t, report.Projects)
+	assert.Empty(t, report.Categories)
+}
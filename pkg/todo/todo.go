@@ -1,6 +1,7 @@
 package todo
 
 import (
+	"context"
 	"time"
 )
 
@@ -11,6 +12,8 @@ type RecurrencePattern struct {
 	Interval  int        `json:"interval"`
 	Until     *time.Time `json:"until"`
 	Count     *int       `json:"count"`
+	RRule     string     `json:"rrule,omitempty"` // RFC 5545 RRULE; when set it takes precedence over Frequency and Interval
+	Paused    bool       `json:"paused"`          // a paused pattern creates no further occurrences
 }
 
 type TodoItem struct {
@@ -18,31 +21,128 @@ type TodoItem struct {
 	Title       string     `json:"title"`
 	CompletedAt *time.Time `json:"completed_at"` // nil means not completed
 	DueDate     *time.Time `json:"due_date"`
+	DueAllDay   bool       `json:"due_all_day"` // DueDate is a calendar date, stored as midnight UTC, rather than an instant
 	CreatedDate time.Time  `json:"created_date"`
 	ReferenceID *int64     `json:"reference_id"` // pointer to handle NULL in database
 	ProjectID   *int64     `json:"project_id"`   // pointer to handle NULL in database (optional project association)
 	CategoryID  *int64     `json:"category_id"`  // pointer to handle NULL in database (optional category association)
+	Priority    Priority   `json:"priority"`
+	Notes       string     `json:"notes"`      // Markdown; empty means no notes
+	ParentID    *int64     `json:"parent_id"`  // the todo this is a subtask of; nil for a top-level todo
+	StartDate   *time.Time `json:"start_date"` // deferred until; active listings hide the todo before then
+	Estimate    *int       `json:"estimate"`   // expected effort in minutes; nil when not estimated
 }
 
 type TodoService interface {
-	AddTodo(title string, dueDate *time.Time) (TodoItem, error)
-	AddTodoToProject(title string, projectID int64, dueDate *time.Time) (TodoItem, error)
-	AddTodoToCategory(title string, categoryID int64, dueDate *time.Time) (TodoItem, error)
-	GetAllTodos() []TodoItem
-	GetActiveTodos() []TodoItem
-	GetCompletedTodos() []TodoItem
-	GetTodosByProject(projectID int64) []TodoItem
-	GetTodosByCategory(categoryID int64) []TodoItem
-	GetUncategorizedTodos() []TodoItem
-	GetTodo(id string) (TodoItem, error)
-	CompleteTodo(id string) (TodoItem, error)
-	UnCompleteTodo(id string) (TodoItem, error)
-	SetDueDate(id string, dueDateStr time.Time) (TodoItem, error)
-	DeleteTodo(id string) (TodoItem, error)
-	TitleSearchTodo(query string, activeOnly bool) []TodoItem
-	AssignTodoToCategory(todoID string, categoryID int64) (TodoItem, error)
-	RemoveTodoFromCategory(todoID string) (TodoItem, error)
-
-	AddRecurrencePattern(pattern RecurrencePattern) (int64, error)
-	GetRecurrencePatternByID(id int64) (RecurrencePattern, error)
+	AddTodo(ctx context.Context, title string, dueDate *time.Time) (TodoItem, error)
+	AddTodoToProject(ctx context.Context, title string, projectID int64, dueDate *time.Time) (TodoItem, error)
+	AddTodoToCategory(ctx context.Context, title string, categoryID int64, dueDate *time.Time) (TodoItem, error)
+	// CreateTodo adds a todo with every field of n in one write, so a failure
+	// adds nothing. The project or parent it names must exist.
+	CreateTodo(ctx context.Context, n NewTodo) (TodoItem, error)
+	GetAllTodos(ctx context.Context) ([]TodoItem, error)
+	GetActiveTodos(ctx context.Context) ([]TodoItem, error)
+	GetCompletedTodos(ctx context.Context) ([]TodoItem, error)
+	GetTodosByProject(ctx context.Context, projectID int64) ([]TodoItem, error)
+	GetTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error)
+	GetUncategorizedTodos(ctx context.Context) ([]TodoItem, error)
+	GetTodo(ctx context.Context, id string) (TodoItem, error)
+	CompleteTodo(ctx context.Context, id string) (TodoItem, error)
+	// CompleteTodoWithNext is CompleteTodo that also returns the next
+	// occurrence it created, or nil when the todo does not recur
+	CompleteTodoWithNext(ctx context.Context, id string) (TodoItem, *TodoItem, error)
+	// CompleteTodoWithPolicy is CompleteTodoWithNext with a choice of what
+	// happens to open subtasks; CompleteTodoWithNext uses BlockOpenSubtasks
+	CompleteTodoWithPolicy(ctx context.Context, id string, policy CompletionPolicy) (TodoItem, *TodoItem, error)
+	UnCompleteTodo(ctx context.Context, id string) (TodoItem, error)
+	SetDueDate(ctx context.Context, id string, dueDateStr time.Time) (TodoItem, error)
+	SetPriority(ctx context.Context, id string, priority Priority) (TodoItem, error)
+	// UpdateTodo changes the fields set in patch and returns the updated todo.
+	// The project and category it names must exist.
+	UpdateTodo(ctx context.Context, id string, patch TodoPatch) (TodoItem, error)
+	// SetNotes replaces a todo's Markdown notes; empty notes clear them
+	SetNotes(ctx context.Context, id string, notes string) (TodoItem, error)
+	DeleteTodo(ctx context.Context, id string) (TodoItem, error)
+	TitleSearchTodo(ctx context.Context, query string, activeOnly bool) ([]TodoItem, error)
+	// SearchTodos is TitleSearchTodo that also matches the query in notes
+	SearchTodos(ctx context.Context, query string, activeOnly bool) ([]TodoItem, error)
+	// AddSubtask adds a todo under parentID in the parent's project and category
+	AddSubtask(ctx context.Context, parentID string, title string, dueDate *time.Time) (TodoItem, error)
+	// MoveTodo reparents a todo; a nil parentID makes it a top-level todo. A
+	// todo cannot be moved under itself or one of its subtasks.
+	MoveTodo(ctx context.Context, id string, parentID *string) (TodoItem, error)
+	// GetSubtasks returns the direct subtasks of parentID in the order added
+	GetSubtasks(ctx context.Context, parentID string) ([]TodoItem, error)
+	// GetSubtaskProgress returns the direct subtask counts of every todo that
+	// has subtasks, keyed by todo ID
+	GetSubtaskProgress(ctx context.Context) (map[string]SubtaskProgress, error)
+	// AddBlocker records that todoID cannot be done until blockerID is
+	// completed and returns every blocker of todoID. A dependency that would
+	// close a loop is rejected.
+	AddBlocker(ctx context.Context, todoID string, blockerID string) ([]TodoItem, error)
+	// RemoveBlocker drops blockerID from todoID's blockers and returns the
+	// blockers left
+	RemoveBlocker(ctx context.Context, todoID string, blockerID string) ([]TodoItem, error)
+	// GetBlockers returns every todo that blocks todoID, completed or not
+	GetBlockers(ctx context.Context, todoID string) ([]TodoItem, error)
+	// GetDependents returns every todo that blockerID blocks
+	GetDependents(ctx context.Context, blockerID string) ([]TodoItem, error)
+	// GetOpenBlockers returns the IDs of the open blockers of every blocked
+	// todo, keyed by todo ID
+	GetOpenBlockers(ctx context.Context) (map[string][]string, error)
+	// GetNextActions is GetActiveTodos without the todos that are blocked
+	GetNextActions(ctx context.Context) ([]TodoItem, error)
+	AssignTodoToCategory(ctx context.Context, todoID string, categoryID int64) (TodoItem, error)
+	RemoveTodoFromCategory(ctx context.Context, todoID string) (TodoItem, error)
+
+	AddRecurrencePattern(ctx context.Context, pattern RecurrencePattern) (int64, error)
+	GetRecurrencePatternByID(ctx context.Context, id int64) (RecurrencePattern, error)
+	// ListRecurrencePatterns returns every pattern, oldest first
+	ListRecurrencePatterns(ctx context.Context) ([]RecurrencePattern, error)
+	// GetRecurrencePatternsForTodo returns the patterns of the series todoID
+	// belongs to, oldest first; the last one is in effect
+	GetRecurrencePatternsForTodo(ctx context.Context, todoID string) ([]RecurrencePattern, error)
+	// UpdateRecurrencePattern replaces the schedule of the pattern with
+	// pattern.ID. Its todo and paused state are kept.
+	UpdateRecurrencePattern(ctx context.Context, pattern RecurrencePattern) (RecurrencePattern, error)
+	DeleteRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, error)
+	PauseRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, error)
+	// ResumeRecurrencePattern also creates the series' next occurrence when
+	// its latest todo was completed while paused, skipping the dates missed
+	ResumeRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, *TodoItem, error)
+}
+
+// todoColumns are the todos columns scanTodo reads, in order. Every query
+// that returns whole todos selects them, so a new field is added in one place.
+const todoColumns = "id, title, completed_at, due_date, created_date, reference_id, project_id, category_id, priority, notes, parent_id, start_date, due_all_day, estimate_minutes"
+
+// scanTodo scans a row selecting todoColumns
+func scanTodo(row interface {
+	Scan(dest ...interface{}) error
+}) (TodoItem, error) {
+	var item TodoItem
+	err := row.Scan(&item.ID, &item.Title, &item.CompletedAt, &item.DueDate, &item.CreatedDate, &item.ReferenceID, &item.ProjectID, &item.CategoryID, &item.Priority, &item.Notes, &item.ParentID, &item.StartDate, &item.DueAllDay, &item.Estimate)
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+// queryTodos runs a query selecting todoColumns
+func queryTodos(ctx context.Context, db DBTX, query string, args ...interface{}) ([]TodoItem, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TodoItem
+	for rows.Next() {
+		item, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
 }
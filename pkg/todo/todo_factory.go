@@ -3,55 +3,93 @@ package todo
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 )
 
 var ErrUnknownStorageType = errors.New("unknown storage type")
 
 type Config struct {
-	StorageType string `json:"storage_type"`
-	SQLDBPath    string `json:"sqldb_path"`
-	HTTPPort      string `json:"http_port"`
-}
+	StorageType        string `json:"storage_type"`
+	SQLDBPath          string `json:"sqldb_path"`
+	HTTPPort           string `json:"http_port"`
+	DisableAutoMigrate bool   `json:"disable_auto_migrate"` // skip applying pending migrations on startup
 
-func NewTodoServiceFromConfig(cfg Config) (TodoService, error) {
-	switch cfg.StorageType {
+	// Connection pool tuning; zero leaves the database/sql default in place
+	MaxOpenConns    int           `json:"max_open_conns"`
+	MaxIdleConns    int           `json:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
 
-	case "mariadb":
-		db, err := sql.Open("mysql", cfg.SQLDBPath)
-		if err != nil {
-			return nil, err
-		}
-		return NewTodoMariaDB(db), nil
-	default:
-		return nil, ErrUnknownStorageType
-	}
+	// ToolTimeout bounds how long a single tool call may run, including its
+	// database queries; zero means no limit
+	ToolTimeout time.Duration `json:"tool_timeout"`
+
+	// SubtaskCompletion is what completing a todo does with its open
+	// subtasks when the caller does not choose
+	SubtaskCompletion CompletionPolicy `json:"subtask_completion"`
+
+	// Location is the timezone dates are read and shown in; dates are
+	// always stored in UTC. Nil means UTC; the server sets it to its local
+	// zone unless TIMEZONE names another.
+	Location *time.Location `json:"-"`
+
+	// ReminderInterval is how often the scheduler looks for reminders to
+	// send; zero turns reminders off
+	ReminderInterval time.Duration `json:"reminder_interval"`
 }
 
-func NewProjectServiceFromConfig(cfg Config) (ProjectService, error) {
+// OpenDatabase opens the SQL database for the configured storage type and
+// returns it with its migration dialect. No migrations are applied.
+func OpenDatabase(cfg Config) (*sql.DB, string, error) {
 	switch cfg.StorageType {
 
 	case "mariadb":
 		db, err := sql.Open("mysql", cfg.SQLDBPath)
 		if err != nil {
-			return nil, err
+			return nil, "", err
+		}
+		return db, DialectMariaDB, nil
+	case "postgres":
+		db, err := sql.Open("pgx", cfg.SQLDBPath)
+		if err != nil {
+			return nil, "", err
 		}
-		return NewProjectMariaDB(db), nil
+		return db, DialectPostgres, nil
+	case "sqlite", "sql":
+		db, err := OpenSQLite(cfg.SQLDBPath)
+		if err != nil {
+			return nil, "", err
+		}
+		return db, DialectSQLite, nil
 	default:
-		return nil, ErrUnknownStorageType
+		return nil, "", ErrUnknownStorageType
 	}
 }
 
-func NewCategoryServiceFromConfig(cfg Config) (CategoryService, error) {
-	switch cfg.StorageType {
+// openDB opens the configured database and brings its schema up to date
+// unless automatic migration is disabled
+func openDB(cfg Config) (*sql.DB, string, error) {
+	db, dialect, err := OpenDatabase(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.DisableAutoMigrate {
+		return db, dialect, nil
+	}
 
-	case "mariadb":
-		db, err := sql.Open("mysql", cfg.SQLDBPath)
-		if err != nil {
-			return nil, err
-		}
-		repo := NewCategoryMariaDB(db)
-		return NewCategoryService(repo), nil
-	default:
-		return nil, ErrUnknownStorageType
+	migrator, err := NewMigrator(db, dialect)
+	if err != nil {
+		db.Close()
+		return nil, "", err
 	}
-}
\ No newline at end of file
+	applied, err := migrator.Up()
+	if err != nil {
+		db.Close()
+		return nil, "", fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	for _, m := range applied {
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+	}
+	return db, dialect, nil
+}
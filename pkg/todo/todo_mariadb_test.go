@@ -1,6 +1,7 @@
 package todo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -20,19 +21,23 @@ func TestMain(m *testing.M) {
 		fmt.Printf("Failed to connect to test database: %v\n", err)
 		os.Exit(1)
 	}
+
+	// The MariaDB tests need a running server (see scripts/test-mariadb.sh);
+	// skip them rather than failing the whole package when none is available
+	if err := db.Ping(); err != nil {
+		fmt.Printf("Skipping MariaDB tests, test database unavailable: %v\n", err)
+		db.Close()
+		os.Exit(m.Run())
+	}
 	mariadbTestDB = db
 
-	// Create recurrence_patterns table for tests
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS recurrence_patterns (
-		id INT AUTO_INCREMENT PRIMARY KEY,
-		todo_id VARCHAR(255) NOT NULL,
-		frequency VARCHAR(50) NOT NULL,
-		` + "`interval`" + ` INT NOT NULL,
-		until DATETIME,
-		count INT
-	)`)
+	// Bring the test database schema up to date
+	migrator, err := NewMigrator(db, DialectMariaDB)
+	if err == nil {
+		_, err = migrator.Up()
+	}
 	if err != nil {
-		fmt.Printf("Failed to create recurrence_patterns table: %v\n", err)
+		fmt.Printf("Failed to migrate test database: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -46,11 +51,20 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// requireMariaDB skips the calling test when no MariaDB test server is reachable
+func requireMariaDB(t *testing.T) {
+	t.Helper()
+	if mariadbTestDB == nil {
+		t.Skip("MariaDB test database unavailable")
+	}
+}
+
 func TestMariaDB_AddTodo(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Test adding todo without due date
-	todo, err := svc.AddTodo("Test todo", nil)
+	todo, err := svc.AddTodo(context.Background(), "Test todo", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
@@ -66,7 +80,7 @@ func TestMariaDB_AddTodo(t *testing.T) {
 
 	// Test adding todo with due date
 	dueDate := time.Now().Add(24 * time.Hour)
-	todoWithDate, err := svc.AddTodo("Dated todo", &dueDate)
+	todoWithDate, err := svc.AddTodo(context.Background(), "Dated todo", &dueDate)
 	if err != nil {
 		t.Fatalf("AddTodo with due date failed: %v", err)
 	}
@@ -75,23 +89,24 @@ func TestMariaDB_AddTodo(t *testing.T) {
 	}
 
 	// Test empty title
-	_, err = svc.AddTodo("", nil)
+	_, err = svc.AddTodo(context.Background(), "", nil)
 	if err == nil {
 		t.Error("Expected error for empty title")
 	}
 }
 
 func TestMariaDB_CompleteUncomplete(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Add test todo
-	todo, err := svc.AddTodo("Complete test", nil)
+	todo, err := svc.AddTodo(context.Background(), "Complete test", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
 
 	// Complete it
-	completed, err := svc.CompleteTodo(todo.ID)
+	completed, err := svc.CompleteTodo(context.Background(), todo.ID)
 	if err != nil {
 		t.Fatalf("CompleteTodo failed: %v", err)
 	}
@@ -105,7 +120,7 @@ func TestMariaDB_CompleteUncomplete(t *testing.T) {
 	}
 
 	// Uncomplete it
-	uncompleted, err := svc.UnCompleteTodo(todo.ID)
+	uncompleted, err := svc.UnCompleteTodo(context.Background(), todo.ID)
 	if err != nil {
 		t.Fatalf("UnCompleteTodo failed: %v", err)
 	}
@@ -115,17 +130,18 @@ func TestMariaDB_CompleteUncomplete(t *testing.T) {
 }
 
 func TestMariaDB_SetDueDate(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Add test todo
-	todo, err := svc.AddTodo("Due date test", nil)
+	todo, err := svc.AddTodo(context.Background(), "Due date test", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
 
 	// Set due date
 	newDate := time.Now().Add(48 * time.Hour)
-	updated, err := svc.SetDueDate(todo.ID, newDate)
+	updated, err := svc.SetDueDate(context.Background(), todo.ID, newDate)
 	if err != nil {
 		t.Fatalf("SetDueDate failed: %v", err)
 	}
@@ -137,30 +153,37 @@ func TestMariaDB_SetDueDate(t *testing.T) {
 }
 
 func TestMariaDB_GetOperations(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Add test todos
-	_, err := svc.AddTodo("Active 1", nil)
+	_, err := svc.AddTodo(context.Background(), "Active 1", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
-	completed, err := svc.AddTodo("Completed 1", nil)
+	completed, err := svc.AddTodo(context.Background(), "Completed 1", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
-	_, err = svc.CompleteTodo(completed.ID)
+	_, err = svc.CompleteTodo(context.Background(), completed.ID)
 	if err != nil {
 		t.Fatalf("CompleteTodo failed: %v", err)
 	}
 
 	// Test GetAllTodos
-	all := svc.GetAllTodos()
+	all, err := svc.GetAllTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllTodos failed: %v", err)
+	}
 	if len(all) < 2 {
 		t.Errorf("Expected at least 2 todos, got %d", len(all))
 	}
 
 	// Test GetActiveTodos
-	active := svc.GetActiveTodos()
+	active, err := svc.GetActiveTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetActiveTodos failed: %v", err)
+	}
 	if len(active) == 0 {
 		t.Error("Expected active todos")
 	}
@@ -171,7 +194,10 @@ func TestMariaDB_GetOperations(t *testing.T) {
 	}
 
 	// Test GetCompletedTodos
-	completedTodos := svc.GetCompletedTodos()
+	completedTodos, err := svc.GetCompletedTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetCompletedTodos failed: %v", err)
+	}
 	if len(completedTodos) == 0 {
 		t.Error("Expected completed todos")
 	}
@@ -182,7 +208,7 @@ func TestMariaDB_GetOperations(t *testing.T) {
 	}
 
 	// Test GetTodo
-	fetched, err := svc.GetTodo(completed.ID)
+	fetched, err := svc.GetTodo(context.Background(), completed.ID)
 	if err != nil {
 		t.Fatalf("GetTodo failed: %v", err)
 	}
@@ -192,16 +218,17 @@ func TestMariaDB_GetOperations(t *testing.T) {
 }
 
 func TestMariaDB_DeleteTodo(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Add test todo
-	todo, err := svc.AddTodo("Delete test", nil)
+	todo, err := svc.AddTodo(context.Background(), "Delete test", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
 
 	// Delete it
-	deleted, err := svc.DeleteTodo(todo.ID)
+	deleted, err := svc.DeleteTodo(context.Background(), todo.ID)
 	if err != nil {
 		t.Fatalf("DeleteTodo failed: %v", err)
 	}
@@ -210,14 +237,15 @@ func TestMariaDB_DeleteTodo(t *testing.T) {
 	}
 
 	// Verify it's gone
-	_, err = svc.GetTodo(todo.ID)
+	_, err = svc.GetTodo(context.Background(), todo.ID)
 	if err == nil {
 		t.Error("Expected error when fetching deleted todo")
 	}
 }
 
 func TestMariaDB_TitleSearch(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Setup test data
 	testTodos := []struct {
@@ -237,7 +265,7 @@ func TestMariaDB_TitleSearch(t *testing.T) {
 	// Add test todos
 	var todoIDs []string
 	for _, td := range testTodos {
-		todo, err := svc.AddTodo(td.title, nil)
+		todo, err := svc.AddTodo(context.Background(), td.title, nil)
 		if err != nil {
 			if td.title == "" {
 				continue // Expected to fail for empty title
@@ -246,7 +274,7 @@ func TestMariaDB_TitleSearch(t *testing.T) {
 		}
 		todoIDs = append(todoIDs, todo.ID)
 		if td.completed {
-			_, err = svc.CompleteTodo(todo.ID)
+			_, err = svc.CompleteTodo(context.Background(), todo.ID)
 			if err != nil {
 				t.Fatalf("CompleteTodo failed: %v", err)
 			}
@@ -330,7 +358,10 @@ func TestMariaDB_TitleSearch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results := svc.TitleSearchTodo(tt.query, tt.activeOnly)
+			results, err := svc.TitleSearchTodo(context.Background(), tt.query, tt.activeOnly)
+			if err != nil {
+				t.Fatalf("TitleSearchTodo failed: %v", err)
+			}
 			
 			if len(results) < tt.expectedMin {
 				t.Errorf("Expected at least %d results, got %d", tt.expectedMin, len(results))
@@ -345,10 +376,11 @@ func TestMariaDB_TitleSearch(t *testing.T) {
 	}
 }
 func TestMariaDB_RecurrencePattern(t *testing.T) {
-	svc := NewTodoMariaDB(mariadbTestDB)
+	requireMariaDB(t)
+	svc := NewTodoMariaDB(mariadbTestDB, nil)
 
 	// Add a test todo
-	todo, err := svc.AddTodo("Recurring todo", nil)
+	todo, err := svc.AddTodo(context.Background(), "Recurring todo", nil)
 	if err != nil {
 		t.Fatalf("AddTodo failed: %v", err)
 	}
@@ -365,7 +397,7 @@ func TestMariaDB_RecurrencePattern(t *testing.T) {
 	}
 
 	// Add recurrence pattern
-	patternID, err := svc.AddRecurrencePattern(pattern)
+	patternID, err := svc.AddRecurrencePattern(context.Background(), pattern)
 	if err != nil {
 		t.Fatalf("AddRecurrencePattern failed: %v", err)
 	}
@@ -374,7 +406,7 @@ func TestMariaDB_RecurrencePattern(t *testing.T) {
 	}
 
 	// Retrieve recurrence pattern
-	retrieved, err := svc.GetRecurrencePatternByID(patternID)
+	retrieved, err := svc.GetRecurrencePatternByID(context.Background(), patternID)
 	if err != nil {
 		t.Fatalf("GetRecurrencePatternByID failed: %v", err)
 	}
@@ -0,0 +1,800 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewTodoMemory creates a new in-memory implementation of TodoService.
+// Recurring todos are expanded in loc; nil means UTC.
+func NewTodoMemory(store *MemoryStore, loc *time.Location) TodoService {
+	return &todo_memory{store: store, loc: loc}
+}
+
+type todo_memory struct {
+	store *MemoryStore
+	loc   *time.Location // timezone recurrences are expanded in
+}
+
+func (t *todo_memory) AddRecurrencePattern(ctx context.Context, pattern RecurrencePattern) (int64, error) {
+	pattern, err := normalizeRecurrencePattern(pattern)
+	if err != nil {
+		return 0, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	t.store.lastPatternID++
+	pattern.ID = t.store.lastPatternID
+	t.store.patterns[pattern.ID] = clonePattern(pattern)
+	return pattern.ID, nil
+}
+
+func (t *todo_memory) GetRecurrencePatternByID(ctx context.Context, id int64) (RecurrencePattern, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	pattern, ok := t.store.patterns[id]
+	if !ok {
+		return RecurrencePattern{}, recurrencePatternNotFound(id)
+	}
+	return clonePattern(pattern), nil
+}
+
+func (t *todo_memory) ListRecurrencePatterns(ctx context.Context) ([]RecurrencePattern, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return t.store.selectPatterns(func(pattern RecurrencePattern) bool { return true }), nil
+}
+
+func (t *todo_memory) GetRecurrencePatternsForTodo(ctx context.Context, todoID string) ([]RecurrencePattern, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	_, item, ok := t.lookup(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	// Patterns live on the first todo of a series
+	root, err := seriesRoot(item)
+	if err != nil {
+		return nil, err
+	}
+	return t.store.selectPatterns(func(pattern RecurrencePattern) bool {
+		return pattern.TodoID == strconv.FormatInt(root, 10)
+	}), nil
+}
+
+func (t *todo_memory) UpdateRecurrencePattern(ctx context.Context, pattern RecurrencePattern) (RecurrencePattern, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	current, ok := t.store.patterns[pattern.ID]
+	if !ok {
+		return RecurrencePattern{}, recurrencePatternNotFound(pattern.ID)
+	}
+	pattern.TodoID, pattern.Paused = current.TodoID, current.Paused
+	pattern, err := normalizeRecurrencePattern(pattern)
+	if err != nil {
+		return RecurrencePattern{}, err
+	}
+	t.store.patterns[pattern.ID] = clonePattern(pattern)
+	return pattern, nil
+}
+
+func (t *todo_memory) DeleteRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	pattern, ok := t.store.patterns[id]
+	if !ok {
+		return RecurrencePattern{}, recurrencePatternNotFound(id)
+	}
+	delete(t.store.patterns, id)
+	return clonePattern(pattern), nil
+}
+
+func (t *todo_memory) PauseRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	pattern, ok := t.store.patterns[id]
+	if !ok {
+		return RecurrencePattern{}, recurrencePatternNotFound(id)
+	}
+	pattern.Paused = true
+	t.store.patterns[id] = pattern
+	return clonePattern(pattern), nil
+}
+
+func (t *todo_memory) ResumeRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, *TodoItem, error) {
+	var pattern RecurrencePattern
+	var next *TodoItem
+	err := t.store.withTx(func(tx *MemoryStore) error {
+		var ok bool
+		pattern, ok = tx.patterns[id]
+		if !ok {
+			return recurrencePatternNotFound(id)
+		}
+		wasPaused := pattern.Paused
+		pattern.Paused = false
+		tx.patterns[id] = pattern
+		pattern = clonePattern(pattern)
+		if !wasPaused {
+			return nil
+		}
+		// A pattern on a todo ID that is not a number has no series to resume
+		root, err := strconv.ParseInt(pattern.TodoID, 10, 64)
+		if err != nil {
+			return nil
+		}
+
+		// A series whose latest todo was completed while paused would
+		// otherwise never continue
+		latest := tx.selectTodos(func(item TodoItem) bool {
+			return item.ID == pattern.TodoID || item.ReferenceID != nil && *item.ReferenceID == root
+		})
+		if len(latest) == 0 || latest[len(latest)-1].CompletedAt == nil {
+			return nil
+		}
+		last := latest[len(latest)-1]
+		next, err = tx.spawnNext(last, *last.CompletedAt, time.Now(), t.loc)
+		return err
+	})
+	if err != nil {
+		return RecurrencePattern{}, nil, err
+	}
+	return pattern, next, nil
+}
+
+func (t *todo_memory) AddTodo(ctx context.Context, title string, dueDate *time.Time) (TodoItem, error) {
+	return t.insert(TodoItem{Title: title, DueDate: dueDate})
+}
+
+func (t *todo_memory) AddTodoToProject(ctx context.Context, title string, projectID int64, dueDate *time.Time) (TodoItem, error) {
+	return t.insert(TodoItem{Title: title, DueDate: dueDate, ProjectID: &projectID})
+}
+
+func (t *todo_memory) CreateTodo(ctx context.Context, n NewTodo) (TodoItem, error) {
+	if err := n.validate(); err != nil {
+		return TodoItem{}, err
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	item := TodoItem{
+		Title:     n.Title,
+		DueDate:   n.dueDate(),
+		DueAllDay: n.DueDate != nil && n.DueAllDay,
+		ProjectID: clonePtr(n.ProjectID),
+		Priority:  n.Priority,
+		Notes:     n.Notes,
+	}
+	if n.ProjectID != nil {
+		if _, ok := t.store.projects[*n.ProjectID]; !ok {
+			return TodoItem{}, projectNotFound(*n.ProjectID)
+		}
+	}
+	if n.ParentID != nil {
+		key, parent, ok := t.lookup(*n.ParentID)
+		if !ok {
+			return TodoItem{}, todoNotFound(*n.ParentID)
+		}
+		item.ProjectID, item.CategoryID, item.ParentID = parent.ProjectID, parent.CategoryID, &key
+	}
+	t.store.lastTodoID++
+	item.ID = strconv.FormatInt(t.store.lastTodoID, 10)
+	item.CreatedDate = time.Now()
+	t.store.todos[t.store.lastTodoID] = cloneTodo(item)
+	return cloneTodo(item), nil
+}
+
+func (t *todo_memory) AddTodoToCategory(ctx context.Context, title string, categoryID int64, dueDate *time.Time) (TodoItem, error) {
+	return t.insert(TodoItem{Title: title, DueDate: dueDate, CategoryID: &categoryID})
+}
+
+// insert stores a new todo, assigning its ID and created date
+func (t *todo_memory) insert(item TodoItem) (TodoItem, error) {
+	if item.Title == "" {
+		return TodoItem{}, newValidationError("title", "title cannot be empty")
+	}
+	item.DueDate = utcTime(item.DueDate)
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	// Mirror the foreign key on todos.category_id
+	if item.CategoryID != nil {
+		if _, ok := t.store.categories[*item.CategoryID]; !ok {
+			return TodoItem{}, categoryNotFound(*item.CategoryID)
+		}
+	}
+
+	t.store.lastTodoID++
+	item.ID = strconv.FormatInt(t.store.lastTodoID, 10)
+	item.CreatedDate = time.Now()
+	t.store.todos[t.store.lastTodoID] = cloneTodo(item)
+	return cloneTodo(item), nil
+}
+
+// update applies fn to the stored todo and returns the result
+func (t *todo_memory) update(id string, fn func(item *TodoItem) error) (TodoItem, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, item, ok := t.lookup(id)
+	if !ok {
+		return TodoItem{}, todoNotFound(id)
+	}
+	if err := fn(&item); err != nil {
+		return TodoItem{}, err
+	}
+	t.store.todos[key] = item
+	return cloneTodo(item), nil
+}
+
+// lookup finds a todo by its string ID; the caller must hold the lock
+func (t *todo_memory) lookup(id string) (int64, TodoItem, bool) {
+	key, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, TodoItem{}, false
+	}
+	item, ok := t.store.todos[key]
+	return key, item, ok
+}
+
+func (t *todo_memory) SetDueDate(ctx context.Context, id string, dueDate time.Time) (TodoItem, error) {
+	dueDate = dueDate.UTC()
+	return t.update(id, func(item *TodoItem) error {
+		item.DueDate = &dueDate
+		item.DueAllDay = false
+		return nil
+	})
+}
+
+func (t *todo_memory) SetPriority(ctx context.Context, id string, priority Priority) (TodoItem, error) {
+	if err := validatePriority(priority); err != nil {
+		return TodoItem{}, err
+	}
+	return t.update(id, func(item *TodoItem) error {
+		item.Priority = priority
+		return nil
+	})
+}
+
+func (t *todo_memory) UpdateTodo(ctx context.Context, id string, patch TodoPatch) (TodoItem, error) {
+	if err := patch.validate(); err != nil {
+		return TodoItem{}, err
+	}
+	return t.update(id, func(item *TodoItem) error {
+		if patch.ProjectID != nil {
+			if _, ok := t.store.projects[*patch.ProjectID]; !ok {
+				return projectNotFound(*patch.ProjectID)
+			}
+		}
+		if patch.CategoryID != nil {
+			if _, ok := t.store.categories[*patch.CategoryID]; !ok {
+				return categoryNotFound(*patch.CategoryID)
+			}
+		}
+		patch.apply(item)
+		return nil
+	})
+}
+
+func (t *todo_memory) SetNotes(ctx context.Context, id string, notes string) (TodoItem, error) {
+	if err := validateNotes(notes); err != nil {
+		return TodoItem{}, err
+	}
+	return t.update(id, func(item *TodoItem) error {
+		item.Notes = notes
+		return nil
+	})
+}
+
+func (t *todo_memory) CompleteTodo(ctx context.Context, id string) (TodoItem, error) {
+	item, _, err := t.CompleteTodoWithNext(ctx, id)
+	return item, err
+}
+
+func (t *todo_memory) CompleteTodoWithNext(ctx context.Context, id string) (TodoItem, *TodoItem, error) {
+	return t.CompleteTodoWithPolicy(ctx, id, BlockOpenSubtasks)
+}
+
+func (t *todo_memory) CompleteTodoWithPolicy(ctx context.Context, id string, policy CompletionPolicy) (TodoItem, *TodoItem, error) {
+	completedAt := time.Now()
+	var item TodoItem
+	var next *TodoItem
+	// Complete and spawn the next occurrence together so a recurring todo is
+	// never left done without its successor
+	err := t.store.withTx(func(tx *MemoryStore) error {
+		key, current, ok := (&todo_memory{store: tx}).lookup(id)
+		if !ok {
+			return todoNotFound(id)
+		}
+		// Completing twice keeps the first completion time and never spawns a
+		// second successor
+		if current.CompletedAt != nil {
+			item = cloneTodo(current)
+			return nil
+		}
+		current.CompletedAt = &completedAt
+		tx.todos[key] = current
+		item = cloneTodo(current)
+		if err := tx.completeSubtasks(key, completedAt, policy, t.loc); err != nil {
+			return err
+		}
+
+		var err error
+		next, err = tx.spawnNext(item, completedAt, time.Time{}, t.loc)
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, nil, err
+	}
+	return item, next, nil
+}
+
+func (t *todo_memory) UnCompleteTodo(ctx context.Context, id string) (TodoItem, error) {
+	return t.update(id, func(item *TodoItem) error {
+		item.CompletedAt = nil
+		return nil
+	})
+}
+
+func (t *todo_memory) GetAllTodos(ctx context.Context) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return t.store.selectTodos(func(item TodoItem) bool { return true }), nil
+}
+
+func (t *todo_memory) GetTodo(ctx context.Context, id string) (TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	_, item, ok := t.lookup(id)
+	if !ok {
+		return TodoItem{}, todoNotFound(id)
+	}
+	return cloneTodo(item), nil
+}
+
+func (t *todo_memory) GetActiveTodos(ctx context.Context) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return byPriority(t.store.selectTodos(func(item TodoItem) bool { return item.CompletedAt == nil })), nil
+}
+
+func (t *todo_memory) GetCompletedTodos(ctx context.Context) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return t.store.selectTodos(func(item TodoItem) bool { return item.CompletedAt != nil }), nil
+}
+
+func (t *todo_memory) DeleteTodo(ctx context.Context, id string) (TodoItem, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, item, ok := t.lookup(id)
+	if !ok {
+		return TodoItem{}, todoNotFound(id)
+	}
+	// Mirror ON DELETE CASCADE on todos.parent_id, todo_tags.todo_id,
+	// both columns of todo_dependencies, time_entries.todo_id and
+	// reminders.todo_id
+	for _, deleted := range append(t.store.subtree(key, false), key) {
+		delete(t.store.todos, deleted)
+		delete(t.store.todoTags, deleted)
+		delete(t.store.blockers, deleted)
+		for _, blockerIDs := range t.store.blockers {
+			delete(blockerIDs, deleted)
+		}
+		for id, entry := range t.store.timeEntries {
+			if entry.TodoID == strconv.FormatInt(deleted, 10) {
+				delete(t.store.timeEntries, id)
+			}
+		}
+		for id, reminder := range t.store.reminders {
+			if reminder.TodoID == strconv.FormatInt(deleted, 10) {
+				delete(t.store.reminders, id)
+			}
+		}
+	}
+	return cloneTodo(item), nil
+}
+
+func (t *todo_memory) TitleSearchTodo(ctx context.Context, query string, activeOnly bool) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	// LIKE '%query%' is case-insensitive under the default collations
+	query = strings.ToLower(query)
+	return t.store.selectTodos(func(item TodoItem) bool {
+		if activeOnly && item.CompletedAt != nil {
+			return false
+		}
+		return strings.Contains(strings.ToLower(item.Title), query)
+	}), nil
+}
+
+func (t *todo_memory) SearchTodos(ctx context.Context, query string, activeOnly bool) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	query = strings.ToLower(query)
+	return t.store.selectTodos(func(item TodoItem) bool {
+		if activeOnly && item.CompletedAt != nil {
+			return false
+		}
+		return strings.Contains(strings.ToLower(item.Title), query) || strings.Contains(strings.ToLower(item.Notes), query)
+	}), nil
+}
+
+func (t *todo_memory) GetTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return byPriority(t.store.selectTodosNewestFirst(func(item TodoItem) bool {
+		return item.CategoryID != nil && *item.CategoryID == categoryID
+	})), nil
+}
+
+func (t *todo_memory) GetUncategorizedTodos(ctx context.Context) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return t.store.selectTodosNewestFirst(func(item TodoItem) bool { return item.CategoryID == nil }), nil
+}
+
+func (t *todo_memory) AssignTodoToCategory(ctx context.Context, todoID string, categoryID int64) (TodoItem, error) {
+	return t.update(todoID, func(item *TodoItem) error {
+		if _, ok := t.store.categories[categoryID]; !ok {
+			return categoryNotFound(categoryID)
+		}
+		item.CategoryID = &categoryID
+		return nil
+	})
+}
+
+func (t *todo_memory) RemoveTodoFromCategory(ctx context.Context, todoID string) (TodoItem, error) {
+	return t.update(todoID, func(item *TodoItem) error {
+		item.CategoryID = nil
+		return nil
+	})
+}
+
+func (t *todo_memory) GetTodosByProject(ctx context.Context, projectID int64) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return byPriority(t.store.selectTodosNewestFirst(func(item TodoItem) bool {
+		return item.ProjectID != nil && *item.ProjectID == projectID
+	})), nil
+}
+
+func (t *todo_memory) AddSubtask(ctx context.Context, parentID string, title string, dueDate *time.Time) (TodoItem, error) {
+	dueDate = utcTime(dueDate)
+	if title == "" {
+		return TodoItem{}, newValidationError("title", "title cannot be empty")
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, parent, ok := t.lookup(parentID)
+	if !ok {
+		return TodoItem{}, todoNotFound(parentID)
+	}
+	t.store.lastTodoID++
+	item := TodoItem{
+		ID:          strconv.FormatInt(t.store.lastTodoID, 10),
+		Title:       title,
+		DueDate:     dueDate,
+		CreatedDate: time.Now(),
+		ProjectID:   parent.ProjectID,
+		CategoryID:  parent.CategoryID,
+		ParentID:    &key,
+	}
+	t.store.todos[t.store.lastTodoID] = cloneTodo(item)
+	return cloneTodo(item), nil
+}
+
+func (t *todo_memory) MoveTodo(ctx context.Context, id string, parentID *string) (TodoItem, error) {
+	return t.update(id, func(item *TodoItem) error {
+		if parentID == nil {
+			item.ParentID = nil
+			return nil
+		}
+		key, _, ok := t.lookup(*parentID)
+		if !ok {
+			return todoNotFound(*parentID)
+		}
+		itemKey, _, _ := t.lookup(item.ID)
+		if err := checkNewParent(item.ID, key, t.store.subtree(itemKey, false)); err != nil {
+			return err
+		}
+		item.ParentID = &key
+		return nil
+	})
+}
+
+func (t *todo_memory) GetSubtasks(ctx context.Context, parentID string) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	key, _, ok := t.lookup(parentID)
+	if !ok {
+		return nil, todoNotFound(parentID)
+	}
+	return t.store.selectTodos(func(item TodoItem) bool {
+		return item.ParentID != nil && *item.ParentID == key
+	}), nil
+}
+
+func (t *todo_memory) GetSubtaskProgress(ctx context.Context) (map[string]SubtaskProgress, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	progress := make(map[string]SubtaskProgress)
+	for _, item := range t.store.todos {
+		if item.ParentID == nil {
+			continue
+		}
+		parentID := strconv.FormatInt(*item.ParentID, 10)
+		p := progress[parentID]
+		p.Total++
+		if item.CompletedAt != nil {
+			p.Done++
+		}
+		progress[parentID] = p
+	}
+	return progress, nil
+}
+
+func (t *todo_memory) AddBlocker(ctx context.Context, todoID string, blockerID string) ([]TodoItem, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, item, ok := t.lookup(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	blockerKey, blocker, ok := t.lookup(blockerID)
+	if !ok {
+		return nil, todoNotFound(blockerID)
+	}
+	if err := checkNewBlocker(item.ID, blocker.ID, t.store.blockerChain(blockerKey)); err != nil {
+		return nil, err
+	}
+	if t.store.blockers[key] == nil {
+		t.store.blockers[key] = make(map[int64]bool)
+	}
+	t.store.blockers[key][blockerKey] = true
+	return t.store.blockersOf(key), nil
+}
+
+func (t *todo_memory) RemoveBlocker(ctx context.Context, todoID string, blockerID string) ([]TodoItem, error) {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	key, _, ok := t.lookup(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	blockerKey, _, ok := t.lookup(blockerID)
+	if !ok {
+		return nil, todoNotFound(blockerID)
+	}
+	delete(t.store.blockers[key], blockerKey)
+	return t.store.blockersOf(key), nil
+}
+
+func (t *todo_memory) GetBlockers(ctx context.Context, todoID string) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	key, _, ok := t.lookup(todoID)
+	if !ok {
+		return nil, todoNotFound(todoID)
+	}
+	return t.store.blockersOf(key), nil
+}
+
+func (t *todo_memory) GetDependents(ctx context.Context, blockerID string) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	blockerKey, _, ok := t.lookup(blockerID)
+	if !ok {
+		return nil, todoNotFound(blockerID)
+	}
+	return t.store.selectTodos(func(item TodoItem) bool {
+		key, _ := strconv.ParseInt(item.ID, 10, 64)
+		return t.store.blockers[key][blockerKey]
+	}), nil
+}
+
+func (t *todo_memory) GetOpenBlockers(ctx context.Context) (map[string][]string, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	open := make(map[string][]string)
+	for key := range t.store.blockers {
+		for _, blocker := range t.store.blockersOf(key) {
+			if blocker.CompletedAt == nil {
+				todoID := strconv.FormatInt(key, 10)
+				open[todoID] = append(open[todoID], blocker.ID)
+			}
+		}
+	}
+	return open, nil
+}
+
+func (t *todo_memory) GetNextActions(ctx context.Context) ([]TodoItem, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+
+	return byPriority(t.store.selectTodos(func(item TodoItem) bool {
+		if item.CompletedAt != nil {
+			return false
+		}
+		key, _ := strconv.ParseInt(item.ID, 10, 64)
+		for _, blocker := range t.store.blockersOf(key) {
+			if blocker.CompletedAt == nil {
+				return false
+			}
+		}
+		return true
+	})), nil
+}
+
+// blockersOf returns the blockers of the todo stored under key in ID order;
+// the caller must hold the lock
+func (s *MemoryStore) blockersOf(key int64) []TodoItem {
+	return s.selectTodos(func(item TodoItem) bool {
+		blockerKey, _ := strconv.ParseInt(item.ID, 10, 64)
+		return s.blockers[key][blockerKey]
+	})
+}
+
+// blockerChain returns the keys of every todo the todo stored under key waits
+// on, directly or through other todos. The caller must hold the lock.
+func (s *MemoryStore) blockerChain(key int64) []int64 {
+	seen := make(map[int64]bool)
+	var chain []int64
+	pending := []int64{key}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for blockerKey := range s.blockers[current] {
+			if !seen[blockerKey] {
+				seen[blockerKey] = true
+				chain = append(chain, blockerKey)
+				pending = append(pending, blockerKey)
+			}
+		}
+	}
+	return chain
+}
+
+// subtree returns the keys of every todo below key, or only the open ones, in
+// ascending order. The caller must hold the lock.
+func (s *MemoryStore) subtree(key int64, openOnly bool) []int64 {
+	var keys []int64
+	parents := []int64{key}
+	for len(parents) > 0 {
+		var children []int64
+		for id, item := range s.todos {
+			if item.ParentID != nil && *item.ParentID == parents[0] {
+				children = append(children, id)
+			}
+		}
+		parents = append(parents[1:], children...)
+		for _, id := range children {
+			if !openOnly || s.todos[id].CompletedAt == nil {
+				keys = append(keys, id)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// completeSubtasks applies policy to the open subtasks below the todo stored
+// under key, which was just completed. The caller must hold the lock.
+func (s *MemoryStore) completeSubtasks(key int64, completedAt time.Time, policy CompletionPolicy, loc *time.Location) error {
+	open := s.subtree(key, true)
+	if len(open) == 0 {
+		return nil
+	}
+	if policy == BlockOpenSubtasks {
+		return openSubtasks(strconv.FormatInt(key, 10), len(open))
+	}
+	// Each subtask is completed on its own, so a recurring one carries on
+	// with its next occurrence
+	for _, id := range open {
+		item := s.todos[id]
+		item.CompletedAt = &completedAt
+		s.todos[id] = item
+		if _, err := s.spawnNext(cloneTodo(item), completedAt, time.Time{}, loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spawnNext creates the occurrence that follows item, which was just
+// completed, the same way the SQL backends do. The caller must hold the lock.
+func (s *MemoryStore) spawnNext(item TodoItem, completedAt time.Time, notBefore time.Time, loc *time.Location) (*TodoItem, error) {
+	root, err := seriesRoot(item)
+	if err != nil {
+		return nil, err
+	}
+
+	// The series follows the most recently added pattern on its first todo
+	var pattern RecurrencePattern
+	found := false
+	for id, candidate := range s.patterns {
+		if candidate.TodoID == strconv.FormatInt(root, 10) && (!found || id > pattern.ID) {
+			pattern, found = candidate, true
+		}
+	}
+	if !found || pattern.Paused {
+		return nil, nil
+	}
+
+	itemID, err := strconv.ParseInt(item.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	instances := 0
+	for id, other := range s.todos {
+		if other.ReferenceID == nil || *other.ReferenceID != root {
+			continue
+		}
+		// Reopening and completing an older occurrence must not fork the series
+		if id > itemID {
+			return nil, nil
+		}
+		instances++
+	}
+
+	// Occurrences are anchored on the first todo's due date; if it has been
+	// deleted, the series continues from item
+	start := item.DueDate
+	if first, ok := s.todos[root]; ok && item.ReferenceID != nil {
+		start = first.DueDate
+	}
+
+	// The first todo plus every occurrence spawned from it
+	next, ok, err := nextInstance(pattern, item, start, instances+1, completedAt, notBefore, loc)
+	if err != nil || !ok {
+		return nil, err
+	}
+	s.lastTodoID++
+	next.ID = strconv.FormatInt(s.lastTodoID, 10)
+	next.CreatedDate = time.Now()
+	s.todos[s.lastTodoID] = cloneTodo(next)
+
+	// The next occurrence gets the same tags and reminders
+	if tagIDs := s.todoTags[itemID]; len(tagIDs) > 0 {
+		s.todoTags[s.lastTodoID] = make(map[int64]bool, len(tagIDs))
+		for tagID := range tagIDs {
+			s.todoTags[s.lastTodoID][tagID] = true
+		}
+	}
+	for _, reminder := range s.remindersOf(item.ID) {
+		s.lastReminderID++
+		s.reminders[s.lastReminderID] = Reminder{ID: s.lastReminderID, TodoID: next.ID, Offset: reminder.Offset}
+	}
+	return &next, nil
+}
@@ -0,0 +1,223 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_TodoLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTodoMemory(NewMemoryStore(), nil)
+
+	_, err := svc.AddTodo(ctx, "", nil)
+	assert.Error(t, err)
+
+	due := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	first, err := svc.AddTodo(ctx, "Buy milk", &due)
+	require.NoError(t, err)
+	second, err := svc.AddTodo(ctx, "Walk dog", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1", first.ID)
+	assert.Equal(t, "2", second.ID)
+
+	// Stored values are copies, not the caller's pointers
+	due = due.Add(24 * time.Hour)
+	got, err := svc.GetTodo(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.DueDate.Day())
+
+	completed, err := svc.CompleteTodo(ctx, first.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, completed.CompletedAt)
+
+	active, err := svc.GetActiveTodos(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, second.ID, active[0].ID)
+
+	done, err := svc.GetCompletedTodos(ctx)
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, first.ID, done[0].ID)
+
+	_, err = svc.UnCompleteTodo(ctx, first.ID)
+	require.NoError(t, err)
+	active, err = svc.GetActiveTodos(ctx)
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	results, err := svc.TitleSearchTodo(ctx, "MILK", false)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, first.ID, results[0].ID)
+
+	deleted, err := svc.DeleteTodo(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Buy milk", deleted.Title)
+
+	// Missing todos report the same error as the SQL backends
+	_, err = svc.GetTodo(ctx, first.ID)
+	assert.ErrorIs(t, err, ErrTodoNotFound)
+	_, err = svc.SetDueDate(ctx, "not-a-number", due)
+	assert.ErrorIs(t, err, ErrTodoNotFound)
+
+	// IDs are never reused
+	third, err := svc.AddTodo(ctx, "Water plants", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "3", third.ID)
+}
+
+func TestMemory_RecurrencePattern(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTodoMemory(NewMemoryStore(), nil)
+
+	count := 5
+	id, err := svc.AddRecurrencePattern(ctx, RecurrencePattern{TodoID: "1", Frequency: "weekly", Interval: 2, Count: &count})
+	require.NoError(t, err)
+
+	pattern, err := svc.GetRecurrencePatternByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, id, pattern.ID)
+	assert.Equal(t, "weekly", pattern.Frequency)
+	assert.Equal(t, 2, pattern.Interval)
+	require.NotNil(t, pattern.Count)
+	assert.Equal(t, 5, *pattern.Count)
+	assert.Nil(t, pattern.Until)
+
+	_, err = svc.GetRecurrencePatternByID(ctx, id+1)
+	assert.ErrorIs(t, err, ErrRecurrencePatternNotFound)
+}
+
+func TestMemory_DeleteProjectDetachesActiveTodos(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage(nil)
+
+	project, err := storage.Projects.CreateProject(ctx, "Garden", nil)
+	require.NoError(t, err)
+	_, err = storage.Projects.CreateProject(ctx, "Garden", nil)
+	assert.Error(t, err, "project names are unique")
+
+	active, err := storage.Todos.AddTodoToProject(ctx, "Plant tomatoes", project.ID, nil)
+	require.NoError(t, err)
+	finished, err := storage.Todos.AddTodoToProject(ctx, "Buy seeds", project.ID, nil)
+	require.NoError(t, err)
+	_, err = storage.Todos.CompleteTodo(ctx, finished.ID)
+	require.NoError(t, err)
+
+	deleted, err := storage.Projects.DeleteProject(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Garden", deleted.Name)
+
+	_, err = storage.Projects.GetProject(ctx, project.ID)
+	assert.Error(t, err)
+
+	got, err := storage.Todos.GetTodo(ctx, active.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.ProjectID, "active todos are detached from a deleted project")
+
+	got, err = storage.Todos.GetTodo(ctx, finished.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.ProjectID, "completed todos keep their project")
+	assert.Equal(t, project.ID, *got.ProjectID)
+}
+
+func TestMemory_DeleteCategoryClearsTodos(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage(nil)
+
+	category, err := storage.Categories.CreateCategory(ctx, "Errands", nil, nil)
+	require.NoError(t, err)
+	_, err = storage.Categories.CreateCategory(ctx, "Errands", nil, nil)
+	assert.Error(t, err)
+
+	item, err := storage.Todos.AddTodoToCategory(ctx, "Post letter", category.ID, nil)
+	require.NoError(t, err)
+	_, err = storage.Todos.AddTodoToCategory(ctx, "No such category", category.ID+1, nil)
+	assert.Error(t, err)
+
+	todos, err := storage.Categories.GetTodosByCategory(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Len(t, todos, 1)
+
+	require.NoError(t, storage.Categories.DeleteCategory(ctx, category.ID))
+
+	got, err := storage.Todos.GetTodo(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.CategoryID)
+
+	uncategorized, err := storage.Categories.GetUncategorizedTodos(ctx)
+	require.NoError(t, err)
+	assert.Len(t, uncategorized, 1)
+}
+
+func TestMemory_WithTxRollsBack(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage(nil)
+	project, err := storage.Projects.CreateProject(ctx, "Garden", nil)
+	require.NoError(t, err)
+	item, err := storage.Todos.AddTodoToProject(ctx, "Mow lawn", project.ID, nil)
+	require.NoError(t, err)
+
+	errAbort := errors.New("abort")
+	err = storage.WithTx(ctx, func(tx Services) error {
+		if _, err := tx.Projects.DeleteProject(ctx, project.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Todos.AddTodo(ctx, "Never kept", nil); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	_, err = storage.Projects.GetProject(ctx, project.ID)
+	assert.NoError(t, err, "project delete should have been rolled back")
+	got, err := storage.Todos.GetTodo(ctx, item.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.ProjectID)
+	todos, err := storage.Todos.GetAllTodos(ctx)
+	require.NoError(t, err)
+	assert.Len(t, todos, 1)
+
+	err = storage.WithTx(ctx, func(tx Services) error {
+		_, err := tx.Todos.AddTodo(ctx, "Kept", nil)
+		return err
+	})
+	require.NoError(t, err)
+	todos, err = storage.Todos.GetAllTodos(ctx)
+	require.NoError(t, err)
+	assert.Len(t, todos, 2)
+}
+
+func TestMemory_ConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item, err := storage.Todos.AddTodo(ctx, "Task "+strconv.Itoa(i), nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = storage.Todos.CompleteTodo(ctx, item.ID)
+			assert.NoError(t, err)
+			_, err = storage.Todos.GetAllTodos(ctx)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	done, err := storage.Todos.GetCompletedTodos(ctx)
+	require.NoError(t, err)
+	assert.Len(t, done, 20)
+}
@@ -0,0 +1,976 @@
+package todo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// NewTodoSQLite creates a new SQLite implementation of TodoService. Recurring
+// todos are expanded in loc; nil means UTC.
+func NewTodoSQLite(db DBTX, loc *time.Location) TodoService {
+	return &todo_sqlite{db: db, loc: loc}
+}
+
+type todo_sqlite struct {
+	db  DBTX
+	loc *time.Location // timezone recurrences are expanded in
+}
+
+func (t *todo_sqlite) AddRecurrencePattern(ctx context.Context, pattern RecurrencePattern) (int64, error) {
+	pattern, err := normalizeRecurrencePattern(pattern)
+	if err != nil {
+		return 0, err
+	}
+	
+	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO recurrence_patterns (todo_id, frequency, `interval`, until, count, rrule) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	
+	res, err := stmt.ExecContext(ctx, pattern.TodoID, pattern.Frequency, pattern.Interval, pattern.Until, pattern.Count, nullableRRule(pattern))
+	if err != nil {
+		return 0, err
+	}
+	
+	return res.LastInsertId()
+}
+
+func (t *todo_sqlite) GetRecurrencePatternByID(ctx context.Context, id int64) (RecurrencePattern, error) {
+	pattern, err := scanRecurrencePattern(t.db.QueryRowContext(ctx, "SELECT id, todo_id, frequency, `interval`, until, count, rrule, paused FROM recurrence_patterns WHERE id = ?", id))
+	if err != nil {
+		return RecurrencePattern{}, orNotFound(err, recurrencePatternNotFound(id))
+	}
+	return pattern, nil
+}
+
+func (t *todo_sqlite) ListRecurrencePatterns(ctx context.Context) ([]RecurrencePattern, error) {
+	return t.queryRecurrencePatterns(ctx, "SELECT id, todo_id, frequency, `interval`, until, count, rrule, paused FROM recurrence_patterns ORDER BY id")
+}
+
+func (t *todo_sqlite) GetRecurrencePatternsForTodo(ctx context.Context, todoID string) ([]RecurrencePattern, error) {
+	item, err := t.GetTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	// Patterns live on the first todo of a series
+	root, err := seriesRoot(item)
+	if err != nil {
+		return nil, err
+	}
+	return t.queryRecurrencePatterns(ctx, "SELECT id, todo_id, frequency, `interval`, until, count, rrule, paused FROM recurrence_patterns WHERE todo_id = ? ORDER BY id", strconv.FormatInt(root, 10))
+}
+
+func (t *todo_sqlite) queryRecurrencePatterns(ctx context.Context, query string, args ...interface{}) ([]RecurrencePattern, error) {
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var patterns []RecurrencePattern
+	for rows.Next() {
+		pattern, err := scanRecurrencePattern(rows)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+func (t *todo_sqlite) UpdateRecurrencePattern(ctx context.Context, pattern RecurrencePattern) (RecurrencePattern, error) {
+	var updated RecurrencePattern
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		current, err := (&todo_sqlite{db: tx}).GetRecurrencePatternByID(ctx, pattern.ID)
+		if err != nil {
+			return err
+		}
+		pattern.TodoID, pattern.Paused = current.TodoID, current.Paused
+		updated, err = normalizeRecurrencePattern(pattern)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE recurrence_patterns SET frequency = ?, `interval` = ?, until = ?, count = ?, rrule = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+			updated.Frequency, updated.Interval, updated.Until, updated.Count, nullableRRule(updated), updated.ID)
+		return err
+	})
+	if err != nil {
+		return RecurrencePattern{}, err
+	}
+	return updated, nil
+}
+
+func (t *todo_sqlite) DeleteRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, error) {
+	var pattern RecurrencePattern
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		var err error
+		pattern, err = (&todo_sqlite{db: tx}).GetRecurrencePatternByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM recurrence_patterns WHERE id = ?", id)
+		return err
+	})
+	if err != nil {
+		return RecurrencePattern{}, err
+	}
+	return pattern, nil
+}
+
+func (t *todo_sqlite) PauseRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, error) {
+	var pattern RecurrencePattern
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		var err error
+		pattern, err = (&todo_sqlite{db: tx}).setPaused(ctx, id, true)
+		return err
+	})
+	if err != nil {
+		return RecurrencePattern{}, err
+	}
+	return pattern, nil
+}
+
+func (t *todo_sqlite) ResumeRecurrencePattern(ctx context.Context, id int64) (RecurrencePattern, *TodoItem, error) {
+	var pattern RecurrencePattern
+	var next *TodoItem
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		wasPaused, err := txTodos.GetRecurrencePatternByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		pattern, err = txTodos.setPaused(ctx, id, false)
+		if err != nil || !wasPaused.Paused {
+			return err
+		}
+		// A pattern on a todo ID that is not a number has no series to resume
+		root, err := strconv.ParseInt(pattern.TodoID, 10, 64)
+		if err != nil {
+			return nil
+		}
+
+		// A series whose latest todo was completed while paused would
+		// otherwise never continue
+		var latestID int64
+		err = tx.QueryRowContext(ctx, "SELECT id FROM todos WHERE id = ? OR reference_id = ? ORDER BY id DESC LIMIT 1", root, root).Scan(&latestID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		latest, err := txTodos.GetTodo(ctx, strconv.FormatInt(latestID, 10))
+		if err != nil || latest.CompletedAt == nil {
+			return err
+		}
+		next, err = txTodos.spawnNext(ctx, latest, *latest.CompletedAt, time.Now())
+		return err
+	})
+	if err != nil {
+		return RecurrencePattern{}, nil, err
+	}
+	return pattern, next, nil
+}
+
+// setPaused pauses or resumes a pattern and returns it as stored
+func (t *todo_sqlite) setPaused(ctx context.Context, id int64, paused bool) (RecurrencePattern, error) {
+	_, err := t.db.ExecContext(ctx, "UPDATE recurrence_patterns SET paused = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", paused, id)
+	if err != nil {
+		return RecurrencePattern{}, err
+	}
+	return t.GetRecurrencePatternByID(ctx, id)
+}
+
+func (t *todo_sqlite) AddTodo(ctx context.Context, title string, dueDate *time.Time) (TodoItem, error) {
+	dueDate = utcTime(dueDate)
+	if title == "" {
+		return TodoItem{}, newValidationError("title", "title cannot be empty")
+	}
+	
+	// Use current timestamp for created_date
+	createdDate := time.Now()
+	
+	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO todos (title, completed_at, due_date, created_date, reference_id, project_id) VALUES (?, NULL, ?, ?, NULL, NULL)")
+	if err != nil {
+		return TodoItem{}, err
+	}
+	defer stmt.Close()
+	
+	res, err := stmt.ExecContext(ctx, title, dueDate, createdDate)
+	if err != nil {
+		return TodoItem{}, err
+	}
+	
+	id, err := res.LastInsertId()
+	if err != nil {
+		return TodoItem{}, err
+	}
+	
+	idStr := strconv.FormatInt(id, 10)
+	newItem := TodoItem{
+		ID:          idStr,
+		Title:       title,
+		CompletedAt: nil,
+		DueDate:     dueDate,
+		CreatedDate: createdDate,
+		ReferenceID: nil,
+		ProjectID:   nil,
+	}
+	return newItem, nil
+}
+
+func (t *todo_sqlite) AddTodoToProject(ctx context.Context, title string, projectID int64, dueDate *time.Time) (TodoItem, error) {
+	dueDate = utcTime(dueDate)
+	if title == "" {
+		return TodoItem{}, newValidationError("title", "title cannot be empty")
+	}
+	
+	// Use current timestamp for created_date
+	createdDate := time.Now()
+	
+	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO todos (title, completed_at, due_date, created_date, reference_id, project_id) VALUES (?, NULL, ?, ?, NULL, ?)")
+	if err != nil {
+		return TodoItem{}, err
+	}
+	defer stmt.Close()
+	
+	res, err := stmt.ExecContext(ctx, title, dueDate, createdDate, projectID)
+	if err != nil {
+		return TodoItem{}, err
+	}
+	
+	id, err := res.LastInsertId()
+	if err != nil {
+		return TodoItem{}, err
+	}
+	
+	idStr := strconv.FormatInt(id, 10)
+	projectIDPtr := &projectID
+	newItem := TodoItem{
+		ID:          idStr,
+		Title:       title,
+		CompletedAt: nil,
+		DueDate:     dueDate,
+		CreatedDate: createdDate,
+		ReferenceID: nil,
+		ProjectID:   projectIDPtr,
+	}
+	return newItem, nil
+}
+
+func (t *todo_sqlite) SetDueDate(ctx context.Context, id string, dueDate time.Time) (TodoItem, error) {
+	dueDate = dueDate.UTC()
+	var item TodoItem
+	// Update and re-read in one transaction so the returned todo is the one written
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE todos SET due_date = ?, due_all_day = FALSE WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		_, err = stmt.ExecContext(ctx, dueDate, id)
+		if err != nil {
+			return err
+		}
+		item, err = scanTodo(tx.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+		return orNotFound(err, todoNotFound(id))
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) SetPriority(ctx context.Context, id string, priority Priority) (TodoItem, error) {
+	if err := validatePriority(priority); err != nil {
+		return TodoItem{}, err
+	}
+	var item TodoItem
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, "UPDATE todos SET priority = ? WHERE id = ?", priority, id)
+		if err != nil {
+			return err
+		}
+		item, err = scanTodo(tx.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+		return orNotFound(err, todoNotFound(id))
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) UpdateTodo(ctx context.Context, id string, patch TodoPatch) (TodoItem, error) {
+	if err := patch.validate(); err != nil {
+		return TodoItem{}, err
+	}
+	var item TodoItem
+	// Check, update and re-read in one transaction so the returned todo is the one written
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		var found int
+		if patch.ProjectID != nil {
+			err := tx.QueryRowContext(ctx, "SELECT 1 FROM projects WHERE id = ?", *patch.ProjectID).Scan(&found)
+			if err != nil {
+				return orNotFound(err, projectNotFound(*patch.ProjectID))
+			}
+		}
+		if patch.CategoryID != nil {
+			err := tx.QueryRowContext(ctx, "SELECT 1 FROM categories WHERE id = ?", *patch.CategoryID).Scan(&found)
+			if err != nil {
+				return orNotFound(err, categoryNotFound(*patch.CategoryID))
+			}
+		}
+		
+		columns, args := patch.columns()
+		if len(columns) > 0 {
+			set := make([]string, len(columns))
+			for i, column := range columns {
+				set[i] = column + " = ?"
+			}
+			_, err := tx.ExecContext(ctx, "UPDATE todos SET "+strings.Join(set, ", ")+" WHERE id = ?", append(args, id)...)
+			if err != nil {
+				return err
+			}
+		}
+		
+		var err error
+		item, err = (&todo_sqlite{db: tx}).GetTodo(ctx, id)
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) SetNotes(ctx context.Context, id string, notes string) (TodoItem, error) {
+	if err := validateNotes(notes); err != nil {
+		return TodoItem{}, err
+	}
+	var item TodoItem
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, "UPDATE todos SET notes = ? WHERE id = ?", notes, id)
+		if err != nil {
+			return err
+		}
+		item, err = scanTodo(tx.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+		return orNotFound(err, todoNotFound(id))
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) CompleteTodo(ctx context.Context, id string) (TodoItem, error) {
+	item, _, err := t.CompleteTodoWithNext(ctx, id)
+	return item, err
+}
+
+func (t *todo_sqlite) CompleteTodoWithNext(ctx context.Context, id string) (TodoItem, *TodoItem, error) {
+	return t.CompleteTodoWithPolicy(ctx, id, BlockOpenSubtasks)
+}
+
+func (t *todo_sqlite) CompleteTodoWithPolicy(ctx context.Context, id string, policy CompletionPolicy) (TodoItem, *TodoItem, error) {
+	completedAt := time.Now()
+	var item TodoItem
+	var next *TodoItem
+	// Complete, re-read and spawn the next occurrence in one transaction so a
+	// recurring todo is never left done without its successor
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		var completed bool
+		var err error
+		item, next, completed, err = txTodos.complete(ctx, id, completedAt)
+		if err != nil || !completed {
+			return err
+		}
+		return txTodos.completeSubtasks(ctx, item, completedAt, policy)
+	})
+	if err != nil {
+		return TodoItem{}, nil, err
+	}
+	return item, next, nil
+}
+
+// complete marks the todo id done at completedAt and spawns its next
+// occurrence. Only an open todo is completed, so completing twice keeps the
+// first completion time and never spawns a second successor; completed
+// reports whether this call completed it. t.db must be a transaction.
+func (t *todo_sqlite) complete(ctx context.Context, id string, completedAt time.Time) (TodoItem, *TodoItem, bool, error) {
+	res, err := t.db.ExecContext(ctx, "UPDATE todos SET completed_at = ? WHERE id = ? AND completed_at IS NULL", completedAt, id)
+	if err != nil {
+		return TodoItem{}, nil, false, err
+	}
+	completed, err := res.RowsAffected()
+	if err != nil {
+		return TodoItem{}, nil, false, err
+	}
+	item, err := scanTodo(t.db.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+	if err != nil {
+		return TodoItem{}, nil, false, orNotFound(err, todoNotFound(id))
+	}
+	if completed == 0 {
+		return item, nil, false, nil
+	}
+	next, err := t.spawnNext(ctx, item, completedAt, time.Time{})
+	if err != nil {
+		return TodoItem{}, nil, false, err
+	}
+	return item, next, true, nil
+}
+
+func (t *todo_sqlite) UnCompleteTodo(ctx context.Context, id string) (TodoItem, error) {
+	var item TodoItem
+	// Update and re-read in one transaction so the returned todo is the one written
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE todos SET completed_at = NULL WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		_, err = stmt.ExecContext(ctx, id)
+		if err != nil {
+			return err
+		}
+		item, err = scanTodo(tx.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+		return orNotFound(err, todoNotFound(id))
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) GetAllTodos(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos")
+}
+
+func (t *todo_sqlite) GetTodo(ctx context.Context, id string) (TodoItem, error) {
+	item, err := scanTodo(t.db.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?", id))
+	if err != nil {
+		return TodoItem{}, orNotFound(err, todoNotFound(id))
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) GetActiveTodos(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE completed_at IS NULL ORDER BY priority DESC, due_date IS NULL, due_date, id")
+}
+
+func (t *todo_sqlite) GetCompletedTodos(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE completed_at IS NOT NULL")
+}
+
+func (t *todo_sqlite) DeleteTodo(ctx context.Context, id string) (TodoItem, error) {
+	var item TodoItem
+	// Read and delete in one transaction so the returned todo is the one deleted
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "SELECT "+todoColumns+" FROM todos WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		item, err = scanTodo(stmt.QueryRowContext(ctx, id))
+		if err != nil {
+			return orNotFound(err, todoNotFound(id))
+		}
+		stmt, err = tx.PrepareContext(ctx, "DELETE FROM todos WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		_, err = stmt.ExecContext(ctx, id)
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) TitleSearchTodo(ctx context.Context, query string, activeOnly bool) ([]TodoItem, error) {
+	var queryStr string
+	if activeOnly {
+		queryStr = "SELECT " + todoColumns + " FROM todos WHERE title LIKE ? AND completed_at IS NULL"
+	} else {
+		queryStr = "SELECT " + todoColumns + " FROM todos WHERE title LIKE ?"
+	}
+
+	return queryTodos(ctx, t.db, queryStr, "%" + query + "%")
+}
+
+func (t *todo_sqlite) SearchTodos(ctx context.Context, query string, activeOnly bool) ([]TodoItem, error) {
+	queryStr := "SELECT " + todoColumns + " FROM todos WHERE (title LIKE ? OR notes LIKE ?)"
+	if activeOnly {
+		queryStr += " AND completed_at IS NULL"
+	}
+	return queryTodos(ctx, t.db, queryStr, "%" + query + "%", "%" + query + "%")
+}
+
+func (t *todo_sqlite) CreateTodo(ctx context.Context, n NewTodo) (TodoItem, error) {
+	if err := n.validate(); err != nil {
+		return TodoItem{}, err
+	}
+	var item TodoItem
+	// Check the project or parent, insert and re-read in one transaction
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		projectID := n.ProjectID
+		var categoryID, parentKey *int64
+		if n.ProjectID != nil {
+			var found int
+			err := tx.QueryRowContext(ctx, "SELECT 1 FROM projects WHERE id = ?", *n.ProjectID).Scan(&found)
+			if err != nil {
+				return orNotFound(err, projectNotFound(*n.ProjectID))
+			}
+		}
+		if n.ParentID != nil {
+			parent, err := txTodos.GetTodo(ctx, *n.ParentID)
+			if err != nil {
+				return err
+			}
+			key, err := parseTodoID(parent.ID)
+			if err != nil {
+				return err
+			}
+			projectID, categoryID, parentKey = parent.ProjectID, parent.CategoryID, &key
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO todos (title, due_date, due_all_day, created_date, project_id, category_id, parent_id, priority, notes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			n.Title, n.dueDate(), n.DueDate != nil && n.DueAllDay, time.Now(), projectID, categoryID, parentKey, n.Priority, n.Notes)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		item, err = txTodos.GetTodo(ctx, strconv.FormatInt(id, 10))
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) AddTodoToCategory(ctx context.Context, title string, categoryID int64, dueDate *time.Time) (TodoItem, error) {
+	dueDate = utcTime(dueDate)
+	if title == "" {
+		return TodoItem{}, newValidationError("title", "title cannot be empty")
+	}
+	
+	// Use current timestamp for created_date
+	createdDate := time.Now()
+	
+	var id int64
+	// Check the category and insert in one transaction, since SQLite has no
+	// foreign key on category_id to do it for us
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		if err := categoryExists(ctx, tx, categoryID); err != nil {
+			return err
+		}
+		
+		stmt, err := tx.PrepareContext(ctx, "INSERT INTO todos (title, completed_at, due_date, created_date, reference_id, project_id, category_id) VALUES (?, NULL, ?, ?, NULL, NULL, ?)")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		res, err := stmt.ExecContext(ctx, title, dueDate, createdDate, categoryID)
+		if err != nil {
+			return err
+		}
+		
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	
+	idStr := strconv.FormatInt(id, 10)
+	categoryIDPtr := &categoryID
+	newItem := TodoItem{
+		ID:          idStr,
+		Title:       title,
+		CompletedAt: nil,
+		DueDate:     dueDate,
+		CreatedDate: createdDate,
+		ReferenceID: nil,
+		ProjectID:   nil,
+		CategoryID:  categoryIDPtr,
+	}
+	return newItem, nil
+}
+
+func (t *todo_sqlite) GetTodosByCategory(ctx context.Context, categoryID int64) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE category_id = ? ORDER BY priority DESC, due_date IS NULL, due_date, created_date DESC", categoryID)
+}
+
+func (t *todo_sqlite) GetUncategorizedTodos(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE category_id IS NULL ORDER BY created_date DESC")
+}
+
+func (t *todo_sqlite) AssignTodoToCategory(ctx context.Context, todoID string, categoryID int64) (TodoItem, error) {
+	var item TodoItem
+	// Update and re-read in one transaction so the returned todo is the one written
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		if err := categoryExists(ctx, tx, categoryID); err != nil {
+			return err
+		}
+		
+		stmt, err := tx.PrepareContext(ctx, "UPDATE todos SET category_id = ? WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		_, err = stmt.ExecContext(ctx, categoryID, todoID)
+		if err != nil {
+			return err
+		}
+		
+		// Return the updated todo
+		item, err = (&todo_sqlite{db: tx}).GetTodo(ctx, todoID)
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) RemoveTodoFromCategory(ctx context.Context, todoID string) (TodoItem, error) {
+	var item TodoItem
+	// Update and re-read in one transaction so the returned todo is the one written
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, "UPDATE todos SET category_id = NULL WHERE id = ?")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		
+		_, err = stmt.ExecContext(ctx, todoID)
+		if err != nil {
+			return err
+		}
+		
+		// Return the updated todo
+		item, err = (&todo_sqlite{db: tx}).GetTodo(ctx, todoID)
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) GetTodosByProject(ctx context.Context, projectID int64) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE project_id = ? ORDER BY priority DESC, due_date IS NULL, due_date, created_date DESC", projectID)
+}
+
+func (t *todo_sqlite) AddSubtask(ctx context.Context, parentID string, title string, dueDate *time.Time) (TodoItem, error) {
+	dueDate = utcTime(dueDate)
+	if title == "" {
+		return TodoItem{}, newValidationError("title", "title cannot be empty")
+	}
+	var item TodoItem
+	// Read the parent and insert in one transaction so the subtask lands in
+	// the parent's current project and category
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		parent, err := txTodos.GetTodo(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		key, err := parseTodoID(parent.ID)
+		if err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO todos (title, completed_at, due_date, created_date, reference_id, project_id, category_id, parent_id) VALUES (?, NULL, ?, ?, NULL, ?, ?, ?)",
+			title, dueDate, time.Now(), parent.ProjectID, parent.CategoryID, key)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		item, err = txTodos.GetTodo(ctx, strconv.FormatInt(id, 10))
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) MoveTodo(ctx context.Context, id string, parentID *string) (TodoItem, error) {
+	var item TodoItem
+	// Check for cycles, update and re-read in one transaction so no other
+	// move can slip a loop in between
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		current, err := txTodos.GetTodo(ctx, id)
+		if err != nil {
+			return err
+		}
+		var newParent *int64
+		if parentID != nil {
+			parent, err := txTodos.GetTodo(ctx, *parentID)
+			if err != nil {
+				return err
+			}
+			key, err := parseTodoID(parent.ID)
+			if err != nil {
+				return err
+			}
+			descendants, err := txTodos.subtree(ctx, current.ID, false)
+			if err != nil {
+				return err
+			}
+			if err := checkNewParent(current.ID, key, descendants); err != nil {
+				return err
+			}
+			newParent = &key
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE todos SET parent_id = ? WHERE id = ?", newParent, current.ID)
+		if err != nil {
+			return err
+		}
+		item, err = txTodos.GetTodo(ctx, current.ID)
+		return err
+	})
+	if err != nil {
+		return TodoItem{}, err
+	}
+	return item, nil
+}
+
+func (t *todo_sqlite) GetSubtasks(ctx context.Context, parentID string) ([]TodoItem, error) {
+	if _, err := t.GetTodo(ctx, parentID); err != nil {
+		return nil, err
+	}
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE parent_id = ? ORDER BY id", parentID)
+}
+
+func (t *todo_sqlite) GetSubtaskProgress(ctx context.Context) (map[string]SubtaskProgress, error) {
+	return querySubtaskProgress(ctx, t.db, "SELECT parent_id, COUNT(*), COUNT(completed_at) FROM todos WHERE parent_id IS NOT NULL GROUP BY parent_id")
+}
+
+func (t *todo_sqlite) AddBlocker(ctx context.Context, todoID string, blockerID string) ([]TodoItem, error) {
+	var blockers []TodoItem
+	// Check for cycles and insert in one transaction so no other dependency
+	// can slip a loop in between
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		item, err := txTodos.GetTodo(ctx, todoID)
+		if err != nil {
+			return err
+		}
+		blocker, err := txTodos.GetTodo(ctx, blockerID)
+		if err != nil {
+			return err
+		}
+		// UNION rather than UNION ALL stops at todos already visited
+		chain, err := queryIDs(ctx, tx, "WITH RECURSIVE chain (id) AS (" +
+			"SELECT blocker_id FROM todo_dependencies WHERE todo_id = ? " +
+			"UNION SELECT d.blocker_id FROM todo_dependencies d JOIN chain c ON d.todo_id = c.id) " +
+			"SELECT id FROM chain", blocker.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkNewBlocker(item.ID, blocker.ID, chain); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO todo_dependencies (todo_id, blocker_id) VALUES (?, ?) ON CONFLICT (todo_id, blocker_id) DO NOTHING", item.ID, blocker.ID)
+		if err != nil {
+			return err
+		}
+		blockers, err = txTodos.GetBlockers(ctx, item.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blockers, nil
+}
+
+func (t *todo_sqlite) RemoveBlocker(ctx context.Context, todoID string, blockerID string) ([]TodoItem, error) {
+	var blockers []TodoItem
+	err := withTx(ctx, t.db, func(tx DBTX) error {
+		txTodos := &todo_sqlite{db: tx, loc: t.loc}
+		item, err := txTodos.GetTodo(ctx, todoID)
+		if err != nil {
+			return err
+		}
+		blocker, err := txTodos.GetTodo(ctx, blockerID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM todo_dependencies WHERE todo_id = ? AND blocker_id = ?", item.ID, blocker.ID)
+		if err != nil {
+			return err
+		}
+		blockers, err = txTodos.GetBlockers(ctx, item.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blockers, nil
+}
+
+func (t *todo_sqlite) GetBlockers(ctx context.Context, todoID string) ([]TodoItem, error) {
+	if _, err := t.GetTodo(ctx, todoID); err != nil {
+		return nil, err
+	}
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE id IN (SELECT blocker_id FROM todo_dependencies WHERE todo_id = ?) ORDER BY id", todoID)
+}
+
+func (t *todo_sqlite) GetDependents(ctx context.Context, blockerID string) ([]TodoItem, error) {
+	if _, err := t.GetTodo(ctx, blockerID); err != nil {
+		return nil, err
+	}
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE id IN (SELECT todo_id FROM todo_dependencies WHERE blocker_id = ?) ORDER BY id", blockerID)
+}
+
+func (t *todo_sqlite) GetOpenBlockers(ctx context.Context) (map[string][]string, error) {
+	return queryOpenBlockers(ctx, t.db, "SELECT d.todo_id, d.blocker_id FROM todo_dependencies d JOIN todos b ON b.id = d.blocker_id WHERE b.completed_at IS NULL ORDER BY d.todo_id, d.blocker_id")
+}
+
+func (t *todo_sqlite) GetNextActions(ctx context.Context) ([]TodoItem, error) {
+	return queryTodos(ctx, t.db, "SELECT "+todoColumns+" FROM todos WHERE completed_at IS NULL AND NOT EXISTS (" +
+		"SELECT 1 FROM todo_dependencies d JOIN todos b ON b.id = d.blocker_id WHERE d.todo_id = todos.id AND b.completed_at IS NULL) " +
+		"ORDER BY priority DESC, due_date IS NULL, due_date, id")
+}
+
+// subtree returns the IDs of every todo below id, or only the open ones
+func (t *todo_sqlite) subtree(ctx context.Context, id string, openOnly bool) ([]int64, error) {
+	query := "WITH RECURSIVE subtree (id, completed_at) AS (" +
+		"SELECT id, completed_at FROM todos WHERE parent_id = ? " +
+		"UNION ALL SELECT c.id, c.completed_at FROM todos c JOIN subtree s ON c.parent_id = s.id) " +
+		"SELECT id FROM subtree"
+	if openOnly {
+		query += " WHERE completed_at IS NULL"
+	}
+	return queryIDs(ctx, t.db, query+" ORDER BY id", id)
+}
+
+// completeSubtasks applies policy to the open subtasks below item, which was
+// just completed. t.db must be the transaction that completed item.
+func (t *todo_sqlite) completeSubtasks(ctx context.Context, item TodoItem, completedAt time.Time, policy CompletionPolicy) error {
+	ids, err := t.subtree(ctx, item.ID, true)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	if policy == BlockOpenSubtasks {
+		return openSubtasks(item.ID, len(ids))
+	}
+	// Each subtask is completed on its own, so a recurring one carries on
+	// with its next occurrence
+	for _, key := range ids {
+		if _, _, _, err := t.complete(ctx, strconv.FormatInt(key, 10), completedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categoryExists returns ErrCategoryNotFound unless the category exists. It
+// stands in for the foreign key SQLite cannot add to todos.category_id.
+func categoryExists(ctx context.Context, db DBTX, id int64) error {
+	var found int
+	err := db.QueryRowContext(ctx, "SELECT 1 FROM categories WHERE id = ?", id).Scan(&found)
+	return orNotFound(err, categoryNotFound(id))
+}
+
+// spawnNext creates the occurrence that follows item, which was just
+// completed. It returns nil when item has no recurrence pattern, the pattern is
+// paused, its Until or Count has been reached, or a later occurrence already
+// exists. Occurrences before notBefore are skipped. t.db must be the
+// transaction that completed item.
+func (t *todo_sqlite) spawnNext(ctx context.Context, item TodoItem, completedAt time.Time, notBefore time.Time) (*TodoItem, error) {
+	root, err := seriesRoot(item)
+	if err != nil {
+		return nil, err
+	}
+	
+	// The series follows the most recently added pattern on its first todo
+	pattern, err := scanRecurrencePattern(t.db.QueryRowContext(ctx, "SELECT id, todo_id, frequency, `interval`, until, count, rrule, paused FROM recurrence_patterns WHERE todo_id = ? ORDER BY id DESC LIMIT 1", strconv.FormatInt(root, 10)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pattern.Paused {
+		return nil, nil
+	}
+	
+	var instances int
+	var latest sql.NullInt64
+	err = t.db.QueryRowContext(ctx, "SELECT COUNT(*), MAX(id) FROM todos WHERE reference_id = ?", root).Scan(&instances, &latest)
+	if err != nil {
+		return nil, err
+	}
+	// Reopening and completing an older occurrence must not fork the series
+	itemID, err := strconv.ParseInt(item.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Valid && latest.Int64 > itemID {
+		return nil, nil
+	}
+	
+	// Occurrences are anchored on the first todo's due date; if it has been
+	// deleted, the series continues from item
+	start := item.DueDate
+	if item.ReferenceID != nil {
+		err = t.db.QueryRowContext(ctx, "SELECT due_date FROM todos WHERE id = ?", root).Scan(&start)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+	
+	// The first todo plus every occurrence spawned from it
+	next, ok, err := nextInstance(pattern, item, start, instances+1, completedAt, notBefore, t.loc)
+	if err != nil || !ok {
+		return nil, err
+	}
+	next.CreatedDate = time.Now()
+	
+	res, err := t.db.ExecContext(ctx, "INSERT INTO todos (title, completed_at, due_date, created_date, reference_id, project_id, category_id, priority, notes, parent_id, due_all_day, estimate_minutes) VALUES (?, NULL, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		next.Title, next.DueDate, next.CreatedDate, next.ReferenceID, next.ProjectID, next.CategoryID, next.Priority, next.Notes, next.ParentID, next.DueAllDay, next.Estimate)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	next.ID = strconv.FormatInt(id, 10)
+
+	// The next occurrence gets the same tags and reminders
+	if _, err := t.db.ExecContext(ctx, "INSERT INTO todo_tags (todo_id, tag_id) SELECT ?, tag_id FROM todo_tags WHERE todo_id = ?", id, item.ID); err != nil {
+		return nil, err
+	}
+	if _, err := t.db.ExecContext(ctx, "INSERT INTO reminders (todo_id, offset_minutes) SELECT ?, offset_minutes FROM reminders WHERE todo_id = ?", id, item.ID); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
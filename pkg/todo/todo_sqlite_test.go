@@ -0,0 +1,404 @@
+package todo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newSQLiteTestDB opens a fresh, fully migrated SQLite database in a temporary directory
+func newSQLiteTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "todos.db"))
+	if err != nil {
+		t.Fatalf("Failed to open SQLite test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewMigrator(db, DialectSQLite)
+	if err != nil {
+		t.Fatalf("Failed to load SQLite migrations: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Failed to migrate SQLite test database: %v", err)
+	}
+	return db
+}
+
+func TestSQLite_AddTodo(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	todo, err := svc.AddTodo(context.Background(), "Test todo", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	if todo.Title != "Test todo" {
+		t.Errorf("Expected title 'Test todo', got '%s'", todo.Title)
+	}
+	if todo.CompletedAt != nil {
+		t.Error("New todo should not be completed")
+	}
+
+	dueDate := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	todoWithDate, err := svc.AddTodo(context.Background(), "Dated todo", &dueDate)
+	if err != nil {
+		t.Fatalf("AddTodo with due date failed: %v", err)
+	}
+	fetched, err := svc.GetTodo(context.Background(), todoWithDate.ID)
+	if err != nil {
+		t.Fatalf("GetTodo failed: %v", err)
+	}
+	if fetched.DueDate == nil || !fetched.DueDate.Equal(dueDate) {
+		t.Errorf("Due date not persisted correctly, got %v", fetched.DueDate)
+	}
+
+	_, err = svc.AddTodo(context.Background(), "", nil)
+	if err == nil {
+		t.Error("Expected error for empty title")
+	}
+}
+
+func TestSQLite_CompleteUncomplete(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	todo, err := svc.AddTodo(context.Background(), "Complete test", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	completed, err := svc.CompleteTodo(context.Background(), todo.ID)
+	if err != nil {
+		t.Fatalf("CompleteTodo failed: %v", err)
+	}
+	if completed.CompletedAt == nil {
+		t.Error("CompletedAt should be set after completing")
+	} else if time.Since(*completed.CompletedAt) > time.Minute {
+		t.Errorf("CompletedAt is too old: %v", completed.CompletedAt)
+	}
+
+	uncompleted, err := svc.UnCompleteTodo(context.Background(), todo.ID)
+	if err != nil {
+		t.Fatalf("UnCompleteTodo failed: %v", err)
+	}
+	if uncompleted.CompletedAt != nil {
+		t.Error("CompletedAt should be nil after uncompleting")
+	}
+}
+
+func TestSQLite_SetDueDate(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	todo, err := svc.AddTodo(context.Background(), "Due date test", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	newDate := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	updated, err := svc.SetDueDate(context.Background(), todo.ID, newDate)
+	if err != nil {
+		t.Fatalf("SetDueDate failed: %v", err)
+	}
+	if updated.DueDate == nil || !updated.DueDate.Equal(newDate) {
+		t.Errorf("Due date not updated correctly, got %v", updated.DueDate)
+	}
+}
+
+func TestSQLite_GetOperations(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	_, err := svc.AddTodo(context.Background(), "Active 1", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	completed, err := svc.AddTodo(context.Background(), "Completed 1", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+	_, err = svc.CompleteTodo(context.Background(), completed.ID)
+	if err != nil {
+		t.Fatalf("CompleteTodo failed: %v", err)
+	}
+
+	all, err := svc.GetAllTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllTodos failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 todos, got %d", len(all))
+	}
+
+	active, err := svc.GetActiveTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetActiveTodos failed: %v", err)
+	}
+	if len(active) != 1 || active[0].Title != "Active 1" {
+		t.Errorf("Expected only 'Active 1' to be active, got %v", active)
+	}
+
+	completedTodos, err := svc.GetCompletedTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetCompletedTodos failed: %v", err)
+	}
+	if len(completedTodos) != 1 || completedTodos[0].ID != completed.ID {
+		t.Errorf("Expected only '%s' to be completed, got %v", completed.ID, completedTodos)
+	}
+
+	fetched, err := svc.GetTodo(context.Background(), completed.ID)
+	if err != nil {
+		t.Fatalf("GetTodo failed: %v", err)
+	}
+	if fetched.ID != completed.ID || fetched.CompletedAt == nil {
+		t.Error("Fetched todo does not match completed todo")
+	}
+}
+
+func TestSQLite_DeleteTodo(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	todo, err := svc.AddTodo(context.Background(), "Delete test", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	deleted, err := svc.DeleteTodo(context.Background(), todo.ID)
+	if err != nil {
+		t.Fatalf("DeleteTodo failed: %v", err)
+	}
+	if deleted.ID != todo.ID {
+		t.Error("Deleted todo ID mismatch")
+	}
+
+	_, err = svc.GetTodo(context.Background(), todo.ID)
+	if err == nil {
+		t.Error("Expected error when fetching deleted todo")
+	}
+	_, err = svc.DeleteTodo(context.Background(), todo.ID)
+	if err == nil {
+		t.Error("Expected error when deleting a missing todo")
+	}
+}
+
+func TestSQLite_TitleSearch(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	for _, title := range []string{"Search active", "Search COMPLETED", "Other"} {
+		todo, err := svc.AddTodo(context.Background(), title, nil)
+		if err != nil {
+			t.Fatalf("AddTodo failed: %v", err)
+		}
+		if title == "Search COMPLETED" {
+			if _, err := svc.CompleteTodo(context.Background(), todo.ID); err != nil {
+				t.Fatalf("CompleteTodo failed: %v", err)
+			}
+		}
+	}
+
+	results, err := svc.TitleSearchTodo(context.Background(), "search", false)
+	if err != nil {
+		t.Fatalf("TitleSearchTodo failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 case insensitive matches, got %d", len(results))
+	}
+	results, err = svc.TitleSearchTodo(context.Background(), "Search", true)
+	if err != nil {
+		t.Fatalf("TitleSearchTodo failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Search active" {
+		t.Errorf("Expected only the active match, got %v", results)
+	}
+	results, err = svc.TitleSearchTodo(context.Background(), "nonexistent", false)
+	if err != nil {
+		t.Fatalf("TitleSearchTodo failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no matches, got %d", len(results))
+	}
+}
+
+func TestSQLite_RecurrencePattern(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	todo, err := svc.AddTodo(context.Background(), "Recurring todo", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	until := time.Now().AddDate(0, 1, 0).Truncate(time.Second)
+	count := 5
+	patternID, err := svc.AddRecurrencePattern(context.Background(), RecurrencePattern{
+		TodoID:    todo.ID,
+		Frequency: "weekly",
+		Interval:  1,
+		Until:     &until,
+		Count:     &count,
+	})
+	if err != nil {
+		t.Fatalf("AddRecurrencePattern failed: %v", err)
+	}
+	if patternID <= 0 {
+		t.Error("Invalid pattern ID returned")
+	}
+
+	retrieved, err := svc.GetRecurrencePatternByID(context.Background(), patternID)
+	if err != nil {
+		t.Fatalf("GetRecurrencePatternByID failed: %v", err)
+	}
+	if retrieved.TodoID != todo.ID || retrieved.Frequency != "weekly" || retrieved.Interval != 1 {
+		t.Errorf("Unexpected pattern: %+v", retrieved)
+	}
+	if retrieved.Until == nil || !retrieved.Until.Equal(until) {
+		t.Error("Until date mismatch")
+	}
+	if retrieved.Count == nil || *retrieved.Count != count {
+		t.Error("Count mismatch")
+	}
+
+	_, err = svc.GetRecurrencePatternByID(context.Background(), patternID + 1)
+	if err == nil {
+		t.Error("Expected error for missing recurrence pattern")
+	}
+}
+
+func TestSQLite_Categories(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	svc := NewTodoSQLite(db, nil)
+	categories := NewCategoryService(NewCategorySQLite(db))
+
+	work, err := categories.CreateCategory(context.Background(), "Work", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateCategory failed: %v", err)
+	}
+
+	inCategory, err := svc.AddTodoToCategory(context.Background(), "Write report", work.ID, nil)
+	if err != nil {
+		t.Fatalf("AddTodoToCategory failed: %v", err)
+	}
+	if inCategory.CategoryID == nil || *inCategory.CategoryID != work.ID {
+		t.Error("CategoryID not set on new todo")
+	}
+	loose, err := svc.AddTodo(context.Background(), "Buy milk", nil)
+	if err != nil {
+		t.Fatalf("AddTodo failed: %v", err)
+	}
+
+	todos, err := svc.GetTodosByCategory(context.Background(), work.ID)
+	if err != nil {
+		t.Fatalf("GetTodosByCategory failed: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != inCategory.ID {
+		t.Errorf("Expected only '%s' in category, got %v", inCategory.ID, todos)
+	}
+	todos, err = svc.GetUncategorizedTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetUncategorizedTodos failed: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != loose.ID {
+		t.Errorf("Expected only '%s' uncategorized, got %v", loose.ID, todos)
+	}
+
+	assigned, err := svc.AssignTodoToCategory(context.Background(), loose.ID, work.ID)
+	if err != nil {
+		t.Fatalf("AssignTodoToCategory failed: %v", err)
+	}
+	if assigned.CategoryID == nil || *assigned.CategoryID != work.ID {
+		t.Error("CategoryID not set after assignment")
+	}
+
+	removed, err := svc.RemoveTodoFromCategory(context.Background(), loose.ID)
+	if err != nil {
+		t.Fatalf("RemoveTodoFromCategory failed: %v", err)
+	}
+	if removed.CategoryID != nil {
+		t.Error("CategoryID should be nil after removal")
+	}
+
+	// Deleting the category detaches its remaining todos
+	if err := categories.DeleteCategory(context.Background(), work.ID); err != nil {
+		t.Fatalf("DeleteCategory failed: %v", err)
+	}
+	detached, err := svc.GetTodo(context.Background(), inCategory.ID)
+	if err != nil {
+		t.Fatalf("GetTodo failed: %v", err)
+	}
+	if detached.CategoryID != nil {
+		t.Error("CategoryID should be cleared when its category is deleted")
+	}
+}
+
+func TestSQLite_Projects(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	svc := NewTodoSQLite(db, nil)
+	projects := NewProjectSQLite(db)
+
+	project, err := projects.CreateProject(context.Background(), "Garden", nil)
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+
+	todo, err := svc.AddTodoToProject(context.Background(), "Plant tomatoes", project.ID, nil)
+	if err != nil {
+		t.Fatalf("AddTodoToProject failed: %v", err)
+	}
+	if todo.ProjectID == nil || *todo.ProjectID != project.ID {
+		t.Error("ProjectID not set on new todo")
+	}
+
+	todos, err := svc.GetTodosByProject(context.Background(), project.ID)
+	if err != nil {
+		t.Fatalf("GetTodosByProject failed: %v", err)
+	}
+	if len(todos) != 1 || todos[0].ID != todo.ID {
+		t.Errorf("Expected only '%s' in project, got %v", todo.ID, todos)
+	}
+}
+
+func TestSQLite_ListErrorsAreReturned(t *testing.T) {
+	// An unmigrated database has no tables, so every list query fails
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "empty.db"))
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+	defer db.Close()
+
+	svc := NewTodoSQLite(db, nil)
+	if _, err := svc.GetAllTodos(context.Background()); err == nil {
+		t.Error("GetAllTodos should return an error when the query fails")
+	}
+	if _, err := svc.GetActiveTodos(context.Background()); err == nil {
+		t.Error("GetActiveTodos should return an error when the query fails")
+	}
+	if _, err := svc.TitleSearchTodo(context.Background(), "x", false); err == nil {
+		t.Error("TitleSearchTodo should return an error when the query fails")
+	}
+
+	projects := NewProjectSQLite(db)
+	if _, err := projects.GetAllProjects(context.Background()); err == nil {
+		t.Error("GetAllProjects should return an error when the query fails")
+	}
+}
+
+func TestSQLite_CancelledContext(t *testing.T) {
+	svc := NewTodoSQLite(newSQLiteTestDB(t), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := svc.GetAllTodos(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from GetAllTodos, got %v", err)
+	}
+	if _, err := svc.AddTodo(ctx, "Never stored", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from AddTodo, got %v", err)
+	}
+
+	todos, err := svc.GetAllTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllTodos failed: %v", err)
+	}
+	if len(todos) != 0 {
+		t.Errorf("Expected no todos after cancelled insert, got %d", len(todos))
+	}
+}
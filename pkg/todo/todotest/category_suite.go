@@ -0,0 +1,164 @@
+package todotest
+
+import (
+	"context"
+	"testing"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunCategoryRepositorySuite checks every CategoryRepository method, including
+// edge cases and error paths, against backends built by factory
+func RunCategoryRepositorySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	run(t, factory, []testCase{
+		{"CreateAndFind", func(t *testing.T, b Backend) {
+			category, err := b.Categories.Create(ctx, todo.Category{
+				Name:        "Work",
+				Description: stringPtr("Office tasks"),
+				Color:       stringPtr("#3498db"),
+			})
+			require.NoError(t, err)
+			assert.NotZero(t, category.ID)
+			assert.Equal(t, "Work", category.Name)
+			assert.Equal(t, stringPtr("Office tasks"), category.Description)
+			assert.Equal(t, stringPtr("#3498db"), category.Color)
+
+			byID, err := b.Categories.FindByID(ctx, category.ID)
+			require.NoError(t, err)
+			assert.Equal(t, category.ID, byID.ID)
+			assert.Equal(t, "Work", byID.Name)
+			assert.Equal(t, category.Description, byID.Description)
+			assert.Equal(t, category.Color, byID.Color)
+
+			byName, err := b.Categories.FindByName(ctx, "Work")
+			require.NoError(t, err)
+			assert.Equal(t, category.ID, byName.ID)
+
+			plain, err := b.Categories.Create(ctx, todo.Category{Name: "Home"})
+			require.NoError(t, err)
+			assert.Nil(t, plain.Description)
+			assert.Nil(t, plain.Color)
+		}},
+		{"CreateValidation", func(t *testing.T, b Backend) {
+			_, err := b.Categories.Create(ctx, todo.Category{})
+			assert.ErrorIs(t, err, todo.ErrValidation, "empty name")
+
+			_, err = b.Categories.Create(ctx, todo.Category{Name: "Work"})
+			require.NoError(t, err)
+			_, err = b.Categories.Create(ctx, todo.Category{Name: "Work"})
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+
+			categories, err := b.Categories.FindAll(ctx)
+			require.NoError(t, err)
+			assert.Len(t, categories, 1)
+		}},
+		{"FindMissing", func(t *testing.T, b Backend) {
+			_, err := b.Categories.FindByID(ctx, 999999)
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+			_, err = b.Categories.FindByName(ctx, "Nothing")
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+			err = b.Categories.Delete(ctx, 999999)
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+		}},
+		{"FindAll", func(t *testing.T, b Backend) {
+			categories, err := b.Categories.FindAll(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, categories)
+
+			var want []int64
+			for _, name := range []string{"One", "Two", "Three"} {
+				category, err := b.Categories.Create(ctx, todo.Category{Name: name})
+				require.NoError(t, err)
+				want = append(want, category.ID)
+			}
+
+			categories, err = b.Categories.FindAll(ctx)
+			require.NoError(t, err)
+			var got []int64
+			for _, category := range categories {
+				got = append(got, category.ID)
+			}
+			assert.ElementsMatch(t, want, got)
+		}},
+		{"Update", func(t *testing.T, b Backend) {
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Work"})
+			require.NoError(t, err)
+
+			category.Name = "Office"
+			category.Color = stringPtr("#FF5733")
+			updated, err := b.Categories.Update(ctx, category)
+			require.NoError(t, err)
+			assert.Equal(t, category.ID, updated.ID)
+			assert.Equal(t, "Office", updated.Name)
+			assert.Equal(t, stringPtr("#FF5733"), updated.Color)
+
+			stored, err := b.Categories.FindByID(ctx, category.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Office", stored.Name)
+
+			_, err = b.Categories.FindByName(ctx, "Work")
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound, "old name no longer matches")
+
+			category.Name = ""
+			_, err = b.Categories.Update(ctx, category)
+			assert.ErrorIs(t, err, todo.ErrValidation, "empty name")
+
+			_, err = b.Categories.Create(ctx, todo.Category{Name: "Home"})
+			require.NoError(t, err)
+			category.Name = "Home"
+			_, err = b.Categories.Update(ctx, category)
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+
+			_, err = b.Categories.Update(ctx, todo.Category{ID: 999999, Name: "Missing"})
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound, "missing category")
+		}},
+		{"DeleteClearsTodos", func(t *testing.T, b Backend) {
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Errands"})
+			require.NoError(t, err)
+			item, err := b.Todos.AddTodoToCategory(ctx, "Post letter", category.ID, nil)
+			require.NoError(t, err)
+
+			require.NoError(t, b.Categories.Delete(ctx, category.ID))
+
+			_, err = b.Categories.FindByID(ctx, category.ID)
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+
+			// Todos survive with their category cleared
+			stored, err := b.Todos.GetTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Nil(t, stored.CategoryID)
+		}},
+		{"FindTodos", func(t *testing.T, b Backend) {
+			work, err := b.Categories.Create(ctx, todo.Category{Name: "Work"})
+			require.NoError(t, err)
+			home, err := b.Categories.Create(ctx, todo.Category{Name: "Home"})
+			require.NoError(t, err)
+
+			report, err := b.Todos.AddTodoToCategory(ctx, "Write report", work.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AddTodoToCategory(ctx, "Hoover", home.ID, nil)
+			require.NoError(t, err)
+			loose, err := b.Todos.AddTodo(ctx, "Uncategorized", nil)
+			require.NoError(t, err)
+
+			items, err := b.Categories.FindTodosByCategory(ctx, work.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{report.ID}, ids(items))
+			assertStoredTodo(t, b, items[0])
+
+			items, err = b.Categories.FindUncategorizedTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{loose.ID}, ids(items))
+			assertStoredTodo(t, b, items[0])
+
+			items, err = b.Categories.FindTodosByCategory(ctx, 999999)
+			require.NoError(t, err)
+			assert.Empty(t, items)
+		}},
+	})
+}
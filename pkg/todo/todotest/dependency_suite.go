@@ -0,0 +1,148 @@
+package todotest
+
+import (
+	"context"
+	"testing"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunDependencySuite checks the blocker methods of TodoService, including
+// cycle detection and next actions, against backends built by factory
+func RunDependencySuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	add := func(t *testing.T, b Backend, title string) string {
+		item, err := b.Todos.AddTodo(ctx, title, nil)
+		require.NoError(t, err)
+		return item.ID
+	}
+
+	run(t, factory, []testCase{
+		{"AddAndRemoveBlockers", func(t *testing.T, b Backend) {
+			paint := add(t, b, "Paint walls")
+			buyPaint := add(t, b, "Buy paint")
+			sand := add(t, b, "Sand walls")
+
+			blockers, err := b.Todos.AddBlocker(ctx, paint, buyPaint)
+			require.NoError(t, err)
+			assert.Equal(t, []string{buyPaint}, ids(blockers))
+			blockers, err = b.Todos.AddBlocker(ctx, paint, sand)
+			require.NoError(t, err)
+			assert.Equal(t, []string{buyPaint, sand}, ids(blockers))
+			for _, item := range blockers {
+				assertStoredTodo(t, b, item)
+			}
+
+			// Adding a blocker twice is not an error
+			blockers, err = b.Todos.AddBlocker(ctx, paint, sand)
+			require.NoError(t, err)
+			assert.Equal(t, []string{buyPaint, sand}, ids(blockers))
+
+			dependents, err := b.Todos.GetDependents(ctx, sand)
+			require.NoError(t, err)
+			assert.Equal(t, []string{paint}, ids(dependents))
+
+			blockers, err = b.Todos.RemoveBlocker(ctx, paint, buyPaint)
+			require.NoError(t, err)
+			assert.Equal(t, []string{sand}, ids(blockers))
+			stored, err := b.Todos.GetBlockers(ctx, paint)
+			require.NoError(t, err)
+			assert.Equal(t, []string{sand}, ids(stored))
+
+			_, err = b.Todos.AddBlocker(ctx, paint, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.AddBlocker(ctx, "999999", paint)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.GetBlockers(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.GetDependents(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"RejectsCycles", func(t *testing.T, b Backend) {
+			first := add(t, b, "First")
+			second := add(t, b, "Second")
+			third := add(t, b, "Third")
+			_, err := b.Todos.AddBlocker(ctx, second, first)
+			require.NoError(t, err)
+			_, err = b.Todos.AddBlocker(ctx, third, second)
+			require.NoError(t, err)
+
+			_, err = b.Todos.AddBlocker(ctx, first, first)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.AddBlocker(ctx, first, second)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.AddBlocker(ctx, first, third)
+			assert.ErrorIs(t, err, todo.ErrValidation, "a loop through other todos is a cycle too")
+
+			blockers, err := b.Todos.GetBlockers(ctx, first)
+			require.NoError(t, err)
+			assert.Empty(t, blockers, "a rejected blocker is not added")
+
+			// A diamond is not a cycle
+			_, err = b.Todos.AddBlocker(ctx, third, first)
+			assert.NoError(t, err)
+		}},
+		{"NextActionsAndOpenBlockers", func(t *testing.T, b Backend) {
+			blocked := add(t, b, "Blocked")
+			blocker := add(t, b, "Blocker")
+			done := add(t, b, "Done already")
+			free := add(t, b, "Free")
+			_, err := b.Todos.SetPriority(ctx, free, todo.PriorityHigh)
+			require.NoError(t, err)
+			_, err = b.Todos.CompleteTodo(ctx, done)
+			require.NoError(t, err)
+			_, err = b.Todos.AddBlocker(ctx, blocked, blocker)
+			require.NoError(t, err)
+			_, err = b.Todos.AddBlocker(ctx, blocked, done)
+			require.NoError(t, err)
+			_, err = b.Todos.AddBlocker(ctx, free, done)
+			require.NoError(t, err)
+
+			open, err := b.Todos.GetOpenBlockers(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[string][]string{blocked: {blocker}}, open, "completed blockers do not block")
+
+			next, err := b.Todos.GetNextActions(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{free, blocker}, ids(next), "sorted like GetActiveTodos")
+			for _, item := range next {
+				assertStoredTodo(t, b, item)
+			}
+
+			_, err = b.Todos.CompleteTodo(ctx, blocker)
+			require.NoError(t, err)
+			next, err = b.Todos.GetNextActions(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{free, blocked}, ids(next))
+			open, err = b.Todos.GetOpenBlockers(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, open)
+		}},
+		{"DeleteTodoRemovesDependencies", func(t *testing.T, b Backend) {
+			blocked := add(t, b, "Blocked")
+			blocker := add(t, b, "Blocker")
+			dependent := add(t, b, "Dependent")
+			_, err := b.Todos.AddBlocker(ctx, blocked, blocker)
+			require.NoError(t, err)
+			_, err = b.Todos.AddBlocker(ctx, dependent, blocked)
+			require.NoError(t, err)
+
+			_, err = b.Todos.DeleteTodo(ctx, blocked)
+			require.NoError(t, err)
+
+			dependents, err := b.Todos.GetDependents(ctx, blocker)
+			require.NoError(t, err)
+			assert.Empty(t, dependents)
+			blockers, err := b.Todos.GetBlockers(ctx, dependent)
+			require.NoError(t, err)
+			assert.Empty(t, blockers)
+			open, err := b.Todos.GetOpenBlockers(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, open)
+		}},
+	})
+}
@@ -0,0 +1,156 @@
+package todotest
+
+import (
+	"context"
+	"testing"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunProjectServiceSuite checks every ProjectService method, including edge
+// cases and error paths, against backends built by factory
+func RunProjectServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	run(t, factory, []testCase{
+		{"CreateAndGetProject", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", stringPtr("Outdoor jobs"))
+			require.NoError(t, err)
+			assert.NotZero(t, project.ID)
+			assert.Equal(t, "Garden", project.Name)
+			require.NotNil(t, project.Description)
+			assert.Equal(t, "Outdoor jobs", *project.Description)
+
+			stored, err := b.Projects.GetProject(ctx, project.ID)
+			require.NoError(t, err)
+			assert.Equal(t, project.ID, stored.ID)
+			assert.Equal(t, "Garden", stored.Name)
+			assert.Equal(t, project.Description, stored.Description)
+
+			noDescription, err := b.Projects.CreateProject(ctx, "Kitchen", nil)
+			require.NoError(t, err)
+			assert.Nil(t, noDescription.Description)
+		}},
+		{"CreateProjectValidation", func(t *testing.T, b Backend) {
+			_, err := b.Projects.CreateProject(ctx, "", nil)
+			assert.ErrorIs(t, err, todo.ErrValidation, "empty name")
+
+			_, err = b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+			_, err = b.Projects.CreateProject(ctx, "Garden", nil)
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+
+			projects, err := b.Projects.GetAllProjects(ctx)
+			require.NoError(t, err)
+			assert.Len(t, projects, 1)
+		}},
+		{"GetProjectMissing", func(t *testing.T, b Backend) {
+			_, err := b.Projects.GetProject(ctx, 999999)
+			assert.ErrorIs(t, err, todo.ErrProjectNotFound)
+		}},
+		{"GetAllProjects", func(t *testing.T, b Backend) {
+			projects, err := b.Projects.GetAllProjects(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, projects)
+
+			var want []int64
+			for _, name := range []string{"One", "Two", "Three"} {
+				project, err := b.Projects.CreateProject(ctx, name, nil)
+				require.NoError(t, err)
+				want = append(want, project.ID)
+			}
+
+			projects, err = b.Projects.GetAllProjects(ctx)
+			require.NoError(t, err)
+			var got []int64
+			for _, project := range projects {
+				got = append(got, project.ID)
+			}
+			assert.ElementsMatch(t, want, got)
+		}},
+		{"UpdateProject", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+
+			updated, err := b.Projects.UpdateProject(ctx, project.ID, "Allotment", stringPtr("Plot 12"))
+			require.NoError(t, err)
+			assert.Equal(t, project.ID, updated.ID)
+			assert.Equal(t, "Allotment", updated.Name)
+			require.NotNil(t, updated.Description)
+			assert.Equal(t, "Plot 12", *updated.Description)
+
+			stored, err := b.Projects.GetProject(ctx, project.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Allotment", stored.Name)
+
+			_, err = b.Projects.UpdateProject(ctx, project.ID, "", nil)
+			assert.ErrorIs(t, err, todo.ErrValidation, "empty name")
+			_, err = b.Projects.CreateProject(ctx, "Kitchen", nil)
+			require.NoError(t, err)
+			_, err = b.Projects.UpdateProject(ctx, project.ID, "Kitchen", nil)
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+			_, err = b.Projects.UpdateProject(ctx, 999999, "Nowhere", nil)
+			assert.ErrorIs(t, err, todo.ErrProjectNotFound, "missing project")
+		}},
+		{"DeleteProject", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+			active, err := b.Todos.AddTodoToProject(ctx, "Plant tomatoes", project.ID, nil)
+			require.NoError(t, err)
+			finished, err := b.Todos.AddTodoToProject(ctx, "Buy seeds", project.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.CompleteTodo(ctx, finished.ID)
+			require.NoError(t, err)
+
+			deleted, err := b.Projects.DeleteProject(ctx, project.ID)
+			require.NoError(t, err)
+			assert.Equal(t, project.ID, deleted.ID)
+			assert.Equal(t, "Garden", deleted.Name)
+
+			_, err = b.Projects.GetProject(ctx, project.ID)
+			assert.ErrorIs(t, err, todo.ErrProjectNotFound)
+
+			// Active todos are detached; completed todos keep their project as history
+			stored, err := b.Todos.GetTodo(ctx, active.ID)
+			require.NoError(t, err)
+			assert.Nil(t, stored.ProjectID)
+			stored, err = b.Todos.GetTodo(ctx, finished.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64Ptr(project.ID), stored.ProjectID)
+
+			_, err = b.Projects.DeleteProject(ctx, project.ID)
+			assert.ErrorIs(t, err, todo.ErrProjectNotFound, "deleting twice")
+		}},
+		{"GetProjectTodos", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+			other, err := b.Projects.CreateProject(ctx, "Kitchen", nil)
+			require.NoError(t, err)
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Outdoor"})
+			require.NoError(t, err)
+
+			empty, err := b.Projects.GetProjectTodos(ctx, project.ID)
+			require.NoError(t, err)
+			assert.Empty(t, empty)
+
+			first, err := b.Todos.AddTodoToProject(ctx, "Weed beds", project.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AssignTodoToCategory(ctx, first.ID, category.ID)
+			require.NoError(t, err)
+			second, err := b.Todos.AddTodoToProject(ctx, "Prune roses", project.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AddTodoToProject(ctx, "Paint walls", other.ID, nil)
+			require.NoError(t, err)
+
+			items, err := b.Projects.GetProjectTodos(ctx, project.ID)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{first.ID, second.ID}, ids(items))
+			for _, item := range items {
+				assertStoredTodo(t, b, item)
+			}
+		}},
+	})
+}
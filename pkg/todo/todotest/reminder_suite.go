@@ -0,0 +1,170 @@
+package todotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunReminderServiceSuite checks ReminderService, including which reminders
+// are pending, against backends built by factory
+func RunReminderServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	add := func(t *testing.T, b Backend, title string, due *time.Time) string {
+		item, err := b.Todos.AddTodo(ctx, title, due)
+		require.NoError(t, err)
+		return item.ID
+	}
+
+	run(t, factory, []testCase{
+		{"SetReminders", func(t *testing.T, b Backend) {
+			due := date(2030, time.March, 2)
+			report := add(t, b, "Write report", &due)
+
+			reminders, err := b.Reminders.SetReminders(ctx, report, []int{0, 60, 1440, 60})
+			require.NoError(t, err)
+			assert.Equal(t, []int{1440, 60, 0}, reminderOffsets(reminders), "earliest first, without duplicates")
+			for _, reminder := range reminders {
+				assert.NotZero(t, reminder.ID)
+				assert.Equal(t, report, reminder.TodoID)
+				assert.Nil(t, reminder.NotifiedFor)
+			}
+
+			got, err := b.Reminders.GetReminders(ctx, report)
+			require.NoError(t, err)
+			assert.Equal(t, reminders, got)
+
+			// Kept offsets keep their reminder; the rest are replaced
+			replaced, err := b.Reminders.SetReminders(ctx, report, []int{15, 60})
+			require.NoError(t, err)
+			assert.Equal(t, []int{60, 15}, reminderOffsets(replaced))
+			assert.Equal(t, reminders[1].ID, replaced[0].ID)
+
+			cleared, err := b.Reminders.SetReminders(ctx, report, nil)
+			require.NoError(t, err)
+			assert.Empty(t, cleared)
+			got, err = b.Reminders.GetReminders(ctx, report)
+			require.NoError(t, err)
+			assert.Empty(t, got)
+		}},
+		{"PendingReminders", func(t *testing.T, b Backend) {
+			soon := date(2030, time.March, 2)
+			later := date(2030, time.March, 9)
+			report := add(t, b, "Write report", &later)
+			review := add(t, b, "Review budget", &soon)
+			undated := add(t, b, "Call plumber", nil)
+			done := add(t, b, "File taxes", &soon)
+			for _, id := range []string{report, review, undated, done} {
+				_, err := b.Reminders.SetReminders(ctx, id, []int{0, 30})
+				require.NoError(t, err)
+			}
+			_, err := b.Todos.CompleteTodo(ctx, done)
+			require.NoError(t, err)
+
+			pending, err := b.Reminders.GetPendingReminders(ctx)
+			require.NoError(t, err)
+			require.Len(t, pending, 4, "only open todos with a due date")
+			assert.Equal(t, review, pending[0].TodoID, "soonest due first")
+			assert.Equal(t, 30, pending[0].Offset)
+			assert.Equal(t, 0, pending[1].Offset)
+			assert.Equal(t, review, pending[0].Todo.ID)
+			assert.Equal(t, "Review budget", pending[0].Todo.Title)
+			assertSameTime(t, &soon, pending[0].Todo.DueDate)
+			assert.Equal(t, soon.Add(-30*time.Minute), pending[0].FiresAt(time.UTC))
+			assert.Equal(t, report, pending[2].TodoID)
+
+			// A sent reminder stays quiet until the due date moves
+			require.NoError(t, b.Reminders.MarkReminderSent(ctx, pending[0].ID, *pending[0].Todo.DueDate))
+			pending, err = b.Reminders.GetPendingReminders(ctx)
+			require.NoError(t, err)
+			require.Len(t, pending, 3)
+			assert.Equal(t, 0, pending[0].Offset)
+
+			moved := soon.Add(time.Hour)
+			_, err = b.Todos.SetDueDate(ctx, review, moved)
+			require.NoError(t, err)
+			pending, err = b.Reminders.GetPendingReminders(ctx)
+			require.NoError(t, err)
+			assert.Len(t, pending, 4, "moving the due date re-arms the reminder")
+
+			require.NoError(t, b.Reminders.MarkReminderSent(ctx, 999999, moved), "unknown reminders are ignored")
+		}},
+		{"AllDayFiresAt", func(t *testing.T, b Backend) {
+			due := time.Date(2030, time.March, 2, 0, 0, 0, 0, time.UTC)
+			report := add(t, b, "Write report", nil)
+			_, err := b.Todos.UpdateTodo(ctx, report, todo.TodoPatch{DueDate: &due, DueAllDay: true})
+			require.NoError(t, err)
+			_, err = b.Reminders.SetReminders(ctx, report, []int{60})
+			require.NoError(t, err)
+
+			pending, err := b.Reminders.GetPendingReminders(ctx)
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			assert.True(t, pending[0].Todo.DueAllDay)
+			tokyo := time.FixedZone("UTC+9", 9*60*60)
+			assert.True(t, time.Date(2030, time.March, 1, 23, 0, 0, 0, tokyo).Equal(pending[0].FiresAt(tokyo)), "an hour before the day starts in the zone")
+		}},
+		{"RecurringTodosKeepReminders", func(t *testing.T, b Backend) {
+			due := date(2030, time.January, 31)
+			first := add(t, b, "Water plants", &due)
+			_, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: first, Frequency: "monthly", Interval: 1})
+			require.NoError(t, err)
+			_, err = b.Reminders.SetReminders(ctx, first, []int{0, 120})
+			require.NoError(t, err)
+
+			_, next, err := b.Todos.CompleteTodoWithNext(ctx, first)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			reminders, err := b.Reminders.GetReminders(ctx, next.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []int{120, 0}, reminderOffsets(reminders))
+			assert.Nil(t, reminders[0].NotifiedFor)
+		}},
+		{"DeletingTodoDeletesReminders", func(t *testing.T, b Backend) {
+			due := date(2030, time.March, 2)
+			report := add(t, b, "Write report", &due)
+			_, err := b.Reminders.SetReminders(ctx, report, []int{0})
+			require.NoError(t, err)
+
+			_, err = b.Todos.DeleteTodo(ctx, report)
+			require.NoError(t, err)
+			pending, err := b.Reminders.GetPendingReminders(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, pending)
+		}},
+		{"Errors", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report", nil)
+
+			_, err := b.Reminders.SetReminders(ctx, "999999", []int{0})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Reminders.GetReminders(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+
+			_, err = b.Reminders.SetReminders(ctx, report, []int{-5})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Reminders.SetReminders(ctx, report, []int{31 * 24 * 60})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Reminders.SetReminders(ctx, report, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+
+			reminders, err := b.Reminders.GetReminders(ctx, report)
+			require.NoError(t, err)
+			assert.Empty(t, reminders, "a failed call records nothing")
+		}},
+	})
+}
+
+// reminderOffsets returns the offsets of reminders in order
+func reminderOffsets(reminders []todo.Reminder) []int {
+	result := make([]int, 0, len(reminders))
+	for _, reminder := range reminders {
+		result = append(result, reminder.Offset)
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package todotest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunSavedFilterServiceSuite checks SavedFilterService against backends built
+// by factory
+func RunSavedFilterServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+	high := todo.PriorityHigh
+
+	run(t, factory, []testCase{
+		{"CreateAndGet", func(t *testing.T, b Backend) {
+			criteria := todo.FilterCriteria{
+				ProjectID:   int64Ptr(3),
+				Tags:        []string{" Work ", "urgent"},
+				DueAfter:    " today ",
+				DueBefore:   "end of week",
+				MinPriority: &high,
+				Query:       "report",
+			}
+			filter, err := b.Filters.CreateFilter(ctx, "  Work this week ", criteria)
+			require.NoError(t, err)
+			assert.NotZero(t, filter.ID)
+			assert.Equal(t, "Work this week", filter.Name)
+			assert.Equal(t, []string{"work", "urgent"}, filter.Criteria.Tags)
+			assert.Equal(t, "today", filter.Criteria.DueAfter)
+			assert.Equal(t, todo.FilterStatusOpen, filter.Criteria.Status, "status defaults to open")
+			assert.False(t, filter.CreatedAt.IsZero())
+
+			got, err := b.Filters.GetFilter(ctx, filter.ID)
+			require.NoError(t, err)
+			assert.Equal(t, filter.Name, got.Name)
+			assert.Equal(t, filter.Criteria, got.Criteria)
+			assertSameTime(t, &filter.CreatedAt, &got.CreatedAt)
+
+			byName, err := b.Filters.GetFilterByName(ctx, "WORK THIS WEEK")
+			require.NoError(t, err)
+			assert.Equal(t, filter.ID, byName.ID, "names match ignoring case")
+		}},
+		{"ListFilters", func(t *testing.T, b Backend) {
+			for _, name := range []string{"overdue personal", "Work this week", "Errands"} {
+				_, err := b.Filters.CreateFilter(ctx, name, todo.FilterCriteria{})
+				require.NoError(t, err)
+			}
+
+			filters, err := b.Filters.ListFilters(ctx)
+			require.NoError(t, err)
+			names := make([]string, 0, len(filters))
+			for _, filter := range filters {
+				names = append(names, filter.Name)
+			}
+			assert.Equal(t, []string{"Errands", "overdue personal", "Work this week"}, names, "ordered by name ignoring case")
+		}},
+		{"UpdateFilter", func(t *testing.T, b Backend) {
+			filter, err := b.Filters.CreateFilter(ctx, "Work", todo.FilterCriteria{Tags: []string{"work"}, Query: "report"})
+			require.NoError(t, err)
+
+			updated, err := b.Filters.UpdateFilter(ctx, filter.ID, "work", todo.FilterCriteria{CategoryID: int64Ptr(2), Status: todo.FilterStatusAll})
+			require.NoError(t, err, "renaming a filter to its own name in another case")
+			assert.Equal(t, "work", updated.Name)
+			assert.Empty(t, updated.Criteria.Tags, "criteria are replaced")
+			assert.Empty(t, updated.Criteria.Query)
+			assert.Equal(t, int64Ptr(2), updated.Criteria.CategoryID)
+
+			got, err := b.Filters.GetFilter(ctx, filter.ID)
+			require.NoError(t, err)
+			assert.Equal(t, updated.Criteria, got.Criteria)
+			assert.Equal(t, todo.FilterStatusAll, got.Criteria.Status)
+		}},
+		{"DeleteFilter", func(t *testing.T, b Backend) {
+			filter, err := b.Filters.CreateFilter(ctx, "Errands", todo.FilterCriteria{})
+			require.NoError(t, err)
+
+			deleted, err := b.Filters.DeleteFilter(ctx, filter.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Errands", deleted.Name)
+			_, err = b.Filters.GetFilter(ctx, filter.ID)
+			assert.ErrorIs(t, err, todo.ErrSavedFilterNotFound)
+		}},
+		{"DuplicateNames", func(t *testing.T, b Backend) {
+			_, err := b.Filters.CreateFilter(ctx, "Errands", todo.FilterCriteria{})
+			require.NoError(t, err)
+			other, err := b.Filters.CreateFilter(ctx, "Work", todo.FilterCriteria{})
+			require.NoError(t, err)
+
+			_, err = b.Filters.CreateFilter(ctx, "errands", todo.FilterCriteria{})
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+			_, err = b.Filters.UpdateFilter(ctx, other.ID, "ERRANDS", todo.FilterCriteria{})
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+		}},
+		{"Errors", func(t *testing.T, b Backend) {
+			_, err := b.Filters.GetFilter(ctx, 999999)
+			assert.ErrorIs(t, err, todo.ErrSavedFilterNotFound)
+			_, err = b.Filters.GetFilterByName(ctx, "missing")
+			assert.ErrorIs(t, err, todo.ErrSavedFilterNotFound)
+			_, err = b.Filters.UpdateFilter(ctx, 999999, "Work", todo.FilterCriteria{})
+			assert.ErrorIs(t, err, todo.ErrSavedFilterNotFound)
+			_, err = b.Filters.DeleteFilter(ctx, 999999)
+			assert.ErrorIs(t, err, todo.ErrSavedFilterNotFound)
+
+			invalid := todo.Priority(42)
+			for name, criteria := range map[string]todo.FilterCriteria{
+				"priority": {MinPriority: &invalid},
+				"status":   {Status: "pending"},
+				"query":    {Query: strings.Repeat("x", 201)},
+				"due":      {DueBefore: strings.Repeat("x", 101)},
+			} {
+				_, err = b.Filters.CreateFilter(ctx, "Invalid "+name, criteria)
+				assert.ErrorIs(t, err, todo.ErrValidation, name)
+			}
+			_, err = b.Filters.CreateFilter(ctx, "  ", todo.FilterCriteria{})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Filters.CreateFilter(ctx, strings.Repeat("x", 101), todo.FilterCriteria{})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+
+			filters, err := b.Filters.ListFilters(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, filters, "a failed call records nothing")
+		}},
+	})
+}
@@ -0,0 +1,235 @@
+package todotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunSubtaskSuite checks the subtask methods of TodoService, including moves
+// that would create a cycle and both completion policies, against backends
+// built by factory
+func RunSubtaskSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	run(t, factory, []testCase{
+		{"AddSubtask", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "House", nil)
+			require.NoError(t, err)
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Chores"})
+			require.NoError(t, err)
+			parent, err := b.Todos.AddTodoToProject(ctx, "Spring clean", project.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AssignTodoToCategory(ctx, parent.ID, category.ID)
+			require.NoError(t, err)
+
+			due := date(2030, time.April, 1)
+			child, err := b.Todos.AddSubtask(ctx, parent.ID, "Wash windows", &due)
+			require.NoError(t, err)
+			assert.Equal(t, "Wash windows", child.Title)
+			assert.Equal(t, int64Ptr(parseID(t, parent.ID)), child.ParentID)
+			assert.Equal(t, int64Ptr(project.ID), child.ProjectID, "subtasks inherit the parent's project")
+			assert.Equal(t, int64Ptr(category.ID), child.CategoryID, "subtasks inherit the parent's category")
+			assertSameTime(t, &due, child.DueDate)
+			assertStoredTodo(t, b, child)
+
+			grandchild, err := b.Todos.AddSubtask(ctx, child.ID, "Buy squeegee", nil)
+			require.NoError(t, err)
+			assert.Equal(t, int64Ptr(parseID(t, child.ID)), grandchild.ParentID)
+
+			_, err = b.Todos.AddSubtask(ctx, "999999", "Orphan", nil)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.AddSubtask(ctx, parent.ID, "", nil)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+		}},
+		{"GetSubtasksAndProgress", func(t *testing.T, b Backend) {
+			parent, err := b.Todos.AddTodo(ctx, "Plan trip", nil)
+			require.NoError(t, err)
+			var children []string
+			for _, title := range []string{"Book flights", "Book hotel", "Pack"} {
+				child, err := b.Todos.AddSubtask(ctx, parent.ID, title, nil)
+				require.NoError(t, err)
+				children = append(children, child.ID)
+			}
+			_, err = b.Todos.AddSubtask(ctx, children[0], "Compare prices", nil)
+			require.NoError(t, err)
+			_, err = b.Todos.CompleteTodo(ctx, children[1])
+			require.NoError(t, err)
+
+			subtasks, err := b.Todos.GetSubtasks(ctx, parent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, children, ids(subtasks), "only direct subtasks, in the order added")
+			for _, item := range subtasks {
+				assertStoredTodo(t, b, item)
+			}
+			none, err := b.Todos.GetSubtasks(ctx, children[2])
+			require.NoError(t, err)
+			assert.Empty(t, none)
+			_, err = b.Todos.GetSubtasks(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+
+			progress, err := b.Todos.GetSubtaskProgress(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]todo.SubtaskProgress{
+				parent.ID:   {Done: 1, Total: 3},
+				children[0]: {Done: 0, Total: 1},
+			}, progress)
+			assert.Equal(t, "1/3 subtasks done", progress[parent.ID].String())
+		}},
+		{"MoveTodo", func(t *testing.T, b Backend) {
+			root, err := b.Todos.AddTodo(ctx, "Renovate", nil)
+			require.NoError(t, err)
+			child, err := b.Todos.AddSubtask(ctx, root.ID, "Kitchen", nil)
+			require.NoError(t, err)
+			grandchild, err := b.Todos.AddSubtask(ctx, child.ID, "Tiles", nil)
+			require.NoError(t, err)
+			other, err := b.Todos.AddTodo(ctx, "Garden", nil)
+			require.NoError(t, err)
+
+			moved, err := b.Todos.MoveTodo(ctx, grandchild.ID, stringPtr(other.ID))
+			require.NoError(t, err)
+			assert.Equal(t, int64Ptr(parseID(t, other.ID)), moved.ParentID)
+			assertStoredTodo(t, b, moved)
+
+			moved, err = b.Todos.MoveTodo(ctx, child.ID, nil)
+			require.NoError(t, err)
+			assert.Nil(t, moved.ParentID, "a nil parent makes a top-level todo")
+			moved, err = b.Todos.MoveTodo(ctx, root.ID, stringPtr(child.ID))
+			require.NoError(t, err)
+			assert.Equal(t, int64Ptr(parseID(t, child.ID)), moved.ParentID)
+
+			// child is now above root, so neither may move under the other's subtree
+			_, err = b.Todos.MoveTodo(ctx, child.ID, stringPtr(root.ID))
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.MoveTodo(ctx, child.ID, stringPtr(child.ID))
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			stored, err := b.Todos.GetTodo(ctx, child.ID)
+			require.NoError(t, err)
+			assert.Nil(t, stored.ParentID, "a rejected move changes nothing")
+
+			_, err = b.Todos.MoveTodo(ctx, child.ID, stringPtr("999999"))
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.MoveTodo(ctx, "999999", nil)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"CompleteBlockedByOpenSubtasks", func(t *testing.T, b Backend) {
+			parent, err := b.Todos.AddTodo(ctx, "Move house", nil)
+			require.NoError(t, err)
+			child, err := b.Todos.AddSubtask(ctx, parent.ID, "Pack boxes", nil)
+			require.NoError(t, err)
+			grandchild, err := b.Todos.AddSubtask(ctx, child.ID, "Buy tape", nil)
+			require.NoError(t, err)
+
+			_, err = b.Todos.CompleteTodo(ctx, parent.ID)
+			assert.ErrorIs(t, err, todo.ErrOpenSubtasks)
+			_, _, err = b.Todos.CompleteTodoWithPolicy(ctx, child.ID, todo.BlockOpenSubtasks)
+			assert.ErrorIs(t, err, todo.ErrOpenSubtasks, "open subtasks at any depth block completion")
+			stored, err := b.Todos.GetTodo(ctx, parent.ID)
+			require.NoError(t, err)
+			assert.Nil(t, stored.CompletedAt, "a blocked completion changes nothing")
+
+			for _, id := range []string{grandchild.ID, child.ID, parent.ID} {
+				completed, err := b.Todos.CompleteTodo(ctx, id)
+				require.NoError(t, err)
+				assert.NotNil(t, completed.CompletedAt)
+			}
+		}},
+		{"CompleteCascadesToOpenSubtasks", func(t *testing.T, b Backend) {
+			parent, err := b.Todos.AddTodo(ctx, "Launch", nil)
+			require.NoError(t, err)
+			done, err := b.Todos.AddSubtask(ctx, parent.ID, "Write copy", nil)
+			require.NoError(t, err)
+			done, err = b.Todos.CompleteTodo(ctx, done.ID)
+			require.NoError(t, err)
+			child, err := b.Todos.AddSubtask(ctx, parent.ID, "Design", nil)
+			require.NoError(t, err)
+			grandchild, err := b.Todos.AddSubtask(ctx, child.ID, "Pick colours", nil)
+			require.NoError(t, err)
+			unrelated, err := b.Todos.AddTodo(ctx, "Unrelated", nil)
+			require.NoError(t, err)
+
+			completed, _, err := b.Todos.CompleteTodoWithPolicy(ctx, parent.ID, todo.CompleteOpenSubtasks)
+			require.NoError(t, err)
+			require.NotNil(t, completed.CompletedAt)
+			for _, id := range []string{child.ID, grandchild.ID} {
+				stored, err := b.Todos.GetTodo(ctx, id)
+				require.NoError(t, err)
+				assertSameTime(t, completed.CompletedAt, stored.CompletedAt, "subtask %s", id)
+			}
+			stored, err := b.Todos.GetTodo(ctx, done.ID)
+			require.NoError(t, err)
+			assertSameTime(t, done.CompletedAt, stored.CompletedAt, "an already completed subtask keeps its time")
+
+			active, err := b.Todos.GetActiveTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{unrelated.ID}, ids(active))
+		}},
+		{"DeleteTodoDeletesSubtasks", func(t *testing.T, b Backend) {
+			parent, err := b.Todos.AddTodo(ctx, "Old plan", nil)
+			require.NoError(t, err)
+			child, err := b.Todos.AddSubtask(ctx, parent.ID, "Step one", nil)
+			require.NoError(t, err)
+			grandchild, err := b.Todos.AddSubtask(ctx, child.ID, "Step one a", nil)
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, grandchild.ID, []string{"plan"})
+			require.NoError(t, err)
+			kept, err := b.Todos.AddTodo(ctx, "New plan", nil)
+			require.NoError(t, err)
+
+			_, err = b.Todos.DeleteTodo(ctx, parent.ID)
+			require.NoError(t, err)
+			for _, id := range []string{child.ID, grandchild.ID} {
+				_, err = b.Todos.GetTodo(ctx, id)
+				assert.ErrorIs(t, err, todo.ErrTodoNotFound, "subtask %s", id)
+			}
+			all, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{kept.ID}, ids(all))
+			tags, err := b.Tags.GetAllTodoTags(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, tags)
+		}},
+		{"RecurringSubtaskKeepsParent", func(t *testing.T, b Backend) {
+			parent, err := b.Todos.AddTodo(ctx, "Garden", nil)
+			require.NoError(t, err)
+			due := date(2030, time.January, 6)
+			child, err := b.Todos.AddSubtask(ctx, parent.ID, "Water plants", &due)
+			require.NoError(t, err)
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: child.ID, Frequency: "weekly", Interval: 1})
+			require.NoError(t, err)
+
+			_, next, err := b.Todos.CompleteTodoWithNext(ctx, child.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			assert.Equal(t, int64Ptr(parseID(t, parent.ID)), next.ParentID)
+			assertStoredTodo(t, b, *next)
+		}},
+		{"CompleteCascadeContinuesRecurringSubtask", func(t *testing.T, b Backend) {
+			parent, err := b.Todos.AddTodo(ctx, "Garden", nil)
+			require.NoError(t, err)
+			due := date(2030, time.January, 6)
+			child, err := b.Todos.AddSubtask(ctx, parent.ID, "Water plants", &due)
+			require.NoError(t, err)
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: child.ID, Frequency: "weekly", Interval: 1})
+			require.NoError(t, err)
+
+			_, _, err = b.Todos.CompleteTodoWithPolicy(ctx, parent.ID, todo.CompleteOpenSubtasks)
+			require.NoError(t, err)
+			subtasks, err := b.Todos.GetSubtasks(ctx, parent.ID)
+			require.NoError(t, err)
+			require.Len(t, subtasks, 2)
+			assert.Equal(t, child.ID, subtasks[0].ID)
+			assert.NotNil(t, subtasks[0].CompletedAt)
+			next := subtasks[1]
+			assert.Nil(t, next.CompletedAt)
+			assert.Equal(t, "Water plants", next.Title)
+			require.NotNil(t, next.DueDate)
+			assert.True(t, next.DueDate.Equal(due.AddDate(0, 0, 7)), "next due %v", next.DueDate)
+		}},
+	})
+}
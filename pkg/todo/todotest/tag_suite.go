@@ -0,0 +1,232 @@
+package todotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunTagServiceSuite checks every TagService method, including edge cases and
+// error paths, against backends built by factory
+func RunTagServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	run(t, factory, []testCase{
+		{"AddAndRemoveTags", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Book flights", nil)
+			require.NoError(t, err)
+
+			tags, err := b.Tags.AddTags(ctx, item.ID, []string{"Travel", "#urgent", " travel "})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"travel", "urgent"}, tagNames(tags))
+			for _, tag := range tags {
+				assert.NotZero(t, tag.ID)
+			}
+
+			// Adding a tag the todo already has is not an error
+			tags, err = b.Tags.AddTags(ctx, item.ID, []string{"urgent", "work"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"travel", "urgent", "work"}, tagNames(tags))
+
+			stored, err := b.Tags.GetTodoTags(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tags, stored)
+
+			left, err := b.Tags.RemoveTags(ctx, item.ID, []string{"URGENT"})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"travel", "work"}, tagNames(left))
+
+			_, err = b.Tags.RemoveTags(ctx, item.ID, []string{"work", "nonexistent"})
+			assert.ErrorIs(t, err, todo.ErrTagNotFound)
+			stored, err = b.Tags.GetTodoTags(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"travel", "work"}, tagNames(stored), "a failed removal removes nothing")
+		}},
+		{"TagValidation", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Book flights", nil)
+			require.NoError(t, err)
+
+			for _, names := range [][]string{nil, {""}, {"#"}, {"a,b"}, {string(make([]byte, 65))}} {
+				_, err = b.Tags.AddTags(ctx, item.ID, names)
+				assert.ErrorIs(t, err, todo.ErrValidation, "%q", names)
+			}
+			_, err = b.Tags.AddTags(ctx, "999999", []string{"travel"})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Tags.GetTodoTags(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+
+			tags, err := b.Tags.ListTags(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, tags)
+		}},
+		{"ListTags", func(t *testing.T, b Backend) {
+			first, err := b.Todos.AddTodo(ctx, "Book flights", nil)
+			require.NoError(t, err)
+			second, err := b.Todos.AddTodo(ctx, "Pack bags", nil)
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, first.ID, []string{"travel", "work"})
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, second.ID, []string{"travel", "home"})
+			require.NoError(t, err)
+			_, err = b.Tags.RemoveTags(ctx, second.ID, []string{"home"})
+			require.NoError(t, err)
+
+			tags, err := b.Tags.ListTags(ctx)
+			require.NoError(t, err)
+			counts := map[string]int{}
+			var names []string
+			for _, tag := range tags {
+				counts[tag.Name] = tag.Todos
+				names = append(names, tag.Name)
+				assert.WithinDuration(t, time.Now(), tag.CreatedAt, 5*time.Second)
+			}
+			assert.Equal(t, []string{"home", "travel", "work"}, names)
+			assert.Equal(t, map[string]int{"home": 0, "travel": 2, "work": 1}, counts)
+
+			all, err := b.Tags.GetAllTodoTags(ctx)
+			require.NoError(t, err)
+			assert.Len(t, all, 2)
+			assert.Equal(t, []string{"travel", "work"}, tagNames(all[first.ID]))
+			assert.Equal(t, []string{"travel"}, tagNames(all[second.ID]))
+		}},
+		{"RenameTag", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Book flights", nil)
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, item.ID, []string{"travel", "work"})
+			require.NoError(t, err)
+
+			renamed, err := b.Tags.RenameTag(ctx, "Travel", "Trips")
+			require.NoError(t, err)
+			assert.Equal(t, "trips", renamed.Name)
+			tags, err := b.Tags.GetTodoTags(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"trips", "work"}, tagNames(tags))
+			assert.Equal(t, renamed.ID, tags[0].ID)
+
+			_, err = b.Tags.RenameTag(ctx, "trips", "work")
+			assert.ErrorIs(t, err, todo.ErrDuplicateName)
+			_, err = b.Tags.RenameTag(ctx, "travel", "holidays")
+			assert.ErrorIs(t, err, todo.ErrTagNotFound)
+			_, err = b.Tags.RenameTag(ctx, "trips", "")
+			assert.ErrorIs(t, err, todo.ErrValidation)
+		}},
+		{"MergeTags", func(t *testing.T, b Backend) {
+			both, err := b.Todos.AddTodo(ctx, "Book flights", nil)
+			require.NoError(t, err)
+			sourceOnly, err := b.Todos.AddTodo(ctx, "Pack bags", nil)
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, both.ID, []string{"trip", "travel"})
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, sourceOnly.ID, []string{"trip"})
+			require.NoError(t, err)
+
+			merged, err := b.Tags.MergeTags(ctx, "trip", "travel")
+			require.NoError(t, err)
+			assert.Equal(t, "travel", merged.Name)
+
+			tags, err := b.Tags.ListTags(ctx)
+			require.NoError(t, err)
+			require.Len(t, tags, 1)
+			assert.Equal(t, "travel", tags[0].Name)
+			assert.Equal(t, 2, tags[0].Todos)
+			for _, id := range []string{both.ID, sourceOnly.ID} {
+				tags, err := b.Tags.GetTodoTags(ctx, id)
+				require.NoError(t, err)
+				assert.Equal(t, []string{"travel"}, tagNames(tags))
+			}
+
+			_, err = b.Tags.MergeTags(ctx, "trip", "travel")
+			assert.ErrorIs(t, err, todo.ErrTagNotFound, "the source is gone")
+			_, err = b.Tags.MergeTags(ctx, "travel", "holidays")
+			assert.ErrorIs(t, err, todo.ErrTagNotFound, "the target must exist")
+			_, err = b.Tags.MergeTags(ctx, "travel", "Travel")
+			assert.ErrorIs(t, err, todo.ErrValidation)
+		}},
+		{"GetTodosByTags", func(t *testing.T, b Backend) {
+			add := func(title string, priority todo.Priority, tags ...string) string {
+				item, err := b.Todos.AddTodo(ctx, title, nil)
+				require.NoError(t, err)
+				_, err = b.Todos.SetPriority(ctx, item.ID, priority)
+				require.NoError(t, err)
+				if len(tags) > 0 {
+					_, err = b.Tags.AddTags(ctx, item.ID, tags)
+					require.NoError(t, err)
+				}
+				return item.ID
+			}
+			travel := add("Book flights", todo.PriorityLow, "travel")
+			both := add("Expense trip", todo.PriorityHigh, "travel", "work")
+			work := add("Write report", todo.PriorityNone, "work")
+			add("Untagged", todo.PriorityUrgent)
+
+			anyOf, err := b.Tags.GetTodosByTags(ctx, []string{"travel", "work"}, false)
+			require.NoError(t, err)
+			assert.Equal(t, []string{both, travel, work}, ids(anyOf))
+			for _, item := range anyOf {
+				assertStoredTodo(t, b, item)
+			}
+
+			allOf, err := b.Tags.GetTodosByTags(ctx, []string{"Travel", "work"}, true)
+			require.NoError(t, err)
+			assert.Equal(t, []string{both}, ids(allOf))
+
+			none, err := b.Tags.GetTodosByTags(ctx, []string{"travel", "nonexistent"}, true)
+			require.NoError(t, err)
+			assert.Empty(t, none)
+
+			_, err = b.Tags.GetTodosByTags(ctx, nil, false)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+		}},
+		{"DeleteTodoRemovesTags", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Book flights", nil)
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, item.ID, []string{"travel"})
+			require.NoError(t, err)
+
+			_, err = b.Todos.DeleteTodo(ctx, item.ID)
+			require.NoError(t, err)
+
+			tags, err := b.Tags.ListTags(ctx)
+			require.NoError(t, err)
+			require.Len(t, tags, 1)
+			assert.Equal(t, 0, tags[0].Todos)
+			all, err := b.Tags.GetAllTodoTags(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, all)
+		}},
+		{"RecurringTodosKeepTags", func(t *testing.T, b Backend) {
+			due := date(2030, time.January, 31)
+			first, err := b.Todos.AddTodo(ctx, "Water plants", &due)
+			require.NoError(t, err)
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: first.ID, Frequency: "weekly", Interval: 1})
+			require.NoError(t, err)
+			_, err = b.Tags.AddTags(ctx, first.ID, []string{"home", "garden"})
+			require.NoError(t, err)
+
+			_, next, err := b.Todos.CompleteTodoWithNext(ctx, first.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			tags, err := b.Tags.GetTodoTags(ctx, next.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"garden", "home"}, tagNames(tags))
+
+			tagged, err := b.Tags.GetTodosByTags(ctx, []string{"garden"}, false)
+			require.NoError(t, err)
+			assert.Len(t, tagged, 2, "both occurrences are listed by tag")
+		}},
+	})
+}
+
+// tagNames returns the names of tags in order
+func tagNames(tags []todo.Tag) []string {
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
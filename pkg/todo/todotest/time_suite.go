@@ -0,0 +1,197 @@
+package todotest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunTimeServiceSuite checks TimeService, including the single running timer,
+// against backends built by factory
+func RunTimeServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	add := func(t *testing.T, b Backend, title string) string {
+		item, err := b.Todos.AddTodo(ctx, title, nil)
+		require.NoError(t, err)
+		return item.ID
+	}
+
+	run(t, factory, []testCase{
+		{"StartAndStopTimer", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+			running, err := b.TimeEntries.GetRunningTimer(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, running, "no timer runs at first")
+			_, err = b.TimeEntries.StopTimer(ctx)
+			assert.ErrorIs(t, err, todo.ErrNoTimerRunning)
+
+			started, err := b.TimeEntries.StartTimer(ctx, report, "first draft")
+			require.NoError(t, err)
+			assert.NotZero(t, started.ID)
+			assert.Equal(t, report, started.TodoID)
+			assert.Equal(t, "first draft", started.Note)
+			assert.True(t, started.Running())
+			assert.WithinDuration(t, time.Now(), started.StartedAt, 5*time.Second)
+
+			running, err = b.TimeEntries.GetRunningTimer(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, running)
+			assert.Equal(t, started.ID, running.ID)
+			assert.Equal(t, report, running.TodoID)
+			assert.True(t, running.Running())
+
+			stopped, err := b.TimeEntries.StopTimer(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, started.ID, stopped.ID)
+			require.NotNil(t, stopped.EndedAt)
+			assert.False(t, stopped.StartedAt.After(*stopped.EndedAt))
+
+			running, err = b.TimeEntries.GetRunningTimer(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, running)
+			entries, err := b.TimeEntries.GetTodoTimeEntries(ctx, report)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, stopped.ID, entries[0].ID)
+			assertSameTime(t, stopped.EndedAt, entries[0].EndedAt)
+		}},
+		{"OneRunningTimer", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+			review := add(t, b, "Review budget")
+
+			started, err := b.TimeEntries.StartTimer(ctx, report, "")
+			require.NoError(t, err)
+			_, err = b.TimeEntries.StartTimer(ctx, review, "")
+			assert.ErrorIs(t, err, todo.ErrTimerRunning)
+			_, err = b.TimeEntries.StartTimer(ctx, report, "")
+			assert.ErrorIs(t, err, todo.ErrTimerRunning, "not even on the same todo")
+
+			running, err := b.TimeEntries.GetRunningTimer(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, running)
+			assert.Equal(t, started.ID, running.ID)
+
+			_, err = b.TimeEntries.StopTimer(ctx)
+			require.NoError(t, err)
+			_, err = b.TimeEntries.StartTimer(ctx, review, "")
+			assert.NoError(t, err, "a stopped timer makes way for the next")
+		}},
+		{"LogTime", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+			start := date(2020, time.March, 2)
+
+			logged, err := b.TimeEntries.LogTime(ctx, report, start, 90*time.Minute, "call with client")
+			require.NoError(t, err)
+			assert.NotZero(t, logged.ID)
+			assert.False(t, logged.Running())
+			assert.Equal(t, 90*time.Minute, logged.Duration(time.Now()))
+
+			// Logged time does not count as a running timer
+			running, err := b.TimeEntries.GetRunningTimer(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, running)
+			_, err = b.TimeEntries.StartTimer(ctx, report, "")
+			require.NoError(t, err)
+			_, err = b.TimeEntries.LogTime(ctx, report, start.Add(-time.Hour), 30*time.Minute, "")
+			require.NoError(t, err, "time can be logged while a timer runs")
+
+			entries, err := b.TimeEntries.GetTodoTimeEntries(ctx, report)
+			require.NoError(t, err)
+			require.Len(t, entries, 3)
+			assertSameTime(t, timePtr(start.Add(-time.Hour)), &entries[0].StartedAt, "oldest first")
+			assertSameTime(t, &start, &entries[1].StartedAt)
+			assert.Equal(t, "call with client", entries[1].Note)
+			assertSameTime(t, timePtr(start.Add(90*time.Minute)), entries[1].EndedAt)
+			assert.True(t, entries[2].Running())
+		}},
+		{"GetTimeEntriesInRange", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+			monday := date(2020, time.March, 2)
+			before, err := b.TimeEntries.LogTime(ctx, report, monday.Add(-48*time.Hour), time.Hour, "")
+			require.NoError(t, err)
+			overlapping, err := b.TimeEntries.LogTime(ctx, report, monday.Add(-30*time.Minute), time.Hour, "")
+			require.NoError(t, err)
+			inside, err := b.TimeEntries.LogTime(ctx, report, monday.Add(2*time.Hour), time.Hour, "")
+			require.NoError(t, err)
+			after, err := b.TimeEntries.LogTime(ctx, report, monday.Add(30*time.Hour), time.Hour, "")
+			require.NoError(t, err)
+			running, err := b.TimeEntries.StartTimer(ctx, report, "")
+			require.NoError(t, err)
+
+			entries, err := b.TimeEntries.GetTimeEntries(ctx, monday, monday.Add(24*time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, []int64{overlapping.ID, inside.ID}, entryIDs(entries))
+
+			entries, err = b.TimeEntries.GetTimeEntries(ctx, monday, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, []int64{overlapping.ID, inside.ID, after.ID, running.ID}, entryIDs(entries), "the running timer overlaps up to now")
+			assert.NotContains(t, entryIDs(entries), before.ID)
+		}},
+		{"DeleteTimeEntry", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+			logged, err := b.TimeEntries.LogTime(ctx, report, date(2020, time.March, 2), time.Hour, "wrong todo")
+			require.NoError(t, err)
+
+			deleted, err := b.TimeEntries.DeleteTimeEntry(ctx, logged.ID)
+			require.NoError(t, err)
+			assert.Equal(t, logged.ID, deleted.ID)
+			assert.Equal(t, "wrong todo", deleted.Note)
+			entries, err := b.TimeEntries.GetTodoTimeEntries(ctx, report)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			_, err = b.TimeEntries.DeleteTimeEntry(ctx, logged.ID)
+			assert.ErrorIs(t, err, todo.ErrTimeEntryNotFound)
+		}},
+		{"DeletingTodoDeletesEntries", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+			_, err := b.TimeEntries.StartTimer(ctx, report, "")
+			require.NoError(t, err)
+
+			_, err = b.Todos.DeleteTodo(ctx, report)
+			require.NoError(t, err)
+			running, err := b.TimeEntries.GetRunningTimer(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, running, "the running timer goes with its todo")
+		}},
+		{"Errors", func(t *testing.T, b Backend) {
+			report := add(t, b, "Write report")
+
+			_, err := b.TimeEntries.StartTimer(ctx, "999999", "")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.TimeEntries.LogTime(ctx, "999999", date(2020, time.March, 2), time.Hour, "")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.TimeEntries.GetTodoTimeEntries(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+
+			_, err = b.TimeEntries.LogTime(ctx, report, date(2020, time.March, 2), 0, "")
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.TimeEntries.LogTime(ctx, report, date(2020, time.March, 2), time.Hour, strings.Repeat("x", 501))
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.TimeEntries.StartTimer(ctx, report, strings.Repeat("x", 501))
+			assert.ErrorIs(t, err, todo.ErrValidation)
+
+			entries, err := b.TimeEntries.GetTodoTimeEntries(ctx, report)
+			require.NoError(t, err)
+			assert.Empty(t, entries, "a failed call records nothing")
+		}},
+	})
+}
+
+// entryIDs returns the IDs of entries in order
+func entryIDs(entries []todo.TimeEntry) []int64 {
+	result := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.ID)
+	}
+	return result
+}
+
+func timePtr(v time.Time) *time.Time { return &v }
@@ -0,0 +1,1038 @@
+package todotest
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunTodoServiceSuite checks every TodoService method, including edge cases
+// and error paths, against backends built by factory
+func RunTodoServiceSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	run(t, factory, []testCase{
+		{"AddTodo", func(t *testing.T, b Backend) {
+			due := date(2030, time.March, 14)
+			item, err := b.Todos.AddTodo(ctx, "Buy milk", &due)
+			require.NoError(t, err)
+			assert.NotEmpty(t, item.ID)
+			assert.Equal(t, "Buy milk", item.Title)
+			assert.Nil(t, item.CompletedAt)
+			assertSameTime(t, &due, item.DueDate)
+			assert.WithinDuration(t, time.Now(), item.CreatedDate, 5*time.Second)
+			assert.Nil(t, item.ReferenceID)
+			assert.Nil(t, item.ProjectID)
+			assert.Nil(t, item.CategoryID)
+
+			stored, err := b.Todos.GetTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assertSameTodo(t, item, stored)
+
+			other, err := b.Todos.AddTodo(ctx, "No due date", nil)
+			require.NoError(t, err)
+			assert.NotEqual(t, item.ID, other.ID)
+			assert.Nil(t, other.DueDate)
+		}},
+		{"AddTodoRejectsEmptyTitle", func(t *testing.T, b Backend) {
+			_, err := b.Todos.AddTodo(ctx, "", nil)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.AddTodoToProject(ctx, "", 1, nil)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.AddTodoToCategory(ctx, "", 1, nil)
+			assert.ErrorIs(t, err, todo.ErrValidation)
+
+			all, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, all)
+		}},
+		{"AddTodoToProject", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+
+			item, err := b.Todos.AddTodoToProject(ctx, "Plant tomatoes", project.ID, nil)
+			require.NoError(t, err)
+			require.NotNil(t, item.ProjectID)
+			assert.Equal(t, project.ID, *item.ProjectID)
+			assert.Nil(t, item.CategoryID)
+
+			stored, err := b.Todos.GetTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assertSameTodo(t, item, stored)
+		}},
+		{"AddTodoToCategory", func(t *testing.T, b Backend) {
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Errands"})
+			require.NoError(t, err)
+
+			item, err := b.Todos.AddTodoToCategory(ctx, "Post letter", category.ID, nil)
+			require.NoError(t, err)
+			require.NotNil(t, item.CategoryID)
+			assert.Equal(t, category.ID, *item.CategoryID)
+			assert.Nil(t, item.ProjectID)
+
+			stored, err := b.Todos.GetTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assertSameTodo(t, item, stored)
+
+			_, err = b.Todos.AddTodoToCategory(ctx, "Lost", 999999, nil)
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+			items, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Len(t, items, 1, "nothing is added for a missing category")
+		}},
+		{"CreateTodo", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+			due := time.Date(2030, time.March, 8, 17, 45, 0, 0, time.UTC)
+
+			item, err := b.Todos.CreateTodo(ctx, todo.NewTodo{
+				Title:     "Plant tomatoes",
+				DueDate:   &due,
+				DueAllDay: true,
+				ProjectID: &project.ID,
+				Priority:  todo.PriorityHigh,
+				Notes:     "- [ ] buy stakes",
+			})
+			require.NoError(t, err)
+			want := time.Date(2030, time.March, 8, 0, 0, 0, 0, time.UTC)
+			assertSameTime(t, &want, item.DueDate, "an all-day date drops its time of day")
+			assert.True(t, item.DueAllDay)
+			assert.Equal(t, &project.ID, item.ProjectID)
+			assert.Equal(t, todo.PriorityHigh, item.Priority)
+			assert.Equal(t, "- [ ] buy stakes", item.Notes)
+			assertStoredTodo(t, b, item)
+
+			subtask, err := b.Todos.CreateTodo(ctx, todo.NewTodo{Title: "Buy stakes", ParentID: &item.ID, Priority: todo.PriorityLow})
+			require.NoError(t, err)
+			require.NotNil(t, subtask.ParentID)
+			assert.Equal(t, item.ID, strconv.FormatInt(*subtask.ParentID, 10))
+			assert.Equal(t, &project.ID, subtask.ProjectID, "a subtask goes in its parent's project")
+			assert.Equal(t, todo.PriorityLow, subtask.Priority)
+			assertStoredTodo(t, b, subtask)
+
+			missing := int64(999999)
+			_, err = b.Todos.CreateTodo(ctx, todo.NewTodo{Title: "Lost", ProjectID: &missing})
+			assert.ErrorIs(t, err, todo.ErrProjectNotFound)
+			missingParent := "999999"
+			_, err = b.Todos.CreateTodo(ctx, todo.NewTodo{Title: "Lost", ParentID: &missingParent})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			for name, invalid := range map[string]todo.NewTodo{
+				"title":    {},
+				"priority": {Title: "Lost", Priority: todo.Priority(42)},
+				"notes":    {Title: "Lost", Notes: strings.Repeat("x", 65536)},
+				"all-day":  {Title: "Lost", DueAllDay: true},
+				"subtask":  {Title: "Lost", ParentID: &item.ID, ProjectID: &project.ID},
+			} {
+				_, err = b.Todos.CreateTodo(ctx, invalid)
+				assert.ErrorIs(t, err, todo.ErrValidation, name)
+			}
+			items, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Len(t, items, 2, "a failed call adds nothing")
+		}},
+		{"GetTodoMissing", func(t *testing.T, b Backend) {
+			_, err := b.Todos.GetTodo(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.GetTodo(ctx, "not-an-id")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"NonNumericIDNotFound", func(t *testing.T, b Backend) {
+			// IDs come straight from tool input, so one that is not a number
+			// is reported like any other missing todo
+			item, err := b.Todos.AddTodo(ctx, "Real", nil)
+			require.NoError(t, err)
+			const id = "abc"
+			_, err = b.Todos.GetTodo(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetTodo")
+			_, err = b.Todos.SetDueDate(ctx, id, date(2030, time.March, 1))
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "SetDueDate")
+			_, err = b.Todos.SetPriority(ctx, id, todo.PriorityLow)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "SetPriority")
+			_, err = b.Todos.UpdateTodo(ctx, id, todo.TodoPatch{Title: stringPtr("Renamed")})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "UpdateTodo")
+			_, err = b.Todos.SetNotes(ctx, id, "notes")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "SetNotes")
+			_, err = b.Todos.CompleteTodo(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "CompleteTodo")
+			_, err = b.Todos.UnCompleteTodo(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "UnCompleteTodo")
+			_, err = b.Todos.DeleteTodo(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "DeleteTodo")
+			_, err = b.Todos.RemoveTodoFromCategory(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "RemoveTodoFromCategory")
+			_, err = b.Todos.AddSubtask(ctx, id, "Orphan", nil)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "AddSubtask")
+			_, err = b.Todos.CreateTodo(ctx, todo.NewTodo{Title: "Orphan", ParentID: stringPtr(id)})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "CreateTodo subtask")
+			_, err = b.Todos.MoveTodo(ctx, id, nil)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "MoveTodo")
+			_, err = b.Todos.MoveTodo(ctx, item.ID, stringPtr(id))
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "MoveTodo parent")
+			_, err = b.Todos.GetSubtasks(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetSubtasks")
+			_, err = b.Todos.AddBlocker(ctx, id, item.ID)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "AddBlocker")
+			_, err = b.Todos.AddBlocker(ctx, item.ID, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "AddBlocker blocker")
+			_, err = b.Todos.GetBlockers(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetBlockers")
+			_, err = b.Todos.GetDependents(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetDependents")
+			_, err = b.Todos.GetRecurrencePatternsForTodo(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetRecurrencePatterns")
+			_, err = b.Tags.AddTags(ctx, id, []string{"travel"})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "AddTags")
+			_, err = b.Tags.GetTodoTags(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetTodoTags")
+			_, err = b.TimeEntries.StartTimer(ctx, id, "")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "StartTimer")
+			_, err = b.TimeEntries.LogTime(ctx, id, date(2020, time.March, 2), time.Hour, "")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "LogTime")
+			_, err = b.TimeEntries.GetTodoTimeEntries(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetTodoTimeEntries")
+			_, err = b.Reminders.SetReminders(ctx, id, []int{0})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "SetReminders")
+			_, err = b.Reminders.GetReminders(ctx, id)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "GetReminders")
+		}},
+		{"EmptyLists", func(t *testing.T, b Backend) {
+			lists := map[string]func() ([]todo.TodoItem, error){
+				"GetAllTodos":           func() ([]todo.TodoItem, error) { return b.Todos.GetAllTodos(ctx) },
+				"GetActiveTodos":        func() ([]todo.TodoItem, error) { return b.Todos.GetActiveTodos(ctx) },
+				"GetCompletedTodos":     func() ([]todo.TodoItem, error) { return b.Todos.GetCompletedTodos(ctx) },
+				"GetTodosByProject":     func() ([]todo.TodoItem, error) { return b.Todos.GetTodosByProject(ctx, 1) },
+				"GetTodosByCategory":    func() ([]todo.TodoItem, error) { return b.Todos.GetTodosByCategory(ctx, 1) },
+				"GetUncategorizedTodos": func() ([]todo.TodoItem, error) { return b.Todos.GetUncategorizedTodos(ctx) },
+				"TitleSearchTodo":       func() ([]todo.TodoItem, error) { return b.Todos.TitleSearchTodo(ctx, "anything", false) },
+			}
+			for name, list := range lists {
+				items, err := list()
+				assert.NoError(t, err, name)
+				assert.Empty(t, items, name)
+			}
+		}},
+		{"ListsReturnEveryColumn", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Home", nil)
+			require.NoError(t, err)
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Chores"})
+			require.NoError(t, err)
+
+			due := date(2030, time.June, 1)
+			item, err := b.Todos.AddTodoToProject(ctx, "Fix shelf", project.ID, &due)
+			require.NoError(t, err)
+			_, err = b.Todos.AssignTodoToCategory(ctx, item.ID, category.ID)
+			require.NoError(t, err)
+			_, err = b.Todos.SetPriority(ctx, item.ID, todo.PriorityHigh)
+			require.NoError(t, err)
+			_, err = b.Todos.SetNotes(ctx, item.ID, "Use the long screws")
+			require.NoError(t, err)
+
+			lists := map[string]func() ([]todo.TodoItem, error){
+				"GetAllTodos":        func() ([]todo.TodoItem, error) { return b.Todos.GetAllTodos(ctx) },
+				"GetActiveTodos":     func() ([]todo.TodoItem, error) { return b.Todos.GetActiveTodos(ctx) },
+				"GetTodosByProject":  func() ([]todo.TodoItem, error) { return b.Todos.GetTodosByProject(ctx, project.ID) },
+				"GetTodosByCategory": func() ([]todo.TodoItem, error) { return b.Todos.GetTodosByCategory(ctx, category.ID) },
+				"TitleSearchTodo":    func() ([]todo.TodoItem, error) { return b.Todos.TitleSearchTodo(ctx, "shelf", true) },
+				"SearchTodos":        func() ([]todo.TodoItem, error) { return b.Todos.SearchTodos(ctx, "screws", true) },
+				"GetProjectTodos":    func() ([]todo.TodoItem, error) { return b.Projects.GetProjectTodos(ctx, project.ID) },
+				"FindTodosByCategory": func() ([]todo.TodoItem, error) {
+					return b.Categories.FindTodosByCategory(ctx, category.ID)
+				},
+			}
+			for name, list := range lists {
+				items, err := list()
+				require.NoError(t, err, name)
+				require.Len(t, items, 1, name)
+				t.Run(name, func(t *testing.T) { assertStoredTodo(t, b, items[0]) })
+			}
+
+			_, err = b.Todos.CompleteTodo(ctx, item.ID)
+			require.NoError(t, err)
+			completed, err := b.Todos.GetCompletedTodos(ctx)
+			require.NoError(t, err)
+			require.Len(t, completed, 1)
+			assertStoredTodo(t, b, completed[0])
+		}},
+		{"CompleteAndUnComplete", func(t *testing.T, b Backend) {
+			first, err := b.Todos.AddTodo(ctx, "First", nil)
+			require.NoError(t, err)
+			second, err := b.Todos.AddTodo(ctx, "Second", nil)
+			require.NoError(t, err)
+
+			completed, err := b.Todos.CompleteTodo(ctx, first.ID)
+			require.NoError(t, err)
+			assert.Equal(t, first.ID, completed.ID)
+			assert.Equal(t, "First", completed.Title)
+			require.NotNil(t, completed.CompletedAt)
+			assert.WithinDuration(t, time.Now(), *completed.CompletedAt, 5*time.Second)
+
+			active, err := b.Todos.GetActiveTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{second.ID}, ids(active))
+			done, err := b.Todos.GetCompletedTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{first.ID}, ids(done))
+
+			reopened, err := b.Todos.UnCompleteTodo(ctx, first.ID)
+			require.NoError(t, err)
+			assert.Equal(t, first.ID, reopened.ID)
+			assert.Nil(t, reopened.CompletedAt)
+
+			active, err = b.Todos.GetActiveTodos(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{first.ID, second.ID}, ids(active))
+			done, err = b.Todos.GetCompletedTodos(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, done)
+		}},
+		{"CompleteMissing", func(t *testing.T, b Backend) {
+			_, err := b.Todos.CompleteTodo(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.UnCompleteTodo(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"SetDueDate", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Renew passport", nil)
+			require.NoError(t, err)
+
+			due := date(2031, time.January, 31)
+			updated, err := b.Todos.SetDueDate(ctx, item.ID, due)
+			require.NoError(t, err)
+			assert.Equal(t, item.ID, updated.ID)
+			assert.Equal(t, "Renew passport", updated.Title)
+			assertSameTime(t, &due, updated.DueDate)
+
+			stored, err := b.Todos.GetTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assertSameTime(t, &due, stored.DueDate)
+
+			_, err = b.Todos.SetDueDate(ctx, "999999", due)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"SetPriority", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "File taxes", nil)
+			require.NoError(t, err)
+			assert.Equal(t, todo.PriorityNone, item.Priority)
+
+			updated, err := b.Todos.SetPriority(ctx, item.ID, todo.PriorityUrgent)
+			require.NoError(t, err)
+			assert.Equal(t, item.ID, updated.ID)
+			assert.Equal(t, "File taxes", updated.Title)
+			assert.Equal(t, todo.PriorityUrgent, updated.Priority)
+			assertStoredTodo(t, b, updated)
+
+			cleared, err := b.Todos.SetPriority(ctx, item.ID, todo.PriorityNone)
+			require.NoError(t, err)
+			assert.Equal(t, todo.PriorityNone, cleared.Priority)
+
+			_, err = b.Todos.SetPriority(ctx, item.ID, todo.Priority(7))
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.SetPriority(ctx, "999999", todo.PriorityLow)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"UpdateTodo", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Home", nil)
+			require.NoError(t, err)
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Chores"})
+			require.NoError(t, err)
+			due := date(2030, time.May, 1)
+			item, err := b.Todos.AddTodo(ctx, "Fix sink", &due)
+			require.NoError(t, err)
+			_, err = b.Todos.SetPriority(ctx, item.ID, todo.PriorityHigh)
+			require.NoError(t, err)
+
+			updated, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{
+				Title:      stringPtr("Fix kitchen sink"),
+				ProjectID:  int64Ptr(project.ID),
+				CategoryID: int64Ptr(category.ID),
+				Notes:      stringPtr("Washer is worn"),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, item.ID, updated.ID)
+			assert.Equal(t, "Fix kitchen sink", updated.Title)
+			assertSameTime(t, &due, updated.DueDate, "fields not in the patch are kept")
+			assert.Equal(t, todo.PriorityHigh, updated.Priority)
+			assert.Equal(t, int64Ptr(project.ID), updated.ProjectID)
+			assert.Equal(t, int64Ptr(category.ID), updated.CategoryID)
+			assert.Equal(t, "Washer is worn", updated.Notes)
+			assertStoredTodo(t, b, updated)
+
+			later := date(2030, time.June, 1)
+			moved, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{DueDate: &later})
+			require.NoError(t, err)
+			assertSameTime(t, &later, moved.DueDate)
+			assert.Equal(t, "Fix kitchen sink", moved.Title)
+
+			cleared, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{ClearDueDate: true, ClearProject: true, ClearCategory: true, Notes: stringPtr("")})
+			require.NoError(t, err)
+			assert.Nil(t, cleared.DueDate)
+			assert.Nil(t, cleared.ProjectID)
+			assert.Nil(t, cleared.CategoryID)
+			assert.Empty(t, cleared.Notes)
+			assertStoredTodo(t, b, cleared)
+
+			unchanged, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{})
+			require.NoError(t, err)
+			assertSameTodo(t, cleared, unchanged)
+		}},
+		{"UpdateTodoStartDate", func(t *testing.T, b Backend) {
+			due := date(2030, time.May, 1)
+			item, err := b.Todos.AddTodo(ctx, "File taxes", &due)
+			require.NoError(t, err)
+			assert.Nil(t, item.StartDate, "new todos are not deferred")
+
+			start := date(2030, time.April, 1)
+			deferred, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{StartDate: &start})
+			require.NoError(t, err)
+			assertSameTime(t, &start, deferred.StartDate)
+			assertSameTime(t, &due, deferred.DueDate, "the due date is kept")
+			assertStoredTodo(t, b, deferred)
+
+			active, err := b.Todos.GetActiveTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{item.ID}, ids(active), "deferring is up to the listing, not the store")
+
+			cleared, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{ClearStartDate: true})
+			require.NoError(t, err)
+			assert.Nil(t, cleared.StartDate)
+			assertStoredTodo(t, b, cleared)
+		}},
+		{"UpdateTodoEstimate", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Write report", nil)
+			require.NoError(t, err)
+			assert.Nil(t, item.Estimate, "new todos are not estimated")
+
+			estimated, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{Estimate: intPtr(90)})
+			require.NoError(t, err)
+			assert.Equal(t, intPtr(90), estimated.Estimate)
+			assertStoredTodo(t, b, estimated)
+
+			cleared, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{ClearEstimate: true})
+			require.NoError(t, err)
+			assert.Nil(t, cleared.Estimate)
+			assertStoredTodo(t, b, cleared)
+		}},
+		{"AllDayDueDates", func(t *testing.T, b Backend) {
+			// 23:30 on November 3 east of UTC is still November 3 as a
+			// calendar date, though the instant falls on November 3 UTC too
+			east := time.FixedZone("UTC+2", 2*60*60)
+			timed := time.Date(2030, time.November, 3, 23, 30, 0, 0, east)
+			item, err := b.Todos.AddTodo(ctx, "Pay rent", &timed)
+			require.NoError(t, err)
+			assertSameTime(t, &timed, item.DueDate, "timed due dates keep their instant")
+			assert.False(t, item.DueAllDay)
+
+			allDay, err := b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{DueDate: &timed, DueAllDay: true})
+			require.NoError(t, err)
+			want := time.Date(2030, time.November, 3, 0, 0, 0, 0, time.UTC)
+			assertSameTime(t, &want, allDay.DueDate, "all-day due dates are midnight UTC on the date as given")
+			assert.True(t, allDay.DueAllDay)
+			assertStoredTodo(t, b, allDay)
+
+			// A daily series of all-day todos stays all-day
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: item.ID, Frequency: "daily", Interval: 1})
+			require.NoError(t, err)
+			_, next, err := b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			tomorrow := want.AddDate(0, 0, 1)
+			assertSameTime(t, &tomorrow, next.DueDate)
+			assert.True(t, next.DueAllDay)
+			assertStoredTodo(t, b, *next)
+
+			moved, err := b.Todos.SetDueDate(ctx, next.ID, timed)
+			require.NoError(t, err)
+			assert.False(t, moved.DueAllDay, "SetDueDate sets a timed due date")
+			assertStoredTodo(t, b, moved)
+			_, err = b.Todos.UpdateTodo(ctx, next.ID, todo.TodoPatch{DueDate: &timed, DueAllDay: true})
+			require.NoError(t, err)
+			cleared, err := b.Todos.UpdateTodo(ctx, next.ID, todo.TodoPatch{ClearDueDate: true})
+			require.NoError(t, err)
+			assert.Nil(t, cleared.DueDate)
+			assert.False(t, cleared.DueAllDay)
+			assertStoredTodo(t, b, cleared)
+		}},
+		{"UpdateTodoErrors", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Fix sink", nil)
+			require.NoError(t, err)
+			now := time.Now()
+
+			for name, patch := range map[string]todo.TodoPatch{
+				"empty title":       {Title: stringPtr("")},
+				"set and clear due": {DueDate: &now, ClearDueDate: true},
+				"set and clear start": {StartDate: &now, ClearStartDate: true},
+				"all-day without date": {DueAllDay: true},
+				"zero estimate":     {Estimate: intPtr(0)},
+				"set and clear estimate": {Estimate: intPtr(30), ClearEstimate: true},
+				"long notes":        {Notes: stringPtr(strings.Repeat("x", 65536))},
+			} {
+				_, err = b.Todos.UpdateTodo(ctx, item.ID, patch)
+				assert.ErrorIs(t, err, todo.ErrValidation, name)
+			}
+			_, err = b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{Title: stringPtr("Renamed"), ProjectID: int64Ptr(999999)})
+			assert.ErrorIs(t, err, todo.ErrProjectNotFound)
+			_, err = b.Todos.UpdateTodo(ctx, item.ID, todo.TodoPatch{Title: stringPtr("Renamed"), CategoryID: int64Ptr(999999)})
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+			_, err = b.Todos.UpdateTodo(ctx, "999999", todo.TodoPatch{Title: stringPtr("Renamed")})
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+
+			stored, err := b.Todos.GetTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Fix sink", stored.Title, "a failed update changes nothing")
+		}},
+		{"SetNotes", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Plan offsite", nil)
+			require.NoError(t, err)
+			assert.Empty(t, item.Notes)
+
+			notes := "## Agenda\n\n- [ ] Book venue\n- [ ] See https://example.com/offsite\n"
+			updated, err := b.Todos.SetNotes(ctx, item.ID, notes)
+			require.NoError(t, err)
+			assert.Equal(t, "Plan offsite", updated.Title)
+			assert.Equal(t, notes, updated.Notes)
+			assertStoredTodo(t, b, updated)
+
+			cleared, err := b.Todos.SetNotes(ctx, item.ID, "")
+			require.NoError(t, err)
+			assert.Empty(t, cleared.Notes)
+			assertStoredTodo(t, b, cleared)
+
+			_, err = b.Todos.SetNotes(ctx, item.ID, strings.Repeat("x", 65536))
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.SetNotes(ctx, "999999", notes)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"ListsSortByPriorityThenDueDate", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Home", nil)
+			require.NoError(t, err)
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Chores"})
+			require.NoError(t, err)
+
+			add := func(title string, priority todo.Priority, due *time.Time) string {
+				item, err := b.Todos.AddTodoToProject(ctx, title, project.ID, due)
+				require.NoError(t, err)
+				_, err = b.Todos.AssignTodoToCategory(ctx, item.ID, category.ID)
+				require.NoError(t, err)
+				_, err = b.Todos.SetPriority(ctx, item.ID, priority)
+				require.NoError(t, err)
+				return item.ID
+			}
+			soon, later := date(2030, time.May, 1), date(2030, time.June, 1)
+			lowSoon := add("Dust shelves", todo.PriorityLow, &soon)
+			noneSoon := add("Water plants", todo.PriorityNone, &soon)
+			highUndated := add("Fix leak", todo.PriorityHigh, nil)
+			highLater := add("Pay rent", todo.PriorityHigh, &later)
+			highSoon := add("Call plumber", todo.PriorityHigh, &soon)
+			want := []string{highSoon, highLater, highUndated, lowSoon, noneSoon}
+
+			lists := map[string]func() ([]todo.TodoItem, error){
+				"GetActiveTodos":     func() ([]todo.TodoItem, error) { return b.Todos.GetActiveTodos(ctx) },
+				"GetTodosByProject":  func() ([]todo.TodoItem, error) { return b.Todos.GetTodosByProject(ctx, project.ID) },
+				"GetTodosByCategory": func() ([]todo.TodoItem, error) { return b.Todos.GetTodosByCategory(ctx, category.ID) },
+				"GetProjectTodos":    func() ([]todo.TodoItem, error) { return b.Projects.GetProjectTodos(ctx, project.ID) },
+				"FindTodosByCategory": func() ([]todo.TodoItem, error) {
+					return b.Categories.FindTodosByCategory(ctx, category.ID)
+				},
+			}
+			for name, list := range lists {
+				items, err := list()
+				require.NoError(t, err, name)
+				assert.Equal(t, want, ids(items), name)
+			}
+		}},
+		{"DeleteTodo", func(t *testing.T, b Backend) {
+			keep, err := b.Todos.AddTodo(ctx, "Keep", nil)
+			require.NoError(t, err)
+			item, err := b.Todos.AddTodo(ctx, "Remove", nil)
+			require.NoError(t, err)
+
+			deleted, err := b.Todos.DeleteTodo(ctx, item.ID)
+			require.NoError(t, err)
+			assertSameTodo(t, item, deleted)
+
+			_, err = b.Todos.GetTodo(ctx, item.ID)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			all, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{keep.ID}, ids(all))
+
+			_, err = b.Todos.DeleteTodo(ctx, item.ID)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound, "deleting twice")
+		}},
+		{"GetAllTodos", func(t *testing.T, b Backend) {
+			var want []string
+			for _, title := range []string{"One", "Two", "Three"} {
+				item, err := b.Todos.AddTodo(ctx, title, nil)
+				require.NoError(t, err)
+				want = append(want, item.ID)
+			}
+			_, err := b.Todos.CompleteTodo(ctx, want[1])
+			require.NoError(t, err)
+
+			all, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, want, ids(all))
+		}},
+		{"TitleSearchTodo", func(t *testing.T, b Backend) {
+			milk, err := b.Todos.AddTodo(ctx, "Buy milk", nil)
+			require.NoError(t, err)
+			oatMilk, err := b.Todos.AddTodo(ctx, "Buy oat MILK", nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AddTodo(ctx, "Walk dog", nil)
+			require.NoError(t, err)
+			_, err = b.Todos.CompleteTodo(ctx, oatMilk.ID)
+			require.NoError(t, err)
+
+			results, err := b.Todos.TitleSearchTodo(ctx, "milk", false)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{milk.ID, oatMilk.ID}, ids(results), "search is case-insensitive substring match")
+
+			results, err = b.Todos.TitleSearchTodo(ctx, "milk", true)
+			require.NoError(t, err)
+			assert.Equal(t, []string{milk.ID}, ids(results), "activeOnly excludes completed todos")
+
+			results, err = b.Todos.TitleSearchTodo(ctx, "cheese", false)
+			require.NoError(t, err)
+			assert.Empty(t, results)
+		}},
+		{"SearchTodos", func(t *testing.T, b Backend) {
+			inTitle, err := b.Todos.AddTodo(ctx, "Renew passport", nil)
+			require.NoError(t, err)
+			inNotes, err := b.Todos.AddTodo(ctx, "Book trip", nil)
+			require.NoError(t, err)
+			_, err = b.Todos.SetNotes(ctx, inNotes.ID, "Check the PASSPORT expires after we return")
+			require.NoError(t, err)
+			done, err := b.Todos.AddTodo(ctx, "Find old passport", nil)
+			require.NoError(t, err)
+			_, err = b.Todos.CompleteTodo(ctx, done.ID)
+			require.NoError(t, err)
+			_, err = b.Todos.AddTodo(ctx, "Walk dog", nil)
+			require.NoError(t, err)
+
+			results, err := b.Todos.SearchTodos(ctx, "passport", false)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{inTitle.ID, inNotes.ID, done.ID}, ids(results), "search matches titles and notes")
+
+			results, err = b.Todos.SearchTodos(ctx, "passport", true)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{inTitle.ID, inNotes.ID}, ids(results), "activeOnly excludes completed todos")
+
+			results, err = b.Todos.TitleSearchTodo(ctx, "passport", false)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{inTitle.ID, done.ID}, ids(results), "title search ignores notes")
+		}},
+		{"CategoryAssignment", func(t *testing.T, b Backend) {
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Work"})
+			require.NoError(t, err)
+			item, err := b.Todos.AddTodo(ctx, "Write report", nil)
+			require.NoError(t, err)
+			other, err := b.Todos.AddTodo(ctx, "Read book", nil)
+			require.NoError(t, err)
+
+			assigned, err := b.Todos.AssignTodoToCategory(ctx, item.ID, category.ID)
+			require.NoError(t, err)
+			require.NotNil(t, assigned.CategoryID)
+			assert.Equal(t, category.ID, *assigned.CategoryID)
+
+			inCategory, err := b.Todos.GetTodosByCategory(ctx, category.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{item.ID}, ids(inCategory))
+			uncategorized, err := b.Todos.GetUncategorizedTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{other.ID}, ids(uncategorized))
+
+			removed, err := b.Todos.RemoveTodoFromCategory(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Nil(t, removed.CategoryID)
+
+			inCategory, err = b.Todos.GetTodosByCategory(ctx, category.ID)
+			require.NoError(t, err)
+			assert.Empty(t, inCategory)
+			uncategorized, err = b.Todos.GetUncategorizedTodos(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{item.ID, other.ID}, ids(uncategorized))
+
+			_, err = b.Todos.AssignTodoToCategory(ctx, "999999", category.ID)
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.RemoveTodoFromCategory(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+			_, err = b.Todos.AssignTodoToCategory(ctx, item.ID, 999999)
+			assert.ErrorIs(t, err, todo.ErrCategoryNotFound)
+		}},
+		{"GetTodosByProject", func(t *testing.T, b Backend) {
+			garden, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+			kitchen, err := b.Projects.CreateProject(ctx, "Kitchen", nil)
+			require.NoError(t, err)
+
+			a, err := b.Todos.AddTodoToProject(ctx, "Weed beds", garden.ID, nil)
+			require.NoError(t, err)
+			c, err := b.Todos.AddTodoToProject(ctx, "Prune roses", garden.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AddTodoToProject(ctx, "Paint walls", kitchen.ID, nil)
+			require.NoError(t, err)
+			_, err = b.Todos.AddTodo(ctx, "No project", nil)
+			require.NoError(t, err)
+
+			items, err := b.Todos.GetTodosByProject(ctx, garden.ID)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{a.ID, c.ID}, ids(items))
+		}},
+		{"RecurrencePattern", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Water plants", nil)
+			require.NoError(t, err)
+
+			until := date(2031, time.December, 31)
+			id, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{
+				TodoID:    item.ID,
+				Frequency: "weekly",
+				Interval:  2,
+				Until:     &until,
+			})
+			require.NoError(t, err)
+			assert.NotZero(t, id)
+
+			pattern, err := b.Todos.GetRecurrencePatternByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, id, pattern.ID)
+			assert.Equal(t, item.ID, pattern.TodoID)
+			assert.Equal(t, "weekly", pattern.Frequency)
+			assert.Equal(t, 2, pattern.Interval)
+			assertSameTime(t, &until, pattern.Until)
+			assert.Nil(t, pattern.Count)
+
+			count := 3
+			countID, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{
+				TodoID:    item.ID,
+				Frequency: "daily",
+				Interval:  1,
+				Count:     &count,
+			})
+			require.NoError(t, err)
+			assert.NotEqual(t, id, countID)
+
+			pattern, err = b.Todos.GetRecurrencePatternByID(ctx, countID)
+			require.NoError(t, err)
+			require.NotNil(t, pattern.Count)
+			assert.Equal(t, 3, *pattern.Count)
+			assert.Nil(t, pattern.Until)
+
+			_, err = b.Todos.GetRecurrencePatternByID(ctx, countID+1000)
+			assert.Error(t, err)
+		}},
+		{"RecurrencePatternValidation", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "Water plants", nil)
+			require.NoError(t, err)
+
+			zero := 0
+			for name, pattern := range map[string]todo.RecurrencePattern{
+				"unknown frequency": {TodoID: item.ID, Frequency: "fortnightly", Interval: 1},
+				"zero interval":     {TodoID: item.ID, Frequency: "daily", Interval: 0},
+				"zero count":        {TodoID: item.ID, Frequency: "daily", Interval: 1, Count: &zero},
+				"missing todo_id":   {Frequency: "daily", Interval: 1},
+			} {
+				_, err := b.Todos.AddRecurrencePattern(ctx, pattern)
+				assert.ErrorIs(t, err, todo.ErrValidation, name)
+			}
+		}},
+		{"CompleteRecurringTodo", func(t *testing.T, b Backend) {
+			project, err := b.Projects.CreateProject(ctx, "Garden", nil)
+			require.NoError(t, err)
+			category, err := b.Categories.Create(ctx, todo.Category{Name: "Outdoor"})
+			require.NoError(t, err)
+			due := date(2030, time.January, 31)
+			first, err := b.Todos.AddTodoToProject(ctx, "Water plants", project.ID, &due)
+			require.NoError(t, err)
+			_, err = b.Todos.AssignTodoToCategory(ctx, first.ID, category.ID)
+			require.NoError(t, err)
+			_, err = b.Todos.SetPriority(ctx, first.ID, todo.PriorityUrgent)
+			require.NoError(t, err)
+			_, err = b.Todos.SetNotes(ctx, first.ID, "Tomatoes need the most")
+			require.NoError(t, err)
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: first.ID, Frequency: "monthly", Interval: 1})
+			require.NoError(t, err)
+
+			completed, next, err := b.Todos.CompleteTodoWithNext(ctx, first.ID)
+			require.NoError(t, err)
+			assert.Equal(t, first.ID, completed.ID)
+			require.NotNil(t, completed.CompletedAt)
+			require.NotNil(t, next)
+			assert.NotEqual(t, first.ID, next.ID)
+			assert.Equal(t, "Water plants", next.Title)
+			assert.Nil(t, next.CompletedAt)
+			want := date(2030, time.February, 28)
+			assertSameTime(t, &want, next.DueDate)
+			assert.Equal(t, int64Ptr(parseID(t, first.ID)), next.ReferenceID)
+			assert.Equal(t, int64Ptr(project.ID), next.ProjectID)
+			assert.Equal(t, int64Ptr(category.ID), next.CategoryID)
+			assert.Equal(t, todo.PriorityUrgent, next.Priority)
+			assert.Equal(t, "Tomatoes need the most", next.Notes)
+			assertStoredTodo(t, b, *next)
+
+			// Later occurrences link back to the first todo, not to each other,
+			// and keep the original day of month
+			_, third, err := b.Todos.CompleteTodoWithNext(ctx, next.ID)
+			require.NoError(t, err)
+			require.NotNil(t, third)
+			want = date(2030, time.March, 31)
+			assertSameTime(t, &want, third.DueDate)
+			assert.Equal(t, int64Ptr(parseID(t, first.ID)), third.ReferenceID)
+
+			active, err := b.Todos.GetActiveTodos(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{third.ID}, ids(active))
+		}},
+		{"CompleteTodoWithRRule", func(t *testing.T, b Backend) {
+			// 2030-03-06 is a Wednesday
+			due := date(2030, time.March, 6)
+			first, err := b.Todos.AddTodo(ctx, "Gym", &due)
+			require.NoError(t, err)
+			patternID, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: first.ID, RRule: "byday=mo,we,fr;freq=weekly;count=3"})
+			require.NoError(t, err)
+
+			pattern, err := b.Todos.GetRecurrencePatternByID(ctx, patternID)
+			require.NoError(t, err)
+			assert.Equal(t, "FREQ=WEEKLY;COUNT=3;BYDAY=MO,WE,FR", pattern.RRule)
+			assert.Equal(t, "weekly", pattern.Frequency)
+			assert.Equal(t, 1, pattern.Interval)
+			require.NotNil(t, pattern.Count)
+			assert.Equal(t, 3, *pattern.Count)
+
+			_, second, err := b.Todos.CompleteTodoWithNext(ctx, first.ID)
+			require.NoError(t, err)
+			require.NotNil(t, second)
+			want := date(2030, time.March, 8)
+			assertSameTime(t, &want, second.DueDate)
+
+			_, third, err := b.Todos.CompleteTodoWithNext(ctx, second.ID)
+			require.NoError(t, err)
+			require.NotNil(t, third)
+			want = date(2030, time.March, 11)
+			assertSameTime(t, &want, third.DueDate)
+
+			_, fourth, err := b.Todos.CompleteTodoWithNext(ctx, third.ID)
+			require.NoError(t, err)
+			assert.Nil(t, fourth, "COUNT=3 ends the series")
+
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: first.ID, RRule: "FREQ=WEEKLY;BYMONTHDAY=1"})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+		}},
+		{"RecurrenceFollowsTimezone", func(t *testing.T, b Backend) {
+			newYork, err := time.LoadLocation("America/New_York")
+			require.NoError(t, err)
+			todos := b.TodosIn(newYork)
+
+			// Monday 20:00 in New York is already Tuesday in UTC, and the
+			// clocks go forward on Sunday 2030-03-10
+			due := time.Date(2030, time.March, 4, 20, 0, 0, 0, newYork)
+			weekly, err := todos.AddTodo(ctx, "Take out bins", &due)
+			require.NoError(t, err)
+			_, err = todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: weekly.ID, RRule: "FREQ=WEEKLY;BYDAY=MO"})
+			require.NoError(t, err)
+			_, next, err := todos.CompleteTodoWithNext(ctx, weekly.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			want := time.Date(2030, time.March, 11, 20, 0, 0, 0, newYork)
+			assertSameTime(t, &want, next.DueDate, "still Monday 20:00 after the DST change")
+
+			due = time.Date(2030, time.March, 9, 9, 0, 0, 0, newYork)
+			daily, err := todos.AddTodo(ctx, "Stand-up", &due)
+			require.NoError(t, err)
+			_, err = todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: daily.ID, Frequency: "daily", Interval: 1})
+			require.NoError(t, err)
+			_, next, err = todos.CompleteTodoWithNext(ctx, daily.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			_, next, err = todos.CompleteTodoWithNext(ctx, next.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			want = time.Date(2030, time.March, 11, 9, 0, 0, 0, newYork)
+			assertSameTime(t, &want, next.DueDate, "still 9:00 after the DST change")
+			assert.Equal(t, time.UTC, next.DueDate.Location(), "dates are stored in UTC")
+		}},
+		{"RecurrencePatternLifecycle", func(t *testing.T, b Backend) {
+			due := date(2030, time.March, 6)
+			first, err := b.Todos.AddTodo(ctx, "Gym", &due)
+			require.NoError(t, err)
+			other, err := b.Todos.AddTodo(ctx, "Read", nil)
+			require.NoError(t, err)
+			weeklyID, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: first.ID, Frequency: "weekly", Interval: 1})
+			require.NoError(t, err)
+			otherID, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: other.ID, Frequency: "daily", Interval: 1})
+			require.NoError(t, err)
+
+			all, err := b.Todos.ListRecurrencePatterns(ctx)
+			require.NoError(t, err)
+			require.Len(t, all, 2)
+			assert.Equal(t, weeklyID, all[0].ID)
+			assert.Equal(t, otherID, all[1].ID)
+
+			// A later occurrence finds the pattern on the first todo
+			_, second, err := b.Todos.CompleteTodoWithNext(ctx, first.ID)
+			require.NoError(t, err)
+			require.NotNil(t, second)
+			patterns, err := b.Todos.GetRecurrencePatternsForTodo(ctx, second.ID)
+			require.NoError(t, err)
+			require.Len(t, patterns, 1)
+			assert.Equal(t, weeklyID, patterns[0].ID)
+			assert.Equal(t, first.ID, patterns[0].TodoID)
+
+			updated, err := b.Todos.UpdateRecurrencePattern(ctx, todo.RecurrencePattern{ID: weeklyID, RRule: "FREQ=WEEKLY;BYDAY=MO,FR"})
+			require.NoError(t, err)
+			assert.Equal(t, first.ID, updated.TodoID)
+			assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,FR", updated.RRule)
+			stored, err := b.Todos.GetRecurrencePatternByID(ctx, weeklyID)
+			require.NoError(t, err)
+			assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,FR", stored.RRule)
+			assert.Equal(t, "weekly", stored.Frequency)
+
+			// 2030-03-13 is a Wednesday, so the updated rule moves to Friday
+			_, third, err := b.Todos.CompleteTodoWithNext(ctx, second.ID)
+			require.NoError(t, err)
+			require.NotNil(t, third)
+			want := date(2030, time.March, 15)
+			assertSameTime(t, &want, third.DueDate)
+
+			_, err = b.Todos.UpdateRecurrencePattern(ctx, todo.RecurrencePattern{ID: weeklyID, Frequency: "hourly", Interval: 1})
+			assert.ErrorIs(t, err, todo.ErrValidation)
+			_, err = b.Todos.UpdateRecurrencePattern(ctx, todo.RecurrencePattern{ID: otherID + 100, Frequency: "daily", Interval: 1})
+			assert.ErrorIs(t, err, todo.ErrRecurrencePatternNotFound)
+
+			deleted, err := b.Todos.DeleteRecurrencePattern(ctx, otherID)
+			require.NoError(t, err)
+			assert.Equal(t, other.ID, deleted.TodoID)
+			_, err = b.Todos.GetRecurrencePatternByID(ctx, otherID)
+			assert.ErrorIs(t, err, todo.ErrRecurrencePatternNotFound)
+			_, err = b.Todos.DeleteRecurrencePattern(ctx, otherID)
+			assert.ErrorIs(t, err, todo.ErrRecurrencePatternNotFound)
+			patterns, err = b.Todos.GetRecurrencePatternsForTodo(ctx, other.ID)
+			require.NoError(t, err)
+			assert.Empty(t, patterns)
+
+			_, err = b.Todos.GetRecurrencePatternsForTodo(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+		{"PauseAndResumeRecurrencePattern", func(t *testing.T, b Backend) {
+			// 2020-01-06 is a Monday, long past
+			due := date(2020, time.January, 6)
+			item, err := b.Todos.AddTodo(ctx, "Timesheet", &due)
+			require.NoError(t, err)
+			patternID, err := b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: item.ID, Frequency: "weekly", Interval: 1})
+			require.NoError(t, err)
+
+			paused, err := b.Todos.PauseRecurrencePattern(ctx, patternID)
+			require.NoError(t, err)
+			assert.True(t, paused.Paused)
+			_, next, err := b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Nil(t, next, "a paused pattern creates no occurrence")
+
+			// Resuming continues the series from today rather than 2020
+			resumed, next, err := b.Todos.ResumeRecurrencePattern(ctx, patternID)
+			require.NoError(t, err)
+			assert.False(t, resumed.Paused)
+			require.NotNil(t, next)
+			assert.True(t, next.DueDate.After(time.Now()))
+			assert.Equal(t, time.Monday, next.DueDate.UTC().Weekday())
+			assert.Equal(t, int64Ptr(parseID(t, item.ID)), next.ReferenceID)
+
+			// Resuming a pattern that is not paused creates nothing
+			_, again, err := b.Todos.ResumeRecurrencePattern(ctx, patternID)
+			require.NoError(t, err)
+			assert.Nil(t, again)
+
+			_, err = b.Todos.PauseRecurrencePattern(ctx, patternID+100)
+			assert.ErrorIs(t, err, todo.ErrRecurrencePatternNotFound)
+		}},
+		{"CompleteRecurringTodoOnlyOnce",func(t *testing.T, b Backend) {
+			due := date(2030, time.May, 1)
+			item, err := b.Todos.AddTodo(ctx, "Stand-up", &due)
+			require.NoError(t, err)
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: item.ID, Frequency: "daily", Interval: 1})
+			require.NoError(t, err)
+
+			first, next, err := b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+
+			// Completing again keeps the first completion and spawns nothing
+			again, none, err := b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Nil(t, none)
+			assertSameTime(t, first.CompletedAt, again.CompletedAt)
+
+			// Reopening and completing an older occurrence does not fork the series
+			_, err = b.Todos.UnCompleteTodo(ctx, item.ID)
+			require.NoError(t, err)
+			_, none, err = b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			assert.Nil(t, none)
+
+			all, err := b.Todos.GetAllTodos(ctx)
+			require.NoError(t, err)
+			assert.Len(t, all, 2)
+		}},
+		{"CompleteRecurringTodoHonorsCount", func(t *testing.T, b Backend) {
+			due := date(2030, time.May, 1)
+			item, err := b.Todos.AddTodo(ctx, "Physio", &due)
+			require.NoError(t, err)
+			count := 3
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: item.ID, Frequency: "weekly", Interval: 1, Count: &count})
+			require.NoError(t, err)
+
+			var dues []time.Time
+			current := item
+			for {
+				dues = append(dues, *current.DueDate)
+				_, next, err := b.Todos.CompleteTodoWithNext(ctx, current.ID)
+				require.NoError(t, err)
+				if next == nil {
+					break
+				}
+				require.Less(t, len(dues), 10, "series never ends")
+				current = *next
+			}
+			require.Len(t, dues, 3)
+			assert.True(t, dues[1].Equal(date(2030, time.May, 8)))
+			assert.True(t, dues[2].Equal(date(2030, time.May, 15)))
+		}},
+		{"CompleteRecurringTodoHonorsUntil", func(t *testing.T, b Backend) {
+			due := date(2030, time.May, 1)
+			item, err := b.Todos.AddTodo(ctx, "Pay rent", &due)
+			require.NoError(t, err)
+			until := date(2030, time.June, 15)
+			_, err = b.Todos.AddRecurrencePattern(ctx, todo.RecurrencePattern{TodoID: item.ID, Frequency: "monthly", Interval: 1, Until: &until})
+			require.NoError(t, err)
+
+			_, next, err := b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			require.NotNil(t, next)
+			want := date(2030, time.June, 1)
+			assertSameTime(t, &want, next.DueDate)
+
+			// July 1 is past Until
+			_, next, err = b.Todos.CompleteTodoWithNext(ctx, next.ID)
+			require.NoError(t, err)
+			assert.Nil(t, next)
+		}},
+		{"CompleteTodoWithoutPattern", func(t *testing.T, b Backend) {
+			item, err := b.Todos.AddTodo(ctx, "One-off", nil)
+			require.NoError(t, err)
+
+			completed, next, err := b.Todos.CompleteTodoWithNext(ctx, item.ID)
+			require.NoError(t, err)
+			assert.NotNil(t, completed.CompletedAt)
+			assert.Nil(t, next)
+
+			_, _, err = b.Todos.CompleteTodoWithNext(ctx, "999999")
+			assert.ErrorIs(t, err, todo.ErrTodoNotFound)
+		}},
+	})
+}
@@ -0,0 +1,149 @@
+// Package todotest provides a conformance suite for storage backends of the
+// todo package. Every backend (MariaDB, SQLite, in-memory, ...) should pass it,
+// so behaviour callers rely on is pinned down in one place rather than in
+// per-backend tests that drift apart.
+//
+// A backend's test file only needs a factory:
+//
+//	func TestSQLiteConformance(t *testing.T) {
+//		todotest.RunSuite(t, func(t *testing.T) todotest.Backend {
+//			db := openMigratedTestDB(t)
+//			return todotest.Backend{
+//				Todos:       todo.NewTodoSQLite(db, nil),
+//				TodosIn:     func(loc *time.Location) todo.TodoService { return todo.NewTodoSQLite(db, loc) },
+//				Projects:    todo.NewProjectSQLite(db),
+//				Categories:  todo.NewCategorySQLite(db),
+//				Tags:        todo.NewTagSQLite(db),
+//				TimeEntries: todo.NewTimeSQLite(db),
+//				Reminders:   todo.NewReminderSQLite(db),
+//				Filters:     todo.NewSavedFilterSQLite(db),
+//			}
+//		})
+//	}
+package todotest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"mcp-godo/pkg/todo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is one storage implementation under test. The services must share
+// the same underlying storage, so a todo added through Todos is visible to
+// Projects, Categories, Tags, TimeEntries, Reminders and Filters.
+type Backend struct {
+	Todos todo.TodoService
+	// TodosIn returns a TodoService on the same storage that expands
+	// recurring todos in loc; Todos expands them in UTC
+	TodosIn func(loc *time.Location) todo.TodoService
+
+	Projects    todo.ProjectService
+	Categories  todo.CategoryRepository
+	Tags        todo.TagService
+	TimeEntries todo.TimeService
+	Reminders   todo.ReminderService
+	Filters     todo.SavedFilterService
+}
+
+// Factory returns a Backend with empty storage. It is called once per subtest;
+// any cleanup should be registered with t.Cleanup.
+type Factory func(t *testing.T) Backend
+
+// RunSuite runs every conformance suite against the backend
+func RunSuite(t *testing.T, factory Factory) {
+	t.Run("TodoService", func(t *testing.T) { RunTodoServiceSuite(t, factory) })
+	t.Run("ProjectService", func(t *testing.T) { RunProjectServiceSuite(t, factory) })
+	t.Run("CategoryRepository", func(t *testing.T) { RunCategoryRepositorySuite(t, factory) })
+	t.Run("TagService", func(t *testing.T) { RunTagServiceSuite(t, factory) })
+	t.Run("Subtasks", func(t *testing.T) { RunSubtaskSuite(t, factory) })
+	t.Run("Dependencies", func(t *testing.T) { RunDependencySuite(t, factory) })
+	t.Run("TimeService", func(t *testing.T) { RunTimeServiceSuite(t, factory) })
+	t.Run("ReminderService", func(t *testing.T) { RunReminderServiceSuite(t, factory) })
+	t.Run("SavedFilterService", func(t *testing.T) { RunSavedFilterServiceSuite(t, factory) })
+}
+
+// testCase is one conformance check, run against a fresh backend
+type testCase struct {
+	name string
+	fn   func(t *testing.T, b Backend)
+}
+
+// run runs each case as a subtest on a fresh backend
+func run(t *testing.T, factory Factory, cases []testCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) { tc.fn(t, factory(t)) })
+	}
+}
+
+// date returns a whole-second UTC time, which every backend stores exactly
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+// assertStoredTodo checks that got, typically an element of a list result,
+// carries every column of the stored todo as returned by GetTodo
+func assertStoredTodo(t *testing.T, b Backend, got todo.TodoItem) {
+	t.Helper()
+	want, err := b.Todos.GetTodo(context.Background(), got.ID)
+	require.NoError(t, err)
+	assertSameTodo(t, want, got)
+}
+
+// assertSameTodo compares two todos field by field, comparing times as instants
+func assertSameTodo(t *testing.T, want, got todo.TodoItem) {
+	t.Helper()
+	assert.Equal(t, want.ID, got.ID, "id")
+	assert.Equal(t, want.Title, got.Title, "title of todo %s", want.ID)
+	assertSameTime(t, want.CompletedAt, got.CompletedAt, "completed_at of todo %s", want.ID)
+	assertSameTime(t, want.DueDate, got.DueDate, "due_date of todo %s", want.ID)
+	assert.Equal(t, want.DueAllDay, got.DueAllDay, "due_all_day of todo %s", want.ID)
+	assert.WithinDuration(t, want.CreatedDate, got.CreatedDate, time.Second, "created_date of todo %s", want.ID)
+	assert.Equal(t, want.ReferenceID, got.ReferenceID, "reference_id of todo %s", want.ID)
+	assert.Equal(t, want.ProjectID, got.ProjectID, "project_id of todo %s", want.ID)
+	assert.Equal(t, want.CategoryID, got.CategoryID, "category_id of todo %s", want.ID)
+	assert.Equal(t, want.Priority, got.Priority, "priority of todo %s", want.ID)
+	assert.Equal(t, want.Notes, got.Notes, "notes of todo %s", want.ID)
+	assert.Equal(t, want.ParentID, got.ParentID, "parent_id of todo %s", want.ID)
+	assertSameTime(t, want.StartDate, got.StartDate, "start_date of todo %s", want.ID)
+	assert.Equal(t, want.Estimate, got.Estimate, "estimate of todo %s", want.ID)
+}
+
+// assertSameTime checks that two optional times are both nil or within a second
+// of each other; backends may drop sub-second precision
+func assertSameTime(t *testing.T, want, got *time.Time, msgAndArgs ...interface{}) {
+	t.Helper()
+	if want == nil || got == nil {
+		assert.Equal(t, want == nil, got == nil, msgAndArgs...)
+		return
+	}
+	assert.WithinDuration(t, *want, *got, time.Second, msgAndArgs...)
+}
+
+// ids returns the IDs of todos in order
+func ids(todos []todo.TodoItem) []string {
+	result := make([]string, 0, len(todos))
+	for _, item := range todos {
+		result = append(result, item.ID)
+	}
+	return result
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func intPtr(v int) *int { return &v }
+
+// parseID returns a todo ID as the integer stored in reference_id
+func parseID(t *testing.T, id string) int64 {
+	t.Helper()
+	v, err := strconv.ParseInt(id, 10, 64)
+	require.NoError(t, err)
+	return v
+}
+
+func stringPtr(v string) *string { return &v }
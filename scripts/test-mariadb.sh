@@ -50,14 +50,13 @@ while true; do
 done
 echo " ready!"
 
-# Verify database exists and create tables
-echo -n "Setting up test database..."
-podman exec -i $CONTAINER_NAME mariadb -u root -p$ROOT_PASSWORD < sql/setup_test_db.sql || {
-    echo " failed!"
+# Create tables by applying the embedded migrations
+echo "Setting up test database..."
+STORAGE_TYPE=mariadb DB_PATH="root:${ROOT_PASSWORD}@tcp(localhost:${PORT})/${DATABASE}?parseTime=true" \
+    go run ./cmd/mcp-godo migrate up || {
     echo "Error: Failed to setup test database"
     exit 1
 }
-echo " OK"
 
 # Print connection info
 echo ""
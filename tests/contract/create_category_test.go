@@ -18,37 +18,37 @@ type MockCategoryService struct {
 	mock.Mock
 }
 
-func (m *MockCategoryService) CreateCategory(name string, description *string, color *string) (todo.Category, error) {
+func (m *MockCategoryService) CreateCategory(ctx context.Context, name string, description *string, color *string) (todo.Category, error) {
 	args := m.Called(name, description, color)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) GetAllCategories() ([]todo.Category, error) {
+func (m *MockCategoryService) GetAllCategories(ctx context.Context) ([]todo.Category, error) {
 	args := m.Called()
 	return args.Get(0).([]todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) GetCategoryByID(id int64) (todo.Category, error) {
+func (m *MockCategoryService) GetCategoryByID(ctx context.Context, id int64) (todo.Category, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) UpdateCategory(id int64, name *string, description *string, color *string) (todo.Category, error) {
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, id int64, name *string, description *string, color *string) (todo.Category, error) {
 	args := m.Called(id, name, description, color)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) DeleteCategory(id int64) error {
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) GetTodosByCategory(categoryID int64) ([]todo.TodoItem, error) {
+func (m *MockCategoryService) GetTodosByCategory(ctx context.Context, categoryID int64) ([]todo.TodoItem, error) {
 	args := m.Called(categoryID)
 	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockCategoryService) GetUncategorizedTodos() ([]todo.TodoItem, error) {
+func (m *MockCategoryService) GetUncategorizedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
 	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
@@ -58,101 +58,216 @@ type MockTodoService struct {
 	mock.Mock
 }
 
-func (m *MockTodoService) AddTodo(title string, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) AddTodo(ctx context.Context, title string, dueDate *time.Time) (todo.TodoItem, error) {
 	args := m.Called(title, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AddTodoToProject(title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) AddTodoToProject(ctx context.Context, title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error) {
 	args := m.Called(title, projectID, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AddTodoToCategory(title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) AddTodoToCategory(ctx context.Context, title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error) {
 	args := m.Called(title, categoryID, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetAllTodos() []todo.TodoItem {
+func (m *MockTodoService) GetAllTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetActiveTodos() []todo.TodoItem {
+func (m *MockTodoService) GetActiveTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetCompletedTodos() []todo.TodoItem {
+func (m *MockTodoService) GetCompletedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetTodosByProject(projectID int64) []todo.TodoItem {
+func (m *MockTodoService) GetTodosByProject(ctx context.Context, projectID int64) ([]todo.TodoItem, error) {
 	args := m.Called(projectID)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetTodosByCategory(categoryID int64) []todo.TodoItem {
+func (m *MockTodoService) GetTodosByCategory(ctx context.Context, categoryID int64) ([]todo.TodoItem, error) {
 	args := m.Called(categoryID)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetUncategorizedTodos() []todo.TodoItem {
+func (m *MockTodoService) GetUncategorizedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) GetTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) CompleteTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) CompleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) UnCompleteTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) CompleteTodoWithNext(ctx context.Context, id string) (todo.TodoItem, *todo.TodoItem, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.TodoItem), args.Get(1).(*todo.TodoItem), args.Error(2)
+}
+
+func (m *MockTodoService) UnCompleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) SetDueDate(id string, dueDate time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) SetDueDate(ctx context.Context, id string, dueDate time.Time) (todo.TodoItem, error) {
 	args := m.Called(id, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) DeleteTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) SetPriority(ctx context.Context, id string, priority todo.Priority) (todo.TodoItem, error) {
+	args := m.Called(id, priority)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) UpdateTodo(ctx context.Context, id string, patch todo.TodoPatch) (todo.TodoItem, error) {
+	args := m.Called(id, patch)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) SetNotes(ctx context.Context, id string, notes string) (todo.TodoItem, error) {
+	args := m.Called(id, notes)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) DeleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) TitleSearchTodo(query string, activeOnly bool) []todo.TodoItem {
+func (m *MockTodoService) TitleSearchTodo(ctx context.Context, query string, activeOnly bool) ([]todo.TodoItem, error) {
+	args := m.Called(query, activeOnly)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) SearchTodos(ctx context.Context, query string, activeOnly bool) ([]todo.TodoItem, error) {
 	args := m.Called(query, activeOnly)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) CompleteTodoWithPolicy(ctx context.Context, id string, policy todo.CompletionPolicy) (todo.TodoItem, *todo.TodoItem, error) {
+	args := m.Called(id, policy)
+	return args.Get(0).(todo.TodoItem), args.Get(1).(*todo.TodoItem), args.Error(2)
+}
+
+func (m *MockTodoService) CreateTodo(ctx context.Context, n todo.NewTodo) (todo.TodoItem, error) {
+	args := m.Called(n)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) AddSubtask(ctx context.Context, parentID string, title string, dueDate *time.Time) (todo.TodoItem, error) {
+	args := m.Called(parentID, title, dueDate)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) MoveTodo(ctx context.Context, id string, parentID *string) (todo.TodoItem, error) {
+	args := m.Called(id, parentID)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetSubtasks(ctx context.Context, parentID string) ([]todo.TodoItem, error) {
+	args := m.Called(parentID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetSubtaskProgress(ctx context.Context) (map[string]todo.SubtaskProgress, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]todo.SubtaskProgress), args.Error(1)
+}
+
+func (m *MockTodoService) AddBlocker(ctx context.Context, todoID string, blockerID string) ([]todo.TodoItem, error) {
+	args := m.Called(todoID, blockerID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) RemoveBlocker(ctx context.Context, todoID string, blockerID string) ([]todo.TodoItem, error) {
+	args := m.Called(todoID, blockerID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetBlockers(ctx context.Context, todoID string) ([]todo.TodoItem, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetDependents(ctx context.Context, blockerID string) ([]todo.TodoItem, error) {
+	args := m.Called(blockerID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AssignTodoToCategory(todoID string, categoryID int64) (todo.TodoItem, error) {
+func (m *MockTodoService) GetOpenBlockers(ctx context.Context) (map[string][]string, error) {
+	args := m.Called()
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+func (m *MockTodoService) GetNextActions(ctx context.Context) ([]todo.TodoItem, error) {
+	args := m.Called()
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) AssignTodoToCategory(ctx context.Context, todoID string, categoryID int64) (todo.TodoItem, error) {
 	args := m.Called(todoID, categoryID)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) RemoveTodoFromCategory(todoID string) (todo.TodoItem, error) {
+func (m *MockTodoService) RemoveTodoFromCategory(ctx context.Context, todoID string) (todo.TodoItem, error) {
 	args := m.Called(todoID)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AddRecurrencePattern(pattern todo.RecurrencePattern) (int64, error) {
+func (m *MockTodoService) AddRecurrencePattern(ctx context.Context, pattern todo.RecurrencePattern) (int64, error) {
 	args := m.Called(pattern)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockTodoService) GetRecurrencePatternByID(id int64) (todo.RecurrencePattern, error) {
+func (m *MockTodoService) GetRecurrencePatternByID(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) ListRecurrencePatterns(ctx context.Context) ([]todo.RecurrencePattern, error) {
+	args := m.Called()
+	return args.Get(0).([]todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) GetRecurrencePatternsForTodo(ctx context.Context, todoID string) ([]todo.RecurrencePattern, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) UpdateRecurrencePattern(ctx context.Context, pattern todo.RecurrencePattern) (todo.RecurrencePattern, error) {
+	args := m.Called(pattern)
+	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) DeleteRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
 }
 
+func (m *MockTodoService) PauseRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) ResumeRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, *todo.TodoItem, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.RecurrencePattern), args.Get(1).(*todo.TodoItem), args.Error(2)
+}
+
 func TestCreateCategoryHandler_Success(t *testing.T) {
 	mockCategoryService := new(MockCategoryService)
 	mockTodoService := new(MockTodoService)
@@ -275,9 +390,9 @@ func TestCreateCategoryHandler_ServiceError(t *testing.T) {
 
 	result, err := categoryHandler.CreateCategoryHandler(context.Background(), request)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to create category")
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to create category")
 	mockCategoryService.AssertExpectations(t)
 }
 
@@ -163,8 +163,8 @@ func TestGetCategoryHandler_ServiceError(t *testing.T) {
 
 	result, err := categoryHandler.GetCategoryHandler(context.Background(), request)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to retrieve category")
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to retrieve category")
 	mockCategoryService.AssertExpectations(t)
 }
\ No newline at end of file
@@ -284,9 +284,9 @@ func TestAssignTodoToCategoryHandler_ServiceError(t *testing.T) {
 	ctx := context.Background()
 	result, err := categoryHandler.AssignTodoToCategoryHandler(ctx, request)
 	
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to assign todo to category")
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to assign todo to category")
 	
 	mockTodoService.AssertExpectations(t)
 }
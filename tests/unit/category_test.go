@@ -1,6 +1,7 @@
 package unit
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -15,42 +16,42 @@ type MockCategoryRepository struct {
 	mock.Mock
 }
 
-func (m *MockCategoryRepository) Create(category todo.Category) (todo.Category, error) {
+func (m *MockCategoryRepository) Create(ctx context.Context, category todo.Category) (todo.Category, error) {
 	args := m.Called(category)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) FindAll() ([]todo.Category, error) {
+func (m *MockCategoryRepository) FindAll(ctx context.Context) ([]todo.Category, error) {
 	args := m.Called()
 	return args.Get(0).([]todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) FindByID(id int64) (todo.Category, error) {
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id int64) (todo.Category, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) FindByName(name string) (todo.Category, error) {
+func (m *MockCategoryRepository) FindByName(ctx context.Context, name string) (todo.Category, error) {
 	args := m.Called(name)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) Update(category todo.Category) (todo.Category, error) {
+func (m *MockCategoryRepository) Update(ctx context.Context, category todo.Category) (todo.Category, error) {
 	args := m.Called(category)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryRepository) Delete(id int64) error {
+func (m *MockCategoryRepository) Delete(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockCategoryRepository) FindTodosByCategory(categoryID int64) ([]todo.TodoItem, error) {
+func (m *MockCategoryRepository) FindTodosByCategory(ctx context.Context, categoryID int64) ([]todo.TodoItem, error) {
 	args := m.Called(categoryID)
 	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockCategoryRepository) FindUncategorizedTodos() ([]todo.TodoItem, error) {
+func (m *MockCategoryRepository) FindUncategorizedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
 	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
@@ -79,7 +80,7 @@ func TestCreateCategory_Success(t *testing.T) {
 	// Mock the Create call
 	mockRepo.On("Create", testCategory).Return(expectedCategory, nil)
 
-	result, err := service.CreateCategory("Work Tasks", stringPtr("Professional tasks"), stringPtr("#3498db"))
+	result, err := service.CreateCategory(context.Background(), "Work Tasks", stringPtr("Professional tasks"), stringPtr("#3498db"))
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCategory.ID, result.ID)
@@ -87,11 +88,63 @@ func TestCreateCategory_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// fakeUnitOfWork hands every unit of work the same transaction-bound repository
+type fakeUnitOfWork struct {
+	tx    todo.CategoryRepository
+	calls int
+	err   error
+}
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(tx todo.Repositories) error) error {
+	u.calls++
+	if err := fn(todo.Repositories{Categories: u.tx}); err != nil {
+		return err
+	}
+	return u.err
+}
+
+func TestCreateCategory_RunsInUnitOfWork(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	txRepo := new(MockCategoryRepository)
+	uow := &fakeUnitOfWork{tx: txRepo}
+	service := todo.NewTransactionalCategoryService(mockRepo, uow)
+
+	expectedCategory := todo.Category{ID: 1, Name: "Work Tasks"}
+
+	// The duplicate check and insert both go through the transaction
+	txRepo.On("FindByName", "Work Tasks").Return(todo.Category{}, assert.AnError)
+	txRepo.On("Create", todo.Category{Name: "Work Tasks"}).Return(expectedCategory, nil)
+
+	result, err := service.CreateCategory(context.Background(), "Work Tasks", nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedCategory.ID, result.ID)
+	assert.Equal(t, 1, uow.calls)
+	txRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateCategory_CommitError(t *testing.T) {
+	mockRepo := new(MockCategoryRepository)
+	txRepo := new(MockCategoryRepository)
+	uow := &fakeUnitOfWork{tx: txRepo, err: assert.AnError}
+	service := todo.NewTransactionalCategoryService(mockRepo, uow)
+
+	txRepo.On("FindByName", "Work Tasks").Return(todo.Category{}, assert.AnError)
+	txRepo.On("Create", todo.Category{Name: "Work Tasks"}).Return(todo.Category{ID: 1, Name: "Work Tasks"}, nil)
+
+	// A failed commit means the category was never created
+	_, err := service.CreateCategory(context.Background(), "Work Tasks", nil, nil)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	txRepo.AssertExpectations(t)
+}
+
 func TestCreateCategory_EmptyName(t *testing.T) {
 	mockRepo := new(MockCategoryRepository)
 	service := todo.NewCategoryService(mockRepo)
 
-	_, err := service.CreateCategory("", nil, nil)
+	_, err := service.CreateCategory(context.Background(), "", nil, nil)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "category name cannot be empty")
@@ -110,7 +163,7 @@ func TestCreateCategory_DuplicateName(t *testing.T) {
 	// Mock the FindByName call to return existing category
 	mockRepo.On("FindByName", "Work Tasks").Return(existingCategory, nil)
 
-	_, err := service.CreateCategory("Work Tasks", nil, nil)
+	_, err := service.CreateCategory(context.Background(), "Work Tasks", nil, nil)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "category with name 'Work Tasks' already exists")
@@ -123,7 +176,7 @@ func TestCreateCategory_InvalidColor(t *testing.T) {
 
 	invalidColor := "invalid-color"
 
-	_, err := service.CreateCategory("Work Tasks", nil, &invalidColor)
+	_, err := service.CreateCategory(context.Background(), "Work Tasks", nil, &invalidColor)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid hex color format")
@@ -141,7 +194,7 @@ func TestGetAllCategories_Success(t *testing.T) {
 
 	mockRepo.On("FindAll").Return(expectedCategories, nil)
 
-	result, err := service.GetAllCategories()
+	result, err := service.GetAllCategories(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(result))
@@ -159,7 +212,7 @@ func TestGetCategoryByID_Success(t *testing.T) {
 
 	mockRepo.On("FindByID", int64(1)).Return(expectedCategory, nil)
 
-	result, err := service.GetCategoryByID(1)
+	result, err := service.GetCategoryByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCategory.ID, result.ID)
@@ -188,7 +241,7 @@ func TestUpdateCategory_Success(t *testing.T) {
 	// Mock the Update call
 	mockRepo.On("Update", updatedCategory).Return(updatedCategory, nil)
 
-	result, err := service.UpdateCategory(1, &newName, nil, nil)
+	result, err := service.UpdateCategory(context.Background(), 1, &newName, nil, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "New Name", result.Name)
@@ -201,7 +254,7 @@ func TestDeleteCategory_Success(t *testing.T) {
 
 	mockRepo.On("Delete", int64(1)).Return(nil)
 
-	err := service.DeleteCategory(1)
+	err := service.DeleteCategory(context.Background(), 1)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -218,7 +271,7 @@ func TestGetTodosByCategory_Success(t *testing.T) {
 
 	mockRepo.On("FindTodosByCategory", int64(1)).Return(expectedTodos, nil)
 
-	result, err := service.GetTodosByCategory(1)
+	result, err := service.GetTodosByCategory(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(result))
@@ -236,7 +289,7 @@ func TestGetUncategorizedTodos_Success(t *testing.T) {
 
 	mockRepo.On("FindUncategorizedTodos").Return(expectedTodos, nil)
 
-	result, err := service.GetUncategorizedTodos()
+	result, err := service.GetUncategorizedTodos(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(result))
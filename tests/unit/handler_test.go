@@ -18,34 +18,34 @@ type MockProjectService struct {
 	mock.Mock
 }
 
-func (m *MockProjectService) CreateProject(name string, description *string) (todo.Project, error) {
+func (m *MockProjectService) CreateProject(ctx context.Context, name string, description *string) (todo.Project, error) {
 	args := m.Called(name, description)
 	return args.Get(0).(todo.Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetAllProjects() []todo.Project {
+func (m *MockProjectService) GetAllProjects(ctx context.Context) ([]todo.Project, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.Project)
+	return args.Get(0).([]todo.Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetProject(id int64) (todo.Project, error) {
+func (m *MockProjectService) GetProject(ctx context.Context, id int64) (todo.Project, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.Project), args.Error(1)
 }
 
-func (m *MockProjectService) UpdateProject(id int64, name string, description *string) (todo.Project, error) {
+func (m *MockProjectService) UpdateProject(ctx context.Context, id int64, name string, description *string) (todo.Project, error) {
 	args := m.Called(id, name, description)
 	return args.Get(0).(todo.Project), args.Error(1)
 }
 
-func (m *MockProjectService) DeleteProject(id int64) (todo.Project, error) {
+func (m *MockProjectService) DeleteProject(ctx context.Context, id int64) (todo.Project, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetProjectTodos(id int64) []todo.TodoItem {
+func (m *MockProjectService) GetProjectTodos(ctx context.Context, id int64) ([]todo.TodoItem, error) {
 	args := m.Called(id)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
 // MockCategoryService for testing
@@ -53,37 +53,37 @@ type MockCategoryService struct {
 	mock.Mock
 }
 
-func (m *MockCategoryService) CreateCategory(name string, description *string, color *string) (todo.Category, error) {
+func (m *MockCategoryService) CreateCategory(ctx context.Context, name string, description *string, color *string) (todo.Category, error) {
 	args := m.Called(name, description, color)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) GetAllCategories() ([]todo.Category, error) {
+func (m *MockCategoryService) GetAllCategories(ctx context.Context) ([]todo.Category, error) {
 	args := m.Called()
 	return args.Get(0).([]todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) GetCategoryByID(id int64) (todo.Category, error) {
+func (m *MockCategoryService) GetCategoryByID(ctx context.Context, id int64) (todo.Category, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) UpdateCategory(id int64, name *string, description *string, color *string) (todo.Category, error) {
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, id int64, name *string, description *string, color *string) (todo.Category, error) {
 	args := m.Called(id, name, description, color)
 	return args.Get(0).(todo.Category), args.Error(1)
 }
 
-func (m *MockCategoryService) DeleteCategory(id int64) error {
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id int64) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockCategoryService) GetTodosByCategory(categoryID int64) ([]todo.TodoItem, error) {
+func (m *MockCategoryService) GetTodosByCategory(ctx context.Context, categoryID int64) ([]todo.TodoItem, error) {
 	args := m.Called(categoryID)
 	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockCategoryService) GetUncategorizedTodos() ([]todo.TodoItem, error) {
+func (m *MockCategoryService) GetUncategorizedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
 	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
@@ -96,7 +96,7 @@ func TestGetActiveTodosHandler_NoTodos(t *testing.T) {
 	h := handler.NewHandlerWithProjectAndCategory(mockTodoService, mockProjectService, mockCategoryService)
 	
 	// Mock no active todos
-	mockTodoService.On("GetActiveTodos").Return([]todo.TodoItem{})
+	mockTodoService.On("GetActiveTodos").Return([]todo.TodoItem{}, nil)
 	
 	ctx := context.Background()
 	request := mcp.CallToolRequest{}
@@ -130,7 +130,10 @@ func TestGetActiveTodosHandler_WithTodos_NoProjectNoCategory(t *testing.T) {
 		},
 	}
 	
-	mockTodoService.On("GetActiveTodos").Return(todos)
+	mockTodoService.On("GetActiveTodos").Return(todos, nil)
+	mockTodoService.On("ListRecurrencePatterns").Return([]todo.RecurrencePattern(nil), nil)
+	mockTodoService.On("GetSubtaskProgress").Return(map[string]todo.SubtaskProgress(nil), nil)
+	mockTodoService.On("GetOpenBlockers").Return(map[string][]string(nil), nil)
 	
 	ctx := context.Background()
 	request := mcp.CallToolRequest{}
@@ -182,7 +185,10 @@ func TestGetActiveTodosHandler_WithProjectAndCategory(t *testing.T) {
 		Name: "Test Category",
 	}
 	
-	mockTodoService.On("GetActiveTodos").Return(todos)
+	mockTodoService.On("GetActiveTodos").Return(todos, nil)
+	mockTodoService.On("ListRecurrencePatterns").Return([]todo.RecurrencePattern(nil), nil)
+	mockTodoService.On("GetSubtaskProgress").Return(map[string]todo.SubtaskProgress(nil), nil)
+	mockTodoService.On("GetOpenBlockers").Return(map[string][]string(nil), nil)
 	mockProjectService.On("GetProject", int64(1)).Return(project, nil)
 	mockCategoryService.On("GetCategoryByID", int64(2)).Return(category, nil)
 	
@@ -233,7 +239,10 @@ func TestGetActiveTodosHandler_WithProjectServiceError(t *testing.T) {
 		Name: "Test Category",
 	}
 	
-	mockTodoService.On("GetActiveTodos").Return(todos)
+	mockTodoService.On("GetActiveTodos").Return(todos, nil)
+	mockTodoService.On("ListRecurrencePatterns").Return([]todo.RecurrencePattern(nil), nil)
+	mockTodoService.On("GetSubtaskProgress").Return(map[string]todo.SubtaskProgress(nil), nil)
+	mockTodoService.On("GetOpenBlockers").Return(map[string][]string(nil), nil)
 	mockProjectService.On("GetProject", int64(1)).Return(todo.Project{}, assert.AnError)
 	mockCategoryService.On("GetCategoryByID", int64(2)).Return(category, nil)
 	
@@ -277,7 +286,10 @@ func TestGetActiveTodosHandler_WithoutServices(t *testing.T) {
 		},
 	}
 	
-	mockTodoService.On("GetActiveTodos").Return(todos)
+	mockTodoService.On("GetActiveTodos").Return(todos, nil)
+	mockTodoService.On("ListRecurrencePatterns").Return([]todo.RecurrencePattern(nil), nil)
+	mockTodoService.On("GetSubtaskProgress").Return(map[string]todo.SubtaskProgress(nil), nil)
+	mockTodoService.On("GetOpenBlockers").Return(map[string][]string(nil), nil)
 	
 	ctx := context.Background()
 	request := mcp.CallToolRequest{}
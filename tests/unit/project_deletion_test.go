@@ -145,9 +145,9 @@ func TestDeleteProjectHandler_ServiceError(t *testing.T) {
 	ctx := context.Background()
 	result, err := h.DeleteProjectHandler(ctx, request)
 	
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "failed to delete project")
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to delete project")
 	
 	mockProjectService.AssertExpectations(t)
 }
@@ -1,6 +1,7 @@
 package unit
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -15,101 +16,216 @@ type MockTodoService struct {
 	mock.Mock
 }
 
-func (m *MockTodoService) AddTodo(title string, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) AddTodo(ctx context.Context, title string, dueDate *time.Time) (todo.TodoItem, error) {
 	args := m.Called(title, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AddTodoToProject(title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) AddTodoToProject(ctx context.Context, title string, projectID int64, dueDate *time.Time) (todo.TodoItem, error) {
 	args := m.Called(title, projectID, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AddTodoToCategory(title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) AddTodoToCategory(ctx context.Context, title string, categoryID int64, dueDate *time.Time) (todo.TodoItem, error) {
 	args := m.Called(title, categoryID, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetAllTodos() []todo.TodoItem {
+func (m *MockTodoService) GetAllTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetActiveTodos() []todo.TodoItem {
+func (m *MockTodoService) GetActiveTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetCompletedTodos() []todo.TodoItem {
+func (m *MockTodoService) GetCompletedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetTodosByProject(projectID int64) []todo.TodoItem {
+func (m *MockTodoService) GetTodosByProject(ctx context.Context, projectID int64) ([]todo.TodoItem, error) {
 	args := m.Called(projectID)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetTodosByCategory(categoryID int64) []todo.TodoItem {
+func (m *MockTodoService) GetTodosByCategory(ctx context.Context, categoryID int64) ([]todo.TodoItem, error) {
 	args := m.Called(categoryID)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetUncategorizedTodos() []todo.TodoItem {
+func (m *MockTodoService) GetUncategorizedTodos(ctx context.Context) ([]todo.TodoItem, error) {
 	args := m.Called()
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) GetTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) GetTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) CompleteTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) CompleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) UnCompleteTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) CompleteTodoWithNext(ctx context.Context, id string) (todo.TodoItem, *todo.TodoItem, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.TodoItem), args.Get(1).(*todo.TodoItem), args.Error(2)
+}
+
+func (m *MockTodoService) UnCompleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) SetDueDate(id string, dueDate time.Time) (todo.TodoItem, error) {
+func (m *MockTodoService) SetDueDate(ctx context.Context, id string, dueDate time.Time) (todo.TodoItem, error) {
 	args := m.Called(id, dueDate)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) DeleteTodo(id string) (todo.TodoItem, error) {
+func (m *MockTodoService) SetPriority(ctx context.Context, id string, priority todo.Priority) (todo.TodoItem, error) {
+	args := m.Called(id, priority)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) UpdateTodo(ctx context.Context, id string, patch todo.TodoPatch) (todo.TodoItem, error) {
+	args := m.Called(id, patch)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) SetNotes(ctx context.Context, id string, notes string) (todo.TodoItem, error) {
+	args := m.Called(id, notes)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) DeleteTodo(ctx context.Context, id string) (todo.TodoItem, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) TitleSearchTodo(query string, activeOnly bool) []todo.TodoItem {
+func (m *MockTodoService) TitleSearchTodo(ctx context.Context, query string, activeOnly bool) ([]todo.TodoItem, error) {
+	args := m.Called(query, activeOnly)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) SearchTodos(ctx context.Context, query string, activeOnly bool) ([]todo.TodoItem, error) {
 	args := m.Called(query, activeOnly)
-	return args.Get(0).([]todo.TodoItem)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) CompleteTodoWithPolicy(ctx context.Context, id string, policy todo.CompletionPolicy) (todo.TodoItem, *todo.TodoItem, error) {
+	args := m.Called(id, policy)
+	return args.Get(0).(todo.TodoItem), args.Get(1).(*todo.TodoItem), args.Error(2)
 }
 
-func (m *MockTodoService) AssignTodoToCategory(todoID string, categoryID int64) (todo.TodoItem, error) {
+func (m *MockTodoService) CreateTodo(ctx context.Context, n todo.NewTodo) (todo.TodoItem, error) {
+	args := m.Called(n)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) AddSubtask(ctx context.Context, parentID string, title string, dueDate *time.Time) (todo.TodoItem, error) {
+	args := m.Called(parentID, title, dueDate)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) MoveTodo(ctx context.Context, id string, parentID *string) (todo.TodoItem, error) {
+	args := m.Called(id, parentID)
+	return args.Get(0).(todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetSubtasks(ctx context.Context, parentID string) ([]todo.TodoItem, error) {
+	args := m.Called(parentID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetSubtaskProgress(ctx context.Context) (map[string]todo.SubtaskProgress, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]todo.SubtaskProgress), args.Error(1)
+}
+
+func (m *MockTodoService) AddBlocker(ctx context.Context, todoID string, blockerID string) ([]todo.TodoItem, error) {
+	args := m.Called(todoID, blockerID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) RemoveBlocker(ctx context.Context, todoID string, blockerID string) ([]todo.TodoItem, error) {
+	args := m.Called(todoID, blockerID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetBlockers(ctx context.Context, todoID string) ([]todo.TodoItem, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetDependents(ctx context.Context, blockerID string) ([]todo.TodoItem, error) {
+	args := m.Called(blockerID)
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) GetOpenBlockers(ctx context.Context) (map[string][]string, error) {
+	args := m.Called()
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+func (m *MockTodoService) GetNextActions(ctx context.Context) ([]todo.TodoItem, error) {
+	args := m.Called()
+	return args.Get(0).([]todo.TodoItem), args.Error(1)
+}
+
+func (m *MockTodoService) AssignTodoToCategory(ctx context.Context, todoID string, categoryID int64) (todo.TodoItem, error) {
 	args := m.Called(todoID, categoryID)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) RemoveTodoFromCategory(todoID string) (todo.TodoItem, error) {
+func (m *MockTodoService) RemoveTodoFromCategory(ctx context.Context, todoID string) (todo.TodoItem, error) {
 	args := m.Called(todoID)
 	return args.Get(0).(todo.TodoItem), args.Error(1)
 }
 
-func (m *MockTodoService) AddRecurrencePattern(pattern todo.RecurrencePattern) (int64, error) {
+func (m *MockTodoService) AddRecurrencePattern(ctx context.Context, pattern todo.RecurrencePattern) (int64, error) {
 	args := m.Called(pattern)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockTodoService) GetRecurrencePatternByID(id int64) (todo.RecurrencePattern, error) {
+func (m *MockTodoService) GetRecurrencePatternByID(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
 	args := m.Called(id)
 	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
 }
 
+func (m *MockTodoService) ListRecurrencePatterns(ctx context.Context) ([]todo.RecurrencePattern, error) {
+	args := m.Called()
+	return args.Get(0).([]todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) GetRecurrencePatternsForTodo(ctx context.Context, todoID string) ([]todo.RecurrencePattern, error) {
+	args := m.Called(todoID)
+	return args.Get(0).([]todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) UpdateRecurrencePattern(ctx context.Context, pattern todo.RecurrencePattern) (todo.RecurrencePattern, error) {
+	args := m.Called(pattern)
+	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) DeleteRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) PauseRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.RecurrencePattern), args.Error(1)
+}
+
+func (m *MockTodoService) ResumeRecurrencePattern(ctx context.Context, id int64) (todo.RecurrencePattern, *todo.TodoItem, error) {
+	args := m.Called(id)
+	return args.Get(0).(todo.RecurrencePattern), args.Get(1).(*todo.TodoItem), args.Error(2)
+}
+
 func (m *MockTodoService) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -128,7 +244,7 @@ func TestAddTodoToCategory_Success(t *testing.T) {
 
 	mockService.On("AddTodoToCategory", "Test Task", int64(1), (*time.Time)(nil)).Return(expectedTodo, nil)
 
-	result, err := mockService.AddTodoToCategory("Test Task", 1, nil)
+	result, err := mockService.AddTodoToCategory(context.Background(), "Test Task", 1, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedTodo.ID, result.ID)
@@ -148,7 +264,7 @@ func TestAssignTodoToCategory_Success(t *testing.T) {
 
 	mockService.On("AssignTodoToCategory", "123", int64(1)).Return(expectedTodo, nil)
 
-	result, err := mockService.AssignTodoToCategory("123", 1)
+	result, err := mockService.AssignTodoToCategory(context.Background(), "123", 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedTodo.ID, result.ID)
@@ -167,7 +283,7 @@ func TestRemoveTodoFromCategory_Success(t *testing.T) {
 
 	mockService.On("RemoveTodoFromCategory", "123").Return(expectedTodo, nil)
 
-	result, err := mockService.RemoveTodoFromCategory("123")
+	result, err := mockService.RemoveTodoFromCategory(context.Background(), "123")
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedTodo.ID, result.ID)
@@ -183,9 +299,9 @@ func TestGetTodosByCategoryFromService_Success(t *testing.T) {
 		{ID: "2", Title: "Task 2", CategoryID: int64Ptr(1)},
 	}
 
-	mockService.On("GetTodosByCategory", int64(1)).Return(expectedTodos)
+	mockService.On("GetTodosByCategory", int64(1)).Return(expectedTodos, nil)
 
-	result := mockService.GetTodosByCategory(1)
+	result, _ := mockService.GetTodosByCategory(context.Background(), 1)
 
 	assert.Equal(t, 2, len(result))
 	assert.Equal(t, "Task 1", result[0].Title)
@@ -201,9 +317,9 @@ func TestGetUncategorizedTodosFromService_Success(t *testing.T) {
 		{ID: "2", Title: "Task 2", CategoryID: nil},
 	}
 
-	mockService.On("GetUncategorizedTodos").Return(expectedTodos)
+	mockService.On("GetUncategorizedTodos").Return(expectedTodos, nil)
 
-	result := mockService.GetUncategorizedTodos()
+	result, _ := mockService.GetUncategorizedTodos(context.Background())
 
 	assert.Equal(t, 2, len(result))
 	assert.Nil(t, result[0].CategoryID)
@@ -216,7 +332,7 @@ func TestAssignTodoToCategory_InvalidTodoID(t *testing.T) {
 	
 	mockService.On("AssignTodoToCategory", "", int64(1)).Return(todo.TodoItem{}, assert.AnError)
 
-	_, err := mockService.AssignTodoToCategory("", 1)
+	_, err := mockService.AssignTodoToCategory(context.Background(), "", 1)
 
 	assert.Error(t, err)
 	mockService.AssertExpectations(t)
@@ -227,7 +343,7 @@ func TestRemoveTodoFromCategory_InvalidTodoID(t *testing.T) {
 	
 	mockService.On("RemoveTodoFromCategory", "").Return(todo.TodoItem{}, assert.AnError)
 
-	_, err := mockService.RemoveTodoFromCategory("")
+	_, err := mockService.RemoveTodoFromCategory(context.Background(), "")
 
 	assert.Error(t, err)
 	mockService.AssertExpectations(t)